@@ -0,0 +1,330 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jon4hz/deadshot/internal/config"
+	"github.com/jon4hz/deadshot/internal/database"
+	log "github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/pkg/ethutils"
+
+	chain "github.com/jon4hz/deadshot/internal/blockchain"
+
+	"github.com/spf13/cobra"
+)
+
+var allowancesFlags struct {
+	network   string
+	wallet    string
+	tokens    []string
+	dexes     []string
+	amount    string
+	unlimited bool
+	all       bool
+	json      bool
+	testnet   bool
+}
+
+var allowancesCmd = &cobra.Command{
+	Use:   "allowances",
+	Short: "List and manage the token allowances of the dex routers",
+	Long: `List the allowances of a wallet for the known tokens of a network against every configured router.
+Allowances can be revoked or set to an amount and tokens can be configured to always approve an unlimited amount.`,
+	PreRunE: allowancesPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listAllowances()
+	},
+}
+
+var allowancesSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set the allowance of the routers for a token",
+	Long: `Set the allowance of the routers for a token to an amount in token units.
+Use "unlimited" as amount to approve the maximum amount.`,
+	PreRunE: allowancesPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if allowancesFlags.amount == "" {
+			return errors.New("missing amount")
+		}
+		return setAllowances(allowancesFlags.amount)
+	},
+}
+
+var allowancesRevokeCmd = &cobra.Command{
+	Use:     "revoke",
+	Short:   "Revoke the allowance of the routers for a token",
+	PreRunE: allowancesPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setAllowances("0")
+	},
+}
+
+var allowancesPolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Set the approval policy of a token",
+	Long: `By default the router is approved for the amount of each trade, which costs an extra transaction for every trade.
+With --unlimited the router is approved for the maximum amount once instead.`,
+	PreRunE: allowancesPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setApprovalPolicy(allowancesFlags.unlimited)
+	},
+}
+
+func init() {
+	allowancesCmd.PersistentFlags().StringVarP(&allowancesFlags.network, "network", "n", "", "Name of the network")
+	allowancesCmd.PersistentFlags().StringVarP(&allowancesFlags.wallet, "wallet", "w", "", "Address or label of the wallet (default: main wallet)")
+	allowancesCmd.PersistentFlags().StringSliceVarP(&allowancesFlags.tokens, "tokens", "t", nil, "Contracts or symbols of the tokens (default: all tokens of the network)")
+	allowancesCmd.PersistentFlags().StringSliceVarP(&allowancesFlags.dexes, "dexes", "d", nil, "Names of the dexes (default: all dexes of the network)")
+	allowancesCmd.PersistentFlags().BoolVar(&allowancesFlags.testnet, "testnet", false, "Include testnets")
+	if err := allowancesCmd.MarkPersistentFlagRequired("network"); err != nil {
+		panic(err)
+	}
+	allowancesCmd.Flags().BoolVarP(&allowancesFlags.all, "all", "a", false, "Include zero allowances")
+	allowancesCmd.Flags().BoolVar(&allowancesFlags.json, "json", false, "Print the allowances as JSON")
+	allowancesSetCmd.Flags().StringVar(&allowancesFlags.amount, "amount", "", `Amount in token units or "unlimited"`)
+	allowancesPolicyCmd.Flags().BoolVar(&allowancesFlags.unlimited, "unlimited", true, "Approve the maximum amount instead of the amount of each trade")
+
+	allowancesCmd.AddCommand(
+		allowancesSetCmd,
+		allowancesRevokeCmd,
+		allowancesPolicyCmd,
+	)
+}
+
+func allowancesPreRun(cmd *cobra.Command, args []string) error {
+	if err := log.SetFile(); err != nil {
+		return err
+	}
+	if err := database.InitDB(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// allowanceJSON is the json representation of an allowance.
+type allowanceJSON struct {
+	Token     string `json:"token"`
+	Contract  string `json:"contract"`
+	Dex       string `json:"dex"`
+	Router    string `json:"router"`
+	Amount    string `json:"amount"`
+	Unlimited bool   `json:"unlimited"`
+	Policy    string `json:"policy"`
+}
+
+func listAllowances() error {
+	c, network, err := allowancesNetwork()
+	if err != nil {
+		return err
+	}
+	w, err := resolveWallet(c, allowancesFlags.wallet)
+	if err != nil {
+		return err
+	}
+	client, err := chain.Connect(network)
+	if err != nil {
+		return err
+	}
+	allowances, err := fetchAllowances(client, network, w.GetWallet())
+	if err != nil {
+		return err
+	}
+	if !allowancesFlags.all {
+		allowances = nonZeroAllowances(allowances)
+	}
+
+	if allowancesFlags.json {
+		res := make([]allowanceJSON, len(allowances))
+		for i, a := range allowances {
+			res[i] = allowanceJSON{
+				Token:     a.Token.GetSymbol(),
+				Contract:  a.Token.GetContract(),
+				Dex:       a.Dex.GetName(),
+				Router:    a.Dex.GetRouter(),
+				Amount:    a.Amount.String(),
+				Unlimited: a.IsUnlimited(),
+				Policy:    approvalPolicy(a.Token),
+			}
+		}
+		return json.NewEncoder(os.Stdout).Encode(res)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Wallet: %s\n\n", w.GetWallet())
+	fmt.Fprintln(tw, "TOKEN\tDEX\tALLOWANCE\tPOLICY")
+	for _, a := range allowances {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", a.Token.GetSymbol(), a.Dex.GetName(), a.AmountString(), approvalPolicy(a.Token))
+	}
+	return tw.Flush()
+}
+
+// setAllowances sets the allowance of all selected routers for the selected tokens.
+func setAllowances(value string) error {
+	if len(allowancesFlags.tokens) == 0 {
+		return errors.New("missing token, set one with --tokens")
+	}
+	c, network, err := allowancesNetwork()
+	if err != nil {
+		return err
+	}
+	w, err := resolveWallet(c, allowancesFlags.wallet)
+	if err != nil {
+		return err
+	}
+	if w.IsWatchOnly() {
+		return fmt.Errorf("the wallet %s isn't managed by deadshot", w.GetWallet())
+	}
+	client, err := chain.Connect(network)
+	if err != nil {
+		return err
+	}
+	allowances, err := fetchAllowances(client, network, w.GetWallet())
+	if err != nil {
+		return err
+	}
+	if err := unlockWallets(c); err != nil {
+		return err
+	}
+
+	chainID := big.NewInt(int64(network.GetChainID()))
+	for _, a := range allowances {
+		amount, err := chain.ParseAllowance(value, a.Token.GetDecimals())
+		if err != nil {
+			return err
+		}
+		if a.Amount.Cmp(amount) == 0 {
+			continue
+		}
+		tx, err := client.Approve(w, w.GetSigner(), chainID, a.Token, a.Dex.GetRouter(), amount)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s on %s: sent %s\n", a.Token.GetSymbol(), a.Dex.GetName(), tx.Hash())
+		ok, err := client.WaitForTransaction(tx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("transaction %s failed", tx.Hash())
+		}
+	}
+	return nil
+}
+
+func setApprovalPolicy(unlimited bool) error {
+	if len(allowancesFlags.tokens) == 0 {
+		return errors.New("missing token, set one with --tokens")
+	}
+	_, network, err := allowancesNetwork()
+	if err != nil {
+		return err
+	}
+	tokens, err := allowancesTokens(network)
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		if err := database.UpdateUnlimitedApprovalByContractAndNetworkID(token.GetContract(), network.GetID(), unlimited); err != nil {
+			return err
+		}
+		token.SetUnlimitedApproval(unlimited)
+		fmt.Printf("%s: %s\n", token.GetSymbol(), approvalPolicy(token))
+	}
+	return nil
+}
+
+func allowancesNetwork() (*config.Config, *database.Network, error) {
+	c, err := config.Get(allowancesFlags.testnet)
+	if err != nil {
+		return nil, nil, err
+	}
+	network := c.Networks.GetNetworkByName(allowancesFlags.network)
+	if network == nil {
+		return nil, nil, fmt.Errorf("%w: %s", database.ErrNetworkNotFound, allowancesFlags.network)
+	}
+	return c, network, nil
+}
+
+func fetchAllowances(client *chain.Client, network *database.Network, owner string) ([]*chain.Allowance, error) {
+	tokens, err := allowancesTokens(network)
+	if err != nil {
+		return nil, err
+	}
+	dexes, err := allowancesDexes(network)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetAllowances(owner, tokens, dexes)
+}
+
+// allowancesTokens returns the configured tokens of the network by contract or symbol.
+func allowancesTokens(network *database.Network) ([]*database.Token, error) {
+	if len(allowancesFlags.tokens) == 0 {
+		return network.GetTokens(), nil
+	}
+	tokens := make([]*database.Token, 0, len(allowancesFlags.tokens))
+	for _, name := range allowancesFlags.tokens {
+		name = strings.TrimSpace(name)
+		isContract := ethutils.IsValidAddress(name)
+		var found *database.Token
+		for _, token := range network.GetTokens() {
+			if isContract && strings.EqualFold(token.GetContract(), name) || !isContract && strings.EqualFold(token.GetSymbol(), name) {
+				found = token
+				break
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("%w: %s on network %s", database.ErrTokenNotFound, name, network.GetName())
+		}
+		if found.GetNative() {
+			return nil, fmt.Errorf("%s is the native currency and doesn't need an approval", found.GetSymbol())
+		}
+		tokens = append(tokens, found)
+	}
+	return tokens, nil
+}
+
+func allowancesDexes(network *database.Network) ([]*database.Dex, error) {
+	if len(allowancesFlags.dexes) == 0 {
+		return network.GetDexes(), nil
+	}
+	dexes := make([]*database.Dex, 0, len(allowancesFlags.dexes))
+	for _, name := range allowancesFlags.dexes {
+		name = strings.TrimSpace(name)
+		var found *database.Dex
+		for _, dex := range network.GetDexes() {
+			if strings.EqualFold(dex.GetName(), name) {
+				found = dex
+				break
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("unknown dex %s on network %s", name, network.GetName())
+		}
+		dexes = append(dexes, found)
+	}
+	return dexes, nil
+}
+
+func nonZeroAllowances(allowances []*chain.Allowance) []*chain.Allowance {
+	res := make([]*chain.Allowance, 0, len(allowances))
+	for _, a := range allowances {
+		if a.Amount.Sign() > 0 {
+			res = append(res, a)
+		}
+	}
+	return res
+}
+
+func approvalPolicy(token *database.Token) string {
+	if token.GetUnlimitedApproval() {
+		return "unlimited"
+	}
+	return "per trade"
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	ctx "context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jon4hz/deadshot/internal/config"
+	"github.com/jon4hz/deadshot/internal/database"
+	log "github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/internal/portfolio"
+
+	"github.com/spf13/cobra"
+)
+
+// decimal places of the amounts in the balances table
+const balancesPlaces = 6
+
+var balancesFlags struct {
+	wallet   string
+	quote    string
+	networks []string
+	json     bool
+	testnet  bool
+}
+
+var balancesCmd = &cobra.Command{
+	Use:   "balances",
+	Short: "Show the balances of a wallet across all networks",
+	Long: `Fetch the balances of all known tokens on every configured network and value them in a stable token.
+Tokens with a zero balance are hidden. The main wallet is used unless another wallet is given by address or label.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := log.SetFile(); err != nil {
+			return err
+		}
+		if err := database.InitDB(); err != nil {
+			return err
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return balances()
+	},
+}
+
+func init() {
+	balancesCmd.Flags().StringVarP(&balancesFlags.wallet, "wallet", "w", "", "Address or label of the wallet (default: main wallet)")
+	balancesCmd.Flags().StringVarP(&balancesFlags.quote, "quote", "q", portfolio.DefaultQuote, "Symbol of the stable token to value the holdings in")
+	balancesCmd.Flags().StringSliceVarP(&balancesFlags.networks, "network", "n", nil, "Names of the networks (default: all networks)")
+	balancesCmd.Flags().BoolVar(&balancesFlags.json, "json", false, "Print the portfolio as JSON")
+	balancesCmd.Flags().BoolVar(&balancesFlags.testnet, "testnet", false, "Include testnets")
+}
+
+func balances() error {
+	c, err := config.Get(balancesFlags.testnet)
+	if err != nil {
+		return err
+	}
+	w, err := resolveWallet(c, balancesFlags.wallet)
+	if err != nil {
+		return err
+	}
+	networks := c.Networks
+	if len(balancesFlags.networks) > 0 {
+		networks = make(database.Networks, 0, len(balancesFlags.networks))
+		for _, name := range balancesFlags.networks {
+			network := c.Networks.GetNetworkByName(strings.TrimSpace(name))
+			if network == nil {
+				return fmt.Errorf("%w: %s", database.ErrNetworkNotFound, name)
+			}
+			networks = append(networks, network)
+		}
+	}
+
+	sigCtx, cancel := signal.NotifyContext(ctx.Background(), os.Interrupt)
+	defer cancel()
+	p := portfolio.Load(sigCtx, networks, w.GetWallet(), balancesFlags.quote)
+
+	if balancesFlags.json {
+		return json.NewEncoder(os.Stdout).Encode(p)
+	}
+	return printPortfolio(p)
+}
+
+func printPortfolio(p *portfolio.Portfolio) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Wallet: %s\n\n", p.Wallet)
+	fmt.Fprintf(w, "NETWORK\tTOKEN\tBALANCE\tVALUE (%s)\n", p.Quote)
+	for _, n := range p.Networks {
+		if n.Error != "" {
+			fmt.Fprintf(w, "%s\t\t\terror: %s\n", n.Network, n.Error)
+			continue
+		}
+		for _, h := range n.Holdings {
+			value := "-"
+			if h.Valued {
+				value = h.Value.StringFixed(2)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", n.Network, h.Symbol, h.Balance.StringFixed(balancesPlaces), value)
+		}
+		fmt.Fprintf(w, "%s\tTotal\t\t%s\n", n.Network, n.Total.StringFixed(2))
+	}
+	fmt.Fprintf(w, "\nTotal\t\t\t%s\n", p.Total.StringFixed(2))
+	return w.Flush()
+}
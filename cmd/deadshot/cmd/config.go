@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jon4hz/deadshot/internal/database"
+
+	"github.com/spf13/cobra"
+)
+
+var configFlags struct {
+	output   string
+	strategy string
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Export and import the config",
+	Long:  `Export the networks, dexes, endpoints and tokens to a yaml file or import them from one. The file uses the same schema as the built-in data.`,
+}
+
+var configExportCmd = &cobra.Command{
+	Use:     "export",
+	Short:   "Export the config as yaml",
+	Long:    `Export all networks with their endpoints, dexes and tokens, including the custom endpoints and the approval policies of the tokens.`,
+	Args:    cobra.NoArgs,
+	PreRunE: customPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if configFlags.output == "" || configFlags.output == "-" {
+			return database.ExportConfig(os.Stdout)
+		}
+		f, err := os.OpenFile(configFlags.output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+		if err != nil {
+			return err
+		}
+		if err := database.ExportConfig(f); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	},
+}
+
+var configImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import a yaml config",
+	Long: `Import networks, dexes, endpoints and tokens from a yaml file. Use - to read from stdin.
+The merge strategy adds and updates the imported entries. The replace strategy also removes the user-defined entries which aren't in the file.
+Predefined entries are never changed, except for the custom endpoint and the approval policies of the tokens.`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: customPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var r io.Reader = os.Stdin
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		if err := database.ImportConfig(r, database.ImportStrategy(configFlags.strategy)); err != nil {
+			return err
+		}
+		fmt.Printf("imported %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	configExportCmd.Flags().StringVarP(&configFlags.output, "output", "o", "", "Write the config to the file instead of stdout")
+	configImportCmd.Flags().StringVarP(&configFlags.strategy, "strategy", "s", string(database.ImportMerge), "How to combine the file with the existing config, merge or replace")
+
+	configCmd.AddCommand(
+		configExportCmd,
+		configImportCmd,
+	)
+}
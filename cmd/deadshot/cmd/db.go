@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jon4hz/deadshot/internal/config"
+	"github.com/jon4hz/deadshot/internal/database"
+	log "github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/internal/wallet"
+
+	"github.com/spf13/cobra"
+)
+
+var dbFlags struct {
+	encrypt bool
+}
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Back up, restore and check the database",
+	Long:  `Back up, restore and check the sqlite database which holds the config and the trade history.`,
+}
+
+var dbBackupCmd = &cobra.Command{
+	Use:   "backup [file]",
+	Short: "Back up the database",
+	Long: `Write a consistent copy of the database, also while deadshot is running. The default file is deadshot-<time>.db in the current directory.
+With --encrypt the backup is encrypted with the password of the file keystore, other keystores ask for a new password.`,
+	Args:    cobra.MaximumNArgs(1),
+	PreRunE: customPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := fmt.Sprintf("deadshot-%s.db", time.Now().Format("20060102-150405"))
+		if len(args) > 0 {
+			file = args[0]
+		}
+		return backupDB(file, dbFlags.encrypt)
+	},
+}
+
+var dbRestoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Restore the database from a backup",
+	Long: `Replace the database with a backup. Stop deadshot before restoring.
+The backup must pass the integrity check. The replaced database is kept next to it with the suffix .before-restore.`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return log.SetFile()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return restoreDB(args[0])
+	},
+}
+
+var dbCheckCmd = &cobra.Command{
+	Use:   "check [file]",
+	Short: "Check the database for corruption and inconsistent data",
+	Long: `Run the integrity check of sqlite and look for inconsistent data, like targets without trades, tokens without networks and duplicate tokens.
+Without a file the database of deadshot is checked, the file can also be a backup.`,
+	Args: cobra.MaximumNArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return log.SetFile()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var file string
+		if len(args) > 0 {
+			file = args[0]
+		}
+		return checkDB(file)
+	},
+}
+
+func init() {
+	dbBackupCmd.Flags().BoolVarP(&dbFlags.encrypt, "encrypt", "e", false, "Encrypt the backup with the keystore password")
+
+	dbCmd.AddCommand(
+		dbBackupCmd,
+		dbRestoreCmd,
+		dbCheckCmd,
+	)
+}
+
+func backupDB(file string, encrypt bool) error {
+	if _, err := os.Stat(file); err == nil {
+		return fmt.Errorf("%s already exists", file)
+	}
+	if !encrypt {
+		if err := database.Backup(file); err != nil {
+			return err
+		}
+		if err := os.Chmod(file, 0o600); err != nil {
+			return err
+		}
+		fmt.Printf("backed up the database to %s\n", file)
+		return nil
+	}
+
+	password, err := backupPassword(true)
+	if err != nil {
+		return err
+	}
+	dir, err := os.MkdirTemp("", "deadshot-backup")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	plain := filepath.Join(dir, "backup.db")
+	if err := database.Backup(plain); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(plain)
+	if err != nil {
+		return err
+	}
+	encrypted, err := wallet.EncryptBackup(data, password)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, encrypted, 0o600); err != nil {
+		return err
+	}
+	fmt.Printf("backed up the encrypted database to %s\n", file)
+	return nil
+}
+
+func restoreDB(file string) error {
+	backup, cleanup, err := openBackup(file)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	previous, err := database.Restore(backup)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("restored the database from %s\n", file)
+	if previous != "" {
+		fmt.Printf("the previous database was moved to %s\n", previous)
+	}
+	return nil
+}
+
+func checkDB(file string) error {
+	if file == "" {
+		file = database.File()
+	}
+	var (
+		issues []database.Issue
+		err    error
+	)
+	if file != "" {
+		backup, cleanup, err := openBackup(file)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		issues, err = database.CheckFile(backup)
+		if err != nil {
+			return err
+		}
+	} else {
+		if err := database.InitDB(); err != nil {
+			return err
+		}
+		if issues, err = database.Check(); err != nil {
+			return err
+		}
+	}
+	if len(issues) == 0 {
+		fmt.Println("no problems found")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tPROBLEM")
+	for _, issue := range issues {
+		fmt.Fprintf(w, "%s\t%s\n", issue.Check, issue.Detail)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return fmt.Errorf("found %d problems", len(issues))
+}
+
+// openBackup returns the path of the sqlite file of the backup.
+// An encrypted backup is decrypted to a temporary file, which is removed by cleanup.
+func openBackup(file string) (string, func(), error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", nil, err
+	}
+	if !wallet.IsEncryptedBackup(data) {
+		return file, func() {}, nil
+	}
+	password, err := backupPassword(false)
+	if err != nil {
+		return "", nil, err
+	}
+	data, err = wallet.DecryptBackup(data, password)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to decrypt the backup: %w", err)
+	}
+	dir, err := os.MkdirTemp("", "deadshot-backup")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	plain := filepath.Join(dir, "backup.db")
+	if err := os.WriteFile(plain, data, 0o600); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return plain, cleanup, nil
+}
+
+// backupPassword returns the password of an encrypted backup.
+// The password of the file keystore is used if there is one, otherwise the backup has its own password.
+// If confirm is set, the keystore password is checked or a new password must be entered twice.
+func backupPassword(confirm bool) (string, error) {
+	password := config.GetCfg().Password
+	if err := wallet.InitKeystore(password, ""); err != nil {
+		return "", err
+	}
+	if wallet.RequirePassword() {
+		if password == "" {
+			var err error
+			if password, err = readPassword("Keystore password: "); err != nil {
+				return "", err
+			}
+		}
+		if confirm {
+			if err := wallet.CheckPassword(password); err != nil {
+				return "", fmt.Errorf("wrong keystore password: %w", err)
+			}
+		}
+		return password, nil
+	}
+	if password != "" {
+		return password, nil
+	}
+	password, err := readPassword("Backup password: ")
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", wallet.ErrEmptyPassword
+	}
+	if confirm {
+		repeated, err := readPassword("Repeat the password: ")
+		if err != nil {
+			return "", err
+		}
+		if repeated != password {
+			return "", errors.New("the passwords don't match")
+		}
+	}
+	return password, nil
+}
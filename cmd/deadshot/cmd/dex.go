@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jon4hz/deadshot/internal/custom"
+	"github.com/jon4hz/deadshot/internal/database"
+
+	"github.com/spf13/cobra"
+)
+
+var dexFlags struct {
+	network      string
+	router       string
+	factory      string
+	fee          int64
+	initCodeHash string
+	protocol     string
+}
+
+var dexCmd = &cobra.Command{
+	Use:     "dex",
+	Short:   "List and manage the dexes of a network",
+	Long:    `List the dexes of a network or add, edit and remove user-defined uniswap v2 forks. Predefined dexes can't be changed.`,
+	PreRunE: customPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := findNetwork(dexFlags.network)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tROUTER\tFACTORY\tFEE\tPROTOCOL\tTYPE")
+		for _, d := range n.GetDexes() {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", d.GetName(), d.GetRouter(), d.GetFactory(), d.GetFee(), d.GetProtocol(), entryType(d.GetPredefined()))
+		}
+		return w.Flush()
+	},
+}
+
+var dexAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a dex to a network",
+	Long: `Add a uniswap v2 fork to a network. The router must be deployed and use the factory and the weth of the network.
+The fee is the part of the amount which is left after a swap in basis points, e.g. 9970 for a fee of 0.3 %.`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: customPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := findNetwork(dexFlags.network)
+		if err != nil {
+			return err
+		}
+		d := database.NewDex(args[0], dexFlags.router, dexFlags.factory, dexFlags.fee, false)
+		d.SetInitCodeHash(dexFlags.initCodeHash)
+		d.SetProtocol(dexFlags.protocol)
+		if err := custom.AddDex(n, d); err != nil {
+			return err
+		}
+		fmt.Printf("added dex %s to %s\n", d.GetName(), n.GetName())
+		return nil
+	},
+}
+
+var dexEditCmd = &cobra.Command{
+	Use:     "edit <name>",
+	Short:   "Edit a user-defined dex",
+	Args:    cobra.ExactArgs(1),
+	PreRunE: customPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, d, err := findDex(dexFlags.network, args[0])
+		if err != nil {
+			return err
+		}
+		flags := cmd.Flags()
+		if flags.Changed("router") {
+			d.Router = dexFlags.router
+		}
+		if flags.Changed("factory") {
+			d.Factory = dexFlags.factory
+		}
+		if flags.Changed("fee") {
+			d.Fee = dexFlags.fee
+		}
+		if flags.Changed("init-code-hash") {
+			d.SetInitCodeHash(dexFlags.initCodeHash)
+		}
+		if flags.Changed("protocol") {
+			d.SetProtocol(dexFlags.protocol)
+		}
+		if err := custom.EditDex(n, d); err != nil {
+			return err
+		}
+		fmt.Printf("updated dex %s\n", d.GetName())
+		return nil
+	},
+}
+
+var dexRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Short:   "Remove a user-defined dex",
+	Args:    cobra.ExactArgs(1),
+	PreRunE: customPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, d, err := findDex(dexFlags.network, args[0])
+		if err != nil {
+			return err
+		}
+		if err := database.DeleteDex(n, d); err != nil {
+			return err
+		}
+		fmt.Printf("removed dex %s\n", d.GetName())
+		return nil
+	},
+}
+
+func init() {
+	dexCmd.PersistentFlags().StringVarP(&dexFlags.network, "network", "n", "", "Name of the network")
+	if err := dexCmd.MarkPersistentFlagRequired("network"); err != nil {
+		panic(err)
+	}
+	for _, cmd := range []*cobra.Command{dexAddCmd, dexEditCmd} {
+		cmd.Flags().StringVar(&dexFlags.router, "router", "", "Contract of the router")
+		cmd.Flags().StringVar(&dexFlags.factory, "factory", "", "Contract of the factory")
+		cmd.Flags().Int64Var(&dexFlags.fee, "fee", custom.DefaultDexFee, "Part of the amount left after a swap in basis points")
+		cmd.Flags().StringVar(&dexFlags.initCodeHash, "init-code-hash", "", "Hash of the pair creation code (default: look up the pairs at the factory)")
+		cmd.Flags().StringVar(&dexFlags.protocol, "protocol", database.ProtocolUniswapV2, "Protocol of the dex, uniswapv2 or solidly")
+	}
+	for _, flag := range []string{"router", "factory"} {
+		if err := dexAddCmd.MarkFlagRequired(flag); err != nil {
+			panic(err)
+		}
+	}
+
+	dexCmd.AddCommand(
+		dexAddCmd,
+		dexEditCmd,
+		dexRemoveCmd,
+	)
+}
+
+func findDex(network, name string) (*database.Network, *database.Dex, error) {
+	n, err := findNetwork(network)
+	if err != nil {
+		return nil, nil, err
+	}
+	d := n.GetDexByName(name)
+	if d == nil {
+		return nil, nil, fmt.Errorf("%w: %s on network %s", database.ErrDexNotFound, name, network)
+	}
+	return n, d, nil
+}
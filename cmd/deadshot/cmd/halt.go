@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jon4hz/deadshot/internal/database"
+	log "github.com/jon4hz/deadshot/internal/logging"
+
+	chain "github.com/jon4hz/deadshot/internal/blockchain"
+
+	"github.com/spf13/cobra"
+)
+
+var haltFlags struct {
+	reason string
+	sell   bool
+	status bool
+}
+
+var haltCmd = &cobra.Command{
+	Use:   "halt",
+	Short: "Halt all trading",
+	Long: `Stop all running orders and refuse to start new trades until trading is resumed.
+A running deadshot instance notices the halt within a few seconds. With --sell the open positions of the running orders are sold at the market price.
+The sells are checked against the guards, except for the maximum daily amount.`,
+	PreRunE: haltPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if haltFlags.status {
+			return haltStatus()
+		}
+		if err := chain.Halt(haltFlags.reason, haltFlags.sell); err != nil {
+			return err
+		}
+		fmt.Println("trading halted")
+		return nil
+	},
+}
+
+var resumeCmd = &cobra.Command{
+	Use:     "resume",
+	Short:   "Resume trading after a halt",
+	PreRunE: haltPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := chain.Resume(); err != nil {
+			return err
+		}
+		fmt.Println("trading resumed")
+		return nil
+	},
+}
+
+func init() {
+	haltCmd.Flags().StringVarP(&haltFlags.reason, "reason", "r", "halted from the cli", "Reason of the halt")
+	haltCmd.Flags().BoolVar(&haltFlags.sell, "sell", false, "Sell the open positions of the running orders")
+	haltCmd.Flags().BoolVar(&haltFlags.status, "status", false, "Print whether trading is halted")
+}
+
+func haltPreRun(cmd *cobra.Command, args []string) error {
+	if err := log.SetFile(); err != nil {
+		return err
+	}
+	if err := database.InitDB(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func haltStatus() error {
+	h, err := database.FetchHalt()
+	if err != nil {
+		return err
+	}
+	if !h.GetHalted() {
+		fmt.Println("trading is running")
+		return nil
+	}
+	fmt.Printf("trading is halted: %s\n", h.GetReason())
+	return nil
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jon4hz/deadshot/internal/custom"
+	"github.com/jon4hz/deadshot/internal/database"
+	log "github.com/jon4hz/deadshot/internal/logging"
+
+	"github.com/spf13/cobra"
+)
+
+var networkFlags struct {
+	fullName       string
+	chainID        uint32
+	nativeCurrency string
+	weth           string
+	multicall      string
+	endpoints      []string
+	gasLimit       uint64
+	eip1559        bool
+	isTestnet      bool
+}
+
+var networkCmd = &cobra.Command{
+	Use:     "network",
+	Short:   "List and manage the networks",
+	Long:    `List all networks or add, edit and remove user-defined EVM networks. Predefined networks can't be changed.`,
+	PreRunE: customPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listNetworks()
+	},
+}
+
+var networkAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a network",
+	Long: `Add a new EVM network. Every endpoint must serve the chain id and weth and multicall must be deployed.
+The native currency and weth are added as tokens, add a dex before trading on the network.`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: customPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n := &database.Network{
+			Name:           args[0],
+			FullName:       networkFlags.fullName,
+			ChainID:        networkFlags.chainID,
+			NativeCurrency: networkFlags.nativeCurrency,
+			WETH:           networkFlags.weth,
+			Multicall:      networkFlags.multicall,
+			GasLimit:       networkFlags.gasLimit,
+			EIP1559Enabled: networkFlags.eip1559,
+			IsTestnet:      networkFlags.isTestnet,
+		}
+		if n.FullName == "" {
+			n.FullName = n.Name
+		}
+		for _, url := range networkFlags.endpoints {
+			n.Endpoints = append(n.Endpoints, database.NewEndpoint(url, false))
+		}
+		if err := custom.AddNetwork(n); err != nil {
+			return err
+		}
+		fmt.Printf("added network %s\n", n.Name)
+		return nil
+	},
+}
+
+var networkEditCmd = &cobra.Command{
+	Use:     "edit <name>",
+	Short:   "Edit a user-defined network",
+	Args:    cobra.ExactArgs(1),
+	PreRunE: customPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := findNetwork(args[0])
+		if err != nil {
+			return err
+		}
+		flags := cmd.Flags()
+		if flags.Changed("full-name") {
+			n.FullName = networkFlags.fullName
+		}
+		if flags.Changed("chain-id") {
+			n.ChainID = networkFlags.chainID
+		}
+		if flags.Changed("native-currency") {
+			n.NativeCurrency = networkFlags.nativeCurrency
+		}
+		if flags.Changed("weth") {
+			n.WETH = networkFlags.weth
+		}
+		if flags.Changed("multicall") {
+			n.Multicall = networkFlags.multicall
+		}
+		if flags.Changed("gas-limit") {
+			n.GasLimit = networkFlags.gasLimit
+		}
+		if flags.Changed("eip1559") {
+			n.EIP1559Enabled = networkFlags.eip1559
+		}
+		if flags.Changed("is-testnet") {
+			n.IsTestnet = networkFlags.isTestnet
+		}
+		if flags.Changed("endpoints") {
+			endpoints := make(database.Endpoints, 0, len(networkFlags.endpoints)+1)
+			if e, ok := n.GetCustomEndpoint(); ok {
+				endpoints = append(endpoints, e)
+			}
+			for _, url := range networkFlags.endpoints {
+				endpoints = append(endpoints, database.NewEndpoint(url, false))
+			}
+			n.Endpoints = endpoints
+		}
+		if err := custom.EditNetwork(n); err != nil {
+			return err
+		}
+		fmt.Printf("updated network %s\n", n.Name)
+		return nil
+	},
+}
+
+var networkRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Short:   "Remove a user-defined network with its dexes and tokens",
+	Args:    cobra.ExactArgs(1),
+	PreRunE: customPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := findNetwork(args[0])
+		if err != nil {
+			return err
+		}
+		if err := database.DeleteNetwork(n); err != nil {
+			return err
+		}
+		fmt.Printf("removed network %s\n", n.GetName())
+		return nil
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{networkAddCmd, networkEditCmd} {
+		cmd.Flags().StringVar(&networkFlags.fullName, "full-name", "", "Full name of the network (default: name)")
+		cmd.Flags().Uint32Var(&networkFlags.chainID, "chain-id", 0, "Chain id of the network")
+		cmd.Flags().StringVar(&networkFlags.nativeCurrency, "native-currency", "", "Symbol of the native currency")
+		cmd.Flags().StringVar(&networkFlags.weth, "weth", "", "Contract of the wrapped native currency")
+		cmd.Flags().StringVar(&networkFlags.multicall, "multicall", "", "Contract of the multicall")
+		cmd.Flags().StringSliceVarP(&networkFlags.endpoints, "endpoints", "e", nil, "URLs of the rpc endpoints")
+		cmd.Flags().Uint64Var(&networkFlags.gasLimit, "gas-limit", custom.DefaultGasLimit, "Gas limit of the transactions")
+		cmd.Flags().BoolVar(&networkFlags.eip1559, "eip1559", false, "Send EIP-1559 transactions")
+		cmd.Flags().BoolVar(&networkFlags.isTestnet, "is-testnet", false, "Mark the network as testnet")
+	}
+	for _, flag := range []string{"chain-id", "native-currency", "weth", "multicall", "endpoints"} {
+		if err := networkAddCmd.MarkFlagRequired(flag); err != nil {
+			panic(err)
+		}
+	}
+
+	networkCmd.AddCommand(
+		networkAddCmd,
+		networkEditCmd,
+		networkRemoveCmd,
+	)
+}
+
+func customPreRun(cmd *cobra.Command, args []string) error {
+	if err := log.SetFile(); err != nil {
+		return err
+	}
+	if err := database.InitDB(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// findNetwork returns the network by name, including testnets and networks without dexes.
+func findNetwork(name string) (*database.Network, error) {
+	networks, err := database.FetchAllNetworks(true)
+	if err != nil {
+		return nil, err
+	}
+	n := database.Networks(networks).GetNetworkByName(name)
+	if n == nil {
+		return nil, fmt.Errorf("%w: %s", database.ErrNetworkNotFound, name)
+	}
+	return n, nil
+}
+
+func listNetworks() error {
+	networks, err := database.FetchAllNetworks(true)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCHAIN ID\tCURRENCY\tDEXES\tTOKENS\tTESTNET\tTYPE")
+	for _, n := range networks {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%d\t%t\t%s\n", n.GetName(), n.GetChainID(), n.GetNativeCurrency(), len(n.GetDexes()), len(n.GetTokens()), n.Testnet(), entryType(n.GetPredefined()))
+	}
+	return w.Flush()
+}
+
+func entryType(predefined bool) string {
+	if predefined {
+		return "predefined"
+	}
+	return "custom"
+}
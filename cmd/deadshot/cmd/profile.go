@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jon4hz/deadshot/internal/database"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var profileFlags struct {
+	network           string
+	slippage          float64
+	gasStrategy       string
+	gasPrice          float64
+	gasBoost          float64
+	gasLimit          uint64
+	deadline          time.Duration
+	maxHops           int
+	priceFeedInterval time.Duration
+	maxSlippage       float64
+	maxPriceImpact    float64
+	maxTradeAmount    float64
+}
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "List and manage the trading profiles of a network",
+	Long: `List the trading profiles of a network or add, edit and remove user-defined profiles.
+A profile sets the default slippage, gas strategy, deadline, max hops, price feed interval and safety limits of a trade.
+The built-in profiles careful and sniper are available on every network, a user-defined profile with the same name replaces them.`,
+	PreRunE: customPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := findNetwork(profileFlags.network)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tSLIPPAGE\tGAS\tDEADLINE\tMAX HOPS\tINTERVAL\tLIMITS\tTYPE")
+		for _, p := range n.GetProfiles() {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				p.GetName(), percent(p.Slippage), gasView(p), durationView(p.GetDeadline()), intView(p.MaxHops),
+				durationView(p.GetPriceFeedInterval()), limitsView(p), entryType(p.GetPredefined()))
+		}
+		return w.Flush()
+	},
+}
+
+var profileAddCmd = &cobra.Command{
+	Use:     "add <name>",
+	Short:   "Add a trading profile to a network",
+	Long:    `Add a trading profile to a network. Settings which are not set keep their default. The values of a target override the profile.`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: customPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := findNetwork(profileFlags.network)
+		if err != nil {
+			return err
+		}
+		p := &database.Profile{Name: args[0]}
+		setProfileFlags(cmd.Flags(), p)
+		if err := database.CreateProfile(n, p); err != nil {
+			return err
+		}
+		fmt.Printf("added profile %s to %s\n", p.GetName(), n.GetName())
+		return nil
+	},
+}
+
+var profileEditCmd = &cobra.Command{
+	Use:     "edit <name>",
+	Short:   "Edit a user-defined trading profile",
+	Args:    cobra.ExactArgs(1),
+	PreRunE: customPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, p, err := findProfile(profileFlags.network, args[0])
+		if err != nil {
+			return err
+		}
+		setProfileFlags(cmd.Flags(), p)
+		if err := database.UpdateProfile(p); err != nil {
+			return err
+		}
+		fmt.Printf("updated profile %s\n", p.GetName())
+		return nil
+	},
+}
+
+var profileRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Short:   "Remove a user-defined trading profile",
+	Args:    cobra.ExactArgs(1),
+	PreRunE: customPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, p, err := findProfile(profileFlags.network, args[0])
+		if err != nil {
+			return err
+		}
+		if err := database.DeleteProfile(n, p); err != nil {
+			return err
+		}
+		fmt.Printf("removed profile %s\n", p.GetName())
+		return nil
+	},
+}
+
+func init() {
+	profileCmd.PersistentFlags().StringVarP(&profileFlags.network, "network", "n", "", "Name of the network")
+	if err := profileCmd.MarkPersistentFlagRequired("network"); err != nil {
+		panic(err)
+	}
+	for _, cmd := range []*cobra.Command{profileAddCmd, profileEditCmd} {
+		cmd.Flags().Float64Var(&profileFlags.slippage, "slippage", 0, "Default slippage in percent")
+		cmd.Flags().StringVar(&profileFlags.gasStrategy, "gas-strategy", string(database.GasStrategyNetwork), "Gas strategy, network, boost or fixed")
+		cmd.Flags().Float64Var(&profileFlags.gasPrice, "gas-price", 0, "Gas price in gwei of the fixed gas strategy")
+		cmd.Flags().Float64Var(&profileFlags.gasBoost, "gas-boost", 0, "Increase of the suggested gas price in percent of the boost gas strategy")
+		cmd.Flags().Uint64Var(&profileFlags.gasLimit, "gas-limit", 0, "Gas limit of the swaps")
+		cmd.Flags().DurationVar(&profileFlags.deadline, "deadline", 0, "Deadline of the swaps, e.g. 5m")
+		cmd.Flags().IntVar(&profileFlags.maxHops, "max-hops", 0, "Maximum number of hops of the routes")
+		cmd.Flags().DurationVar(&profileFlags.priceFeedInterval, "price-feed-interval", 0, "Time between two price updates, e.g. 500ms")
+		cmd.Flags().Float64Var(&profileFlags.maxSlippage, "max-slippage", 0, "Maximum slippage of a swap in percent")
+		cmd.Flags().Float64Var(&profileFlags.maxPriceImpact, "max-price-impact", 0, "Maximum price impact of a swap in percent")
+		cmd.Flags().Float64Var(&profileFlags.maxTradeAmount, "max-trade-amount", 0, "Maximum value of a swap in the native currency")
+	}
+
+	profileCmd.AddCommand(
+		profileAddCmd,
+		profileEditCmd,
+		profileRemoveCmd,
+	)
+}
+
+// setProfileFlags sets the changed flags on the profile.
+func setProfileFlags(flags *pflag.FlagSet, p *database.Profile) {
+	if flags.Changed("slippage") {
+		p.Slippage = profileFlags.slippage
+	}
+	if flags.Changed("gas-strategy") {
+		p.GasStrategy = database.GasStrategy(profileFlags.gasStrategy)
+	}
+	if flags.Changed("gas-price") {
+		p.GasPrice = profileFlags.gasPrice
+	}
+	if flags.Changed("gas-boost") {
+		p.GasBoost = profileFlags.gasBoost
+	}
+	if flags.Changed("gas-limit") {
+		p.GasLimit = profileFlags.gasLimit
+	}
+	if flags.Changed("deadline") {
+		p.Deadline = profileFlags.deadline
+	}
+	if flags.Changed("max-hops") {
+		p.MaxHops = profileFlags.maxHops
+	}
+	if flags.Changed("price-feed-interval") {
+		p.PriceFeedInterval = profileFlags.priceFeedInterval
+	}
+	if flags.Changed("max-slippage") {
+		p.MaxSlippage = profileFlags.maxSlippage
+	}
+	if flags.Changed("max-price-impact") {
+		p.MaxPriceImpact = profileFlags.maxPriceImpact
+	}
+	if flags.Changed("max-trade-amount") {
+		p.MaxTradeAmount = profileFlags.maxTradeAmount
+	}
+}
+
+func findProfile(network, name string) (*database.Network, *database.Profile, error) {
+	n, err := findNetwork(network)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := n.GetProfile(name)
+	if p == nil {
+		return nil, nil, fmt.Errorf("%w: %s on network %s", database.ErrProfileNotFound, name, network)
+	}
+	return n, p, nil
+}
+
+func gasView(p *database.Profile) string {
+	switch p.GetGasStrategy() {
+	case database.GasStrategyBoost:
+		return fmt.Sprintf("network +%g%%", p.GetGasBoost())
+	case database.GasStrategyFixed:
+		return fmt.Sprintf("%g gwei", p.GetGasPrice())
+	}
+	return string(database.GasStrategyNetwork)
+}
+
+func limitsView(p *database.Profile) string {
+	return fmt.Sprintf("slippage %s, impact %s, amount %s",
+		percent(p.GetMaxSlippage()), percent(p.GetMaxPriceImpact()), floatView(p.GetMaxTradeAmount()))
+}
+
+func percent(v float64) string {
+	if v <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%g%%", v)
+}
+
+func floatView(v float64) string {
+	if v <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%g", v)
+}
+
+func intView(v int) string {
+	if v <= 0 {
+		return "-"
+	}
+	return fmt.Sprint(v)
+}
+
+func durationView(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return d.String()
+}
@@ -62,7 +62,11 @@ func reset(secret, cfg, log, all bool) error {
 		}
 	}
 	if cfg {
-		if err := os.Remove(database.ConfigDBFile); err != nil {
+		file := database.File()
+		if file == "" {
+			return errors.New("the config is stored in postgres and can't be reset from here")
+		}
+		if err := os.Remove(file); err != nil {
 			if errors.Is(err, os.ErrNotExist) {
 				return nil
 			}
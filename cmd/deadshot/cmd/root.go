@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	ctx "context"
+	"time"
+
 	"github.com/jon4hz/deadshot/internal/config"
 	"github.com/jon4hz/deadshot/internal/context"
 	"github.com/jon4hz/deadshot/internal/database"
@@ -12,15 +15,31 @@ import (
 	"github.com/jon4hz/deadshot/internal/version"
 	"github.com/jon4hz/deadshot/internal/wallet"
 
+	chain "github.com/jon4hz/deadshot/internal/blockchain"
+
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+const (
+	// haltWatchInterval is the interval in which the halted flag is checked.
+	haltWatchInterval = time.Second * 2
+	// endpointWatchInterval is the interval in which the endpoints in the database are checked for changes.
+	endpointWatchInterval = time.Second * 5
+)
+
 var rootOpts struct {
-	testnet  bool
-	debug    bool
-	keystore string
+	testnet     bool
+	debug       bool
+	keystore    string
+	lockTimeout time.Duration
+	watch       string
+
+	maxTradeAmount float64
+	maxDailyAmount float64
+	maxSlippage    float64
+	maxPriceImpact float64
 }
 
 var rootCmd = &cobra.Command{
@@ -58,14 +77,37 @@ func init() {
 	rootCmd.Flags().BoolVar(&rootOpts.testnet, "testnet", false, "use testnet")
 	rootCmd.Flags().BoolVar(&rootOpts.debug, "debug", false, "enable debug mode")
 	rootCmd.Flags().StringVarP(&rootOpts.keystore, "keystore", "k", "auto", "Set the keystore. Available: auto, file")
+	rootCmd.Flags().DurationVar(&rootOpts.lockTimeout, "lock-timeout", 0, "Lock the wallet after this time of inactivity (default: never)")
+	rootCmd.Flags().StringVarP(&rootOpts.watch, "watch", "w", "", "Watch the address without loading a private key")
+	rootCmd.Flags().Float64Var(&rootOpts.maxTradeAmount, "max-trade-amount", 0, "Maximum value of a single swap in the native currency (default: no limit)")
+	rootCmd.Flags().Float64Var(&rootOpts.maxDailyAmount, "max-daily-amount", 0, "Maximum value of all swaps per day in the native currency (default: no limit)")
+	rootCmd.Flags().Float64Var(&rootOpts.maxSlippage, "max-slippage", 0, "Maximum slippage of a swap in percent (default: no limit)")
+	rootCmd.Flags().Float64Var(&rootOpts.maxPriceImpact, "max-price-impact", 0, "Maximum price impact of a swap in percent (default: no limit)")
 
 	viper.BindPFlag("testnet", rootCmd.Flags().Lookup("testnet"))
 	viper.BindPFlag("debug", rootCmd.Flags().Lookup("debug"))
 	viper.BindPFlag("keystore", rootCmd.Flags().Lookup("keystore"))
+	viper.BindPFlag("lock_timeout", rootCmd.Flags().Lookup("lock-timeout"))
+	viper.BindPFlag("watch", rootCmd.Flags().Lookup("watch"))
+	viper.BindPFlag("guards.max_trade_amount", rootCmd.Flags().Lookup("max-trade-amount"))
+	viper.BindPFlag("guards.max_daily_amount", rootCmd.Flags().Lookup("max-daily-amount"))
+	viper.BindPFlag("guards.max_slippage", rootCmd.Flags().Lookup("max-slippage"))
+	viper.BindPFlag("guards.max_price_impact", rootCmd.Flags().Lookup("max-price-impact"))
 
 	rootCmd.AddCommand(
+		allowancesCmd,
+		balancesCmd,
+		configCmd,
+		dbCmd,
+		dexCmd,
+		haltCmd,
+		networkCmd,
+		profileCmd,
+		resumeCmd,
 		resetCmd,
 		logCmd,
+		scanCmd,
+		tokenCmd,
 		uitestCmd,
 		versionCmd,
 	)
@@ -82,6 +124,9 @@ func root() error {
 		return err
 	}
 	ctx := context.New(c, config.GetCfg())
+	// stop the trades if another process halts trading
+	go chain.WatchHalt(ctx, haltWatchInterval)
+	watchChanges(ctx, c.Networks)
 	for _, pipe := range pipeline.NewPipeline() {
 		if err := skip.Maybe(
 			pipe,
@@ -95,3 +140,12 @@ func root() error {
 	}
 	return nil
 }
+
+// watchChanges applies changes of the config file and of the endpoints in the database to the running trades.
+func watchChanges(watchCtx ctx.Context, networks database.Networks, clients ...*chain.Client) {
+	config.Watch(func(c *config.Cfg) {
+		chain.ApplyGuards(c.GetGuards())
+		chain.ApplyRateLimits()
+	})
+	go chain.WatchEndpoints(watchCtx, endpointWatchInterval, networks, clients...)
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	ctx "context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/jon4hz/deadshot/internal/config"
+	"github.com/jon4hz/deadshot/internal/database"
+	log "github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/internal/scanner"
+	"github.com/jon4hz/deadshot/pkg/ethutils"
+
+	chain "github.com/jon4hz/deadshot/internal/blockchain"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var scanFlags struct {
+	network   string
+	tokens    []string
+	amount    float64
+	minProfit float64
+	maxHops   int
+	gas       uint64
+	interval  time.Duration
+	json      bool
+	once      bool
+	testnet   bool
+}
+
+var scanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Scan a network for arbitrage opportunities",
+	Long: `Compare the prices of the tokens across all dexes of a network and check triangular paths through the connector tokens.
+Opportunities above the minimum profit after fees and gas are shown in a live updating table or printed as JSON lines in headless mode.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := log.SetFile(); err != nil {
+			return err
+		}
+		if err := database.InitDB(); err != nil {
+			return err
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return scan()
+	},
+}
+
+func init() {
+	scanCmd.Flags().StringVarP(&scanFlags.network, "network", "n", "", "Name of the network to scan")
+	scanCmd.Flags().StringSliceVarP(&scanFlags.tokens, "tokens", "t", nil, "Contracts or symbols of the tokens to scan (default: all tokens of the network)")
+	scanCmd.Flags().Float64VarP(&scanFlags.amount, "amount", "a", 1, "Trade size in the native currency")
+	scanCmd.Flags().Float64Var(&scanFlags.minProfit, "min-profit", 0.5, "Minimum net profit in percent of the trade size")
+	scanCmd.Flags().IntVar(&scanFlags.maxHops, "max-hops", 3, "Maximum number of hops of a cross-dex trade")
+	scanCmd.Flags().Uint64Var(&scanFlags.gas, "gas", chain.DefaultArbitrageGasPerSwap, "Estimated gas of a single swap")
+	scanCmd.Flags().DurationVarP(&scanFlags.interval, "interval", "i", scanner.DefaultInterval, "Time between two scans")
+	scanCmd.Flags().BoolVar(&scanFlags.json, "json", false, "Run headless and print the results as JSON lines")
+	scanCmd.Flags().BoolVar(&scanFlags.once, "once", false, "Print the result of a single scan as JSON and exit")
+	scanCmd.Flags().BoolVar(&scanFlags.testnet, "testnet", false, "Include testnets")
+	if err := scanCmd.MarkFlagRequired("network"); err != nil {
+		panic(err)
+	}
+}
+
+func scan() error {
+	c, err := config.Get(scanFlags.testnet)
+	if err != nil {
+		return err
+	}
+	network := c.Networks.GetNetworkByName(scanFlags.network)
+	if network == nil {
+		return fmt.Errorf("%w: %s", database.ErrNetworkNotFound, scanFlags.network)
+	}
+	client, err := chain.Connect(network)
+	if err != nil {
+		return err
+	}
+	tokens, err := scanTokens(client, network, scanFlags.tokens)
+	if err != nil {
+		return err
+	}
+
+	s := scanner.New(client, network, tokens, &chain.ArbitrageOptions{
+		Amount:     ethutils.ToWei(scanFlags.amount, 18),
+		MinProfit:  decimal.NewFromFloat(scanFlags.minProfit),
+		MaxHops:    scanFlags.maxHops,
+		GasPerSwap: scanFlags.gas,
+	}, scanFlags.interval)
+
+	switch {
+	case scanFlags.once:
+		r := s.Scan()
+		if err := json.NewEncoder(os.Stdout).Encode(r); err != nil {
+			return err
+		}
+		if r.Error != "" {
+			return errors.New(r.Error)
+		}
+		return nil
+
+	case scanFlags.json:
+		sigCtx, cancel := signal.NotifyContext(ctx.Background(), os.Interrupt)
+		defer cancel()
+		watchChanges(sigCtx, database.Networks{network}, client)
+		return s.RunJSON(sigCtx, os.Stdout)
+	}
+	return tea.NewProgram(scanner.NewModel(s), tea.WithAltScreen()).Start()
+}
+
+// scanTokens resolves the tokens by contract or symbol.
+// Contracts which aren't configured for the network are looked up on-chain.
+func scanTokens(client *chain.Client, network *database.Network, names []string) ([]*database.Token, error) {
+	if len(names) == 0 {
+		return network.GetTokens(), nil
+	}
+	tokens := make([]*database.Token, 0, len(names))
+	for _, name := range names {
+		token, err := scanToken(client, network, strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+func scanToken(client *chain.Client, network *database.Network, name string) (*database.Token, error) {
+	isContract := ethutils.IsValidAddress(name)
+	for _, token := range network.GetTokens() {
+		if isContract && strings.EqualFold(token.GetContract(), name) || !isContract && strings.EqualFold(token.GetSymbol(), name) {
+			return token, nil
+		}
+	}
+	if !isContract {
+		return nil, fmt.Errorf("unknown token %s on network %s", name, network.GetName())
+	}
+	info, err := client.GetTokenInfo(name)
+	if err != nil {
+		return nil, err
+	}
+	token, ok := info[name]
+	if !ok {
+		return nil, fmt.Errorf("token %s not found", name)
+	}
+	return database.NewToken(name, token.GetSymbol(), token.GetDecimals(), false, nil), nil
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jon4hz/deadshot/internal/custom"
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/tokenlist"
+
+	"github.com/spf13/cobra"
+)
+
+var tokenFlags struct {
+	network   string
+	symbol    string
+	decimals  uint8
+	connector bool
+}
+
+var tokenCmd = &cobra.Command{
+	Use:     "token",
+	Short:   "List and manage the tokens of a network",
+	Long:    `List the tokens of a network or add, edit and remove user-defined tokens. Connector tokens are used to find routes between tokens.`,
+	PreRunE: tokenPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := findNetwork(tokenFlags.network)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SYMBOL\tCONTRACT\tDECIMALS\tCONNECTOR\tTYPE")
+		for _, t := range n.GetTokens() {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%t\t%s\n", t.GetSymbol(), t.GetContract(), t.GetDecimals(), t.IsConnector(), entryType(t.GetPredefined()))
+		}
+		return w.Flush()
+	},
+}
+
+var tokenAddCmd = &cobra.Command{
+	Use:     "add <contract>",
+	Short:   "Add a token to a network",
+	Long:    `Add a token to a network. The symbol and decimals are read from the contract unless they are set.`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: tokenPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := findNetwork(tokenFlags.network)
+		if err != nil {
+			return err
+		}
+		t := database.NewToken(args[0], tokenFlags.symbol, tokenFlags.decimals, false, nil)
+		t.SetConnector(tokenFlags.connector)
+		if err := custom.AddToken(n, t); err != nil {
+			return err
+		}
+		fmt.Printf("added token %s to %s\n", t.GetSymbol(), n.GetName())
+		return nil
+	},
+}
+
+var tokenEditCmd = &cobra.Command{
+	Use:     "edit <contract>",
+	Short:   "Edit a user-defined token",
+	Args:    cobra.ExactArgs(1),
+	PreRunE: tokenPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, t, err := findToken(tokenFlags.network, args[0])
+		if err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("symbol") {
+			t.SetSymbol(tokenFlags.symbol)
+		}
+		if cmd.Flags().Changed("decimals") {
+			t.SetDecimals(tokenFlags.decimals)
+		}
+		if cmd.Flags().Changed("connector") {
+			t.SetConnector(tokenFlags.connector)
+		}
+		if err := custom.EditToken(n, t); err != nil {
+			return err
+		}
+		fmt.Printf("updated token %s\n", t.GetSymbol())
+		return nil
+	},
+}
+
+var tokenRemoveCmd = &cobra.Command{
+	Use:     "remove <contract>",
+	Short:   "Remove a user-defined token",
+	Args:    cobra.ExactArgs(1),
+	PreRunE: tokenPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, t, err := findToken(tokenFlags.network, args[0])
+		if err != nil {
+			return err
+		}
+		if err := database.DeleteToken(n, t); err != nil {
+			return err
+		}
+		fmt.Printf("removed token %s\n", t.GetSymbol())
+		return nil
+	},
+}
+
+var tokenImportCmd = &cobra.Command{
+	Use:   "import <file or url>",
+	Short: "Import a token list",
+	Long: `Import the tokens of a token list in the uniswap token list format from a local file or url.
+Every token is added to the network with the matching chain id, tokens of unknown chains are skipped.
+Imported tokens aren't used to find routes, known tokens only get the missing name, logo and tags.`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: customPreRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l, err := tokenlist.Load(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		networks, err := database.FetchAllNetworks(true)
+		if err != nil {
+			return err
+		}
+		if tokenFlags.network != "" {
+			n := database.Networks(networks).GetNetworkByName(tokenFlags.network)
+			if n == nil {
+				return fmt.Errorf("%w: %s", database.ErrNetworkNotFound, tokenFlags.network)
+			}
+			networks = []*database.Network{n}
+		}
+		results, skipped, err := tokenlist.Import(l, networks)
+		for _, r := range results {
+			fmt.Printf("%s: added %d, updated %d tokens\n", r.Network.GetName(), r.Added, r.Updated)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("imported %s, skipped %d tokens of other chains or with invalid data\n", l.Name, skipped)
+		return nil
+	},
+}
+
+func init() {
+	tokenCmd.PersistentFlags().StringVarP(&tokenFlags.network, "network", "n", "", "Name of the network")
+	for _, cmd := range []*cobra.Command{tokenAddCmd, tokenEditCmd} {
+		cmd.Flags().StringVar(&tokenFlags.symbol, "symbol", "", "Symbol of the token (default: read from the contract)")
+		cmd.Flags().Uint8Var(&tokenFlags.decimals, "decimals", 0, "Decimals of the token (default: read from the contract)")
+		cmd.Flags().BoolVar(&tokenFlags.connector, "connector", true, "Use the token to find routes")
+	}
+
+	tokenCmd.AddCommand(
+		tokenAddCmd,
+		tokenEditCmd,
+		tokenRemoveCmd,
+		tokenImportCmd,
+	)
+}
+
+// tokenPreRun requires the network, only the token list import works across networks.
+func tokenPreRun(cmd *cobra.Command, args []string) error {
+	if tokenFlags.network == "" {
+		return errors.New(`required flag(s) "network" not set`)
+	}
+	return customPreRun(cmd, args)
+}
+
+func findToken(network, contract string) (*database.Network, *database.Token, error) {
+	n, err := findNetwork(network)
+	if err != nil {
+		return nil, nil, err
+	}
+	t := n.GetTokenByContract(contract)
+	if t == nil {
+		return nil, nil, fmt.Errorf("%w: %s on network %s", database.ErrTokenNotFound, contract, network)
+	}
+	return n, t, nil
+}
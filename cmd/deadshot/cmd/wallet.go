@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/config"
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/wallet"
+	"github.com/jon4hz/deadshot/pkg/ethutils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/term"
+)
+
+var errNoWallet = errors.New("no wallet configured, set one with --wallet")
+
+// resolveWallet returns the wallet by address or label or the main wallet if name is empty.
+// Addresses which don't belong to a configured wallet are returned as watch-only wallets.
+func resolveWallet(c *config.Config, name string) (*database.Wallet, error) {
+	if name == "" {
+		if c.Wallet == nil || c.Wallet.GetWallet() == "" {
+			return nil, errNoWallet
+		}
+		return c.Wallet, nil
+	}
+	isAddress := ethutils.IsValidAddress(name)
+	for _, w := range c.AllWallets() {
+		if w == nil {
+			continue
+		}
+		if isAddress && strings.EqualFold(w.GetWallet(), name) || !isAddress && strings.EqualFold(w.GetLabel(), name) {
+			return w, nil
+		}
+	}
+	if isAddress {
+		return database.NewWatchOnlyWallet(common.HexToAddress(name).Hex()), nil
+	}
+	return nil, fmt.Errorf("unknown wallet %s", name)
+}
+
+// unlockWallets loads the private keys of all wallets.
+// The password is taken from the config or read from the terminal if the keystore requires one.
+func unlockWallets(c *config.Config) error {
+	password := config.GetCfg().Password
+	if err := wallet.InitKeystore(password, ""); err != nil {
+		return err
+	}
+	if password == "" && wallet.RequirePassword() {
+		var err error
+		if password, err = readPassword("Password: "); err != nil {
+			return err
+		}
+	}
+	return wallet.Unlock(password, c.Wallet, c.Wallets)
+}
+
+// readPassword reads a password from the terminal without echoing it.
+func readPassword(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	b, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,3 @@
+package solidlyrouter
+
+//go:generate abigen -abi solidly_router.json -out solidlyrouter.go -pkg solidlyrouter
@@ -0,0 +1,158 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/blockchain/abi/erc20"
+	"github.com/jon4hz/deadshot/internal/blockchain/multicall"
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/internal/signer"
+	"github.com/jon4hz/deadshot/pkg/ethutils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+var ErrInvalidAllowance = errors.New("invalid allowance")
+
+// UnlimitedAllowance is the amount of an unlimited approval.
+var UnlimitedAllowance = math.MaxBig256
+
+// Some tokens decrease even the maximum allowance on every transfer,
+// so everything above half of the maximum counts as unlimited.
+var unlimitedThreshold = new(big.Int).Rsh(math.MaxBig256, 1)
+
+// Allowance is the amount the router of a dex may spend of a token.
+type Allowance struct {
+	Token  *database.Token
+	Dex    *database.Dex
+	Amount *big.Int
+}
+
+// IsUnlimited returns whether the allowance is an unlimited approval.
+func (a *Allowance) IsUnlimited() bool {
+	return a.Amount != nil && a.Amount.Cmp(unlimitedThreshold) >= 0
+}
+
+// AmountString returns the allowance in token units or "unlimited".
+func (a *Allowance) AmountString() string {
+	if a.IsUnlimited() {
+		return "unlimited"
+	}
+	return ethutils.ToDecimal(a.Amount, a.Token.GetDecimals()).String()
+}
+
+// ParseAllowance parses an amount in token units or "unlimited".
+func ParseAllowance(value string, decimals uint8) (*big.Int, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "unlimited") {
+		return UnlimitedAllowance, nil
+	}
+	amount, err := decimal.NewFromString(value)
+	if err != nil || amount.IsNegative() {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAllowance, value)
+	}
+	return ethutils.ToWei(amount.Truncate(int32(decimals)), decimals), nil
+}
+
+// GetAllowances returns the allowances of the owner for the tokens against the routers of the dexes.
+// All allowances are read in a single multicall. Native tokens are skipped and dexes sharing a router are only listed once.
+func (c *Client) GetAllowances(owner string, tokens []*database.Token, dexes []*database.Dex) ([]*Allowance, error) {
+	routers := make(map[string]bool)
+	uniqueDexes := make([]*database.Dex, 0, len(dexes))
+	for _, dex := range dexes {
+		router := strings.ToLower(dex.GetRouter())
+		if routers[router] {
+			continue
+		}
+		routers[router] = true
+		uniqueDexes = append(uniqueDexes, dex)
+	}
+
+	allowances := make([]*Allowance, 0, len(tokens)*len(uniqueDexes))
+	calls := make([]multicall.AllowanceCall, 0, len(tokens)*len(uniqueDexes))
+	for _, token := range tokens {
+		if token.GetNative() {
+			continue
+		}
+		for _, dex := range uniqueDexes {
+			allowances = append(allowances, &Allowance{Token: token, Dex: dex})
+			calls = append(calls, multicall.AllowanceCall{Token: token.GetContract(), Spender: dex.GetRouter()})
+		}
+	}
+	if len(calls) == 0 {
+		return nil, ErrNoContracts
+	}
+
+	values, err := c.getMulticall().GetAllowances(owner, calls)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*Allowance, 0, len(allowances))
+	for i, a := range allowances {
+		v, ok := values[calls[i]]
+		if !ok {
+			continue
+		}
+		a.Amount = v
+		res = append(res, a)
+	}
+	return res, nil
+}
+
+// Approve sets the allowance of the spender for the token. An amount of zero revokes the allowance.
+func (c *Client) Approve(wallet *database.Wallet, s signer.Signer, chainID *big.Int, token *database.Token, spender string, amount *big.Int) (*types.Transaction, error) {
+	owner := common.HexToAddress(wallet.GetWallet())
+	nonce, err := c.Eth().PendingNonceAt(context.Background(), owner)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error": err,
+		}).Error("failed to get nonce")
+		return nil, err
+	}
+	wallet.SetNonce(nonce)
+
+	instance, err := erc20.NewErc20(common.HexToAddress(token.GetContract()), c.Eth())
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error": err,
+		}).Error("failed to create erc20 instance")
+		return nil, err
+	}
+	return c.approve(instance, owner, common.HexToAddress(spender), common.HexToAddress(token.GetContract()), amount, chainID, wallet.GetNonce(), s)
+}
+
+// approve sends an approve transaction with the given nonce.
+func (c *Client) approve(instance *erc20.Erc20, owner, spender, token common.Address, amount, chainID *big.Int, nonce int64, s signer.Signer) (*types.Transaction, error) {
+	auth, err := signer.NewTransactor(s, chainID)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error": err,
+		}).Error("failed to create signer")
+		return nil, err
+	}
+	auth.Nonce = big.NewInt(nonce)
+	tx, err := instance.Approve(auth, spender, amount)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error":   err,
+			"owner":   owner.String(),
+			"spender": spender.String(),
+			"token":   token.String(),
+		}).Error("failed to approve")
+		return nil, err
+	}
+	logging.Log.WithFields(logrus.Fields{
+		"tx":     tx.Hash().String(),
+		"amount": amount,
+	}).Info("sent approve transaction")
+	return tx, nil
+}
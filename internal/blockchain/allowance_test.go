@@ -0,0 +1,42 @@
+package blockchain
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestParseAllowance(t *testing.T) {
+	tests := []struct {
+		value string
+		want  *big.Int
+		err   error
+	}{
+		{value: "unlimited", want: UnlimitedAllowance},
+		{value: "0", want: big.NewInt(0)},
+		{value: "1.5", want: big.NewInt(1500000)},
+		{value: "0.0000001", want: big.NewInt(0)},
+		{value: "-1", err: ErrInvalidAllowance},
+		{value: "abc", err: ErrInvalidAllowance},
+	}
+	for _, tt := range tests {
+		got, err := ParseAllowance(tt.value, 6)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("ParseAllowance(%q) error = %v, want %v", tt.value, err, tt.err)
+			continue
+		}
+		if tt.err == nil && got.Cmp(tt.want) != 0 {
+			t.Errorf("ParseAllowance(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestAllowanceIsUnlimited(t *testing.T) {
+	decreased := new(big.Int).Sub(UnlimitedAllowance, big.NewInt(1000))
+	if !(&Allowance{Amount: decreased}).IsUnlimited() {
+		t.Error("a decreased maximum allowance should be unlimited")
+	}
+	if (&Allowance{Amount: big.NewInt(1000)}).IsUnlimited() {
+		t.Error("a small allowance shouldn't be unlimited")
+	}
+}
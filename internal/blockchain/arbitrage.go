@@ -0,0 +1,313 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/pkg/ethutils"
+	"github.com/jon4hz/deadshot/pkg/uniswap"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultArbitrageGasPerSwap is the estimated gas of a single router swap.
+const DefaultArbitrageGasPerSwap = 150000
+
+// The kinds of arbitrage opportunities.
+const (
+	OpportunityCrossDex   = "cross-dex"
+	OpportunityTriangular = "triangular"
+)
+
+// triangularHops is the number of hops of a triangular round trip.
+const triangularHops = 3
+
+var ErrNotEnoughTokens = errors.New("at least two tokens are required")
+
+// ArbitrageOptions configures an arbitrage scan.
+type ArbitrageOptions struct {
+	// Amount is the trade size in the wrapped native token.
+	// The start amount of every other token is the best quote for this amount.
+	Amount *big.Int
+	// MinProfit is the minimum net profit in percent of the trade size.
+	MinProfit decimal.Decimal
+	// MaxHops is the maximum number of hops of a single cross-dex leg.
+	MaxHops int
+	// GasPerSwap is the estimated gas of every swap transaction.
+	GasPerSwap uint64
+}
+
+// Opportunity is a profitable round trip which starts and ends in the same token.
+type Opportunity struct {
+	Kind string `json:"kind"`
+	// Path contains the symbols of all tokens of the round trip.
+	Path []string `json:"path"`
+	// Dexes contains the dex of every leg.
+	Dexes     []string        `json:"dexes"`
+	Token     string          `json:"token"`
+	AmountIn  decimal.Decimal `json:"amountIn"`
+	AmountOut decimal.Decimal `json:"amountOut"`
+	// Profit, GasCost and NetProfit are denominated in the native currency.
+	Profit        decimal.Decimal `json:"profit"`
+	GasCost       decimal.Decimal `json:"gasCost"`
+	NetProfit     decimal.Decimal `json:"netProfit"`
+	ProfitPercent decimal.Decimal `json:"profitPercent"`
+}
+
+// ScanArbitrage compares the prices of the tokens across all dexes of the network
+// and checks triangular paths through the connector tokens.
+// Only opportunities above the minimum profit after fees and gas are returned, the most profitable first.
+func (c *Client) ScanArbitrage(network *database.Network, tokens []*database.Token, opts *ArbitrageOptions) ([]*Opportunity, error) {
+	weth := strings.ToLower(network.GetWETH())
+	wethToken := database.NewToken(weth, "W"+network.GetNativeCurrency(), 18, false, nil)
+	for _, token := range network.GetTokens() {
+		if strings.EqualFold(token.GetContract(), weth) {
+			wethToken = token
+		}
+	}
+	all := append([]*database.Token{wethToken}, tokens...)
+	all = append(all, network.Connectors()...)
+
+	uniTokens, contracts, err := pairTokens(all, weth)
+	if err != nil {
+		return nil, err
+	}
+	if len(contracts) < 2 {
+		return nil, ErrNotEnoughTokens
+	}
+	pairs, err := c.genUniPairsByDex(all[0], all[1], network.GetDexes(), weth, all...)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error":   err,
+			"network": network.GetName(),
+		}).Error("failed to generate uniswap pairs")
+		return nil, err
+	}
+
+	gasPrice, err := c.Eth().SuggestGasPrice(context.Background())
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error": err,
+		}).Error("failed to get gas price")
+		return nil, err
+	}
+
+	if _, ok := uniTokens[weth]; !ok {
+		return nil, ErrNotEnoughTokens
+	}
+	scan := &arbitrageScan{
+		dexes:    network.GetDexes(),
+		pairs:    pairs,
+		weth:     uniTokens[weth],
+		opts:     opts,
+		gasPrice: gasPrice,
+	}
+	seen := map[string]bool{weth: true}
+	for _, token := range tokens {
+		contract := strings.ToLower(token.GetContract())
+		if t, ok := uniTokens[contract]; ok && !seen[contract] {
+			seen[contract] = true
+			scan.tokens = append(scan.tokens, t)
+		}
+	}
+	for _, token := range network.Connectors() {
+		if t, ok := uniTokens[strings.ToLower(token.GetContract())]; ok {
+			scan.connectors = append(scan.connectors, t)
+		}
+	}
+	return scan.run(), nil
+}
+
+// arbitrageScan evaluates the round trips of a scan on a snapshot of the pair reserves.
+type arbitrageScan struct {
+	dexes      []*database.Dex
+	pairs      map[*database.Dex][]*uniswap.Pair
+	weth       *uniswap.Token
+	tokens     []*uniswap.Token
+	connectors []*uniswap.Token
+	opts       *ArbitrageOptions
+	gasPrice   *big.Int
+}
+
+func (s *arbitrageScan) run() []*Opportunity {
+	var (
+		opportunities []*Opportunity
+		starts        = append([]*uniswap.Token{s.weth}, s.tokens...)
+	)
+	for i, start := range starts {
+		amount, ok := s.startAmount(start)
+		if !ok {
+			continue
+		}
+		for _, other := range starts[i+1:] {
+			opportunities = append(opportunities, s.crossDex(start, other, amount)...)
+		}
+		opportunities = append(opportunities, s.triangular(start, amount)...)
+	}
+	sort.SliceStable(opportunities, func(i, j int) bool {
+		return opportunities[i].NetProfit.GreaterThan(opportunities[j].NetProfit)
+	})
+	return opportunities
+}
+
+// startAmount returns the amount of the token which is worth the trade size.
+func (s *arbitrageScan) startAmount(token *uniswap.Token) (*uniswap.TokenAmount, bool) {
+	amount, err := uniswap.NewTokenAmount(s.weth, s.opts.Amount)
+	if err != nil {
+		return nil, false
+	}
+	if token.Address() == s.weth.Address() {
+		return amount, true
+	}
+	_, trade := s.bestQuote(amount, token, s.opts.MaxHops)
+	if trade == nil {
+		return nil, false
+	}
+	return trade.OutputAmount(), true
+}
+
+// crossDex buys the other token on one dex and sells it on another one.
+func (s *arbitrageScan) crossDex(start, other *uniswap.Token, amount *uniswap.TokenAmount) []*Opportunity {
+	var opportunities []*Opportunity
+	for _, buy := range s.dexes {
+		buyTrade := s.quote(buy, amount, other, s.opts.MaxHops)
+		if buyTrade == nil {
+			continue
+		}
+		for _, sell := range s.dexes {
+			if sell == buy {
+				continue
+			}
+			sellTrade := s.quote(sell, buyTrade.OutputAmount(), start, s.opts.MaxHops)
+			if sellTrade == nil {
+				continue
+			}
+			if o := s.opportunity(OpportunityCrossDex, amount, sellTrade.OutputAmount(), 2,
+				[]string{start.Symbol(), other.Symbol(), start.Symbol()},
+				[]string{buy.GetName(), sell.GetName()},
+			); o != nil {
+				opportunities = append(opportunities, o)
+			}
+		}
+	}
+	return opportunities
+}
+
+// triangular swaps the start token through two connector tokens and back.
+// Every hop is routed through the dex with the best price.
+func (s *arbitrageScan) triangular(start *uniswap.Token, amount *uniswap.TokenAmount) []*Opportunity {
+	var opportunities []*Opportunity
+	for _, c1 := range s.connectors {
+		for _, c2 := range s.connectors {
+			if c1 == c2 || c1.Address() == start.Address() || c2.Address() == start.Address() {
+				continue
+			}
+			var (
+				dexes []string
+				swaps int
+				out   = amount
+				path  = []*uniswap.Token{start, c1, c2, start}
+			)
+			for hop := 0; hop < triangularHops; hop++ {
+				dex, trade := s.bestQuote(out, path[hop+1], 1)
+				if trade == nil {
+					out = nil
+					break
+				}
+				// consecutive hops on the same dex are a single swap
+				if len(dexes) == 0 || dexes[len(dexes)-1] != dex.GetName() {
+					swaps++
+				}
+				dexes = append(dexes, dex.GetName())
+				out = trade.OutputAmount()
+			}
+			if out == nil {
+				continue
+			}
+			if o := s.opportunity(OpportunityTriangular, amount, out, swaps,
+				[]string{start.Symbol(), c1.Symbol(), c2.Symbol(), start.Symbol()},
+				dexes,
+			); o != nil {
+				opportunities = append(opportunities, o)
+			}
+		}
+	}
+	return opportunities
+}
+
+// opportunity calculates the net profit of a round trip.
+// It returns nil if the net profit is below the minimum profit.
+func (s *arbitrageScan) opportunity(kind string, in, out *uniswap.TokenAmount, swaps int, path, dexes []string) *Opportunity {
+	if out.Raw().Cmp(in.Raw()) <= 0 {
+		return nil
+	}
+	// convert the profit to the native currency with the rate of the start amount
+	profit := new(big.Int).Sub(out.Raw(), in.Raw())
+	profit.Mul(profit, s.opts.Amount)
+	profit.Quo(profit, in.Raw())
+
+	gas := new(big.Int).SetUint64(s.opts.GasPerSwap * uint64(swaps))
+	gas.Mul(gas, s.gasPrice)
+
+	net := ethutils.ToDecimal(new(big.Int).Sub(profit, gas), s.weth.Decimals())
+	percent := net.Div(ethutils.ToDecimal(s.opts.Amount, s.weth.Decimals())).Mul(decimal.NewFromInt(100))
+	if percent.LessThan(s.opts.MinProfit) {
+		return nil
+	}
+	return &Opportunity{
+		Kind:          kind,
+		Path:          path,
+		Dexes:         dexes,
+		Token:         in.Token.Symbol(),
+		AmountIn:      ethutils.ToDecimal(in.Raw(), in.Token.Decimals()),
+		AmountOut:     ethutils.ToDecimal(out.Raw(), out.Token.Decimals()),
+		Profit:        ethutils.ToDecimal(profit, s.weth.Decimals()),
+		GasCost:       ethutils.ToDecimal(gas, s.weth.Decimals()),
+		NetProfit:     net,
+		ProfitPercent: percent,
+	}
+}
+
+// quote returns the best exact in trade on the dex or nil if there is none.
+func (s *arbitrageScan) quote(dex *database.Dex, in *uniswap.TokenAmount, out *uniswap.Token, maxHops int) *uniswap.Trade {
+	pairs, ok := s.pairs[dex]
+	if !ok {
+		return nil
+	}
+	trades, err := uniswap.BestTradeExactIn(
+		pairs, in, out,
+		&uniswap.BestTradeOptions{
+			MaxNumResults: 1,
+			MaxHops:       maxHops,
+			DexFee:        dex.GetFeeBigInt(),
+		}, nil, nil, nil,
+	)
+	if err != nil || len(trades) == 0 {
+		return nil
+	}
+	return trades[0]
+}
+
+// bestQuote returns the best exact in trade across all dexes.
+func (s *arbitrageScan) bestQuote(in *uniswap.TokenAmount, out *uniswap.Token, maxHops int) (*database.Dex, *uniswap.Trade) {
+	var (
+		bestDex   *database.Dex
+		bestTrade *uniswap.Trade
+	)
+	for _, dex := range s.dexes {
+		t := s.quote(dex, in, out, maxHops)
+		if t == nil {
+			continue
+		}
+		if bestTrade == nil || t.OutputAmount().Raw().Cmp(bestTrade.OutputAmount().Raw()) > 0 {
+			bestDex, bestTrade = dex, t
+		}
+	}
+	return bestDex, bestTrade
+}
@@ -0,0 +1,63 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/pkg/uniswap"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+)
+
+func TestArbitrageScan(t *testing.T) {
+	weth, _ := uniswap.NewToken(common.HexToAddress("0x0000000000000000000000000000000000000001"), "WETH", "WETH", 18)
+	token, _ := uniswap.NewToken(common.HexToAddress("0x0000000000000000000000000000000000000002"), "T", "T", 18)
+	newPair := func(addr string, reserve0, reserve1 int64) *uniswap.Pair {
+		a, _ := uniswap.NewTokenAmount(weth, new(big.Int).Mul(big.NewInt(reserve0), big.NewInt(1e18)))
+		b, _ := uniswap.NewTokenAmount(token, new(big.Int).Mul(big.NewInt(reserve1), big.NewInt(1e18)))
+		p, err := uniswap.NewPair(common.HexToAddress(addr), a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p.SetFee(big.NewInt(9970))
+	}
+
+	cheap := database.NewDex("cheap", "", "", 9970, false)
+	expensive := database.NewDex("expensive", "", "", 9970, false)
+	scan := &arbitrageScan{
+		dexes: []*database.Dex{cheap, expensive},
+		pairs: map[*database.Dex][]*uniswap.Pair{
+			cheap:     {newPair("0x0000000000000000000000000000000000000010", 1000, 2000)},
+			expensive: {newPair("0x0000000000000000000000000000000000000011", 1000, 1800)},
+		},
+		weth:   weth,
+		tokens: []*uniswap.Token{token},
+		opts: &ArbitrageOptions{
+			Amount:     big.NewInt(1e18),
+			MinProfit:  decimal.NewFromInt(1),
+			MaxHops:    3,
+			GasPerSwap: DefaultArbitrageGasPerSwap,
+		},
+		gasPrice: big.NewInt(1e9),
+	}
+
+	opportunities := scan.run()
+	if len(opportunities) != 1 {
+		t.Fatalf("expected a single opportunity, got %d", len(opportunities))
+	}
+	o := opportunities[0]
+	if o.Kind != OpportunityCrossDex || o.Dexes[0] != "cheap" || o.Dexes[1] != "expensive" {
+		t.Errorf("expected to buy on cheap and sell on expensive, got %s %v", o.Kind, o.Dexes)
+	}
+	if !o.NetProfit.Equal(o.Profit.Sub(o.GasCost)) {
+		t.Errorf("expected net profit %s, got %s", o.Profit.Sub(o.GasCost), o.NetProfit)
+	}
+
+	// the price difference doesn't cover a profit of 20%
+	scan.opts.MinProfit = decimal.NewFromInt(20)
+	if opportunities := scan.run(); len(opportunities) != 0 {
+		t.Errorf("expected no opportunities, got %d", len(opportunities))
+	}
+}
@@ -0,0 +1,374 @@
+package blockchain
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/jon4hz/deadshot/internal/blockchain/multicall"
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/pkg/ethutils"
+	"github.com/jon4hz/deadshot/pkg/uniswap"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+// The uniswap v2 router derives every pair of a path from its own factory,
+// so a single swap can't hop between dexes. Until the executor supports split
+// or multi-router swaps, routes are searched per dex and the best dex wins.
+
+// DexTrade is a trade routed through the pairs of a single dex.
+type DexTrade struct {
+	Dex   *database.Dex
+	Trade *uniswap.Trade
+}
+
+// DexQuote is the best trade across multiple dexes and the runner-up on a different dex.
+type DexQuote struct {
+	Best *DexTrade
+	// Next is nil if only one dex has a route.
+	Next *DexTrade
+}
+
+// GetDex returns the dex of the best trade or nil if the quote is empty.
+func (q *DexQuote) GetDex() *database.Dex {
+	if q == nil || q.Best == nil {
+		return nil
+	}
+	return q.Best.Dex
+}
+
+// DexOr returns the dex of the best trade or the fallback if the quote has none, e.g. the dex of the trade.
+func (q *DexQuote) DexOr(fallback *database.Dex) *database.Dex {
+	if dex := q.GetDex(); dex != nil {
+		return dex
+	}
+	return fallback
+}
+
+// SingleDexQuote wraps a trade of a single dex in a quote. It returns nil if there is no trade.
+func SingleDexQuote(dex *database.Dex, trade *uniswap.Trade) *DexQuote {
+	if trade == nil {
+		return nil
+	}
+	return &DexQuote{Best: &DexTrade{Dex: dex, Trade: trade}}
+}
+
+// ExchangeName returns the name of the dex to show to the user.
+// If all dexes are quoted, the dex is the best one so far or nil if it isn't known.
+func ExchangeName(dex *database.Dex, allDexes bool) string {
+	if !allDexes {
+		return dex.GetName()
+	}
+	if dex == nil {
+		return "All dexes"
+	}
+	return "All dexes (best: " + dex.GetName() + ")"
+}
+
+// GetTrade returns the best trade or nil if the quote is empty.
+func (q *DexQuote) GetTrade() *uniswap.Trade {
+	if q == nil || q.Best == nil {
+		return nil
+	}
+	return q.Best.Trade
+}
+
+// Improvement returns how much better the best trade is than the runner-up in percent.
+// For exact in trades the output amounts are compared, for exact out trades the input amounts.
+func (q *DexQuote) Improvement() (decimal.Decimal, bool) {
+	if q == nil || q.Best == nil || q.Next == nil {
+		return decimal.Zero, false
+	}
+	best, next := tradeAmount(q.Best.Trade), tradeAmount(q.Next.Trade)
+	if next.IsZero() {
+		return decimal.Zero, false
+	}
+	diff := best.Sub(next)
+	if q.Best.Trade.TradeType == uniswap.ExactOutput {
+		diff = next.Sub(best)
+	}
+	return diff.Div(next).Mul(decimalHundred), true
+}
+
+// tradeAmount returns the side of the trade which is not fixed.
+func tradeAmount(t *uniswap.Trade) decimal.Decimal {
+	if t.TradeType == uniswap.ExactOutput {
+		return decimal.NewFromBigInt(t.InputAmount().Raw(), 0)
+	}
+	return decimal.NewFromBigInt(t.OutputAmount().Raw(), 0)
+}
+
+// betterTrade returns whether trade a is better than trade b.
+func betterTrade(a, b *uniswap.Trade) bool {
+	if a.TradeType == uniswap.ExactOutput {
+		return a.InputAmount().Raw().Cmp(b.InputAmount().Raw()) < 0
+	}
+	return a.OutputAmount().Raw().Cmp(b.OutputAmount().Raw()) > 0
+}
+
+// newDexQuote sorts the trades and returns the best and the runner-up.
+func newDexQuote(trades []*DexTrade) (*DexQuote, error) {
+	if len(trades) == 0 {
+		return nil, ErrNoTradeFound
+	}
+	sort.SliceStable(trades, func(i, j int) bool {
+		return betterTrade(trades[i].Trade, trades[j].Trade)
+	})
+	q := &DexQuote{Best: trades[0]}
+	if len(trades) > 1 {
+		q.Next = trades[1]
+	}
+	return q, nil
+}
+
+// genUniPairsByDex generates the uniswap pairs of every dex.
+// The reserves of all dexes are fetched in a single multicall.
+// Dexes without any pairs or whose pairs can't be looked up are skipped, an error is only returned if no dex has pairs.
+func (c *Client) genUniPairsByDex(token0, token1 *database.Token, dexes []*database.Dex, weth string, tokens ...*database.Token) (map[*database.Dex][]*uniswap.Pair, error) {
+	uniTokens, contracts, err := pairTokens(append(append([]*database.Token{}, tokens...), token0, token1), weth)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error": err,
+		}).Error("failed to convert tokens to uniswap tokens")
+		return nil, err
+	}
+	pairs, err := c.getPairs(dexes, contracts)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		addresses = make([]string, 0)
+		feeCalls  []multicall.FeeCall
+	)
+	for _, dex := range dexes {
+		for _, p := range pairs[dex] {
+			addresses = append(addresses, p.GetAddress())
+		}
+		// solidly factories define a separate fee for volatile and stable pairs
+		if dex.IsSolidly() && len(pairs[dex]) > 0 {
+			for _, stable := range pairKinds(dex) {
+				feeCalls = append(feeCalls, multicall.FeeCall{Factory: dex.GetFactory(), Stable: stable})
+			}
+		}
+	}
+	if len(addresses) == 0 {
+		logging.Log.WithFields(logrus.Fields{
+			"error": ErrNoPairsFound,
+		}).Warn("no pairs found")
+		return nil, ErrNoPairsFound
+	}
+	reserves, fees, err := c.getMulticall().GetPairReserves(addresses, feeCalls)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error": err,
+		}).Error("failed to get pair reserves")
+		return nil, err
+	}
+
+	uniPairs := make(map[*database.Dex][]*uniswap.Pair, len(pairs))
+	for _, dex := range dexes {
+		for _, p := range pairs[dex] {
+			r := reserves[p.GetAddress()]
+			amount0, err := uniswap.NewTokenAmount(uniTokens[p.GetToken0()], r.Reserve0)
+			if err != nil {
+				return nil, err
+			}
+			amount1, err := uniswap.NewTokenAmount(uniTokens[p.GetToken1()], r.Reserve1)
+			if err != nil {
+				return nil, err
+			}
+			pair, err := uniswap.NewPair(common.HexToAddress(p.GetAddress()), amount0, amount1)
+			if err != nil {
+				logging.Log.WithFields(logrus.Fields{
+					"error": err,
+				}).Error("failed to create uniswap pair")
+				return nil, err
+			}
+			// every pair carries the fee of its dex, so pairs of different dexes can be compared
+			fee := dex.GetFeeBigInt()
+			if dex.IsSolidly() {
+				pair.SetStable(p.GetStable())
+				if bps, ok := fees[multicall.FeeCall{Factory: dex.GetFactory(), Stable: p.GetStable()}]; ok {
+					fee = new(big.Int).Sub(big.NewInt(10000), bps)
+				}
+			}
+			uniPairs[dex] = append(uniPairs[dex], pair.SetFee(fee))
+		}
+	}
+	return uniPairs, nil
+}
+
+// quoteDexes runs the trade function for the pairs of every dex and returns the best quote.
+// Dexes are evaluated in the given order, so ties go to the first dex.
+func quoteDexes(dexes []*database.Dex, pairs map[*database.Dex][]*uniswap.Pair, fn func(pairs []*uniswap.Pair, dexFee *big.Int) (*uniswap.Trade, error)) (*DexQuote, error) {
+	trades := make([]*DexTrade, 0, len(pairs))
+	for _, dex := range dexes {
+		p, ok := pairs[dex]
+		if !ok {
+			continue
+		}
+		t, err := fn(p, dex.GetFeeBigInt())
+		if err != nil {
+			continue
+		}
+		trades = append(trades, &DexTrade{Dex: dex, Trade: t})
+	}
+	return newDexQuote(trades)
+}
+
+// GetBestTradeExactInAllDexes returns the best exact in trade across all given dexes.
+func (c *Client) GetBestTradeExactInAllDexes(token0, token1 *database.Token, amount *big.Int, dexes []*database.Dex, tokens []*database.Token, maxHops int, weth string) (*DexQuote, error) {
+	pairs, err := c.genUniPairsByDex(token0, token1, dexes, weth, tokens...)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error":  err,
+			"token0": token0.GetContract(),
+			"token1": token1.GetContract(),
+		}).Error("failed to generate uniswap pairs")
+		return nil, err
+	}
+	uniToken0, uniToken1, err := toUniswapTokens(token0, token1, weth)
+	if err != nil {
+		return nil, err
+	}
+	return quoteDexes(dexes, pairs, func(p []*uniswap.Pair, dexFee *big.Int) (*uniswap.Trade, error) {
+		return bestXTrade(uniToken0, uniToken1, amount, p, maxHops, dexFee)
+	})
+}
+
+// GetBestTradeExactOutAllDexes returns the best exact out trade across all given dexes.
+// Solidly dexes are skipped, because their routers can't swap for an exact output amount.
+func (c *Client) GetBestTradeExactOutAllDexes(token0, token1 *database.Token, amount *big.Int, dexes []*database.Dex, tokens []*database.Token, maxHops int, weth string) (*DexQuote, error) {
+	dexes = exactOutDexes(dexes)
+	if len(dexes) == 0 {
+		return nil, ErrSolidlyExactOut
+	}
+	pairs, err := c.genUniPairsByDex(token0, token1, dexes, weth, tokens...)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error":  err,
+			"token0": token0.GetContract(),
+			"token1": token1.GetContract(),
+		}).Error("failed to generate uniswap pairs")
+		return nil, err
+	}
+	uniToken0, uniToken1, err := toUniswapTokens(token0, token1, weth)
+	if err != nil {
+		return nil, err
+	}
+	return quoteDexes(dexes, pairs, func(p []*uniswap.Pair, dexFee *big.Int) (*uniswap.Trade, error) {
+		return bestXTradeExactOut(uniToken0, uniToken1, amount, p, maxHops, dexFee)
+	})
+}
+
+// GetBestOrderTradesAllDexes returns the best buy and sell quotes across all given dexes.
+func (c *Client) GetBestOrderTradesAllDexes(token0, token1 *database.Token, buyAmount, sellAmount *big.Int, dexes []*database.Dex, tokens []*database.Token, maxHops int, weth string) (*DexQuote, *DexQuote, error) {
+	pairs, err := c.genUniPairsByDex(token0, token1, dexes, weth, tokens...)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error":  err,
+			"token0": token0.GetContract(),
+			"token1": token1.GetContract(),
+		}).Error("failed to generate uniswap pairs")
+		return nil, nil, err
+	}
+	uniToken0, uniToken1, err := toUniswapTokens(token0, token1, weth)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var buyQuote, sellQuote *DexQuote
+	var eg errgroup.Group
+	eg.Go(func() error {
+		var err error
+		buyQuote, err = quoteDexes(dexes, pairs, func(p []*uniswap.Pair, dexFee *big.Int) (*uniswap.Trade, error) {
+			return bestXTrade(uniToken0, uniToken1, buyAmount, p, maxHops, dexFee)
+		})
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		sellQuote, err = quoteDexes(dexes, pairs, func(p []*uniswap.Pair, dexFee *big.Int) (*uniswap.Trade, error) {
+			return bestXTrade(uniToken1, uniToken0, sellAmount, p, maxHops, dexFee)
+		})
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return nil, nil, err
+	}
+	return buyQuote, sellQuote, nil
+}
+
+// CheckListedAllDexes checks if any of the given dexes has a trading route for the two tokens.
+// The returned quote contains the dex with the best price.
+func (c *Client) CheckListedAllDexes(token0, token1 *database.Token, dexes []*database.Dex, weth string, tokens []*database.Token, maxHops int) (*DexQuote, error) {
+	return c.GetBestTradeExactInAllDexes(token0, token1, ethutils.ToWei(1, token0.GetDecimals()), dexes, tokens, maxHops, weth)
+}
+
+func toUniswapTokens(token0, token1 *database.Token, weth string) (*uniswap.Token, *uniswap.Token, error) {
+	uniToken0, err := token0.ToUniswap(weth)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error":  err,
+			"token0": token0.GetContract(),
+		}).Error("failed to convert token0 to uniswap.Token")
+		return nil, nil, err
+	}
+	uniToken1, err := token1.ToUniswap(weth)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error":  err,
+			"token1": token1.GetContract(),
+		}).Error("failed to convert token1 to uniswap.Token")
+		return nil, nil, err
+	}
+	return uniToken0, uniToken1, nil
+}
+
+func bestXTradeExactOut(token0, token1 *uniswap.Token, amount *big.Int, pairs []*uniswap.Pair, maxHops int, dexFee *big.Int) (*uniswap.Trade, error) {
+	token1Amount, err := uniswap.NewTokenAmount(token1, amount)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error":  err,
+			"token1": token1.Address(),
+		}).Error("failed to create token1 amount")
+		return nil, err
+	}
+	trades, err := uniswap.BestTradeExactOut(
+		pairs, token0, token1Amount,
+		&uniswap.BestTradeOptions{
+			MaxNumResults: bestTradesResults,
+			MaxHops:       maxHops,
+			DexFee:        dexFee,
+		}, nil, nil, nil,
+	)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error":  err,
+			"token0": token0.Address(),
+			"token1": token1.Address(),
+		}).Error("failed to find best trade (exact out)")
+		return nil, err
+	}
+	if len(trades) == 0 {
+		logging.Log.Debug("no trade found")
+		return nil, ErrNoTradeFound
+	}
+	return trades[0], nil
+}
+
+// exactOutDexes returns the dexes which support exact out swaps.
+func exactOutDexes(dexes []*database.Dex) []*database.Dex {
+	filtered := make([]*database.Dex, 0, len(dexes))
+	for _, dex := range dexes {
+		if !dex.IsSolidly() {
+			filtered = append(filtered, dex)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,54 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/pkg/uniswap"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestQuoteDexes(t *testing.T) {
+	token0, _ := uniswap.NewToken(common.HexToAddress("0x0000000000000000000000000000000000000001"), "t0", "t0", 18)
+	token1, _ := uniswap.NewToken(common.HexToAddress("0x0000000000000000000000000000000000000002"), "t1", "t1", 18)
+	newPair := func(addr string, reserve0, reserve1 int64, fee int64) *uniswap.Pair {
+		a, _ := uniswap.NewTokenAmount(token0, big.NewInt(reserve0))
+		b, _ := uniswap.NewTokenAmount(token1, big.NewInt(reserve1))
+		p, err := uniswap.NewPair(common.HexToAddress(addr), a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p.SetFee(big.NewInt(fee))
+	}
+
+	dexA := database.NewDex("a", "", "", 9970, false)
+	dexB := database.NewDex("b", "", "", 9990, false)
+	dexC := database.NewDex("c", "", "", 9970, false)
+	pairs := map[*database.Dex][]*uniswap.Pair{
+		dexA: {newPair("0x0000000000000000000000000000000000000010", 1e6, 1e6, 9970)},
+		dexB: {newPair("0x0000000000000000000000000000000000000011", 1e6, 1e6, 9990)},
+	}
+
+	quote, err := quoteDexes([]*database.Dex{dexA, dexB, dexC}, pairs, func(p []*uniswap.Pair, dexFee *big.Int) (*uniswap.Trade, error) {
+		return bestXTrade(token0, token1, big.NewInt(1000), p, 3, dexFee)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.GetDex() != dexB {
+		t.Errorf("expected dex %s, got %s", dexB.GetName(), quote.GetDex().GetName())
+	}
+	if quote.Next == nil || quote.Next.Dex != dexA {
+		t.Fatalf("expected runner-up dex %s", dexA.GetName())
+	}
+	improvement, ok := quote.Improvement()
+	if !ok || !improvement.IsPositive() {
+		t.Errorf("expected a positive improvement, got %s", improvement)
+	}
+
+	if _, err := quoteDexes([]*database.Dex{dexC}, pairs, nil); err != ErrNoTradeFound {
+		t.Errorf("expected %v, got %v", ErrNoTradeFound, err)
+	}
+}
@@ -2,12 +2,13 @@ package blockchain
 
 import (
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jon4hz/deadshot/internal/blockchain/abi/uniswapv2router2"
 	"github.com/jon4hz/deadshot/internal/blockchain/multicall"
+	"github.com/jon4hz/deadshot/internal/database"
 	"github.com/jon4hz/deadshot/internal/logging"
-	"github.com/jon4hz/deadshot/pkg/uniswap"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -18,23 +19,56 @@ import (
 
 const Zero = "0x0000000000000000000000000000000000000000"
 
+// Client is connected to an endpoint of a network.
+// The endpoint can be switched while the client is in use, e.g. if it was removed from the config.
 type Client struct {
-	Client *ethclient.Client
-	multic *multicall.Client
+	eth          *ethclient.Client
+	multic       *multicall.Client
+	url          string
+	multicallHex string
+	// network is the network of the client, it's only known if the client was created by Connect.
+	network *database.Network
+	pairs   pairCache
+	mu      sync.RWMutex
 }
 
 // NewClient initilalizes the blockchain clients.
 func NewClient(node, multicallHex string) (*Client, error) {
-	if strings.HasPrefix("http", node) {
-		return newRetryableHTTPClient(node, multicallHex)
+	eth, m, err := dial(node, multicallHex)
+	if err != nil {
+		return nil, err
 	}
-	return newClient(node, multicallHex)
+	return &Client{
+		eth:          eth,
+		multic:       m,
+		url:          node,
+		multicallHex: multicallHex,
+	}, nil
 }
 
-func newRetryableHTTPClient(node, multicallHex string) (*Client, error) {
-	var err error
-	c := new(Client)
+// dial connects to the node and initializes the multicall client.
+func dial(node, multicallHex string) (*ethclient.Client, *multicall.Client, error) {
+	var (
+		eth *ethclient.Client
+		err error
+	)
+	if strings.HasPrefix("http", node) {
+		eth, err = dialRetryableHTTP(node)
+	} else {
+		eth, err = dialNode(node)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	m, err := multicall.Init(eth, multicallHex)
+	if err != nil {
+		eth.Close()
+		return nil, nil, err
+	}
+	return eth, m, nil
+}
 
+func dialRetryableHTTP(node string) (*ethclient.Client, error) {
 	rc := retryablehttp.NewClient()
 	rc.Logger = nil
 	rc.RetryWaitMin = 200 * time.Millisecond
@@ -50,23 +84,11 @@ func newRetryableHTTPClient(node, multicallHex string) (*Client, error) {
 		}).Error("failed to create rpc client")
 		return nil, err
 	}
-
-	c.Client = ethclient.NewClient(rpc)
-
-	m, err := multicall.Init(c.Client, multicallHex)
-	if err != nil {
-		return nil, err
-	}
-	c.multic = m
-
-	return c, nil
+	return ethclient.NewClient(rpc), nil
 }
 
-func newClient(node, multicallHex string) (*Client, error) {
-	var err error
-	c := new(Client)
-
-	c.Client, err = ethclient.Dial(node)
+func dialNode(node string) (*ethclient.Client, error) {
+	eth, err := ethclient.Dial(node)
 	if err != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"error": err,
@@ -74,20 +96,69 @@ func newClient(node, multicallHex string) (*Client, error) {
 		}).Error("Failed to connect to node")
 		return nil, err
 	}
+	return eth, nil
+}
 
-	m, err := multicall.Init(c.Client, multicallHex)
+// Eth returns the ethereum client of the current endpoint.
+func (c *Client) Eth() *ethclient.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.eth
+}
+
+func (c *Client) getMulticall() *multicall.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.multic
+}
+
+// URL returns the url of the current endpoint.
+func (c *Client) URL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.url
+}
+
+// Network returns the network of the client or nil if it's unknown.
+func (c *Client) Network() *database.Network {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.network
+}
+
+func (c *Client) setNetwork(n *database.Network) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.network = n
+}
+
+// Close closes the connection to the endpoint.
+func (c *Client) Close() {
+	c.Eth().Close()
+}
+
+// switchGracePeriod is the time after which the connection to the previous endpoint is closed,
+// so requests which are already running can finish.
+const switchGracePeriod = time.Minute
+
+// SwitchEndpoint connects the client to another endpoint of the same network.
+func (c *Client) SwitchEndpoint(node string) error {
+	eth, m, err := dial(node, c.multicallHex)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	c.multic = m
-
-	return c, nil
+	c.mu.Lock()
+	previous := c.eth
+	c.eth, c.multic, c.url = eth, m, node
+	c.mu.Unlock()
+	time.AfterFunc(switchGracePeriod, previous.Close)
+	return nil
 }
 
 // NewRouter initializes the uniswapv2 router.
 func (c *Client) NewRouter(contract string) (*uniswapv2router2.Uniswapv2router2, error) {
 	addr := common.HexToAddress(contract)
-	router, err := uniswapv2router2.NewUniswapv2router2(addr, c.Client)
+	router, err := uniswapv2router2.NewUniswapv2router2(addr, c.Eth())
 	if err != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"error":  err,
@@ -97,28 +168,3 @@ func (c *Client) NewRouter(contract string) (*uniswapv2router2.Uniswapv2router2,
 	}
 	return router, nil
 }
-
-// ToUniswap converts a pair to a uniswap.Pair.
-func (p Pair) ToUniswap() (*uniswap.Pair, *uniswap.Token, *uniswap.Token, error) {
-	token0, err := uniswap.NewToken(common.HexToAddress(p.token0.GetContract()), "", p.token0.GetSymbol(), p.token0.GetDecimals())
-	if err != nil {
-		return nil, nil, nil, err
-	}
-	token1, err := uniswap.NewToken(common.HexToAddress(p.token1.GetContract()), "", p.token1.GetSymbol(), p.token1.GetDecimals())
-	if err != nil {
-		return nil, nil, nil, err
-	}
-	tokenAmount0, err := uniswap.NewTokenAmount(token0, p.reserve0)
-	if err != nil {
-		return nil, nil, nil, err
-	}
-	tokenAmount1, err := uniswap.NewTokenAmount(token1, p.reserve1)
-	if err != nil {
-		return nil, nil, nil, err
-	}
-	uniswapPair, err := uniswap.NewPair(common.HexToAddress(p.address), tokenAmount0, tokenAmount1)
-	if err != nil {
-		return nil, nil, nil, err
-	}
-	return uniswapPair, token0, token1, nil
-}
@@ -180,11 +180,11 @@ func TestEthCall(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	gasPrice, err := c.Client.SuggestGasPrice(context.Background())
+	gasPrice, err := c.Eth().SuggestGasPrice(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
-	res, err := c.Client.CallContract(context.Background(), ethereum.CallMsg{
+	res, err := c.Eth().CallContract(context.Background(), ethereum.CallMsg{
 		Data:     d,
 		From:     common.HexToAddress(me),
 		To:       &ctr,
@@ -204,7 +204,7 @@ func TestTxReceipt(t *testing.T) {
 		t.Fatal(err)
 	}
 	txh := common.HexToHash("0xd3fa1ecc8c6a0d5f3605344b3d23218d5b0a4c59be8dc6c8d8b12809d8e2dcca")
-	r, err := c.Client.TransactionReceipt(context.Background(), txh)
+	r, err := c.Eth().TransactionReceipt(context.Background(), txh)
 	if err != nil {
 		t.Fatal(err)
 	}
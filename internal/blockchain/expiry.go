@@ -0,0 +1,115 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/internal/logstream"
+
+	"github.com/sirupsen/logrus"
+)
+
+// blockCheckInterval is the minimum time between two requests of the block number.
+const blockCheckInterval = 3 * time.Second
+
+// expiryChecker cancels the pending targets of a trade after their expiry.
+type expiryChecker struct {
+	client    *Client
+	block     uint64
+	lastCheck time.Time
+}
+
+// currentBlock returns the latest block number or 0 if it's unknown.
+// The block number is only requested if the trade expires at a block.
+func (e *expiryChecker) currentBlock(trade *database.Trade, now time.Time) uint64 {
+	if !trade.HasBlockExpiry() || now.Sub(e.lastCheck) < blockCheckInterval {
+		return e.block
+	}
+	e.lastCheck = now
+	block, err := e.client.Eth().BlockNumber(context.Background())
+	if err != nil {
+		logging.Log.WithField("err", err).Warn("failed to get the block number")
+		return e.block
+	}
+	e.block = block
+	return block
+}
+
+// check expires the pending targets of the trade and returns whether the trade should stop.
+func (e *expiryChecker) check(trade *database.Trade, logStream chan<- string) bool {
+	now := time.Now()
+	return expireTrade(trade, now, e.currentBlock(trade, now), logStream)
+}
+
+// expireTrade marks the targets of the trade as expired, if their expiry or the expiry of the trade is reached.
+// It returns whether the trade should stop, because it expired or because an expired target left nothing to do.
+func expireTrade(trade *database.Trade, now time.Time, block uint64, logStream chan<- string) bool {
+	tradeExpired := trade.GetExpiry().Reached(now, block)
+	var expired bool
+	expire := func(targets []*database.Target, kind string) {
+		for _, t := range targets {
+			if !t.GetPending() || (!tradeExpired && !t.GetExpiry().Reached(now, block)) {
+				continue
+			}
+			t.SetExpired()
+			expired = true
+			logging.Log.WithFields(logrus.Fields{
+				"price":  t.ViewPrice(),
+				"expiry": t.GetExpiry().String(),
+			}).Info("target expired")
+			logStream <- logstream.Format(fmt.Sprintf("%s target at %s expired", kind, t.ViewPrice()), logstream.WARN)
+		}
+	}
+	expire(trade.GetBuyTargets(), "buy")
+	expire(trade.GetSellTargets(), "sell")
+
+	if tradeExpired {
+		trade.SetExpired()
+		logging.Log.WithField("expiry", trade.GetExpiry().String()).Info("trade expired")
+		logStream <- logstream.Format("order expired, stopping now...", logstream.WARN)
+		return true
+	}
+	if !expired {
+		return false
+	}
+
+	buysOpen, sellsOpen := openTargets(trade.GetBuyTargets()), openTargets(trade.GetSellTargets())
+	switch {
+	case buysOpen == 0 && sellsOpen == 0:
+		logStream <- logstream.Format("no targets left, stopping now...", logstream.INFO)
+		return true
+	case buysOpen == 0 && len(trade.GetBuyTargets()) > 0 && trade.GetBuyTargetHit() == 0:
+		logStream <- logstream.Format("all buy targets expired, stopping now...", logstream.INFO)
+		return true
+	}
+	return false
+}
+
+// openTargets returns the number of targets which are pending or waiting for their transaction.
+func openTargets(targets []*database.Target) int {
+	var n int
+	for _, t := range targets {
+		if targetOpen(t) {
+			n++
+		}
+	}
+	return n
+}
+
+// openSellTargets returns the number of open sell targets without the stop loss.
+func openSellTargets(trade *database.Trade) int {
+	var n int
+	for _, t := range trade.GetSellTargets() {
+		if !t.GetStopLoss() && targetOpen(t) {
+			n++
+		}
+	}
+	return n
+}
+
+func targetOpen(t *database.Target) bool {
+	return t.GetPending() || (t.GetHit() && !t.GetConfirmed() && !t.GetFailed())
+}
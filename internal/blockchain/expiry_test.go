@@ -0,0 +1,41 @@
+package blockchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jon4hz/deadshot/internal/database"
+)
+
+func TestExpireTrade(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Minute), now.Add(time.Hour)
+	logStream := make(chan string, 10)
+
+	buy1 := &database.Target{Price: "100", ExpiresAt: &past}
+	buy2 := &database.Target{Price: "90", ExpiresAtBlock: 100}
+	sell := &database.Target{Price: "200", ExpiresAt: &future}
+	trade := &database.Trade{BuyTargets: database.Targets{buy1, buy2}, SellTargets: database.Targets{sell}}
+
+	if expireTrade(trade, now, 99, logStream) {
+		t.Error("the trade shouldn't stop while a buy target is pending")
+	}
+	if !buy1.GetExpired() || buy2.GetExpired() || sell.GetExpired() {
+		t.Fatal("only the first buy target should be expired")
+	}
+	if next := trade.GetNextBuyTarget(); next != buy2 {
+		t.Errorf("next buy target = %v, want the second one", next)
+	}
+	if !expireTrade(trade, now, 100, logStream) {
+		t.Error("the trade should stop after all buy targets expired without a buy")
+	}
+
+	sell = &database.Target{Price: "200"}
+	trade = &database.Trade{SellTargets: database.Targets{sell}, ExpiresAtBlock: 100}
+	if expireTrade(trade, now, 0, logStream) {
+		t.Error("an unknown block shouldn't expire the trade")
+	}
+	if !expireTrade(trade, now, 100, logStream) || !trade.GetExpired() || !sell.GetExpired() {
+		t.Error("the expired trade should stop and expire its pending targets")
+	}
+}
@@ -0,0 +1,194 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jon4hz/deadshot/internal/config"
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/pkg/ethutils"
+	"github.com/jon4hz/deadshot/pkg/uniswap"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// maximum hops to value a swap in the native currency
+const guardMaxHops = 3
+
+var (
+	ErrGuardTradeAmount = errors.New("swap exceeds the maximum trade amount")
+	ErrGuardDailyAmount = errors.New("swap exceeds the maximum daily amount")
+	ErrGuardSlippage    = errors.New("slippage exceeds the maximum slippage")
+	ErrGuardPriceImpact = errors.New("price impact exceeds the maximum price impact")
+	ErrGuardToken       = errors.New("token is not allowed")
+	ErrGuardRouter      = errors.New("router is not allowed")
+)
+
+// spendingMu serializes checking and recording the daily amount,
+// so concurrent swaps can't exceed the limit together.
+var spendingMu sync.Mutex
+
+// guardedSwap is a swap of the trade dispatcher which is checked against the guards.
+type guardedSwap struct {
+	trade  *database.Trade
+	target *database.Target
+	// quote is the uniswap trade of the target amount.
+	quote *uniswap.Trade
+	// input is the token which is spent by the swap.
+	input *database.Token
+}
+
+// checkGuards checks the swap against the guards.
+// If the daily amount is limited, the value of the swap is reserved and returned, so it can be released if the swap fails.
+func (c *Client) checkGuards(g *config.Guards, s *guardedSwap) (*big.Int, error) {
+	if g == nil {
+		return nil, nil
+	}
+	network, dex := s.trade.GetNetwork(), s.target.GetDex()
+	if !guardAllows(g.Tokens, s.trade.GetToken0().GetContract(), s.trade.GetToken0().GetSymbol()) {
+		return nil, fmt.Errorf("%w: %s", ErrGuardToken, s.trade.GetToken0().GetSymbol())
+	}
+	if !guardAllows(g.Tokens, s.trade.GetToken1().GetContract(), s.trade.GetToken1().GetSymbol()) {
+		return nil, fmt.Errorf("%w: %s", ErrGuardToken, s.trade.GetToken1().GetSymbol())
+	}
+	if !guardAllows(g.Routers, dex.GetRouter(), dex.GetName()) {
+		return nil, fmt.Errorf("%w: %s", ErrGuardRouter, dex.GetName())
+	}
+	if err := checkSlippage(g, s.target.GetSlippage()); err != nil {
+		return nil, err
+	}
+	if g.MaxPriceImpact > 0 {
+		if s.quote == nil {
+			return nil, fmt.Errorf("%w: unknown price impact", ErrGuardPriceImpact)
+		}
+		if err := checkPriceImpact(g, GetActualPriceImpact(s.quote, dex.GetFeeBigInt())); err != nil {
+			return nil, err
+		}
+	}
+	if g.MaxTradeAmount <= 0 && g.MaxDailyAmount <= 0 {
+		return nil, nil
+	}
+
+	value, err := c.nativeValue(network, s.input, s.target.GetActualAmount())
+	if err != nil {
+		return nil, fmt.Errorf("could not value the swap in the native currency: %w", err)
+	}
+	amount := ethutils.ToDecimal(value, 18)
+	if g.MaxTradeAmount > 0 && amount.GreaterThan(decimal.NewFromFloat(g.MaxTradeAmount)) {
+		return nil, fmt.Errorf("%w: %s > %v", ErrGuardTradeAmount, amount.StringFixed(significantDecimals), g.MaxTradeAmount)
+	}
+	if g.MaxDailyAmount <= 0 {
+		return nil, nil
+	}
+
+	spendingMu.Lock()
+	defer spendingMu.Unlock()
+	now := time.Now()
+	spent, err := database.FetchDailySpending(network.GetID(), now)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch the daily amount: %w", err)
+	}
+	total := ethutils.ToDecimal(new(big.Int).Add(spent, value), 18)
+	if total.GreaterThan(decimal.NewFromFloat(g.MaxDailyAmount)) {
+		return nil, fmt.Errorf("%w: %s > %v", ErrGuardDailyAmount, total.StringFixed(significantDecimals), g.MaxDailyAmount)
+	}
+	if err := database.AddDailySpending(network.GetID(), now, value); err != nil {
+		return nil, fmt.Errorf("could not record the daily amount: %w", err)
+	}
+	return value, nil
+}
+
+// haltGuards returns the guards of the sell when trading is halted.
+// The sell only closes the open position, so the daily amount doesn't limit it.
+func haltGuards(g *config.Guards) *config.Guards {
+	if g == nil {
+		return nil
+	}
+	halt := *g
+	halt.MaxDailyAmount = 0
+	return &halt
+}
+
+// releaseReservedSpending removes the reserved value of a failed swap from the daily amount.
+func releaseReservedSpending(network *database.Network, value *big.Int) {
+	if value == nil {
+		return
+	}
+	spendingMu.Lock()
+	defer spendingMu.Unlock()
+	if err := database.AddDailySpending(network.GetID(), time.Now(), new(big.Int).Neg(value)); err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"network": network.GetName(),
+			"err":     err,
+		}).Error("failed to release the reserved daily amount")
+	}
+}
+
+// nativeValue returns the value of the token amount in the native currency of the network.
+func (c *Client) nativeValue(network *database.Network, token *database.Token, amount *big.Int) (*big.Int, error) {
+	if token.GetNative() || strings.EqualFold(token.GetContract(), network.GetWETH()) {
+		return amount, nil
+	}
+	var native *database.Token
+	for _, t := range network.GetTokens() {
+		if t.GetNative() {
+			native = t
+			break
+		}
+	}
+	if native == nil {
+		return nil, fmt.Errorf("no native token on network %s", network.GetName())
+	}
+	q, err := c.GetBestTradeExactInAllDexes(token, native, amount, network.GetDexes(), network.Connectors(), guardMaxHops, network.GetWETH())
+	if err != nil {
+		return nil, err
+	}
+	if q == nil || q.Best == nil {
+		return nil, fmt.Errorf("no route from %s to %s", token.GetSymbol(), native.GetSymbol())
+	}
+	return q.Best.Trade.OutputAmount().Raw(), nil
+}
+
+// checkSlippage checks the slippage of a target in basis points against the maximum slippage in percent.
+func checkSlippage(g *config.Guards, slippage float64) error {
+	if g.MaxSlippage <= 0 {
+		return nil
+	}
+	percent := slippage * 100 / database.MaxSlippage
+	if percent > g.MaxSlippage {
+		return fmt.Errorf("%w: %.2f%% > %.2f%%", ErrGuardSlippage, percent, g.MaxSlippage)
+	}
+	return nil
+}
+
+// checkPriceImpact checks the price impact in percent against the maximum price impact.
+func checkPriceImpact(g *config.Guards, impact float64) error {
+	if g.MaxPriceImpact <= 0 {
+		return nil
+	}
+	if impact > g.MaxPriceImpact {
+		return fmt.Errorf("%w: %.2f%% > %.2f%%", ErrGuardPriceImpact, impact, g.MaxPriceImpact)
+	}
+	return nil
+}
+
+// guardAllows returns true if the allowlist is empty or contains one of the names.
+func guardAllows(allowlist []string, names ...string) bool {
+	if len(allowlist) == 0 {
+		return true
+	}
+	for _, allowed := range allowlist {
+		for _, name := range names {
+			if strings.EqualFold(strings.TrimSpace(allowed), name) {
+				return true
+			}
+		}
+	}
+	return false
+}
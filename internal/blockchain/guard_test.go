@@ -0,0 +1,56 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jon4hz/deadshot/internal/config"
+	"github.com/jon4hz/deadshot/internal/database"
+)
+
+func TestCheckSlippage(t *testing.T) {
+	g := &config.Guards{MaxSlippage: 5}
+	tests := []struct {
+		slippage float64
+		err      error
+	}{
+		{slippage: 100},
+		{slippage: 500},
+		{slippage: 501, err: ErrGuardSlippage},
+		{slippage: database.MaxSlippage, err: ErrGuardSlippage},
+	}
+	for _, tt := range tests {
+		if err := checkSlippage(g, tt.slippage); !errors.Is(err, tt.err) {
+			t.Errorf("checkSlippage(%v) error = %v, want %v", tt.slippage, err, tt.err)
+		}
+	}
+	if err := checkSlippage(&config.Guards{}, database.MaxSlippage); err != nil {
+		t.Errorf("checkSlippage without limit error = %v, want nil", err)
+	}
+}
+
+func TestCheckPriceImpact(t *testing.T) {
+	g := &config.Guards{MaxPriceImpact: 2}
+	if err := checkPriceImpact(g, 1.5); err != nil {
+		t.Errorf("checkPriceImpact(1.5) error = %v, want nil", err)
+	}
+	if err := checkPriceImpact(g, 2.5); !errors.Is(err, ErrGuardPriceImpact) {
+		t.Errorf("checkPriceImpact(2.5) error = %v, want %v", err, ErrGuardPriceImpact)
+	}
+}
+
+func TestGuardAllows(t *testing.T) {
+	if !guardAllows(nil, "USDC") {
+		t.Error("an empty allowlist should allow everything")
+	}
+	allowlist := []string{"usdc", " 0x10ED43C718714eb63d5aA57B78B54704E256024E"}
+	if !guardAllows(allowlist, "0x0", "USDC") {
+		t.Error("USDC should be allowed by symbol")
+	}
+	if !guardAllows(allowlist, "0x10ed43c718714eb63d5aa57b78b54704e256024e") {
+		t.Error("the router should be allowed by address")
+	}
+	if guardAllows(allowlist, "DAI") {
+		t.Error("DAI shouldn't be allowed")
+	}
+}
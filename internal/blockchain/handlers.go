@@ -4,9 +4,7 @@ import (
 	"context"
 	"errors"
 	"math/big"
-	"strings"
 
-	"github.com/jon4hz/deadshot/internal/blockchain/multicall"
 	"github.com/jon4hz/deadshot/internal/database"
 	"github.com/jon4hz/deadshot/pkg/ethutils"
 
@@ -27,7 +25,7 @@ func (c *Client) GetTokenInfo(contracts ...string) (map[string]*database.Token,
 	if len(validContracts) == 0 {
 		return nil, ErrNoContracts
 	}
-	x, err := c.multic.GetTokenInfo(validContracts)
+	x, err := c.getMulticall().GetTokenInfo(validContracts)
 	if err != nil {
 		return nil, err
 	}
@@ -51,91 +49,52 @@ func (c *Client) GetBalanceOf(address string, contract string) (*big.Int, error)
 
 func (c *Client) getNativeBalanceOf(address string) (*big.Int, error) {
 	addr := common.HexToAddress(address)
-	return c.Client.BalanceAt(context.Background(), addr, nil)
+	return c.Eth().BalanceAt(context.Background(), addr, nil)
 }
 
-func (c *Client) GetBalanceOfToken(address string, token *database.Token) (*big.Int, error) {
-	if token.GetNative() {
-		return c.GetBalanceOf(address, "")
+// GetNativeBalances returns a map of addresses with their native balance as values.
+func (c *Client) GetNativeBalances(addresses ...string) (map[string]*big.Int, error) {
+	if len(addresses) == 0 {
+		return nil, ErrNoContracts
 	}
-	return c.GetBalanceOf(address, token.GetContract())
+	return c.getMulticall().GetNativeBalances(addresses)
 }
 
-// GetPairInfo returns a map of liquidity tokens with their pair info as values.
-func (c *Client) GetPairInfo(contracts ...string) (map[string]*Pair, error) {
-	if len(contracts) == 0 {
+// GetTokenBalances returns a map of token contracts with the balance of the address as values.
+// All balances are read in a single multicall, tokens whose call failed are not included.
+func (c *Client) GetTokenBalances(address string, tokens ...*database.Token) (map[string]*big.Int, error) {
+	if len(tokens) == 0 {
 		return nil, ErrNoContracts
 	}
-	pairs, err := c.multic.GetPairInfo(contracts)
-	if err != nil {
-		return nil, err
-	}
-	m := make(map[string]*Pair)
-	for k, v := range pairs {
-		m[k] = NewPair(
-			k,
-			v.Token0.ToDatabaseToken(),
-			v.Token1.ToDatabaseToken(),
-			v.Reserve0,
-			v.Reserve1,
-			v.TotalSupply,
-		)
-	}
-	return m, nil
-}
-
-// GetPairTokens returns a slice of liquidity tokens.
-func (c *Client) GetValidPairTokens(tokens []*database.Token, factory string) ([]string, error) {
-	pairs := genPairs(tokens, factory)
-
-	tokenPairsM := make([]multicall.TokenPair, len(pairs))
-	for i, v := range pairs {
-		tokenPairsM[i] = multicall.TokenPair(v)
+	// the native balance is read for the zero address
+	native := common.Address{}.Hex()
+	contracts := make([]string, len(tokens))
+	for i, token := range tokens {
+		contracts[i] = token.GetContract()
+		if token.GetNative() {
+			contracts[i] = native
+		}
 	}
-
-	pairTokens, err := c.multic.GetPairToken(tokenPairsM)
+	res, err := c.getMulticall().GetTokenBalances(address, contracts)
 	if err != nil {
 		return nil, err
 	}
-
-	tokenPairs := make([]string, 0)
-	var i int
-	for _, v := range pairTokens {
-		tokenPairs = append(tokenPairs, v.String())
-		i++
-	}
-
-	return tokenPairs, nil
-}
-
-func genPairs(tokens []*database.Token, factory string) []TokenPair {
-	var tokenPairs []TokenPair
-	for i := 0; i < len(tokens); i++ {
-		for j := 0; j < len(tokens); j++ {
-			if i == j {
-				continue
-			}
-			if tokens[i] == tokens[j] {
-				continue
-			}
-			tokenPair := NewTokenPair(tokens[i].GetContract(), tokens[j].GetContract(), factory)
-			if containsPair(tokenPairs, *tokenPair) {
-				continue
-			}
-			tokenPairs = append(tokenPairs, *tokenPair)
+	balances := make(map[string]*big.Int, len(res))
+	for _, token := range tokens {
+		contract := token.GetContract()
+		if token.GetNative() {
+			contract = native
+		}
+		if balance, ok := res[contract]; ok {
+			balances[token.GetContract()] = balance
 		}
 	}
-	return tokenPairs
+	return balances, nil
 }
 
-func containsPair(tokenPairs []TokenPair, pair TokenPair) bool {
-	for _, p := range tokenPairs {
-		if strings.EqualFold(p.Token0, pair.Token0) && strings.EqualFold(p.Token1, pair.Token1) {
-			return true
-		}
-		if strings.EqualFold(p.Token1, pair.Token0) && strings.EqualFold(p.Token0, pair.Token1) {
-			return true
-		}
+func (c *Client) GetBalanceOfToken(address string, token *database.Token) (*big.Int, error) {
+	if token.GetNative() {
+		return c.GetBalanceOf(address, "")
 	}
-	return false
+	return c.GetBalanceOf(address, token.GetContract())
 }
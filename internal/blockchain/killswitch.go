@@ -0,0 +1,232 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/jon4hz/deadshot/internal/config"
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/internal/logstream"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrHalted is returned if a trade is started while trading is halted.
+var ErrHalted = errors.New("trading is halted")
+
+// dispatcher is a running trade dispatcher which is stopped by the kill switch.
+type dispatcher struct {
+	client    *Client
+	cancel    context.CancelFunc
+	wallet    *database.Wallet
+	trade     *database.Trade
+	price     *Price
+	logStream chan<- string
+	// guards can be replaced while the dispatcher is running, e.g. after the config file changed.
+	guards *config.Guards
+	mu     sync.Mutex
+}
+
+func (d *dispatcher) getGuards() *config.Guards {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.guards
+}
+
+func (d *dispatcher) setGuards(g *config.Guards) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.guards = g
+}
+
+// haltLogTimeout is the time to wait for a listener of the log stream of a halted dispatcher.
+const haltLogTimeout = time.Second
+
+// log sends the message to the log stream of the dispatcher without blocking if nobody is listening anymore.
+func (d *dispatcher) log(msg string) {
+	select {
+	case d.logStream <- msg:
+	case <-time.After(haltLogTimeout):
+	}
+}
+
+var (
+	killSwitchMu sync.Mutex
+	dispatchers  = make(map[*dispatcher]struct{})
+	priceFeeds   = make(map[*Price]struct{})
+)
+
+func registerDispatcher(d *dispatcher) {
+	killSwitchMu.Lock()
+	defer killSwitchMu.Unlock()
+	dispatchers[d] = struct{}{}
+}
+
+func unregisterDispatcher(d *dispatcher) {
+	killSwitchMu.Lock()
+	defer killSwitchMu.Unlock()
+	delete(dispatchers, d)
+}
+
+func registerPriceFeed(p *Price) {
+	killSwitchMu.Lock()
+	defer killSwitchMu.Unlock()
+	priceFeeds[p] = struct{}{}
+}
+
+func unregisterPriceFeed(p *Price) {
+	killSwitchMu.Lock()
+	defer killSwitchMu.Unlock()
+	delete(priceFeeds, p)
+}
+
+// CheckHalted returns ErrHalted if trading is halted.
+func CheckHalted() error {
+	h, err := database.FetchHalt()
+	if err != nil {
+		return err
+	}
+	if h.GetHalted() {
+		if h.GetReason() != "" {
+			return fmt.Errorf("%w: %s", ErrHalted, h.GetReason())
+		}
+		return ErrHalted
+	}
+	return nil
+}
+
+// Halt persists the halted flag and stops every running trade dispatcher and price feed of this process.
+// If sell is true, the open positions of the dispatchers are sold at the market price first.
+func Halt(reason string, sell bool) error {
+	if err := database.SaveHalt(true, reason, sell); err != nil {
+		return fmt.Errorf("could not save the halted flag: %w", err)
+	}
+	logging.Log.WithFields(logrus.Fields{
+		"reason": reason,
+		"sell":   sell,
+	}).Warn("halting all trades")
+	return stopAll(reason, sell)
+}
+
+// Resume clears the halted flag, so new trades can be started again.
+func Resume() error {
+	logging.Log.Info("resuming trading")
+	return database.SaveHalt(false, "", false)
+}
+
+// WatchHalt polls the halted flag and stops the trades of this process as soon as it's set,
+// e.g. by the halt command of another process.
+// This function is blocking and should be run in a goroutine.
+func WatchHalt(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var halted bool
+	if h, err := database.FetchHalt(); err == nil {
+		halted = h.GetHalted()
+	}
+	for {
+		select {
+		case <-ticker.C:
+			h, err := database.FetchHalt()
+			if err != nil {
+				logging.Log.WithField("err", err).Error("failed to fetch the halted flag")
+				continue
+			}
+			// new trades refuse to start while halted, so only a new halt has to stop anything
+			wasHalted := halted
+			halted = h.GetHalted()
+			if !halted || wasHalted {
+				continue
+			}
+			if err := stopAll(h.GetReason(), h.GetSell()); err != nil {
+				logging.Log.WithField("err", err).Error("failed to halt all trades")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// stopAll stops all registered trade dispatchers and price feeds.
+func stopAll(reason string, sell bool) error {
+	killSwitchMu.Lock()
+	running := make([]*dispatcher, 0, len(dispatchers))
+	for d := range dispatchers {
+		running = append(running, d)
+		delete(dispatchers, d)
+	}
+	feeds := make([]*Price, 0, len(priceFeeds))
+	for p := range priceFeeds {
+		feeds = append(feeds, p)
+		delete(priceFeeds, p)
+	}
+	killSwitchMu.Unlock()
+
+	var errs []error
+	for _, d := range running {
+		d.cancel()
+		msg := "trading halted"
+		if reason != "" {
+			msg += ": " + reason
+		}
+		d.log(logstream.Format(msg, logstream.WARN))
+		if !sell {
+			continue
+		}
+		tx, err := d.client.sellPosition(d.wallet, d.trade, d.price, d.getGuards())
+		if err != nil {
+			logging.Log.WithFields(logrus.Fields{
+				"token1": d.trade.GetToken1().GetContract(),
+				"err":    err,
+			}).Error("failed to sell the open position")
+			d.log(logstream.Format(fmt.Sprintf("could not sell the open position: %s", err), logstream.ERR))
+			errs = append(errs, err)
+			continue
+		}
+		if tx != nil {
+			d.log(logstream.Format(fmt.Sprintf("selling the open position: %s", tx.Hash().Hex()), logstream.INFO))
+		}
+	}
+	for _, p := range feeds {
+		p.Stop()
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("could not sell %d open positions: %w", len(errs), errs[0])
+	}
+	return nil
+}
+
+// sellPosition sells the amount in trade at the market price of the last sell quote.
+// The sell is checked against the guards like every other swap, only the daily amount doesn't limit it.
+func (c *Client) sellPosition(wallet *database.Wallet, trade *database.Trade, price *Price, guards *config.Guards) (*types.Transaction, error) {
+	amount := trade.AmountInTrade()
+	if amount.Sign() <= 0 {
+		return nil, nil
+	}
+	_, sellQuote := price.GetQuotes()
+	quote := sellQuote.GetTrade()
+	if quote == nil {
+		return nil, ErrNoSellTrade
+	}
+	dex := sellQuote.DexOr(trade.GetDex())
+	target := database.NewTargetWithProfile(trade.GetProfile())
+	target.SetTargetType(database.DefaultTargetTypes.GetSell())
+	target.SetDex(dex)
+	target.SetActualAmount(new(big.Int).Set(amount))
+	// the amount is already known, don't derive it from a price
+	target.SetPercentageAmount(100)
+	sellTrade, err := setMissingSellTargetInfo(target, trade.GetProfile(), trade.GetToken1(), nil, quote.Route, trade.GetNetwork().GetWETH(), dex.GetFeeBigInt())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.checkGuards(haltGuards(guards), &guardedSwap{trade: trade, target: target, quote: sellTrade, input: trade.GetToken1()}); err != nil {
+		return nil, err
+	}
+	return c.Swap(wallet, trade, target)
+}
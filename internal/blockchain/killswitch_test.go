@@ -0,0 +1,105 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/jon4hz/deadshot/internal/config"
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/pkg/uniswap"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestStopAll(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	logStream := make(chan string, 1)
+	d := &dispatcher{cancel: cancel, logStream: logStream}
+	registerDispatcher(d)
+	p := NewPrice()
+	registerPriceFeed(p)
+
+	if err := stopAll("rug", false); err != nil {
+		t.Fatalf("stopAll() error = %v", err)
+	}
+	if ctx.Err() == nil {
+		t.Error("the dispatcher wasn't canceled")
+	}
+	if p.ctx.Err() == nil {
+		t.Error("the price feed wasn't stopped")
+	}
+	if msg := <-logStream; !strings.Contains(msg, "trading halted: rug") {
+		t.Errorf("log = %q, want the halt reason", msg)
+	}
+	if len(dispatchers) != 0 || len(priceFeeds) != 0 {
+		t.Error("stopped dispatchers and price feeds should be unregistered")
+	}
+}
+
+func TestStopAllSellChecksGuards(t *testing.T) {
+	token0 := database.NewToken("0x0000000000000000000000000000000000000001", "T0", 18, false, nil)
+	token1 := database.NewToken("0x0000000000000000000000000000000000000002", "T1", 18, false, nil)
+	uniToken0, _ := token0.ToUniswap("")
+	uniToken1, _ := token1.ToUniswap("")
+	a, _ := uniswap.NewTokenAmount(uniToken0, big.NewInt(1e18))
+	b, _ := uniswap.NewTokenAmount(uniToken1, big.NewInt(1e18))
+	pair, err := uniswap.NewPair(common.HexToAddress("0x0000000000000000000000000000000000000010"), a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dex := database.NewDex("dex", "0x0000000000000000000000000000000000000020", "", 9970, false)
+	sell, err := bestXTrade(uniToken1, uniToken0, big.NewInt(1e15), []*uniswap.Pair{pair}, 3, dex.GetFeeBigInt())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(previous database.TargetTypes) { database.DefaultTargetTypes = previous }(database.DefaultTargetTypes)
+	database.DefaultTargetTypes = database.TargetTypes{{Type: "buy"}, {Type: "sell"}}
+
+	trade := &database.Trade{Token0: token0, Token1: token1, Dex: dex, Network: &database.Network{}}
+	trade.AmountInTrade().Add(trade.AmountInTrade(), big.NewInt(1e15))
+	p := NewPrice()
+	p.sellQuote = SingleDexQuote(dex, sell)
+
+	tests := []struct {
+		name   string
+		guards *config.Guards
+		err    error
+	}{
+		{name: "router", guards: &config.Guards{Routers: []string{"other"}, MaxDailyAmount: 1}, err: ErrGuardRouter},
+		{name: "token", guards: &config.Guards{Tokens: []string{"T0"}}, err: ErrGuardToken},
+		{name: "slippage", guards: &config.Guards{MaxSlippage: 0.001}, err: ErrGuardSlippage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, cancel := context.WithCancel(context.Background())
+			logStream := make(chan string, 2)
+			registerDispatcher(&dispatcher{client: new(Client), cancel: cancel, trade: trade, price: p, logStream: logStream, guards: tt.guards})
+
+			if err := stopAll("rug", true); !errors.Is(err, tt.err) {
+				t.Fatalf("stopAll() error = %v, want %v", err, tt.err)
+			}
+			<-logStream
+			if msg := <-logStream; !strings.Contains(msg, "could not sell the open position") {
+				t.Errorf("log = %q, want the rejected sell", msg)
+			}
+		})
+	}
+}
+
+func TestHaltGuards(t *testing.T) {
+	if haltGuards(nil) != nil {
+		t.Error("expected no guards")
+	}
+	g := &config.Guards{MaxDailyAmount: 1, MaxTradeAmount: 2}
+	halt := haltGuards(g)
+	if halt.MaxDailyAmount != 0 || halt.MaxTradeAmount != 2 {
+		t.Errorf("haltGuards() = %+v, want only the daily amount removed", halt)
+	}
+	if g.MaxDailyAmount != 1 {
+		t.Error("the guards of the dispatcher must not change")
+	}
+}
@@ -2,14 +2,18 @@ package blockchain
 
 import (
 	"context"
+	"errors"
 	"math/big"
 
+	"github.com/jon4hz/deadshot/internal/database"
 	"github.com/jon4hz/deadshot/internal/logging"
 
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/sirupsen/logrus"
 )
 
+var ErrNoEndpoint = errors.New("could not connect to any endpoint")
+
 // GetChainID returns the chain id of the given node url.
 func GetChainID(url string) (*big.Int, error) {
 	client, err := ethclient.Dial(url)
@@ -62,3 +66,33 @@ func ValidateEndpointURL(url string, chainID uint32) bool {
 	}
 	return true
 }
+
+// Connect returns a client for the first endpoint of the network which serves the right chain.
+// A custom endpoint is always preferred.
+func Connect(network *database.Network) (*Client, error) {
+	for _, url := range endpointURLs(network) {
+		if !ValidateEndpointURL(url, network.GetChainID()) {
+			continue
+		}
+		client, err := NewClient(url, network.GetMulticall())
+		if err != nil {
+			logging.Log.WithFields(logrus.Fields{
+				"error": err,
+				"url":   url,
+			}).Warn("failed to connect to endpoint")
+			continue
+		}
+		client.setNetwork(network)
+		return client, nil
+	}
+	return nil, ErrNoEndpoint
+}
+
+// endpointURLs returns the urls of the endpoints which may be used for the network.
+// A custom endpoint replaces all other endpoints.
+func endpointURLs(network *database.Network) []string {
+	if custom, ok := network.GetCustomEndpoint(); ok {
+		return []string{custom.GetURL()}
+	}
+	return network.GetEndpoints().GetUrls()
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/jon4hz/geth-multicall/multicall"
 )
 
@@ -69,3 +70,32 @@ func GetErc20BalanceOf(contract string, res *multicall.Result) (*big.Int, error)
 	}
 	return balance, nil
 }
+
+// GetErc20BalanceOfRaw returns the balance of a contract from the raw result.
+// It reports false if the call failed, e.g. because the contract isn't an erc20 token.
+func GetErc20BalanceOfRaw(contract string, res *multicall.Result) (*big.Int, bool) {
+	call, ok := res.Calls[erc20BalanceOf.getID(contract)]
+	if !ok || !call.Success || len(call.Raw) < common.HashLength {
+		return nil, false
+	}
+	return new(big.Int).SetBytes(call.Raw[:common.HashLength]), true
+}
+
+// GetErc20AllowanceCall is a multicall.ViewCall for getting the allowance of a spender for an erc20 token.
+func GetErc20AllowanceCall(contract, owner, spender string) multicall.ViewCall {
+	return multicall.NewViewCall(
+		erc20Allowance.getAllowanceID(contract, spender),
+		contract,
+		"allowance(address,address)(uint256)",
+		[]any{owner, spender},
+	)
+}
+
+// GetErc20AllowanceRaw returns the allowance of a spender from the raw result.
+func GetErc20AllowanceRaw(contract, spender string, res *multicall.Result) (*big.Int, bool) {
+	call, ok := res.Calls[erc20Allowance.getAllowanceID(contract, spender)]
+	if !ok || !call.Success || len(call.Raw) < common.HashLength {
+		return nil, false
+	}
+	return new(big.Int).SetBytes(call.Raw[:common.HashLength]), true
+}
@@ -0,0 +1,40 @@
+package calls
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jon4hz/geth-multicall/multicall"
+)
+
+var ErrGettingEthBalance = errors.New("error getting native balance")
+
+// GetEthBalanceCall is a multicall.ViewCall for getting the native balance of an address.
+// The call is made to the multicall contract itself.
+func GetEthBalanceCall(multicallContract, address string) multicall.ViewCall {
+	return multicall.NewViewCall(
+		ethBalance.getID(address),
+		multicallContract,
+		"getEthBalance(address)(uint256)",
+		[]any{address},
+	)
+}
+
+// GetEthBalance returns the native balance of an address.
+func GetEthBalance(address string, res *multicall.Result) (*big.Int, error) {
+	balance, ok := res.Calls[ethBalance.getID(address)].Decoded[0].(*big.Int)
+	if !ok {
+		return nil, ErrGettingEthBalance
+	}
+	return balance, nil
+}
+
+// GetEthBalanceRaw returns the native balance of an address from the raw result.
+func GetEthBalanceRaw(address string, res *multicall.Result) (*big.Int, bool) {
+	call, ok := res.Calls[ethBalance.getID(address)]
+	if !ok || !call.Success || len(call.Raw) < common.HashLength {
+		return nil, false
+	}
+	return new(big.Int).SetBytes(call.Raw[:common.HashLength]), true
+}
@@ -16,6 +16,11 @@ const (
 	pairTotalSupply
 	pairToken0
 	pairToken1
+	pairStable
+	solidlyPairToken
+	factoryFee
+	ethBalance
+	erc20Allowance
 )
 
 func (i id) getID(contract string) string {
@@ -29,3 +34,15 @@ func (i id) getPairTokenInfoID(contract string, tokenX uint8) string {
 func (i id) getPairTokenID(token0, token1 string) string {
 	return fmt.Sprintf("%d_%s_%s", i, token0, token1)
 }
+
+func (i id) getSolidlyPairTokenID(token0, token1 string, stable bool) string {
+	return fmt.Sprintf("%d_%s_%s_%t", i, token0, token1, stable)
+}
+
+func (i id) getAllowanceID(contract, spender string) string {
+	return fmt.Sprintf("%d_%s_%s", i, contract, spender)
+}
+
+func (i id) getFactoryFeeID(factory string, stable bool) string {
+	return fmt.Sprintf("%d_%s_%t", i, factory, stable)
+}
@@ -14,6 +14,7 @@ var (
 	ErrGettingPairToken0      = errors.New("error getting pair token0")
 	ErrGettingPairToken1      = errors.New("error getting pair token1")
 	ErrGettingPairToken       = errors.New("error getting pair token")
+	ErrGettingPairStable      = errors.New("error getting pair stable")
 )
 
 // GetPairReservesCall is a multicall.Viewcall to get the pair reserves.
@@ -28,6 +29,9 @@ func GetPairReservesCall(contract string) multicall.ViewCall {
 
 // GetPairReserves returns the pair reserves.
 func GetPairReserves(contract string, res *multicall.Result) (*big.Int, *big.Int, error) {
+	if len(res.Calls[pairReserves.getID(contract)].Decoded) < 2 {
+		return nil, nil, ErrGettingPairReserves
+	}
 	reserve0, ok := res.Calls[pairReserves.getID(contract)].Decoded[0].(*big.Int)
 	if !ok {
 		return nil, nil, ErrGettingPairReserves
@@ -39,6 +43,16 @@ func GetPairReserves(contract string, res *multicall.Result) (*big.Int, *big.Int
 	return reserve0, reserve1, nil
 }
 
+// GetPairReservesRaw returns the pair reserves from an undecoded result.
+// It returns false if the call failed.
+func GetPairReservesRaw(contract string, res *multicall.Result) (*big.Int, *big.Int, bool) {
+	call, ok := res.Calls[pairReserves.getID(contract)]
+	if !ok || !call.Success || len(call.Raw) < 2*common.HashLength {
+		return nil, nil, false
+	}
+	return new(big.Int).SetBytes(call.Raw[:common.HashLength]), new(big.Int).SetBytes(call.Raw[common.HashLength : 2*common.HashLength]), true
+}
+
 // GetPairTotalSupplyCall is a multicall.Viewcall to get the pair total supply.
 func GetPairTotalSupplyCall(contract string) multicall.ViewCall {
 	return multicall.NewViewCall(
@@ -77,6 +91,16 @@ func GetPairToken0(contract string, res *multicall.Result) (common.Address, erro
 	return token0, nil
 }
 
+// GetPairToken0Raw returns the pair token0 from an undecoded result.
+// It returns false if the call failed, e.g. because no contract exists at the address.
+func GetPairToken0Raw(contract string, res *multicall.Result) (common.Address, bool) {
+	call, ok := res.Calls[pairToken0.getID(contract)]
+	if !ok || !call.Success || len(call.Raw) != common.HashLength {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(call.Raw), true
+}
+
 // GetPairToken1Call is a multicall.Viewcall to get the pair token1.
 func GetPairToken1Call(contract string) multicall.ViewCall {
 	return multicall.NewViewCall(
@@ -178,3 +202,62 @@ func GetPairToken(token0, token1, factory string, res *multicall.Result) (common
 	}
 	return pair, nil
 }
+
+// GetPairStableCall is a multicall.Viewcall to get whether a solidly pair is stable.
+func GetPairStableCall(contract string) multicall.ViewCall {
+	return multicall.NewViewCall(
+		pairStable.getID(contract),
+		contract,
+		"stable()(bool)",
+		[]any{},
+	)
+}
+
+// GetPairStableRaw returns whether a solidly pair is stable from an undecoded result.
+// It returns false for ok if the call failed.
+func GetPairStableRaw(contract string, res *multicall.Result) (stable, ok bool) {
+	call, found := res.Calls[pairStable.getID(contract)]
+	if !found || !call.Success || len(call.Raw) != common.HashLength {
+		return false, false
+	}
+	return call.Raw[common.HashLength-1] == 1, true
+}
+
+// GetSolidlyPairTokenCall is a multicall.Viewcall to get the pair token of a solidly factory.
+func GetSolidlyPairTokenCall(token0, token1, factory string, stable bool) multicall.ViewCall {
+	return multicall.NewViewCall(
+		solidlyPairToken.getSolidlyPairTokenID(token0, token1, stable),
+		factory,
+		"getPair(address,address,bool)(address)",
+		[]any{token0, token1, stable},
+	)
+}
+
+// GetSolidlyPairToken returns the pair token of a solidly factory.
+func GetSolidlyPairToken(token0, token1 string, stable bool, res *multicall.Result) (common.Address, error) {
+	pair, ok := res.Calls[solidlyPairToken.getSolidlyPairTokenID(token0, token1, stable)].Decoded[0].(common.Address)
+	if !ok {
+		return common.Address{}, ErrGettingPairToken
+	}
+	return pair, nil
+}
+
+// GetFactoryFeeCall is a multicall.Viewcall to get the fee of the volatile or stable pairs of a solidly factory.
+func GetFactoryFeeCall(factory string, stable bool) multicall.ViewCall {
+	return multicall.NewViewCall(
+		factoryFee.getFactoryFeeID(factory, stable),
+		factory,
+		"getFee(bool)(uint256)",
+		[]any{stable},
+	)
+}
+
+// GetFactoryFeeRaw returns the fee of a solidly factory in basis points from an undecoded result.
+// It returns false if the call failed, e.g. because the factory has a fixed fee.
+func GetFactoryFeeRaw(factory string, stable bool, res *multicall.Result) (*big.Int, bool) {
+	call, ok := res.Calls[factoryFee.getFactoryFeeID(factory, stable)]
+	if !ok || !call.Success || len(call.Raw) != common.HashLength {
+		return nil, false
+	}
+	return new(big.Int).SetBytes(call.Raw), true
+}
@@ -1,6 +1,8 @@
 package multicall
 
 import (
+	"math/big"
+
 	"github.com/jon4hz/deadshot/internal/blockchain/multicall/calls"
 	"github.com/jon4hz/deadshot/pkg/ethutils"
 
@@ -119,6 +121,103 @@ func (c *Client) GetPairInfo(contracts []string) (map[string]*Pair, error) {
 	return info, nil
 }
 
+// GetPairReserves returns a map of pair addresses with their reserves as values.
+// The fees of solidly factories are read in the same multicall, missing fees mean the factory has no getFee function.
+func (c *Client) GetPairReserves(contracts []string, fees []FeeCall) (map[string]*Pair, map[FeeCall]*big.Int, error) {
+	vcs := make(multicall.ViewCalls, 0, len(contracts)+len(fees))
+	for _, contract := range contracts {
+		vcs = append(vcs, calls.GetPairReservesCall(contract))
+	}
+	for _, fee := range fees {
+		vcs = append(vcs, calls.GetFactoryFeeCall(fee.Factory, fee.Stable))
+	}
+
+	res, err := c.callRaw(vcs, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := make(map[string]*Pair)
+	for _, contract := range contracts {
+		reserve0, reserve1, ok := calls.GetPairReservesRaw(contract, res)
+		if !ok {
+			return nil, nil, calls.ErrGettingPairReserves
+		}
+		info[contract] = &Pair{
+			Reserve0: reserve0,
+			Reserve1: reserve1,
+		}
+	}
+	factoryFees := make(map[FeeCall]*big.Int)
+	for _, fee := range fees {
+		if v, ok := calls.GetFactoryFeeRaw(fee.Factory, fee.Stable, res); ok {
+			factoryFees[fee] = v
+		}
+	}
+	return info, factoryFees, nil
+}
+
+// CheckPairs returns a map of pair addresses with their token0 and stable flag as values.
+// The stable flag is only read for the contracts of solidly pairs.
+// Addresses without a pair contract are not included.
+func (c *Client) CheckPairs(contracts []string, solidly map[string]bool) (map[string]PairCheck, error) {
+	vcs := make(multicall.ViewCalls, 0, len(contracts))
+	for _, contract := range contracts {
+		vcs = append(vcs, calls.GetPairToken0Call(contract))
+		if solidly[contract] {
+			vcs = append(vcs, calls.GetPairStableCall(contract))
+		}
+	}
+
+	res, err := c.callRaw(vcs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	checks := make(map[string]PairCheck)
+	for _, contract := range contracts {
+		token0, ok := calls.GetPairToken0Raw(contract, res)
+		if !ok {
+			continue
+		}
+		check := PairCheck{Token0: token0}
+		if solidly[contract] {
+			if check.Stable, ok = calls.GetPairStableRaw(contract, res); !ok {
+				continue
+			}
+		}
+		checks[contract] = check
+	}
+	return checks, nil
+}
+
+// GetSolidlyPairToken returns the liquidity tokens of the volatile or stable pairs of a solidly factory.
+// Zero addresses are not included.
+func (c *Client) GetSolidlyPairToken(tokenPairs []TokenPair, stable bool) (map[TokenPair]common.Address, error) {
+	vcs := make(multicall.ViewCalls, len(tokenPairs))
+	for i, pair := range tokenPairs {
+		vcs[i] = calls.GetSolidlyPairTokenCall(pair.Token0, pair.Token1, pair.Factory, stable)
+	}
+
+	res, err := c.call(vcs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := make(map[TokenPair]common.Address)
+	for _, pair := range tokenPairs {
+		addr, err := calls.GetSolidlyPairToken(pair.Token0, pair.Token1, stable, res)
+		if err != nil {
+			return nil, err
+		}
+		if ethutils.IsZeroAddress(addr) {
+			continue
+		}
+		pairs[pair] = addr
+	}
+	return pairs, nil
+}
+
 // GetPairToken returns a slice of liquidity tokens
 // zero addresses are not included.
 func (c *Client) GetPairToken(tokenPairs []TokenPair) (map[TokenPair]common.Address, error) {
@@ -146,3 +245,82 @@ func (c *Client) GetPairToken(tokenPairs []TokenPair) (map[TokenPair]common.Addr
 	}
 	return pairs, nil
 }
+
+// GetNativeBalances returns a map of addresses with their native balance as values.
+func (c *Client) GetNativeBalances(addresses []string) (map[string]*big.Int, error) {
+	vcs := make(multicall.ViewCalls, len(addresses))
+	for i, address := range addresses {
+		vcs[i] = calls.GetEthBalanceCall(c.Contract(), address)
+	}
+
+	res, err := c.call(vcs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]*big.Int, len(addresses))
+	for _, address := range addresses {
+		balances[address], err = calls.GetEthBalance(address, res)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return balances, nil
+}
+
+// GetTokenBalances returns a map of token contracts with the balance of the address as values.
+// The native balance is returned for the zero address. Tokens whose call failed are not included.
+func (c *Client) GetTokenBalances(address string, contracts []string) (map[string]*big.Int, error) {
+	vcs := make(multicall.ViewCalls, len(contracts))
+	for i, contract := range contracts {
+		if ethutils.IsZeroAddress(contract) {
+			vcs[i] = calls.GetEthBalanceCall(c.Contract(), address)
+			continue
+		}
+		vcs[i] = calls.GetErc20BalanceOfCall(contract, address)
+	}
+
+	res, err := c.callRaw(vcs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]*big.Int, len(contracts))
+	for _, contract := range contracts {
+		var (
+			balance *big.Int
+			ok      bool
+		)
+		if ethutils.IsZeroAddress(contract) {
+			balance, ok = calls.GetEthBalanceRaw(address, res)
+		} else {
+			balance, ok = calls.GetErc20BalanceOfRaw(contract, res)
+		}
+		if ok {
+			balances[contract] = balance
+		}
+	}
+	return balances, nil
+}
+
+// GetAllowances returns a map of token and spender pairs with the allowance of the owner as values.
+// Allowances whose call failed are not included.
+func (c *Client) GetAllowances(owner string, allowances []AllowanceCall) (map[AllowanceCall]*big.Int, error) {
+	vcs := make(multicall.ViewCalls, len(allowances))
+	for i, a := range allowances {
+		vcs[i] = calls.GetErc20AllowanceCall(a.Token, owner, a.Spender)
+	}
+
+	res, err := c.callRaw(vcs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[AllowanceCall]*big.Int, len(allowances))
+	for _, a := range allowances {
+		if v, ok := calls.GetErc20AllowanceRaw(a.Token, a.Spender, res); ok {
+			values[a] = v
+		}
+	}
+	return values, nil
+}
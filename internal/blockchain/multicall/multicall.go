@@ -52,3 +52,20 @@ func (c *Client) call(calls multicall.ViewCalls, opts *CallOpts) (*multicall.Res
 	}
 	return res, err
 }
+
+// callRaw executes a web3 multicall without decoding the results.
+// Use it if some of the calls might fail.
+func (c *Client) callRaw(calls multicall.ViewCalls, opts *CallOpts) (*multicall.Result, error) { //nolint:unparam
+	b := "latest"
+	if opts != nil && opts.Block != "" {
+		b = opts.Block
+	}
+
+	res, err := c.CallRaw(calls, b)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error": err,
+		}).Error("Error executing raw web3 multicall")
+	}
+	return res, err
+}
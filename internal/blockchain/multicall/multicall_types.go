@@ -4,6 +4,8 @@ import (
 	"math/big"
 
 	"github.com/jon4hz/deadshot/internal/database"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 type Token struct {
@@ -27,6 +29,24 @@ type Pair struct {
 	Token1      Token
 }
 
+// PairCheck is the on-chain state of a pair contract, which is needed to cache the pair.
+type PairCheck struct {
+	Token0 common.Address
+	Stable bool
+}
+
+// FeeCall reads the fee of the volatile or stable pairs of a solidly factory.
+type FeeCall struct {
+	Factory string
+	Stable  bool
+}
+
+// AllowanceCall reads the allowance of a spender for a token.
+type AllowanceCall struct {
+	Token   string
+	Spender string
+}
+
 type TokenPair struct {
 	Factory string
 	Token0  string
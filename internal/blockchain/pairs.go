@@ -0,0 +1,299 @@
+package blockchain
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jon4hz/deadshot/internal/blockchain/multicall"
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/pkg/ethutils"
+	"github.com/jon4hz/deadshot/pkg/uniswap"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+)
+
+// pairRecheckInterval is the time after which a pair that didn't exist is looked up again.
+const pairRecheckInterval = time.Hour
+
+// pairCache caches the pairs of every dex by factory.
+// The pairs of a dex are loaded from the database the first time the dex is used.
+type pairCache struct {
+	// mu is held during the whole lookup, so missing pairs are only checked once.
+	mu    sync.Mutex
+	dexes map[string]map[string]*database.Pair
+}
+
+// pairKey returns the cache key of the pair of two sorted token contracts.
+func pairKey(token0, token1 string, stable bool) string {
+	if stable {
+		return token0 + "_" + token1 + "_stable"
+	}
+	return token0 + "_" + token1
+}
+
+// pairKinds returns the stable flags of the pairs a dex can have for two tokens.
+// Solidly dexes have a volatile and a stable pair for every token combination.
+func pairKinds(dex *database.Dex) []bool {
+	if dex.IsSolidly() {
+		return []bool{false, true}
+	}
+	return []bool{false}
+}
+
+// sortTokens sorts two lowercase token contracts like the pair contract does.
+func sortTokens(tokenA, tokenB string) (string, string) {
+	if tokenA < tokenB {
+		return tokenA, tokenB
+	}
+	return tokenB, tokenA
+}
+
+// dex returns the cached pairs of the dex. The caller must hold the lock.
+func (pc *pairCache) dex(dex *database.Dex) map[string]*database.Pair {
+	factory := strings.ToLower(dex.GetFactory())
+	if pairs, ok := pc.dexes[factory]; ok {
+		return pairs
+	}
+	pairs := make(map[string]*database.Pair)
+	// dexes which aren't stored in the database are only cached in memory
+	if id := dex.GetID(); id != 0 {
+		cached, err := database.FindPairsByDexID(id)
+		if err != nil {
+			logging.Log.WithFields(logrus.Fields{
+				"error": err,
+				"dex":   dex.GetName(),
+			}).Warn("failed to load cached pairs")
+		}
+		for _, p := range cached {
+			pairs[pairKey(p.GetToken0(), p.GetToken1(), p.GetStable())] = p
+		}
+	}
+	if pc.dexes == nil {
+		pc.dexes = make(map[string]map[string]*database.Pair)
+	}
+	pc.dexes[factory] = pairs
+	return pairs
+}
+
+// getPairs returns the existing pairs of every dex for all combinations of the token contracts.
+// Unknown pairs are derived with CREATE2 and checked on-chain once, afterwards they are served from the cache.
+// A dex whose unknown pairs can't be checked only returns its cached pairs, an error is only returned if every dex failed.
+func (c *Client) getPairs(dexes []*database.Dex, contracts []string) (map[*database.Dex][]*database.Pair, error) {
+	c.pairs.mu.Lock()
+	defer c.pairs.mu.Unlock()
+
+	var (
+		pairs   = make(map[*database.Dex][]*database.Pair, len(dexes))
+		missing = make(map[*database.Dex][]*database.Pair)
+	)
+	for _, dex := range dexes {
+		cached := c.pairs.dex(dex)
+		for i := 0; i < len(contracts); i++ {
+			for j := i + 1; j < len(contracts); j++ {
+				token0, token1 := sortTokens(contracts[i], contracts[j])
+				for _, stable := range pairKinds(dex) {
+					key := pairKey(token0, token1, stable)
+					p, ok := cached[key]
+					if !ok {
+						p = &database.Pair{DexID: dex.GetID(), Token0: token0, Token1: token1, Stable: stable}
+						cached[key] = p
+					}
+					if p.GetExists() {
+						pairs[dex] = append(pairs[dex], p)
+						continue
+					}
+					if time.Since(p.GetCheckedAt()) > pairRecheckInterval {
+						missing[dex] = append(missing[dex], p)
+					}
+				}
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return pairs, nil
+	}
+
+	failed := c.checkPairs(missing)
+	for dex, err := range failed {
+		if len(failed) == len(dexes) {
+			return nil, err
+		}
+		logging.Log.WithFields(logrus.Fields{
+			"error": err,
+			"dex":   dex.GetName(),
+		}).Warn("skipping dex, failed to check its pairs")
+	}
+
+	var save []*database.Pair
+	for _, dex := range dexes {
+		if _, ok := failed[dex]; ok {
+			continue
+		}
+		for _, p := range missing[dex] {
+			if p.GetExists() {
+				pairs[dex] = append(pairs[dex], p)
+			}
+			if p.GetDexID() != 0 {
+				save = append(save, p)
+			}
+		}
+	}
+	if err := database.SavePairs(save); err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error": err,
+		}).Warn("failed to save pairs")
+	}
+	return pairs, nil
+}
+
+// checkPairs looks up the address and existence of the pairs.
+// The addresses of dexes with an init code hash are derived locally and only their existence is checked on-chain,
+// all other pairs are looked up through the factory. It returns the error of every dex whose pairs couldn't be checked.
+func (c *Client) checkPairs(missing map[*database.Dex][]*database.Pair) map[*database.Dex]error {
+	var (
+		derived      []string
+		derivedDexes []*database.Dex
+		solidly      = make(map[string]bool)
+		byAddress    = make(map[string]*database.Pair)
+		failed       = make(map[*database.Dex]error)
+	)
+	for dex, pairs := range missing {
+		initCodeHash := dex.GetInitCodeHash()
+		if initCodeHash == "" {
+			if err := c.checkPairsByFactory(dex, pairs); err != nil {
+				failed[dex] = err
+			}
+			continue
+		}
+		addrs := make([]string, len(pairs))
+		for i, p := range pairs {
+			addr, err := derivePairAddress(dex.GetFactory(), p.GetToken0(), p.GetToken1(), dex.IsSolidly(), p.GetStable(), initCodeHash)
+			if err != nil {
+				failed[dex] = err
+				break
+			}
+			addrs[i] = addr
+		}
+		if _, ok := failed[dex]; ok {
+			continue
+		}
+		for i, addr := range addrs {
+			derived = append(derived, addr)
+			solidly[addr] = dex.IsSolidly()
+			byAddress[addr] = pairs[i]
+		}
+		derivedDexes = append(derivedDexes, dex)
+	}
+	if len(derived) == 0 {
+		return failed
+	}
+
+	checks, err := c.getMulticall().CheckPairs(derived, solidly)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error": err,
+		}).Error("failed to check derived pairs")
+		for _, dex := range derivedDexes {
+			failed[dex] = err
+		}
+		return failed
+	}
+	for addr, p := range byAddress {
+		check, ok := checks[addr]
+		p.SetChecked(addr, ok && strings.EqualFold(check.Token0.Hex(), p.GetToken0()) && check.Stable == p.GetStable())
+	}
+	return failed
+}
+
+// checkPairsByFactory looks up the pairs through the getPair function of the factory.
+func (c *Client) checkPairsByFactory(dex *database.Dex, pairs []*database.Pair) error {
+	for _, stable := range pairKinds(dex) {
+		var (
+			kind       []*database.Pair
+			tokenPairs []multicall.TokenPair
+		)
+		for _, p := range pairs {
+			if p.GetStable() == stable {
+				kind = append(kind, p)
+				tokenPairs = append(tokenPairs, *multicall.NewTokenPair(p.GetToken0(), p.GetToken1(), dex.GetFactory()))
+			}
+		}
+		if len(kind) == 0 {
+			continue
+		}
+
+		var (
+			addrs map[multicall.TokenPair]common.Address
+			err   error
+		)
+		if dex.IsSolidly() {
+			addrs, err = c.getMulticall().GetSolidlyPairToken(tokenPairs, stable)
+		} else {
+			addrs, err = c.getMulticall().GetPairToken(tokenPairs)
+		}
+		if err != nil {
+			logging.Log.WithFields(logrus.Fields{
+				"error":   err,
+				"factory": dex.GetFactory(),
+			}).Error("failed to get pairs from factory")
+			return err
+		}
+		for i, p := range kind {
+			addr, ok := addrs[tokenPairs[i]]
+			p.SetChecked(addr.Hex(), ok)
+		}
+	}
+	return nil
+}
+
+// derivePairAddress computes the pair address of two tokens with CREATE2.
+// The salt of solidly pairs contains the stable flag.
+func derivePairAddress(factory, token0, token1 string, solidly, stable bool, initCodeHash string) (string, error) {
+	t0, err := uniswap.NewToken(common.HexToAddress(token0), "", "", 0)
+	if err != nil {
+		return "", err
+	}
+	t1, err := uniswap.NewToken(common.HexToAddress(token1), "", "", 0)
+	if err != nil {
+		return "", err
+	}
+	var addr common.Address
+	if solidly {
+		addr, err = uniswap.GetSolidlyAddress(common.HexToAddress(factory), t0, t1, stable, common.HexToHash(initCodeHash))
+	} else {
+		addr, err = uniswap.GetAddress(common.HexToAddress(factory), t0, t1, common.HexToHash(initCodeHash))
+	}
+	if err != nil {
+		return "", err
+	}
+	return addr.Hex(), nil
+}
+
+// pairTokens converts the tokens to uniswap tokens by lowercase contract.
+// Native tokens are traded through their wrapped token weth, the contracts are returned in the given order.
+func pairTokens(tokens []*database.Token, weth string) (map[string]*uniswap.Token, []string, error) {
+	uniTokens := make(map[string]*uniswap.Token, len(tokens))
+	contracts := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		contract := strings.ToLower(token.GetContract())
+		if token.GetNative() {
+			contract = strings.ToLower(weth)
+		}
+		if ethutils.IsZeroAddress(contract) {
+			continue
+		}
+		if _, ok := uniTokens[contract]; ok {
+			continue
+		}
+		t, err := token.ToUniswap(weth)
+		if err != nil {
+			return nil, nil, err
+		}
+		uniTokens[contract] = t
+		contracts = append(contracts, contract)
+	}
+	return uniTokens, contracts, nil
+}
@@ -0,0 +1,94 @@
+package blockchain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jon4hz/deadshot/internal/database"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestDerivePairAddress(t *testing.T) {
+	addr, err := derivePairAddress(
+		"0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f",
+		strings.ToLower("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+		strings.ToLower("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"),
+		false, false,
+		"0x96e8ac4277198ff8b6f785478aa9a39f403cb768dd02cbee326c3e7da348845f",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr != "0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc" {
+		t.Errorf("expected 0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc, got %s", addr)
+	}
+}
+
+func TestGetPairsCached(t *testing.T) {
+	native := database.NewToken("0x0000000000000000000000000000000000000000", "ETH", 18, true, nil)
+	tokenA := database.NewToken("0x0000000000000000000000000000000000000002", "A", 18, false, nil)
+	tokenB := database.NewToken("0x0000000000000000000000000000000000000001", "B", 18, false, nil)
+	tokenC := database.NewToken("0x0000000000000000000000000000000000000003", "C", 18, false, nil)
+	weth := "0x0000000000000000000000000000000000000004"
+	uniTokens, contracts, err := pairTokens([]*database.Token{native, tokenA, tokenB, tokenA, tokenC}, weth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the native token is replaced by weth
+	if len(contracts) != 4 || contracts[0] != weth {
+		t.Fatalf("expected weth and 3 contracts, got %v", contracts)
+	}
+	if uniTokens[weth].Address() != common.HexToAddress(weth).String() {
+		t.Errorf("expected the native token at %s, got %s", weth, uniTokens[weth].Address())
+	}
+	contracts = contracts[1:]
+
+	dex := database.NewDex("a", "", "0x0000000000000000000000000000000000000010", 9970, false)
+	c := new(Client)
+	cached := c.pairs.dex(dex)
+	ab := database.NewPair(0, contracts[1], contracts[0], false, "0x0000000000000000000000000000000000000020", true)
+	cached[pairKey(ab.GetToken0(), ab.GetToken1(), false)] = ab
+	for _, p := range []*database.Pair{
+		database.NewPair(0, contracts[0], contracts[2], false, "", false),
+		database.NewPair(0, contracts[1], contracts[2], false, "", false),
+	} {
+		cached[pairKey(p.GetToken0(), p.GetToken1(), false)] = p
+	}
+
+	// every pair is cached, so no multicall is needed
+	pairs, err := c.getPairs([]*database.Dex{dex}, contracts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs[dex]) != 1 || pairs[dex][0] != ab {
+		t.Errorf("expected only the existing pair, got %v", pairs[dex])
+	}
+}
+
+func TestGetPairsSkipsFailedDex(t *testing.T) {
+	// nothing listens on the endpoint, so every lookup fails
+	c, err := NewClient("http://127.0.0.1:1", "0x0000000000000000000000000000000000000030")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	contracts := []string{"0x0000000000000000000000000000000000000001", "0x0000000000000000000000000000000000000002"}
+
+	cachedDex := database.NewDex("cached", "", "0x0000000000000000000000000000000000000010", 9970, false)
+	ab := database.NewPair(0, contracts[0], contracts[1], false, "0x0000000000000000000000000000000000000020", true)
+	c.pairs.dex(cachedDex)[pairKey(ab.GetToken0(), ab.GetToken1(), false)] = ab
+	failingDex := database.NewDex("failing", "", "0x0000000000000000000000000000000000000011", 9970, false)
+
+	pairs, err := c.getPairs([]*database.Dex{cachedDex, failingDex}, contracts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs[cachedDex]) != 1 || len(pairs[failingDex]) != 0 {
+		t.Errorf("expected only the cached pair, got %v", pairs)
+	}
+
+	if _, err := c.getPairs([]*database.Dex{failingDex}, contracts); err == nil {
+		t.Error("expected an error if every dex failed")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/ratelimit"
 	"github.com/jon4hz/deadshot/pkg/ethutils"
 	"github.com/jon4hz/deadshot/pkg/uniswap"
 
@@ -32,14 +33,19 @@ type Price struct {
 	feedRunning      bool
 	buyTrade         *uniswap.Trade
 	sellTrade        *uniswap.Trade
+	buyQuote         *DexQuote
+	sellQuote        *DexQuote
 	buyAmount        *big.Int
 	sellAmount       *big.Int
+	client           *Client
+	interval         time.Duration
+	intervalChanged  chan struct{}
 	mu               sync.Mutex
 }
 
 type PriceResult struct {
-	buyTrade  *uniswap.Trade
-	sellTrade *uniswap.Trade
+	buyQuote  *DexQuote
+	sellQuote *DexQuote
 	err       error
 }
 
@@ -52,9 +58,10 @@ func NewPrice() *Price {
 
 func NewPriceWithContext(ctx context.Context, cancel context.CancelFunc) *Price {
 	return &Price{
-		ctx:       ctx,
-		cancel:    cancel,
-		Heartbeat: make(chan struct{}, 1),
+		ctx:             ctx,
+		cancel:          cancel,
+		Heartbeat:       make(chan struct{}, 1),
+		intervalChanged: make(chan struct{}, 1),
 	}
 }
 
@@ -98,22 +105,25 @@ func (p *Price) SetPriceResult(r PriceResult) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	var heartbeat bool
-	if r.buyTrade != nil { // this prevents the price from being set to nil
+	buyTrade, sellTrade := r.buyQuote.GetTrade(), r.sellQuote.GetTrade()
+	if buyTrade != nil { // this prevents the price from being set to nil
 		// send a heartbeat if the price has changed and the heartbeat is running
-		if p.buyTrade != nil && !p.buyTrade.ExecutionPrice.Decimal().Equal(r.buyTrade.ExecutionPrice.Decimal()) {
+		if p.buyTrade != nil && !p.buyTrade.ExecutionPrice.Decimal().Equal(buyTrade.ExecutionPrice.Decimal()) {
 			heartbeat = true
-		} else if p.buyTrade == nil && r.buyTrade != nil { // first time the price is set
+		} else if p.buyTrade == nil && buyTrade != nil { // first time the price is set
 			heartbeat = true
 		}
-		p.buyTrade = r.buyTrade
+		p.buyTrade = buyTrade
+		p.buyQuote = r.buyQuote
 	}
-	if r.sellTrade != nil {
-		if p.sellTrade != nil && !p.sellTrade.ExecutionPrice.Decimal().Equal(r.sellTrade.ExecutionPrice.Decimal()) {
+	if sellTrade != nil {
+		if p.sellTrade != nil && !p.sellTrade.ExecutionPrice.Decimal().Equal(sellTrade.ExecutionPrice.Decimal()) {
 			heartbeat = true
-		} else if p.sellTrade == nil && r.sellTrade != nil { // first time the price is set
+		} else if p.sellTrade == nil && sellTrade != nil { // first time the price is set
 			heartbeat = true
 		}
-		p.sellTrade = r.sellTrade
+		p.sellTrade = sellTrade
+		p.sellQuote = r.sellQuote
 	}
 	p.err = r.err
 	if heartbeat && r.err != nil {
@@ -133,6 +143,25 @@ func (p *Price) GetTrades() (*uniswap.Trade, *uniswap.Trade) {
 	return p.buyTrade, p.sellTrade
 }
 
+// GetQuotes returns the buy and sell quotes, including the dex they are routed through.
+func (p *Price) GetQuotes() (*DexQuote, *DexQuote) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.buyQuote, p.sellQuote
+}
+
+func (p *Price) GetBuyQuote() *DexQuote {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.buyQuote
+}
+
+func (p *Price) GetSellQuote() *DexQuote {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.sellQuote
+}
+
 func (p *Price) GetBuyTrade() *uniswap.Trade {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -176,26 +205,32 @@ func (p *Price) SetSellAmount(amount *big.Int) {
 }
 
 // StartFeed starts a new price feed for the given token.
-func (p *Price) StartFeed(c *Client, token0, token1 *database.Token, dex *database.Dex, tokens []*database.Token, interval time.Duration, maxHops int, weth string) {
+// If more than one dex is passed, every fetch quotes all of them and picks the best price.
+// The interval is never shorter than the rate limit of the endpoint allows.
+func (p *Price) StartFeed(c *Client, token0, token1 *database.Token, dexes []*database.Dex, tokens []*database.Token, interval time.Duration, maxHops int, weth string) {
+	p.mu.Lock()
+	p.client, p.interval = c, interval
+	p.mu.Unlock()
 	p.setRunning(true)
-	if interval == 0 {
-		interval = defaultPriceFetchInterval
-	}
+	registerPriceFeed(p)
 
 	go func() {
 		defer func() {
 			p.setRunning(false)
+			unregisterPriceFeed(p)
 		}()
-		ticker := time.NewTicker(interval)
+		ticker := time.NewTicker(p.feedInterval())
 
-		res := p.fetchPrice(c, token0, token1, dex, maxHops, weth, tokens...)
+		res := p.fetchPrice(c, token0, token1, dexes, maxHops, weth, tokens...)
 		p.SetPriceResult(res)
 
 		for {
 			select {
 			case <-ticker.C:
-				res := p.fetchPrice(c, token0, token1, dex, maxHops, weth, tokens...)
+				res := p.fetchPrice(c, token0, token1, dexes, maxHops, weth, tokens...)
 				p.SetPriceResult(res)
+			case <-p.intervalChanged:
+				ticker.Reset(p.feedInterval())
 			case <-p.ctx.Done():
 				ticker.Stop()
 				return
@@ -204,6 +239,30 @@ func (p *Price) StartFeed(c *Client, token0, token1 *database.Token, dex *databa
 	}()
 }
 
+// feedInterval returns the interval of the feed, considering the rate limit of the current endpoint.
+func (p *Price) feedInterval() time.Duration {
+	p.mu.Lock()
+	interval, client := p.interval, p.client
+	p.mu.Unlock()
+	if client != nil {
+		if limit := ratelimit.GetPriceFeedInterval(client.URL()); limit > interval {
+			interval = limit
+		}
+	}
+	if interval == 0 {
+		return defaultPriceFetchInterval
+	}
+	return interval
+}
+
+// updateInterval makes a running feed apply the current rate limit of its endpoint.
+func (p *Price) updateInterval() {
+	select {
+	case p.intervalChanged <- struct{}{}:
+	default:
+	}
+}
+
 func (p *Price) Stop() {
 	defer func() {
 		recover() // prevent panic if the price feed is already stopped
@@ -214,7 +273,7 @@ func (p *Price) Stop() {
 	p.cancel()
 }
 
-func (p *Price) fetchPrice(c *Client, token0, token1 *database.Token, dex *database.Dex, maxHops int, weth string, tokens ...*database.Token) PriceResult {
+func (p *Price) fetchPrice(c *Client, token0, token1 *database.Token, dexes []*database.Dex, maxHops int, weth string, tokens ...*database.Token) PriceResult {
 	buyAmount := p.GetBuyAmount()
 	if buyAmount == nil {
 		buyAmount = ethutils.ToWei(1, token0.GetDecimals())
@@ -223,10 +282,21 @@ func (p *Price) fetchPrice(c *Client, token0, token1 *database.Token, dex *datab
 	if sellAmount == nil {
 		sellAmount = ethutils.ToWei(1, token1.GetDecimals())
 	}
-	buy, sell, err := c.GetBestOrderTrades(token0, token1, buyAmount, sellAmount, dex, tokens, maxHops, weth)
+	if len(dexes) > 1 {
+		buy, sell, err := c.GetBestOrderTradesAllDexes(token0, token1, buyAmount, sellAmount, dexes, tokens, maxHops, weth)
+		return PriceResult{
+			buyQuote:  buy,
+			sellQuote: sell,
+			err:       err,
+		}
+	}
+	if len(dexes) == 0 {
+		return PriceResult{err: ErrNoPairsFound}
+	}
+	buy, sell, err := c.GetBestOrderTrades(token0, token1, buyAmount, sellAmount, dexes[0], tokens, maxHops, weth)
 	return PriceResult{
-		buyTrade:  buy,
-		sellTrade: sell,
+		buyQuote:  SingleDexQuote(dexes[0], buy),
+		sellQuote: SingleDexQuote(dexes[0], sell),
 		err:       err,
 	}
 }
@@ -20,7 +20,7 @@ func TestPriceFeed(t *testing.T) {
 
 	p := NewPrice()
 	interval := time.Millisecond * 200
-	p.StartFeed(c, usdc, weth, &quickswap, nil, interval, 3, "0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270")
+	p.StartFeed(c, usdc, weth, []*database.Dex{&quickswap}, nil, interval, 3, "0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270")
 	p.SetHeartbeat(true)
 	go func() {
 		time.Sleep(time.Second * 10)
@@ -0,0 +1,39 @@
+package blockchain
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/pkg/ethutils"
+
+	"github.com/shopspring/decimal"
+)
+
+// gweiDecimals are the decimals of a gas price in gwei.
+const gweiDecimals = 9
+
+// gasPrice returns the gas price of the target. If the target has none, the gas strategy of the profile is applied.
+// A gas price of nil lets the node suggest the gas price.
+func (c *Client) gasPrice(profile *database.Profile, target *database.Target) (*big.Int, error) {
+	if gasPrice := target.GetGasPrice(); gasPrice != nil || profile == nil {
+		return gasPrice, nil
+	}
+	switch profile.GetGasStrategy() {
+	case database.GasStrategyFixed:
+		return ethutils.ToWei(profile.GetGasPrice(), gweiDecimals), nil
+	case database.GasStrategyBoost:
+		suggested, err := c.Eth().SuggestGasPrice(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		return boostGasPrice(suggested, profile.GetGasBoost()), nil
+	}
+	return nil, nil
+}
+
+// boostGasPrice increases the gas price by boost percent.
+func boostGasPrice(gasPrice *big.Int, boost float64) *big.Int {
+	factor := decimal.NewFromFloat(1 + boost/100)
+	return decimal.NewFromBigInt(gasPrice, 0).Mul(factor).BigInt()
+}
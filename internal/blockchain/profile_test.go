@@ -0,0 +1,39 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/jon4hz/deadshot/internal/database"
+)
+
+func TestBoostGasPrice(t *testing.T) {
+	if got := boostGasPrice(big.NewInt(100_000_000_000), 30); got.Cmp(big.NewInt(130_000_000_000)) != 0 {
+		t.Errorf("expected 130 gwei, got %s", got)
+	}
+	if got := boostGasPrice(big.NewInt(5), 0); got.Cmp(big.NewInt(5)) != 0 {
+		t.Errorf("expected the gas price unchanged, got %s", got)
+	}
+}
+
+func TestGasPriceOfProfile(t *testing.T) {
+	c := new(Client)
+	target := database.NewTargetWithDefaults()
+	// nil lets the node suggest the gas price
+	if gp, err := c.gasPrice(nil, target); err != nil || gp != nil {
+		t.Errorf("expected no gas price, got %v, %v", gp, err)
+	}
+	fixed := &database.Profile{GasStrategy: database.GasStrategyFixed, GasPrice: 5}
+	gp, err := c.gasPrice(fixed, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gp.Cmp(big.NewInt(5_000_000_000)) != 0 {
+		t.Errorf("expected 5 gwei, got %s", gp)
+	}
+	// the gas price of the target overrides the profile
+	target.SetGasPrice(big.NewInt(1))
+	if gp, _ := c.gasPrice(fixed, target); gp.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("expected the gas price of the target, got %s", gp)
+	}
+}
@@ -0,0 +1,168 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/jon4hz/deadshot/internal/config"
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/internal/logstream"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ApplyGuards replaces the guards of the running trade dispatchers.
+// The safety limits of the profile of a trade still apply on top of the new guards.
+func ApplyGuards(g config.Guards) {
+	for _, d := range runningDispatchers() {
+		guards := g.WithProfile(d.trade.GetProfile())
+		if reflect.DeepEqual(guards, d.getGuards()) {
+			continue
+		}
+		d.setGuards(guards)
+		logging.Log.WithField("token1", d.trade.GetToken1().GetContract()).Info("applied the new guards to the trade")
+		d.log(logstream.Format("the guards were updated", logstream.INFO))
+	}
+}
+
+// ApplyRateLimits makes the running price feeds apply the current rate limits of their endpoints.
+func ApplyRateLimits() {
+	for _, p := range runningPriceFeeds() {
+		p.updateInterval()
+	}
+}
+
+// WatchEndpoints polls the endpoints in the database and applies changes to the networks,
+// e.g. if another process added a custom endpoint.
+// The running trade dispatchers and the given clients switch to another endpoint if their endpoint can't be used anymore.
+// This function is blocking and should be run in a goroutine.
+func WatchEndpoints(ctx context.Context, interval time.Duration, networks database.Networks, clients ...*Client) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			endpoints, err := database.FetchEndpoints()
+			if err != nil {
+				logging.Log.WithField("err", err).Error("failed to fetch the endpoints")
+				continue
+			}
+			for _, n := range networks {
+				if updateEndpoints(n, endpoints[n.GetID()]) {
+					switchEndpoints(n, clients)
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// updateEndpoints replaces the endpoints of the network and returns whether they changed.
+func updateEndpoints(n *database.Network, endpoints database.Endpoints) bool {
+	added, removed := endpoints.Diff(n.GetEndpoints())
+	if len(added) == 0 && len(removed) == 0 {
+		return false
+	}
+	logging.Log.WithFields(logrus.Fields{
+		"network": n.GetName(),
+		"added":   added,
+		"removed": removed,
+	}).Info("the endpoints of the network changed")
+	n.SetEndpoints(endpoints)
+	return true
+}
+
+// switchEndpoints moves the clients of the network to another endpoint, if their endpoint can't be used anymore.
+func switchEndpoints(n *database.Network, clients []*Client) {
+	running := runningDispatchers()
+	affected := make(map[*Client][]*dispatcher)
+	for _, c := range clients {
+		if c.Network() != nil && c.Network().GetID() == n.GetID() {
+			affected[c] = nil
+		}
+	}
+	for _, d := range running {
+		if d.trade.GetNetwork().GetID() == n.GetID() {
+			affected[d.client] = append(affected[d.client], d)
+		}
+	}
+
+	urls := endpointURLs(n)
+	for c, dispatchers := range affected {
+		previous := c.URL()
+		if contains(urls, previous) {
+			continue
+		}
+		url, err := switchEndpoint(c, n, urls)
+		if err != nil {
+			logging.Log.WithFields(logrus.Fields{
+				"network":  n.GetName(),
+				"endpoint": previous,
+				"err":      err,
+			}).Error("failed to switch the endpoint")
+			for _, d := range dispatchers {
+				d.log(logstream.Format(fmt.Sprintf("could not switch the endpoint, still using %s: %s", previous, err), logstream.ERR))
+			}
+			continue
+		}
+		logging.Log.WithFields(logrus.Fields{
+			"network":  n.GetName(),
+			"previous": previous,
+			"endpoint": url,
+		}).Info("switched the endpoint")
+		for _, d := range dispatchers {
+			d.log(logstream.Format("switched to the endpoint "+url, logstream.INFO))
+		}
+	}
+	// the rate limit depends on the endpoint
+	ApplyRateLimits()
+}
+
+// switchEndpoint connects the client to the first usable endpoint and returns its url.
+func switchEndpoint(c *Client, n *database.Network, urls []string) (string, error) {
+	for _, url := range urls {
+		if !ValidateEndpointURL(url, n.GetChainID()) {
+			continue
+		}
+		if err := c.SwitchEndpoint(url); err != nil {
+			continue
+		}
+		return url, nil
+	}
+	return "", ErrNoEndpoint
+}
+
+func contains(urls []string, url string) bool {
+	for _, u := range urls {
+		if u == url {
+			return true
+		}
+	}
+	return false
+}
+
+// runningDispatchers returns the registered trade dispatchers.
+func runningDispatchers() []*dispatcher {
+	killSwitchMu.Lock()
+	defer killSwitchMu.Unlock()
+	running := make([]*dispatcher, 0, len(dispatchers))
+	for d := range dispatchers {
+		running = append(running, d)
+	}
+	return running
+}
+
+// runningPriceFeeds returns the registered price feeds.
+func runningPriceFeeds() []*Price {
+	killSwitchMu.Lock()
+	defer killSwitchMu.Unlock()
+	feeds := make([]*Price, 0, len(priceFeeds))
+	for p := range priceFeeds {
+		feeds = append(feeds, p)
+	}
+	return feeds
+}
@@ -0,0 +1,53 @@
+package blockchain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jon4hz/deadshot/internal/config"
+	"github.com/jon4hz/deadshot/internal/database"
+)
+
+func TestApplyGuards(t *testing.T) {
+	trade := &database.Trade{Token1: weth}
+	trade.SetProfile(&database.Profile{MaxSlippage: 1})
+	logStream := make(chan string, 1)
+	d := &dispatcher{trade: trade, logStream: logStream, guards: &config.Guards{MaxSlippage: 1}}
+	registerDispatcher(d)
+	defer unregisterDispatcher(d)
+
+	// the profile still tightens the new guards
+	ApplyGuards(config.Guards{MaxSlippage: 5, MaxTradeAmount: 2})
+	got := d.getGuards()
+	if got.MaxSlippage != 1 || got.MaxTradeAmount != 2 {
+		t.Errorf("guards = %+v, want a max slippage of 1 and a max trade amount of 2", got)
+	}
+	if msg := <-logStream; !strings.Contains(msg, "the guards were updated") {
+		t.Errorf("log = %q, want the update", msg)
+	}
+
+	// unchanged guards aren't logged again
+	ApplyGuards(config.Guards{MaxSlippage: 5, MaxTradeAmount: 2})
+	select {
+	case msg := <-logStream:
+		t.Errorf("unexpected log %q", msg)
+	default:
+	}
+}
+
+func TestUpdateEndpoints(t *testing.T) {
+	n := &database.Network{Name: "deadshot-test", Endpoints: database.Endpoints{database.NewEndpoint("http://localhost:8545", false)}}
+	if updateEndpoints(n, database.Endpoints{database.NewEndpoint("http://localhost:8545", false)}) {
+		t.Error("the same endpoints shouldn't be a change")
+	}
+	custom := database.Endpoints{
+		database.NewEndpoint("http://localhost:8545", false),
+		database.NewEndpoint("http://localhost:9545", true),
+	}
+	if !updateEndpoints(n, custom) {
+		t.Error("a new custom endpoint should be a change")
+	}
+	if urls := endpointURLs(n); len(urls) != 1 || urls[0] != "http://localhost:9545" {
+		t.Errorf("endpointURLs() = %v, want only the custom endpoint", urls)
+	}
+}
@@ -0,0 +1,146 @@
+package blockchain
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/jon4hz/deadshot/internal/blockchain/abi/solidlyrouter"
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logging"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrSolidlyExactOut = errors.New("solidly routers only support an exact input amount")
+	ErrSolidlyRoute    = errors.New("the stable flags don't match the path")
+)
+
+// NewSolidlyRouter returns a new instance of a solidly router.
+func (c *Client) NewSolidlyRouter(contract string) (*solidlyrouter.Solidlyrouter, error) {
+	addr := common.HexToAddress(contract)
+	router, err := solidlyrouter.NewSolidlyrouter(addr, c.Eth())
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error":  err,
+			"router": contract,
+		}).Error("Failed to create solidly router")
+		return nil, err
+	}
+	return router, nil
+}
+
+// solidlyRoutes converts the path and the stable flags of the target to the routes of a solidly router.
+func solidlyRoutes(path []common.Address, stables []bool) ([]solidlyrouter.Routerroute, error) {
+	if len(path) < 2 || len(stables) != len(path)-1 {
+		return nil, ErrSolidlyRoute
+	}
+	routes := make([]solidlyrouter.Routerroute, len(stables))
+	for i, stable := range stables {
+		routes[i] = solidlyrouter.Routerroute{
+			From:   path[i],
+			To:     path[i+1],
+			Stable: stable,
+		}
+	}
+	return routes, nil
+}
+
+// swapSolidly sends the swap of the target to a solidly router.
+// The nonce of the wallet must already be up to date.
+func (c *Client) swapSolidly(wallet *database.Wallet, trade *database.Trade, target *database.Target, dex *database.Dex, t0, t1 *database.Token, auth *bind.TransactOpts, approval *big.Int, deadline *big.Int) (*types.Transaction, error) {
+	if target.GetAmountMode().GetName() != database.DefaultAmountModes.GetAmountIn().GetName() {
+		return nil, ErrSolidlyExactOut
+	}
+
+	routes, err := solidlyRoutes(target.GetPath(), target.GetStables())
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error":   err,
+			"path":    target.GetPath(),
+			"stables": target.GetStables(),
+		}).Error("failed to create solidly routes")
+		return nil, err
+	}
+
+	router, err := c.NewSolidlyRouter(dex.GetRouter())
+	if err != nil {
+		return nil, err
+	}
+
+	// Token0 is the native token, no approval necessary
+	if t0.GetNative() {
+		auth.Value = target.GetActualAmount()
+		auth.Nonce = big.NewInt(wallet.GetNonce())
+		tx, err := router.SwapExactETHForTokens(auth, target.GetAmountMinMax(), routes, common.HexToAddress(wallet.GetWallet()), deadline) // receive a minimum amount
+		if err != nil {
+			logging.Log.WithFields(logrus.Fields{
+				"error":        err,
+				"type":         "SwapExactETHForTokens",
+				"amountIn":     target.GetActualAmount(),
+				"amountOutMin": target.GetAmountMinMax(),
+				"tradeWallet":  wallet.GetWallet(),
+				"deadline":     target.GetDeadline(),
+			}).Error("failed to swap")
+			return nil, err
+		}
+		logging.Log.WithFields(logrus.Fields{
+			"tx": tx.Hash().String(),
+		}).Info("sent solidly SwapExactETHForTokens transaction")
+		return tx, nil
+	}
+
+	approved, err := c.manageApproval(
+		common.HexToAddress(wallet.GetWallet()),
+		common.HexToAddress(dex.GetRouter()),
+		common.HexToAddress(t0.GetContract()),
+		approvalAmount(approval, target.GetActualAmount()),
+		big.NewInt(int64(trade.GetNetwork().GetChainID())),
+		wallet.GetNonce(),
+		trade.GetSigner(),
+		t0.GetUnlimitedApproval(),
+	)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error": err,
+		}).Error("failed to manage approval")
+		return nil, err
+	}
+
+	// increment the nonce if there was an approval tx
+	if approved {
+		wallet.IncrementNonce()
+	}
+	auth.Nonce = big.NewInt(wallet.GetNonce())
+
+	var (
+		tx     *types.Transaction
+		txType string
+	)
+	// token1 is native currency
+	if t1.GetNative() {
+		txType = "SwapExactTokensForETH"
+		tx, err = router.SwapExactTokensForETH(auth, target.GetActualAmount(), target.GetAmountMinMax(), routes, common.HexToAddress(wallet.GetWallet()), deadline) // receive a minimum amount
+	} else {
+		txType = "SwapExactTokensForTokens"
+		tx, err = router.SwapExactTokensForTokens(auth, target.GetActualAmount(), target.GetAmountMinMax(), routes, common.HexToAddress(wallet.GetWallet()), deadline) // receive a minimum amount
+	}
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error":        err,
+			"type":         txType,
+			"amountIn":     target.GetActualAmount(),
+			"amountOutMin": target.GetAmountMinMax(),
+			"tradeWallet":  wallet.GetWallet(),
+			"deadline":     target.GetDeadline(),
+		}).Error("failed to swap")
+		return nil, err
+	}
+	logging.Log.WithFields(logrus.Fields{
+		"tx": tx.Hash().String(),
+	}).Info("sent solidly " + txType + " transaction")
+	return tx, nil
+}
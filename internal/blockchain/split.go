@@ -0,0 +1,153 @@
+package blockchain
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/pkg/uniswap"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+)
+
+// splitRoutesPerDex is the number of candidate routes per dex for a split trade.
+const splitRoutesPerDex = 3
+
+var ErrSplitExactOut = errors.New("split trades only support an exact input amount")
+
+// SplitLeg is the part of a split trade which is routed through a single dex.
+type SplitLeg struct {
+	Dex   *database.Dex
+	Split *uniswap.Split
+}
+
+// SplitQuote is an exact in trade split across multiple routes and dexes.
+type SplitQuote struct {
+	Trade *uniswap.SplitTrade
+	Legs  []*SplitLeg
+}
+
+// GetBestSplitTradeExactIn splits the amount across the best routes of all given dexes.
+func (c *Client) GetBestSplitTradeExactIn(token0, token1 *database.Token, amount *big.Int, dexes []*database.Dex, tokens []*database.Token, maxHops int, weth string) (*SplitQuote, error) {
+	pairs, err := c.genUniPairsByDex(token0, token1, dexes, weth, tokens...)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error":  err,
+			"token0": token0.GetContract(),
+			"token1": token1.GetContract(),
+		}).Error("failed to generate uniswap pairs")
+		return nil, err
+	}
+	uniToken0, uniToken1, err := toUniswapTokens(token0, token1, weth)
+	if err != nil {
+		return nil, err
+	}
+	token0Amount, err := uniswap.NewTokenAmount(uniToken0, amount)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error":  err,
+			"token0": token0.GetContract(),
+		}).Error("failed to create token0 amount")
+		return nil, err
+	}
+
+	var (
+		routes     []*uniswap.Route
+		routeDexes []*database.Dex
+	)
+	for _, dex := range dexes {
+		p, ok := pairs[dex]
+		if !ok {
+			continue
+		}
+		trades, err := uniswap.BestTradeExactIn(
+			p, token0Amount, uniToken1,
+			&uniswap.BestTradeOptions{
+				MaxNumResults: splitRoutesPerDex,
+				MaxHops:       maxHops,
+				DexFee:        dex.GetFeeBigInt(),
+			}, nil, nil, nil,
+		)
+		if err != nil {
+			logging.Log.WithFields(logrus.Fields{
+				"error": err,
+				"dex":   dex.GetName(),
+			}).Warn("failed to find routes for split trade")
+			continue
+		}
+		for _, t := range trades {
+			routes = append(routes, t.Route)
+			routeDexes = append(routeDexes, dex)
+		}
+	}
+	if len(routes) == 0 {
+		return nil, ErrNoTradeFound
+	}
+
+	split, err := uniswap.BestSplitTradeExactIn(routes, token0Amount, uniswap.DefaultSplitSteps, nil)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error":  err,
+			"token0": token0.GetContract(),
+			"token1": token1.GetContract(),
+		}).Error("failed to split trade")
+		return nil, err
+	}
+	quote := &SplitQuote{Trade: split}
+	for _, s := range split.Splits {
+		quote.Legs = append(quote.Legs, &SplitLeg{Dex: routeDexes[s.Index], Split: s})
+	}
+	return quote, nil
+}
+
+// SwapSplit executes every leg of the split quote as a separate swap.
+// The router of every dex is approved once for the sum of its legs.
+// The transactions which were sent before an error occurred are returned along with the error.
+func (c *Client) SwapSplit(wallet *database.Wallet, trade *database.Trade, target *database.Target, quote *SplitQuote) ([]*types.Transaction, error) {
+	if target.GetAmountMode().GetName() != database.DefaultAmountModes.GetAmountIn().GetName() {
+		return nil, ErrSplitExactOut
+	}
+
+	approvals := make(map[string]*big.Int)
+	for _, leg := range quote.Legs {
+		router := strings.ToLower(leg.Dex.GetRouter())
+		if _, ok := approvals[router]; !ok {
+			approvals[router] = big.NewInt(0)
+		}
+		approvals[router].Add(approvals[router], leg.Split.Trade.InputAmount().Raw())
+	}
+
+	slippage := uniswap.NewPercent(big.NewInt(int64(target.GetSlippage())), big.NewInt(database.MaxSlippage))
+	txs := make([]*types.Transaction, 0, len(quote.Legs))
+	for _, leg := range quote.Legs {
+		part := target.Copy()
+		part.SetDex(leg.Dex)
+		part.SetPath(leg.Split.Trade.Route.GetAddresses())
+		part.SetStables(leg.Split.Trade.Route.GetStables())
+		part.SetActualAmount(leg.Split.Trade.InputAmount().Raw())
+		min, err := leg.Split.Trade.MinimumAmountOut(slippage)
+		if err != nil {
+			return txs, err
+		}
+		part.SetAmountMinMax(min.Raw().String())
+
+		// only the first leg of a router approves, the following legs use the same allowance
+		router := strings.ToLower(leg.Dex.GetRouter())
+		approval := approvals[router]
+		approvals[router] = big.NewInt(0)
+
+		tx, err := c.swap(wallet, trade, part, approval)
+		if err != nil {
+			logging.Log.WithFields(logrus.Fields{
+				"error": err,
+				"dex":   leg.Dex.GetName(),
+			}).Error("failed to swap split leg")
+			return txs, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
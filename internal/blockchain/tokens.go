@@ -15,7 +15,7 @@ func (c *Client) getTokenBalanceOf(address, contract string) (*big.Int, error) {
 	addr := common.HexToAddress(address)
 	ctr := common.HexToAddress(contract)
 
-	instance, err := erc20.NewErc20(ctr, c.Client)
+	instance, err := erc20.NewErc20(ctr, c.Eth())
 	if err != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"address":  address,
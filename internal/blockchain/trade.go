@@ -2,7 +2,6 @@ package blockchain
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"errors"
 	"math/big"
 	"time"
@@ -10,20 +9,16 @@ import (
 	"github.com/jon4hz/deadshot/internal/blockchain/abi/erc20"
 	"github.com/jon4hz/deadshot/internal/database"
 	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/internal/signer"
 	"github.com/jon4hz/deadshot/pkg/ethutils"
 	"github.com/jon4hz/deadshot/pkg/uniswap"
 
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 )
 
-/* const (
-	maxApprovalHex = "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
-) */
-
 const bestTradesResults = 2
 
 var (
@@ -35,7 +30,10 @@ var (
 )
 
 func (c *Client) GetBestTradeExactOut(token0, token1 *database.Token, amount *big.Int, dex *database.Dex, tokens []*database.Token, maxHops int, weth string) (*uniswap.Trade, error) {
-	uniPairs, err := c.genUniPairs(token0, token1, dex, tokens...)
+	if dex.IsSolidly() {
+		return nil, ErrSolidlyExactOut
+	}
+	uniPairs, err := c.genUniPairs(token0, token1, dex, weth, tokens...)
 	if err != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"error":  err,
@@ -99,73 +97,20 @@ func (c *Client) GetBestTradeExactOut(token0, token1 *database.Token, amount *bi
 	return trades[0], nil
 }
 
-func (c *Client) generatePairs(factory string, tokens ...*database.Token) (map[string]*Pair, error) {
-	pairTokens, err := c.GetValidPairTokens(tokens, factory)
-	if err != nil {
-		logging.Log.WithFields(logrus.Fields{
-			"error":   err,
-			"tokens":  tokens,
-			"factory": factory,
-		}).Error("failed to get valid pair tokens")
-		return nil, err
-	}
-	if len(pairTokens) == 0 {
-		logging.Log.WithFields(logrus.Fields{
-			"error":   ErrNoPairsFound,
-			"tokens":  tokens,
-			"factory": factory,
-		}).Warn("no pairs found")
-		return nil, err
-	}
-
-	pairs, err := c.GetPairInfo(pairTokens...)
-	if err != nil {
-		logging.Log.WithFields(logrus.Fields{
-			"error": err,
-		}).Error("failed to get pair info")
-		return nil, err
-	}
-	if len(pairs) == 0 {
-		logging.Log.Error("pair length is zero")
-		return nil, err
-	}
-	return pairs, nil
-}
-
-func (c *Client) genUniPairs(token0, token1 *database.Token, dex *database.Dex, tokens ...*database.Token) ([]*uniswap.Pair, error) {
-	tokens = append(tokens, token0, token1)
-
-	pairs, err := c.generatePairs(dex.GetFactory(), tokens...)
+func (c *Client) genUniPairs(token0, token1 *database.Token, dex *database.Dex, weth string, tokens ...*database.Token) ([]*uniswap.Pair, error) {
+	pairs, err := c.genUniPairsByDex(token0, token1, []*database.Dex{dex}, weth, tokens...)
 	if err != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"error": err,
+			"dex":   dex.GetName(),
 		}).Error("failed to generate pairs")
 		return nil, err
 	}
-	if len(pairs) == 0 {
-		logging.Log.WithFields(logrus.Fields{
-			"error": ErrNoPairsFound,
-		}).Warn("no pairs found")
-		return nil, ErrNoPairsFound
-	}
-
-	uniPairs := make([]*uniswap.Pair, len(pairs))
-	var i int
-	for _, v := range pairs {
-		uniPairs[i], _, _, err = v.ToUniswap()
-		if err != nil {
-			logging.Log.WithFields(logrus.Fields{
-				"error": err,
-			}).Error("failed to convert pair to uniswap pair")
-			return nil, err
-		}
-		i++
-	}
-	return uniPairs, nil
+	return pairs[dex], nil
 }
 
 func (c *Client) GetBestTradeExactIn(token0, token1 *database.Token, amount *big.Int, dex *database.Dex, tokens []*database.Token, maxHops int, weth string) (*uniswap.Trade, error) {
-	uniPairs, err := c.genUniPairs(token0, token1, dex, tokens...)
+	uniPairs, err := c.genUniPairs(token0, token1, dex, weth, tokens...)
 	if err != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"error":  err,
@@ -228,7 +173,7 @@ func (c *Client) GetBestTradeExactIn(token0, token1 *database.Token, amount *big
 
 func (c *Client) GetBestOrderTrades(token0, token1 *database.Token, buyAmount, sellAmount *big.Int, dex *database.Dex, tokens []*database.Token, maxHops int, weth string) (*uniswap.Trade, *uniswap.Trade, error) {
 	// Get all the onchain infos
-	uniPairs, err := c.genUniPairs(token0, token1, dex, tokens...)
+	uniPairs, err := c.genUniPairs(token0, token1, dex, weth, tokens...)
 	if err != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"error":  err,
@@ -309,11 +254,11 @@ func bestXTrade(token0, token1 *uniswap.Token, amount *big.Int, pairs []*uniswap
 	return trades[0], nil
 }
 
-// CheckListed checks if there is an available trading route for two given tokens.
+// CheckListed checks if there is an available trading route with up to maxHops hops for two given tokens.
 // If there is no route, chain.ErrNoTradeFound is returned so you should check against that specific error when calling this functions,
 // other errors can be retured too.
-func (c *Client) CheckListed(token0, token1 *database.Token, dex *database.Dex, weth string, tokens []*database.Token) (*uniswap.Trade, error) {
-	trade, err := c.GetBestTradeExactIn(token0, token1, ethutils.ToWei(1, token0.GetDecimals()), dex, tokens, 5, weth)
+func (c *Client) CheckListed(token0, token1 *database.Token, dex *database.Dex, weth string, tokens []*database.Token, maxHops int) (*uniswap.Trade, error) {
+	trade, err := c.GetBestTradeExactIn(token0, token1, ethutils.ToWei(1, token0.GetDecimals()), dex, tokens, maxHops, weth)
 	if err != nil {
 		return nil, err
 	}
@@ -322,6 +267,13 @@ func (c *Client) CheckListed(token0, token1 *database.Token, dex *database.Dex,
 
 // Swap triggers a swap of a target.
 func (c *Client) Swap(wallet *database.Wallet, trade *database.Trade, target *database.Target) (*types.Transaction, error) {
+	return c.swap(wallet, trade, target, nil)
+}
+
+// swap triggers a swap of a target.
+// If approval is not nil, the router gets approved for that amount instead of the amount of the target,
+// e.g. to approve all parts of a split trade at once. An approval of zero skips the approval.
+func (c *Client) swap(wallet *database.Wallet, trade *database.Trade, target *database.Target, approval *big.Int) (*types.Transaction, error) {
 	var t0, t1 *database.Token
 	if target.GetTargetType().GetType() == database.DefaultTargetTypes.GetBuy().GetType() {
 		t0 = trade.GetToken0()
@@ -331,17 +283,25 @@ func (c *Client) Swap(wallet *database.Wallet, trade *database.Trade, target *da
 		t1 = trade.GetToken0()
 	}
 
+	// the target is executed on the dex its path was quoted on
+	dex := trade.GetDex()
+	if d := target.GetDex(); d != nil {
+		dex = d
+	}
+
 	// create a new router instance
-	router, err := c.NewRouter(trade.GetDex().GetRouter())
+	router, err := c.NewRouter(dex.GetRouter())
 	if err != nil {
 		return nil, err
 	}
 
-	// convert deadline unix timestamp
-	deadlineUnixTimestamp := time.Now().UTC().Unix() + int64(target.GetDeadline().Seconds())
+	// the deadline is stored on the target, so it's known after a restart
+	deadline := time.Now().Add(*target.GetDeadline())
+	target.SetDeadlineAt(deadline)
+	deadlineUnixTimestamp := deadline.UTC().Unix()
 
 	// create signer
-	auth, err := bind.NewKeyedTransactorWithChainID(wallet.GetPrivateKey(), big.NewInt(int64(trade.GetNetwork().GetChainID())))
+	auth, err := signer.NewTransactor(trade.GetSigner(), big.NewInt(int64(trade.GetNetwork().GetChainID())))
 	if err != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"error": err,
@@ -349,10 +309,16 @@ func (c *Client) Swap(wallet *database.Wallet, trade *database.Trade, target *da
 		return nil, err
 	}
 	auth.GasLimit = *target.GetGasLimit()
-	auth.GasPrice = target.GetGasPrice()
+	auth.GasPrice, err = c.gasPrice(trade.GetProfile(), target)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error": err,
+		}).Error("failed to get the gas price")
+		return nil, err
+	}
 
 	if time.Since(wallet.LastNonceUpdate()) > time.Millisecond*500 {
-		nonce, err := c.Client.PendingNonceAt(context.Background(), common.HexToAddress(wallet.GetWallet()))
+		nonce, err := c.Eth().PendingNonceAt(context.Background(), common.HexToAddress(wallet.GetWallet()))
 		if err != nil {
 			logging.Log.WithFields(logrus.Fields{
 				"error": err,
@@ -364,6 +330,11 @@ func (c *Client) Swap(wallet *database.Wallet, trade *database.Trade, target *da
 		wallet.IncrementNonce()
 	}
 
+	// solidly routers take the stable flag of every hop
+	if dex.IsSolidly() {
+		return c.swapSolidly(wallet, trade, target, dex, t0, t1, auth, approval, big.NewInt(deadlineUnixTimestamp))
+	}
+
 	// Token0 is the native token, no approval necessary
 	if t0.GetNative() {
 		// ExactIn
@@ -418,12 +389,13 @@ func (c *Client) Swap(wallet *database.Wallet, trade *database.Trade, target *da
 		if target.GetAmountMode().GetName() == database.DefaultAmountModes.GetAmountIn().GetName() {
 			approved, err := c.manageApproval(
 				common.HexToAddress(wallet.GetWallet()),
-				common.HexToAddress(trade.GetDex().GetRouter()),
+				common.HexToAddress(dex.GetRouter()),
 				common.HexToAddress(t0.GetContract()),
-				target.GetActualAmount(),
+				approvalAmount(approval, target.GetActualAmount()),
 				big.NewInt(int64(trade.GetNetwork().GetChainID())),
 				wallet.GetNonce(),
-				wallet.GetPrivateKey(),
+				trade.GetSigner(),
+				t0.GetUnlimitedApproval(),
 			)
 			if err != nil {
 				logging.Log.WithFields(logrus.Fields{
@@ -459,12 +431,13 @@ func (c *Client) Swap(wallet *database.Wallet, trade *database.Trade, target *da
 		} else if target.GetAmountMode().GetName() == database.DefaultAmountModes.GetAmountOut().GetName() {
 			approved, err := c.manageApproval(
 				common.HexToAddress(wallet.GetWallet()),
-				common.HexToAddress(trade.GetDex().GetRouter()),
+				common.HexToAddress(dex.GetRouter()),
 				common.HexToAddress(t0.GetContract()),
-				target.GetAmountMinMax(),
+				approvalAmount(approval, target.GetAmountMinMax()),
 				big.NewInt(int64(trade.GetNetwork().GetChainID())),
 				wallet.GetNonce(),
-				wallet.GetPrivateKey(),
+				trade.GetSigner(),
+				t0.GetUnlimitedApproval(),
 			)
 			if err != nil {
 				logging.Log.WithFields(logrus.Fields{
@@ -502,12 +475,13 @@ func (c *Client) Swap(wallet *database.Wallet, trade *database.Trade, target *da
 		if target.GetAmountMode().GetName() == database.DefaultAmountModes.GetAmountIn().GetName() {
 			approved, err := c.manageApproval(
 				common.HexToAddress(wallet.GetWallet()),
-				common.HexToAddress(trade.GetDex().GetRouter()),
+				common.HexToAddress(dex.GetRouter()),
 				common.HexToAddress(t0.GetContract()),
-				target.GetActualAmount(),
+				approvalAmount(approval, target.GetActualAmount()),
 				big.NewInt(int64(trade.GetNetwork().GetChainID())),
 				wallet.GetNonce(),
-				wallet.GetPrivateKey(),
+				trade.GetSigner(),
+				t0.GetUnlimitedApproval(),
 			)
 			if err != nil {
 				logging.Log.WithFields(logrus.Fields{
@@ -543,12 +517,13 @@ func (c *Client) Swap(wallet *database.Wallet, trade *database.Trade, target *da
 		} else if target.GetAmountMode().GetName() == database.DefaultAmountModes.GetAmountOut().GetName() {
 			approved, err := c.manageApproval(
 				common.HexToAddress(wallet.GetWallet()),
-				common.HexToAddress(trade.GetDex().GetRouter()),
+				common.HexToAddress(dex.GetRouter()),
 				common.HexToAddress(t0.GetContract()),
-				target.GetAmountMinMax(),
+				approvalAmount(approval, target.GetAmountMinMax()),
 				big.NewInt(int64(trade.GetNetwork().GetChainID())),
 				wallet.GetNonce(),
-				wallet.GetPrivateKey(),
+				trade.GetSigner(),
+				t0.GetUnlimitedApproval(),
 			)
 			if err != nil {
 				logging.Log.WithFields(logrus.Fields{
@@ -584,10 +559,19 @@ func (c *Client) Swap(wallet *database.Wallet, trade *database.Trade, target *da
 	return nil, nil
 }
 
-// manageApproval checks if a token is already approved and approve it if not
+// approvalAmount returns the approval override or the amount if there is none.
+func approvalAmount(approval, amount *big.Int) *big.Int {
+	if approval != nil {
+		return approval
+	}
+	return amount
+}
+
+// manageApproval checks if a token is already approved and approve it if not.
+// If unlimited is true, the spender is approved for the maximum amount instead, so later trades don't need an approval.
 // if manageApproval sent an approve tx, the function returns true and the nonce must be incremented.
-func (c *Client) manageApproval(owner, spender, token common.Address, amount, chainID *big.Int, nonce int64, key *ecdsa.PrivateKey) (bool, error) {
-	instance, err := erc20.NewErc20(token, c.Client)
+func (c *Client) manageApproval(owner, spender, token common.Address, amount, chainID *big.Int, nonce int64, s signer.Signer, unlimited bool) (bool, error) {
+	instance, err := erc20.NewErc20(token, c.Eth())
 	if err != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"error": err,
@@ -607,28 +591,12 @@ func (c *Client) manageApproval(owner, spender, token common.Address, amount, ch
 	}
 
 	if allowance.Cmp(amount) < 0 {
-		auth, err := bind.NewKeyedTransactorWithChainID(key, chainID)
-		auth.Nonce = big.NewInt(nonce)
-		if err != nil {
-			logging.Log.WithFields(logrus.Fields{
-				"error": err,
-			}).Error("failed to create signer")
-			return false, err
+		if unlimited {
+			amount = UnlimitedAllowance
 		}
-		tx, err := instance.Approve(auth, spender, amount)
-		if err != nil {
-			logging.Log.WithFields(logrus.Fields{
-				"error":   err,
-				"owner":   owner.String(),
-				"spender": spender.String(),
-				"token":   token.String(),
-			}).Error("failed to approve")
+		if _, err := c.approve(instance, owner, spender, token, amount, chainID, nonce, s); err != nil {
 			return false, err
 		}
-		logging.Log.WithFields(logrus.Fields{
-			"tx": tx.Hash().String(),
-		}).Info("sent approve transaction")
-
 		return true, nil
 	}
 
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/jon4hz/deadshot/internal/config"
 	"github.com/jon4hz/deadshot/internal/database"
 	"github.com/jon4hz/deadshot/internal/logging"
 	"github.com/jon4hz/deadshot/internal/logstream"
@@ -30,14 +31,26 @@ var (
 )
 
 // TradeDispatcher is a loop that checks if the price matches a target (considering the slippage) and executes the trade
+// Every swap is checked against the guards before it's sent, ApplyGuards replaces them while the dispatcher is running.
 // This function is blocking and should be run in a goroutine.
-func (c *Client) TradeDispatcher(ctx context.Context, cancel context.CancelFunc, wallet *database.Wallet, trade *database.Trade, price *Price, logStream chan<- string) {
+func (c *Client) TradeDispatcher(ctx context.Context, cancel context.CancelFunc, wallet *database.Wallet, trade *database.Trade, price *Price, guards *config.Guards, logStream chan<- string) {
 	logging.Log.WithFields(logrus.Fields{
 		"token0": trade.GetToken0().GetContract(),
 		"token1": trade.GetToken1().GetContract(),
 	}).Info("starting TradeDispatcher")
+	if err := CheckHalted(); err != nil {
+		logging.Log.WithField("err", err).Warn("refusing to start TradeDispatcher")
+		logStream <- logstream.Format(err.Error(), logstream.ERR)
+		price.Stop()
+		cancel()
+		return
+	}
 	logStream <- logstream.Format("starting trade dispatcher", logstream.INFO)
 
+	d := &dispatcher{client: c, cancel: cancel, wallet: wallet, trade: trade, price: price, logStream: logStream, guards: guards}
+	registerDispatcher(d)
+	defer unregisterDispatcher(d)
+
 	setNextBuyPrice(price, trade)
 	setNextSellPrice(price, trade)
 
@@ -77,8 +90,14 @@ func (c *Client) TradeDispatcher(ctx context.Context, cancel context.CancelFunc,
 	logStream <- logstream.Format(fmt.Sprintf("got initial price: %s", initTrade.ExecutionPrice.Invert().ToSignificant(significantDecimals)), logstream.INFO)
 	trade.SetInitPrice(initPrice.String())
 
+	expiry := &expiryChecker{client: c}
+	if expiry.check(trade, logStream) {
+		cancel()
+		return
+	}
+
 	var earlySellErrMsg sync.Once
-	err := c.dispatchTrade(cancel, wallet, trade, price, logStream, &earlySellErrMsg)
+	err := c.dispatchTrade(cancel, wallet, trade, price, d.getGuards(), logStream, &earlySellErrMsg)
 	if err != nil {
 		go func() {
 			cancel()
@@ -87,7 +106,11 @@ func (c *Client) TradeDispatcher(ctx context.Context, cancel context.CancelFunc,
 	for {
 		select {
 		case <-price.Heartbeat:
-			err := c.dispatchTrade(cancel, wallet, trade, price, logStream, &earlySellErrMsg)
+			if expiry.check(trade, logStream) {
+				cancel()
+				return
+			}
+			err := c.dispatchTrade(cancel, wallet, trade, price, d.getGuards(), logStream, &earlySellErrMsg)
 			if err != nil {
 				return
 			}
@@ -138,11 +161,13 @@ func getCurrentSellPrice(t *uniswap.Trade, decimals uint8) (*big.Int, bool) {
 	return ps, true
 }
 
-func (c *Client) dispatchTrade(cancel context.CancelFunc, wallet *database.Wallet, trade *database.Trade, price *Price, logStream chan<- string, earlySellErrMsg *sync.Once) error {
+func (c *Client) dispatchTrade(cancel context.CancelFunc, wallet *database.Wallet, trade *database.Trade, price *Price, guards *config.Guards, logStream chan<- string, earlySellErrMsg *sync.Once) error {
 	if price.GetError() != nil {
 		return nil
 	}
-	currentBuyTrade, currentSellTrade := price.GetTrades()
+	buyQuote, sellQuote := price.GetQuotes()
+	currentBuyTrade, currentSellTrade := buyQuote.GetTrade(), sellQuote.GetTrade()
+	buyDex, sellDex := buyQuote.DexOr(trade.GetDex()), sellQuote.DexOr(trade.GetDex())
 	var eg errgroup.Group
 	eg.Go(func() error {
 		currentBuyPrice, ok := getCurrenctBuyPrice(currentBuyTrade, trade.GetToken0().GetDecimals())
@@ -153,16 +178,17 @@ func (c *Client) dispatchTrade(cancel context.CancelFunc, wallet *database.Walle
 		}
 		for _, v := range trade.GetBuyTargets() {
 			// check if the price is in the range of the target
-			if !v.GetHit() && !v.GetConfirmed() && v.TriggerFunc(currentBuyPrice, v.GetPrice(), v.GetStopLoss()) {
+			if v.GetPending() && v.TriggerFunc(currentBuyPrice, v.GetPrice(), v.GetStopLoss()) {
 				v.SetHit(true)
 				// logStream <- logstream.Format("buy target triggered", logstream.INFO)
-				err := setMissingBuyTargetInfo(v, trade.GetToken0(), currentBuyTrade.Route, trade.GetNetwork().GetWETH(), trade.GetDex().GetFeeBigInt())
+				v.SetDex(buyDex)
+				quote, err := setMissingBuyTargetInfo(v, trade.GetProfile(), trade.GetToken0(), currentBuyTrade.Route, trade.GetNetwork().GetWETH(), buyDex.GetFeeBigInt())
 				if err != nil {
 					logStream <- logstream.Format(fmt.Sprintf("an error unexpected occurred: %s", err), logstream.ERR)
 					return err
 				}
 				// SWAP
-				go handleSwap(cancel, c, logStream, wallet, trade, v)
+				go handleSwap(cancel, c, logStream, wallet, guards, &guardedSwap{trade: trade, target: v, quote: quote, input: trade.GetToken0()})
 				setNextBuyPrice(price, trade)
 			}
 		}
@@ -178,7 +204,7 @@ func (c *Client) dispatchTrade(cancel context.CancelFunc, wallet *database.Walle
 		for _, v := range trade.GetSellTargets() {
 			p := v.GetPrice()
 			// check if the price is in the range of the target
-			if !v.GetHit() && !v.GetConfirmed() && v.TriggerFunc(currentSellPrice, p, v.GetStopLoss()) {
+			if v.GetPending() && v.TriggerFunc(currentSellPrice, p, v.GetStopLoss()) {
 				// cancel if no buy targets are hit yet
 				if trade.GetBuyTargetHit() == 0 {
 					earlySellErrMsg.Do(func() {
@@ -191,7 +217,8 @@ func (c *Client) dispatchTrade(cancel context.CancelFunc, wallet *database.Walle
 				v.SetHit(true)
 				// logStream <- logstream.Format("sell target triggered", logstream.INFO) // commented out, as it can flood
 
-				err := setMissingSellTargetInfo(v, trade.GetToken1(), currentSellPrice, currentSellTrade.Route, trade.GetNetwork().GetWETH(), trade.GetDex().GetFeeBigInt())
+				v.SetDex(sellDex)
+				quote, err := setMissingSellTargetInfo(v, trade.GetProfile(), trade.GetToken1(), currentSellPrice, currentSellTrade.Route, trade.GetNetwork().GetWETH(), sellDex.GetFeeBigInt())
 				if err != nil {
 					// if the actual sell amount is unknown, which is the case if the buy transaction is not confirmed yet
 					// then we skip the error, mark the target as not hit and return nil.
@@ -204,7 +231,7 @@ func (c *Client) dispatchTrade(cancel context.CancelFunc, wallet *database.Walle
 					return err
 				}
 				// SWAP
-				go handleSwap(cancel, c, logStream, wallet, trade, v)
+				go handleSwap(cancel, c, logStream, wallet, guards, &guardedSwap{trade: trade, target: v, quote: quote, input: trade.GetToken1()})
 				setNextSellPrice(price, trade)
 			}
 		}
@@ -217,21 +244,21 @@ func (c *Client) dispatchTrade(cancel context.CancelFunc, wallet *database.Walle
 	return nil
 }
 
-// set the missing informations for the buy target.
-func setMissingBuyTargetInfo(target *database.Target, token *database.Token, route *uniswap.Route, weth string, dexFee *big.Int) error {
-	target.SetDefaults()
+// set the missing informations for the buy target and return the trade of the target amount.
+func setMissingBuyTargetInfo(target *database.Target, profile *database.Profile, token *database.Token, route *uniswap.Route, weth string, dexFee *big.Int) (*uniswap.Trade, error) {
+	target.SetProfileDefaults(profile)
 
 	uniToken, err := token.ToUniswap(weth)
 	if err != nil {
-		return fmt.Errorf("could not convert token to uniswap token: %w", err)
+		return nil, fmt.Errorf("could not convert token to uniswap token: %w", err)
 	}
 	amount, err := uniswap.NewTokenAmount(uniToken, target.GetActualAmount())
 	if err != nil {
-		return fmt.Errorf("could not convert token amount to uniswap token amount: %w", err)
+		return nil, fmt.Errorf("could not convert token amount to uniswap token amount: %w", err)
 	}
 	trade, err := uniswap.NewTrade(route, amount, uniswap.ExactInput, dexFee)
 	if err != nil {
-		return fmt.Errorf("could not create trade: %w", err)
+		return nil, fmt.Errorf("could not create trade: %w", err)
 	}
 	slippage := int64(target.GetSlippage())
 	if slippage == database.MaxSlippage {
@@ -239,20 +266,21 @@ func setMissingBuyTargetInfo(target *database.Target, token *database.Token, rou
 	} else {
 		min, err := trade.MinimumAmountOut(uniswap.NewPercent(big.NewInt(slippage), big.NewInt(database.MaxSlippage)))
 		if err != nil {
-			return fmt.Errorf("could not get minimum amount out: %w", err)
+			return nil, fmt.Errorf("could not get minimum amount out: %w", err)
 		}
 		target.SetAmountMinMax(min.Raw().String())
 	}
 
 	target.SetPath(route.GetAddresses())
+	target.SetStables(route.GetStables())
 	target.SetExecutionPrice(trade.ExecutionPrice.Invert().Decimal())
 	logging.Log.WithFields(logrus.Fields{"execution price": target.GetExecutionPrice().String()}).Info("set buy infos")
-	return nil
+	return trade, nil
 }
 
-// set the missing informations for the sell target.
-func setMissingSellTargetInfo(target *database.Target, token *database.Token, price *big.Int, route *uniswap.Route, weth string, dexFee *big.Int) error {
-	target.SetDefaults()
+// set the missing informations for the sell target and return the trade of the target amount.
+func setMissingSellTargetInfo(target *database.Target, profile *database.Profile, token *database.Token, price *big.Int, route *uniswap.Route, weth string, dexFee *big.Int) (*uniswap.Trade, error) {
+	target.SetProfileDefaults(profile)
 
 	if target.GetPercentageAmount() == 0 {
 		mul := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(target.GetActualAmountDecimals())), nil)
@@ -261,15 +289,15 @@ func setMissingSellTargetInfo(target *database.Target, token *database.Token, pr
 
 	uniToken, err := token.ToUniswap(weth)
 	if err != nil {
-		return fmt.Errorf("could not convert token to uniswap token: %w", err)
+		return nil, fmt.Errorf("could not convert token to uniswap token: %w", err)
 	}
 	amount, err := uniswap.NewTokenAmount(uniToken, target.GetActualAmount())
 	if err != nil {
-		return fmt.Errorf("could not convert token amount to uniswap token amount: %w", err)
+		return nil, fmt.Errorf("could not convert token amount to uniswap token amount: %w", err)
 	}
 	trade, err := uniswap.NewTrade(route, amount, uniswap.ExactInput, dexFee)
 	if err != nil {
-		return fmt.Errorf("could not create trade: %w", err)
+		return nil, fmt.Errorf("could not create trade: %w", err)
 	}
 	slippage := int64(target.GetSlippage())
 	if slippage == database.MaxSlippage {
@@ -277,14 +305,15 @@ func setMissingSellTargetInfo(target *database.Target, token *database.Token, pr
 	} else {
 		min, err := trade.MinimumAmountOut(uniswap.NewPercent(big.NewInt(slippage), big.NewInt(database.MaxSlippage)))
 		if err != nil {
-			return fmt.Errorf("could not get minimum amount out: %w", err)
+			return nil, fmt.Errorf("could not get minimum amount out: %w", err)
 		}
 		target.SetAmountMinMax(min.Raw().String())
 	}
 	target.SetPath(route.GetAddresses())
+	target.SetStables(route.GetStables())
 	target.SetExecutionPrice(trade.ExecutionPrice.Decimal())
 	logging.Log.WithFields(logrus.Fields{"execution price": target.GetExecutionPrice().String()}).Info("set sell infos")
-	return nil
+	return trade, nil
 }
 
 func handleSwap(
@@ -292,9 +321,24 @@ func handleSwap(
 	c *Client,
 	logStream chan<- string,
 	wallet *database.Wallet,
-	trade *database.Trade,
-	target *database.Target,
+	guards *config.Guards,
+	swap *guardedSwap,
 ) {
+	trade, target := swap.trade, swap.target
+	reserved, err := c.checkGuards(guards, swap)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"token0": trade.GetToken0().GetContract(),
+			"token1": trade.GetToken1().GetContract(),
+			"amount": target.GetActualAmount(),
+			"err":    err,
+		}).Warn("swap rejected by guards")
+		logStream <- logstream.Format(fmt.Sprintf("swap rejected: %s", err), logstream.ERR)
+		target.SetFailed()
+		cancel()
+		return
+	}
+
 	preBal, err := database.FetchBalanceByContractAndNetworkID(trade.GetToken1().GetContract(), trade.GetNetwork().GetID())
 	if err != nil {
 		logging.Log.WithFields(
@@ -325,6 +369,7 @@ func handleSwap(
 	if err != nil {
 		logStream <- logstream.Format("could not send transaction", logstream.ERR)
 		logging.Log.Error(err)
+		releaseReservedSpending(trade.GetNetwork(), reserved)
 		target.SetFailed()
 		cancel()
 		return
@@ -341,6 +386,7 @@ func handleSwap(
 	if !success {
 		logStream <- logstream.Format("transaction failed", logstream.ERR)
 		logging.Log.Error("transaction failed")
+		releaseReservedSpending(trade.GetNetwork(), reserved)
 		target.SetFailed()
 		cancel()
 		return
@@ -386,7 +432,7 @@ func handleSwap(
 
 		trade.IncrBuyTargetHit()
 
-		if len(trade.GetSellTargets()) == 0 && trade.GetBuyTargetHit() == len(trade.GetBuyTargets()) {
+		if len(trade.GetSellTargets()) == 0 && openTargets(trade.GetBuyTargets()) == 0 {
 			logStream <- logstream.Format("all buy targets hit", logstream.INFO)
 			logging.Log.Info("all buy targets hit")
 			cancel()
@@ -416,7 +462,7 @@ func handleSwap(
 		if trade.HasStoploss() {
 			maxHits = maxHits - 1
 		}
-		if trade.GetSellTargetHit() >= maxHits {
+		if trade.GetSellTargetHit() >= maxHits || openSellTargets(trade) == 0 {
 			logStream <- logstream.Format("all sell targets hit, stopping now...", logstream.INFO)
 			logging.Log.Info("all sell targets hit, stopping now...")
 			cancel()
@@ -446,7 +492,7 @@ func (c *Client) transactionDelegator(tx *types.Transaction) (*big.Int, bool, er
 			return nil, false, ErrTxTimeout
 		case <-retryTicker.C:
 			if receipt == nil {
-				receipt, err = c.Client.TransactionReceipt(context.Background(), tx.Hash())
+				receipt, err = c.Eth().TransactionReceipt(context.Background(), tx.Hash())
 				if err != nil {
 					if errors.Is(err, ethereum.NotFound) {
 						continue
@@ -457,7 +503,7 @@ func (c *Client) transactionDelegator(tx *types.Transaction) (*big.Int, bool, er
 					return nil, false, nil
 				}
 			}
-			txc, _, err := c.Client.TransactionByHash(context.Background(), tx.Hash())
+			txc, _, err := c.Eth().TransactionByHash(context.Background(), tx.Hash())
 			if err != nil {
 				continue
 			}
@@ -467,6 +513,12 @@ func (c *Client) transactionDelegator(tx *types.Transaction) (*big.Int, bool, er
 	}
 }
 
+// WaitForTransaction waits until the transaction is mined and returns whether it was successful.
+func (c *Client) WaitForTransaction(tx *types.Transaction) (bool, error) {
+	_, ok, err := c.transactionDelegator(tx)
+	return ok, err
+}
+
 // rebalanceRelativeSellTargets rebalances the sell targets of the trade which use a relative amount.
 func rebalanceSellTargets(trade *database.Trade) {
 	for _, target := range trade.GetSellTargets() {
@@ -47,27 +47,6 @@ func init() {
 	}
 }
 
-func TestGeneratePairs(t *testing.T) {
-	var tokens []*database.Token
-	err := yaml.Unmarshal(tokensB, &tokens)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	c, err := NewClient(url, mc)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	pairs, err := c.GetValidPairTokens(tokens, "0x5757371414417b8C6CAad45bAeF941aBc7d3Ab32")
-	if err != nil {
-		t.Fatal(err)
-	}
-	if len(pairs) == 0 {
-		t.Error("Expected pairs to be not empty")
-	}
-}
-
 func TestToken(t *testing.T) {
 	token, err := uniswap.NewToken(common.HexToAddress("0x6e7a5FAFcec6BB1e78bAE2A1F0B612012BF14827"), uniswap.Univ2Name, uniswap.Univ2Symbol, 18)
 	if err != nil {
@@ -88,38 +67,6 @@ func TestToken(t *testing.T) {
 	}
 }
 
-func TestRandomPrice(t *testing.T) {
-	c, err := NewClient(url, mc)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	pairs, err := c.generatePairs(quickswap.GetFactory(), tokens...)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	var pair *uniswap.Pair
-	var token0 *uniswap.Token
-	var token1 *uniswap.Token
-	for _, v := range pairs {
-		pair, token0, token1, err = v.ToUniswap()
-		if err != nil {
-			t.Fatal(err)
-		}
-		break // only test the first pair
-	}
-
-	price, err := pair.PriceOf(token0)
-	if err != nil {
-		t.Fatal(err)
-	}
-	t.Log(price.ToSignificant(10))
-	t.Log(token0.Symbol())
-	t.Log(token0.Decimals())
-	t.Log(token1.Symbol())
-}
-
 func TestRouteMidPrice(t *testing.T) {
 	pairToken := common.HexToAddress("0x6e7a5FAFcec6BB1e78bAE2A1F0B612012BF14827")
 
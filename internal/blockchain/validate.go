@@ -0,0 +1,218 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/blockchain/abi/solidlyrouter"
+	"github.com/jon4hz/deadshot/internal/blockchain/abi/uniswapv2factory"
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/pkg/ethutils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// range of valid dex fees, the fee is the part of the amount which is left after the swap in basis points.
+const (
+	minDexFee = 9000
+	maxDexFee = 10000
+)
+
+var (
+	ErrChainIDMismatch  = errors.New("endpoint serves another chain")
+	ErrNoContract       = errors.New("no contract deployed")
+	ErrInvalidAddress   = errors.New("invalid address")
+	ErrInvalidFee       = fmt.Errorf("fee must be between %d and %d", minDexFee, maxDexFee)
+	ErrFactoryMismatch  = errors.New("factory doesn't match the router")
+	ErrWETHMismatch     = errors.New("weth doesn't match the router")
+	ErrInvalidToken     = errors.New("contract is not an erc20 token")
+	ErrDecimalsMismatch = errors.New("decimals don't match the contract")
+
+	ErrInvalidInitCodeHash  = errors.New("invalid init code hash")
+	ErrInitCodeHashMismatch = errors.New("init code hash doesn't match the pairs of the factory")
+	ErrNoReferencePair      = errors.New("no pair of weth and a connector token to verify the init code hash")
+)
+
+// ValidateNetwork checks on-chain that every endpoint serves the chain id of the network
+// and that weth and multicall contracts are deployed. It returns a client for the first endpoint,
+// the caller must close it.
+func ValidateNetwork(n *database.Network) (*Client, error) {
+	if n.GetChainID() == 0 {
+		return nil, errors.New("missing chain id")
+	}
+	for _, contract := range []string{n.GetWETH(), n.GetMulticall()} {
+		if !ethutils.IsValidAddress(contract) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidAddress, contract)
+		}
+	}
+	urls := n.GetEndpoints().GetUrls()
+	if len(urls) == 0 {
+		return nil, ErrNoEndpoint
+	}
+	for _, url := range urls {
+		id, err := GetChainID(url)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", url, err)
+		}
+		if id.Uint64() != uint64(n.GetChainID()) {
+			return nil, fmt.Errorf("%w: %s serves chain %s", ErrChainIDMismatch, url, id)
+		}
+	}
+	c, err := NewClient(urls[0], n.GetMulticall())
+	if err != nil {
+		return nil, err
+	}
+	for _, contract := range []string{n.GetWETH(), n.GetMulticall()} {
+		if err := c.requireCode(contract); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	// fetching the weth token through multicall verifies both contracts at once
+	if _, err := c.ValidateToken(database.NewToken(n.GetWETH(), "", 0, false, nil)); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("weth: %w", err)
+	}
+	return c, nil
+}
+
+// ValidateDex checks on-chain that the router and factory of the dex are deployed and belong together.
+// If the dex has an init code hash, a pair derived with it must match the pair of the factory.
+func (c *Client) ValidateDex(n *database.Network, d *database.Dex) error {
+	if d.GetFee() < minDexFee || d.GetFee() > maxDexFee {
+		return ErrInvalidFee
+	}
+	for _, contract := range []string{d.GetRouter(), d.GetFactory()} {
+		if !ethutils.IsValidAddress(contract) {
+			return fmt.Errorf("%w: %q", ErrInvalidAddress, contract)
+		}
+		if err := c.requireCode(contract); err != nil {
+			return err
+		}
+	}
+
+	var factory, weth common.Address
+	if d.IsSolidly() {
+		router, err := solidlyrouter.NewSolidlyrouter(common.HexToAddress(d.GetRouter()), c.Eth())
+		if err != nil {
+			return err
+		}
+		if factory, err = router.Factory(&bind.CallOpts{}); err != nil {
+			return fmt.Errorf("router: %w", err)
+		}
+		if weth, err = router.Weth(&bind.CallOpts{}); err != nil {
+			return fmt.Errorf("router: %w", err)
+		}
+	} else {
+		router, err := c.NewRouter(d.GetRouter())
+		if err != nil {
+			return err
+		}
+		if factory, err = router.Factory(&bind.CallOpts{}); err != nil {
+			return fmt.Errorf("router: %w", err)
+		}
+		if weth, err = router.WETH(&bind.CallOpts{}); err != nil {
+			return fmt.Errorf("router: %w", err)
+		}
+		f, err := uniswapv2factory.NewUniswapv2factory(common.HexToAddress(d.GetFactory()), c.Eth())
+		if err != nil {
+			return err
+		}
+		if _, err := f.AllPairsLength(&bind.CallOpts{}); err != nil {
+			return fmt.Errorf("factory: %w", err)
+		}
+	}
+	if !strings.EqualFold(factory.Hex(), d.GetFactory()) {
+		return fmt.Errorf("%w: router uses %s", ErrFactoryMismatch, factory.Hex())
+	}
+	if !strings.EqualFold(weth.Hex(), n.GetWETH()) {
+		return fmt.Errorf("%w: router uses %s", ErrWETHMismatch, weth.Hex())
+	}
+	if d.GetInitCodeHash() == "" {
+		return nil
+	}
+	return c.validateInitCodeHash(n, d)
+}
+
+// validateInitCodeHash derives the pair of weth and the first connector token which has one with the init code hash
+// and compares it with the pair returned by the factory.
+func (c *Client) validateInitCodeHash(n *database.Network, d *database.Dex) error {
+	hash := d.GetInitCodeHash()
+	if b, err := hexutil.Decode(hash); err != nil || len(b) != common.HashLength {
+		return fmt.Errorf("%w: %q", ErrInvalidInitCodeHash, hash)
+	}
+	weth := strings.ToLower(n.GetWETH())
+	for _, token := range n.GetTokens() {
+		contract := strings.ToLower(token.GetContract())
+		if !token.IsConnector() || token.GetNative() || contract == weth {
+			continue
+		}
+		token0, token1 := sortTokens(weth, contract)
+		for _, stable := range pairKinds(d) {
+			p := database.NewPair(d.GetID(), token0, token1, stable, "", false)
+			if err := c.checkPairsByFactory(d, []*database.Pair{p}); err != nil {
+				return fmt.Errorf("factory: %w", err)
+			}
+			if !p.GetExists() {
+				continue
+			}
+			derived, err := derivePairAddress(d.GetFactory(), token0, token1, d.IsSolidly(), stable, hash)
+			if err != nil {
+				return err
+			}
+			if !strings.EqualFold(derived, p.GetAddress()) {
+				return fmt.Errorf("%w: derived %s, factory has %s", ErrInitCodeHashMismatch, derived, p.GetAddress())
+			}
+			return nil
+		}
+	}
+	return ErrNoReferencePair
+}
+
+// ValidateToken fetches the symbol and decimals of the token from the chain.
+// A missing symbol or decimals are filled in, set decimals must match the contract.
+func (c *Client) ValidateToken(t *database.Token) (*database.Token, error) {
+	if !ethutils.IsValidAddress(t.GetContract()) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAddress, t.GetContract())
+	}
+	if err := c.requireCode(t.GetContract()); err != nil {
+		return nil, err
+	}
+	infos, err := c.GetTokenInfo(t.GetContract())
+	if err != nil {
+		return nil, err
+	}
+	var info *database.Token
+	for contract, v := range infos {
+		if strings.EqualFold(contract, t.GetContract()) {
+			info = v
+		}
+	}
+	if info == nil || info.GetSymbol() == "" {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, t.GetContract())
+	}
+	if t.GetDecimals() != 0 && t.GetDecimals() != info.GetDecimals() {
+		return nil, fmt.Errorf("%w: %d != %d", ErrDecimalsMismatch, t.GetDecimals(), info.GetDecimals())
+	}
+	t.SetDecimals(info.GetDecimals())
+	if t.GetSymbol() == "" {
+		t.SetSymbol(info.GetSymbol())
+	}
+	return t, nil
+}
+
+// requireCode returns ErrNoContract if no contract is deployed at the address.
+func (c *Client) requireCode(contract string) error {
+	code, err := c.Eth().CodeAt(context.Background(), common.HexToAddress(contract), nil)
+	if err != nil {
+		return err
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("%w at %s", ErrNoContract, contract)
+	}
+	return nil
+}
@@ -4,9 +4,12 @@ import (
 	"errors"
 	"os"
 	"path"
+	"sync"
 	"time"
 
 	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/internal/ratelimit"
 
 	"github.com/spf13/viper"
 )
@@ -26,6 +29,56 @@ type Cfg struct {
 	Debug    bool   `yaml:"debug"`
 	Keystore string `yaml:"keystore"`
 	Password string `yaml:"password"`
+	// LockTimeout is the time of inactivity after which the wallet gets locked.
+	// A timeout of zero never locks the wallet.
+	LockTimeout time.Duration `yaml:"lock_timeout" mapstructure:"lock_timeout"`
+	// Watch is the address of a wallet which is only monitored.
+	// In watch-only mode no private key is loaded and no transactions are sent.
+	Watch string `yaml:"watch"`
+	// Guards are the safety limits of automated trades.
+	// They are reloaded if the config file changes, use GetGuards to read them.
+	Guards Guards `yaml:"guards"`
+	// RateLimits limit the requests to rpc endpoints, they are reloaded if the config file changes.
+	RateLimits []ratelimit.Limit `yaml:"rate_limits" mapstructure:"rate_limits"`
+	// Database selects where the config and the trade history are stored.
+	Database database.Options `yaml:"database"`
+
+	mu sync.RWMutex
+}
+
+// Guards limit the swaps of the trade dispatcher. A zero value disables a limit.
+type Guards struct {
+	// MaxTradeAmount is the maximum value of a single swap in the native currency of the network.
+	MaxTradeAmount float64 `yaml:"max_trade_amount" mapstructure:"max_trade_amount"`
+	// MaxDailyAmount is the maximum value of all swaps on a network per day (UTC) in the native currency.
+	MaxDailyAmount float64 `yaml:"max_daily_amount" mapstructure:"max_daily_amount"`
+	// MaxSlippage is the maximum slippage of a swap in percent.
+	MaxSlippage float64 `yaml:"max_slippage" mapstructure:"max_slippage"`
+	// MaxPriceImpact is the maximum price impact of a swap in percent.
+	MaxPriceImpact float64 `yaml:"max_price_impact" mapstructure:"max_price_impact"`
+	// Tokens are the contracts or symbols of the tokens which may be traded. An empty list allows all tokens.
+	Tokens []string `yaml:"tokens"`
+	// Routers are the router addresses or dex names which may be used. An empty list allows all routers.
+	Routers []string `yaml:"routers"`
+}
+
+// WithProfile returns a copy of the guards with the safety limits of the profile.
+// A profile can only tighten the guards, the stricter limit is used.
+func (g Guards) WithProfile(p *database.Profile) *Guards {
+	if p != nil {
+		g.MaxTradeAmount = stricter(g.MaxTradeAmount, p.GetMaxTradeAmount())
+		g.MaxSlippage = stricter(g.MaxSlippage, p.GetMaxSlippage())
+		g.MaxPriceImpact = stricter(g.MaxPriceImpact, p.GetMaxPriceImpact())
+	}
+	return &g
+}
+
+// stricter returns the lower of two limits. A zero limit is disabled.
+func stricter(a, b float64) float64 {
+	if a <= 0 || (b > 0 && b < a) {
+		return b
+	}
+	return a
 }
 
 var cfg Cfg
@@ -43,16 +96,22 @@ func Init() {
 	if err := viper.Unmarshal(&cfg); err != nil {
 		panic(err)
 	}
+	database.Configure(cfg.Database)
+	ratelimit.Set(cfg.RateLimits)
 }
 
 func GetCfg() *Cfg {
 	return &cfg
 }
 
+// configFile is the path of the config file which was read or empty if there was none.
+var configFile string
+
 func readConfigs() error {
 	var err error
 	for _, file := range []string{defaultConfig, userConfig(), viper.GetString("config")} {
 		if err = readConfig(file); err == nil {
+			configFile = file
 			break
 		}
 	}
@@ -71,7 +130,9 @@ type Config struct {
 	Networks database.Networks
 	Misc     *database.Misc
 	Wallet   *database.Wallet
-	Runtime  *RuntimeConfig
+	// Wallets are the additional wallets besides the main wallet.
+	Wallets []*database.Wallet
+	Runtime *RuntimeConfig
 }
 
 type RuntimeConfig struct {
@@ -94,6 +155,7 @@ func load(inclTestnet bool) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	config.Networks = usableNetworks(config.Networks)
 
 	// load a new runtime config
 	config.Runtime = NewRuntimeConfigWithDefaults()
@@ -103,6 +165,10 @@ func load(inclTestnet bool) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	config.Wallets, err = database.FetchWallets()
+	if err != nil {
+		return nil, err
+	}
 
 	config.Misc, err = database.FetchMisc()
 	if err != nil {
@@ -145,12 +211,30 @@ func (c *Config) validate() error {
 	return nil
 }
 
+// AllWallets returns the main wallet followed by the additional wallets.
+func (c *Config) AllWallets() []*database.Wallet {
+	return append([]*database.Wallet{c.Wallet}, c.Wallets...)
+}
+
 // ReloadNetworks reloads the network config from the database.
 func (c *Config) ReloadNetworks() error {
 	networks, err := database.FetchAllNetworks(c.Runtime.IncludeTestnet)
 	if err != nil {
 		return err
 	}
-	c.Networks = networks
+	c.Networks = usableNetworks(networks)
 	return nil
 }
+
+// usableNetworks removes the user-defined networks which can't be traded on yet, because they have no dex.
+func usableNetworks(networks database.Networks) database.Networks {
+	usable := make(database.Networks, 0, len(networks))
+	for _, n := range networks {
+		if !n.GetPredefined() && len(n.GetDexes()) == 0 {
+			logging.Log.WithField("network", n.GetName()).Warn("skipping network without dexes")
+			continue
+		}
+		usable = append(usable, n)
+	}
+	return usable
+}
@@ -2,9 +2,12 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/ratelimit"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -12,3 +15,44 @@ func TestConfigInOsDir(t *testing.T) {
 	assert.Nil(t, database.InitDB())
 	load(true)
 }
+
+func TestGuardsWithProfile(t *testing.T) {
+	g := Guards{MaxSlippage: 5, MaxTradeAmount: 1}
+	p := &database.Profile{MaxSlippage: 1, MaxPriceImpact: 2, MaxTradeAmount: 10}
+	got := g.WithProfile(p)
+	assert.Equal(t, 1.0, got.MaxSlippage)
+	assert.Equal(t, 2.0, got.MaxPriceImpact)
+	// the profile can't loosen the guards
+	assert.Equal(t, 1.0, got.MaxTradeAmount)
+	assert.Equal(t, 5.0, g.MaxSlippage)
+	assert.Equal(t, &g, g.WithProfile(nil))
+}
+
+func TestReload(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("guards.max_slippage", 2.5)
+	viper.Set("lock_timeout", "5m")
+	viper.Set("database.dsn", "postgres://secret")
+	viper.Set("rate_limits", []map[string]interface{}{{"url": "https://rpc.example", "per_minute": 600}})
+
+	changes, err := Reload()
+	assert.Nil(t, err)
+	byName := make(map[string]Change)
+	for _, c := range changes {
+		byName[c.Setting] = c
+	}
+	assert.Len(t, changes, 4)
+	assert.Equal(t, Change{Setting: "guards.max_slippage", Old: 0.0, New: 2.5}, byName["guards.max_slippage"])
+	assert.True(t, byName["lock_timeout"].Restart)
+	assert.True(t, byName["database.dsn"].Secret)
+	assert.False(t, byName["rate_limits"].Restart)
+
+	// only the reloadable settings are applied
+	assert.Equal(t, 2.5, GetCfg().GetGuards().MaxSlippage)
+	assert.Equal(t, time.Duration(0), GetCfg().LockTimeout)
+	assert.Equal(t, 200*time.Millisecond, ratelimit.GetPriceFeedInterval("https://rpc.example"))
+
+	changes, err = Reload()
+	assert.Nil(t, err)
+	assert.Len(t, changes, 2, "the settings which require a restart are still reported")
+}
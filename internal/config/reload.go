@@ -0,0 +1,136 @@
+package config
+
+import (
+	"reflect"
+
+	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/internal/ratelimit"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// Change is a setting which differs in the reloaded config file.
+type Change struct {
+	Setting  string
+	Old, New interface{}
+	// Restart is set if the change only applies after a restart.
+	Restart bool
+	// Secret is set if the values must not be logged.
+	Secret bool
+}
+
+// setting is a setting of the config file which is compared on reload.
+type setting struct {
+	name       string
+	value      func(c *Cfg) interface{}
+	reloadable bool
+	secret     bool
+}
+
+// settings are compared on reload. The password isn't compared, it's cleared after the wallets are unlocked.
+var settings = []setting{
+	{name: "guards.max_trade_amount", value: func(c *Cfg) interface{} { return c.Guards.MaxTradeAmount }, reloadable: true},
+	{name: "guards.max_daily_amount", value: func(c *Cfg) interface{} { return c.Guards.MaxDailyAmount }, reloadable: true},
+	{name: "guards.max_slippage", value: func(c *Cfg) interface{} { return c.Guards.MaxSlippage }, reloadable: true},
+	{name: "guards.max_price_impact", value: func(c *Cfg) interface{} { return c.Guards.MaxPriceImpact }, reloadable: true},
+	{name: "guards.tokens", value: func(c *Cfg) interface{} { return c.Guards.Tokens }, reloadable: true},
+	{name: "guards.routers", value: func(c *Cfg) interface{} { return c.Guards.Routers }, reloadable: true},
+	{name: "rate_limits", value: func(c *Cfg) interface{} { return c.RateLimits }, reloadable: true},
+	{name: "testnet", value: func(c *Cfg) interface{} { return c.Testnet }},
+	{name: "debug", value: func(c *Cfg) interface{} { return c.Debug }},
+	{name: "keystore", value: func(c *Cfg) interface{} { return c.Keystore }},
+	{name: "lock_timeout", value: func(c *Cfg) interface{} { return c.LockTimeout }},
+	{name: "watch", value: func(c *Cfg) interface{} { return c.Watch }},
+	{name: "database.driver", value: func(c *Cfg) interface{} { return c.Database.Driver }},
+	{name: "database.dsn", value: func(c *Cfg) interface{} { return c.Database.DSN }, secret: true},
+}
+
+// GetGuards returns the current guards.
+func (c *Cfg) GetGuards() Guards {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Guards
+}
+
+// diff returns the changed settings of the next config.
+func (c *Cfg) diff(next *Cfg) []Change {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var changes []Change
+	for _, s := range settings {
+		old, new := s.value(c), s.value(next)
+		if reflect.DeepEqual(old, new) {
+			continue
+		}
+		changes = append(changes, Change{Setting: s.name, Old: old, New: new, Restart: !s.reloadable, Secret: s.secret})
+	}
+	return changes
+}
+
+// apply takes over the settings of the next config which can change at runtime.
+func (c *Cfg) apply(next *Cfg) {
+	c.mu.Lock()
+	c.Guards = next.Guards
+	c.RateLimits = next.RateLimits
+	c.mu.Unlock()
+	ratelimit.Set(next.RateLimits)
+}
+
+// Reload reads the config again and applies the guards and the rate limits.
+// It returns all changed settings, the other settings only apply after a restart.
+func Reload() ([]Change, error) {
+	var next Cfg
+	if err := viper.Unmarshal(&next); err != nil {
+		return nil, err
+	}
+	changes := cfg.diff(&next)
+	cfg.apply(&next)
+	return changes, nil
+}
+
+// Watch reloads the config file if it changes and logs what changed.
+// The function apply is called after the new settings were applied, e.g. to update the running trades.
+func Watch(apply func(c *Cfg)) {
+	if configFile == "" {
+		logging.Log.Debug("no config file to watch")
+		return
+	}
+	// the settings which require a restart are only reported once per value, a save often causes several events
+	reported := make(map[string]interface{})
+	viper.OnConfigChange(func(e fsnotify.Event) {
+		changes, err := Reload()
+		if err != nil {
+			logging.Log.WithFields(logrus.Fields{
+				"config": e.Name,
+				"err":    err,
+			}).Error("failed to reload the config file")
+			return
+		}
+		if len(changes) == 0 {
+			return
+		}
+		for _, c := range changes {
+			l := logging.Log.WithField("setting", c.Setting)
+			if !c.Secret {
+				l = l.WithFields(logrus.Fields{
+					"old": c.Old,
+					"new": c.New,
+				})
+			}
+			if c.Restart {
+				if v, ok := reported[c.Setting]; ok && reflect.DeepEqual(v, c.New) {
+					continue
+				}
+				reported[c.Setting] = c.New
+				l.Warn("config changed, restart to apply it")
+				continue
+			}
+			l.Info("config changed")
+		}
+		apply(&cfg)
+	})
+	viper.WatchConfig()
+	logging.Log.WithField("config", configFile).Info("watching the config file")
+}
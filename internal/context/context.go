@@ -24,8 +24,12 @@ type Context struct {
 	Unlocked         bool
 	LastUnlockedTime time.Time
 	UnlockerMsg      string
+	// LastActivity is the time of the last user input. The wallet gets locked if it's older than the lock timeout.
+	LastActivity time.Time
 
 	Network *database.Network
+	// Wallet is the wallet selected for the trade.
+	Wallet *database.Wallet
 
 	LatencyResultChan chan LatencyResult
 	LatencyResultDone chan struct{}
@@ -38,7 +42,10 @@ type Context struct {
 	Token1        *database.Token
 
 	Dex       *database.Dex
+	AllDexes  bool
 	TradeType *database.TradeType
+	// Profile is the trading profile of the trade, nil uses the defaults.
+	Profile *database.Profile
 
 	Price *chain.Price
 
@@ -50,10 +57,32 @@ type Context struct {
 	Trade *database.Trade
 }
 
+// SetUnlocked marks the wallets as unlocked and resets the idle timer.
+func (c *Context) SetUnlocked() {
+	c.Unlocked = true
+	c.LastUnlockedTime = time.Now()
+	c.LastActivity = c.LastUnlockedTime
+}
+
+// WatchOnly returns whether the wallet is only monitored.
+func (c *Context) WatchOnly() bool {
+	return c.Config.Wallet != nil && c.Config.Wallet.IsWatchOnly()
+}
+
+// TradeWallet returns the wallet selected for the trade or the main wallet if none was selected.
+func (c *Context) TradeWallet() *database.Wallet {
+	if c.Wallet != nil {
+		return c.Wallet
+	}
+	return c.Config.Wallet
+}
+
 type Secret struct {
 	Secret     string
 	Index      int
 	IsMnmeonic bool
+	// DerivationPath is the template of the derivation path of a mnemonic phrase.
+	DerivationPath string
 }
 
 type LatencyResult struct {
@@ -0,0 +1,133 @@
+// Package custom validates user-defined networks, dexes and tokens on-chain before they are saved.
+package custom
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/database"
+
+	chain "github.com/jon4hz/deadshot/internal/blockchain"
+)
+
+// default values of user-defined entries.
+const (
+	DefaultGasLimit = 1000000
+	DefaultDexFee   = 9970
+	nativeDecimals  = 18
+)
+
+var ErrMissingName = errors.New("missing name")
+
+// AddNetwork validates a new network on-chain and saves it.
+// The native currency and weth are added as tokens of the network.
+func AddNetwork(n *database.Network) error {
+	if strings.TrimSpace(n.Name) == "" {
+		return ErrMissingName
+	}
+	if n.GasLimit == 0 {
+		n.GasLimit = DefaultGasLimit
+	}
+	c, err := chain.ValidateNetwork(n)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	weth, err := c.ValidateToken(database.NewToken(n.WETH, "", 0, false, nil))
+	if err != nil {
+		return err
+	}
+	weth.SetConnector(true)
+	native := database.NewToken(chain.Zero, n.NativeCurrency, nativeDecimals, true, nil)
+	n.Tokens = append(n.Tokens, native, weth)
+	return database.CreateNetwork(n)
+}
+
+// EditNetwork validates the changed network on-chain and saves it.
+// If weth changed, the new weth is added as token of the network.
+func EditNetwork(n *database.Network) error {
+	if n.GetPredefined() {
+		return database.ErrPredefined
+	}
+	c, err := chain.ValidateNetwork(n)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if n.GetTokenByContract(n.GetWETH()) == nil {
+		weth, err := c.ValidateToken(database.NewToken(n.GetWETH(), "", 0, false, nil))
+		if err != nil {
+			return err
+		}
+		weth.SetConnector(true)
+		if err := database.CreateToken(n, weth); err != nil {
+			return err
+		}
+	}
+	return database.UpdateNetwork(n)
+}
+
+// AddDex validates a new dex on-chain and adds it to the network.
+func AddDex(n *database.Network, d *database.Dex) error {
+	if strings.TrimSpace(d.Name) == "" {
+		return ErrMissingName
+	}
+	if d.Fee == 0 {
+		d.Fee = DefaultDexFee
+	}
+	c, err := chain.Connect(n)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if err := c.ValidateDex(n, d); err != nil {
+		return err
+	}
+	return database.CreateDex(n, d)
+}
+
+// EditDex validates the changed dex on-chain and saves it.
+func EditDex(n *database.Network, d *database.Dex) error {
+	if d.GetPredefined() {
+		return database.ErrPredefined
+	}
+	c, err := chain.Connect(n)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if err := c.ValidateDex(n, d); err != nil {
+		return err
+	}
+	return database.UpdateDex(d)
+}
+
+// AddToken validates a new token on-chain and adds it to the network.
+// The symbol and decimals are fetched from the contract if they aren't set.
+func AddToken(n *database.Network, t *database.Token) error {
+	c, err := chain.Connect(n)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if _, err := c.ValidateToken(t); err != nil {
+		return err
+	}
+	return database.CreateToken(n, t)
+}
+
+// EditToken validates the changed token on-chain and saves it.
+func EditToken(n *database.Network, t *database.Token) error {
+	if t.GetPredefined() {
+		return database.ErrPredefined
+	}
+	c, err := chain.Connect(n)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if _, err := c.ValidateToken(t); err != nil {
+		return err
+	}
+	return database.UpdateToken(t)
+}
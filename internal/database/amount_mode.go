@@ -59,7 +59,7 @@ func (a AmountModes) GetAmountOut() *AmountMode {
 
 // LoadAllAmountModes fetches all amount modes from the database and sets them as a global variable..
 func LoadAllAmountModes() error {
-	result := findAllAmountModes(&DefaultAmountModes)
+	result := repo.FindAllAmountModes(&DefaultAmountModes)
 	if result.Error != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"err": result.Error,
@@ -0,0 +1,201 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/jon4hz/deadshot/internal/logging"
+
+	"github.com/glebarez/sqlite"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	// ErrUnsupportedBackend is returned if the backend doesn't support an operation, e.g. a backup of postgres.
+	ErrUnsupportedBackend = errors.New("not supported by the database backend")
+	// ErrCorrupt is returned if a database fails the integrity check.
+	ErrCorrupt = errors.New("the database is corrupt")
+)
+
+// restoreSuffix is appended to the database file which is replaced by a restore.
+const restoreSuffix = ".before-restore"
+
+// Issue is a problem found by the database check.
+type Issue struct {
+	// Check is the name of the check which found the problem.
+	Check string
+	// Detail describes the problem.
+	Detail string
+	// Integrity marks a problem of the database file itself, all other problems are inconsistent data.
+	Integrity bool
+}
+
+func (i Issue) String() string {
+	return i.Check + ": " + i.Detail
+}
+
+// Backup writes a consistent copy of the sqlite database to the file, while the database may be in use.
+// The file must not exist.
+func Backup(file string) error {
+	return repo.Backup(file)
+}
+
+// Check runs the integrity check of the database and looks for inconsistent data.
+func Check() ([]Issue, error) {
+	return repo.Check()
+}
+
+// CheckFile runs the checks on a sqlite database file without migrating it, e.g. on a backup.
+func CheckFile(file string) ([]Issue, error) {
+	if _, err := os.Stat(file); err != nil {
+		return nil, err
+	}
+	db, err := gorm.Open(sqlite.Open(file), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if d, err := db.DB(); err == nil {
+			d.Close()
+		}
+	}()
+	return checkDB(db)
+}
+
+// Restore replaces the sqlite database with the backup file.
+// The backup must pass the integrity check. The replaced database is kept next to it with the suffix .before-restore.
+// The database must not be open, it returns the path of the replaced database or an empty string if there was none.
+func Restore(backup string) (string, error) {
+	file := File()
+	if file == "" {
+		return "", fmt.Errorf("restore %w", ErrUnsupportedBackend)
+	}
+	issues, err := CheckFile(backup)
+	if err != nil {
+		return "", err
+	}
+	for _, issue := range issues {
+		if issue.Integrity {
+			return "", fmt.Errorf("%w: %s", ErrCorrupt, issue)
+		}
+	}
+
+	// copy the backup next to the database first, so the database is never left half written
+	tmp := file + ".restore"
+	if err := copyFile(backup, tmp); err != nil {
+		return "", err
+	}
+	var previous string
+	if _, err := os.Stat(file); err == nil {
+		previous = file + restoreSuffix
+		if err := os.Rename(file, previous); err != nil {
+			os.Remove(tmp)
+			return "", err
+		}
+	}
+	// the journal files belong to the replaced database
+	for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+		if err := os.Remove(file + suffix); err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		return "", err
+	}
+	logging.Log.WithFields(logrus.Fields{
+		"backup":   backup,
+		"previous": previous,
+	}).Info("restored the database")
+	return previous, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), defaultFolderPermissions); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// consistencyChecks find rows which reference missing rows or which should be unique.
+// Each query returns a description of the inconsistent rows.
+var consistencyChecks = []struct {
+	name  string
+	query string
+}{
+	{
+		name:  "targets without trades",
+		query: "SELECT 'target ' || id FROM targets WHERE deleted_at IS NULL AND (trade_id IS NULL OR trade_id NOT IN (SELECT id FROM trades))",
+	},
+	{
+		name:  "raw targets without trades",
+		query: "SELECT 'raw target ' || id FROM raw_targets WHERE deleted_at IS NULL AND (trade_id IS NULL OR trade_id NOT IN (SELECT id FROM trades))",
+	},
+	{
+		name:  "tokens without networks",
+		query: "SELECT 'token ' || id || ' ' || contract FROM tokens WHERE deleted_at IS NULL AND (network_id IS NULL OR network_id NOT IN (SELECT id FROM networks))",
+	},
+	{
+		name:  "duplicate tokens",
+		query: "SELECT 'token ' || LOWER(contract) || ' on network ' || network_id || ' exists ' || COUNT(*) || ' times' FROM tokens WHERE deleted_at IS NULL GROUP BY network_id, LOWER(contract) HAVING COUNT(*) > 1",
+	},
+	{
+		name:  "dexes without networks",
+		query: "SELECT 'dex ' || id || ' ' || name FROM dexes WHERE deleted_at IS NULL AND (network_id IS NULL OR network_id NOT IN (SELECT id FROM networks))",
+	},
+	{
+		name:  "endpoints without networks",
+		query: "SELECT 'endpoint ' || id || ' ' || url FROM endpoints WHERE deleted_at IS NULL AND (network_id IS NULL OR network_id NOT IN (SELECT id FROM networks))",
+	},
+	{
+		name:  "pairs without dexes",
+		query: "SELECT 'pair ' || id FROM pairs WHERE deleted_at IS NULL AND (dex_id IS NULL OR dex_id NOT IN (SELECT id FROM dexes))",
+	},
+}
+
+// checkDB runs the integrity check of sqlite and the consistency checks.
+func checkDB(db *gorm.DB) ([]Issue, error) {
+	var issues []Issue
+	if db.Dialector.Name() == DriverSQLite {
+		var results []string
+		if err := db.Raw("PRAGMA integrity_check").Scan(&results).Error; err != nil {
+			return nil, err
+		}
+		for _, r := range results {
+			if r != "ok" {
+				issues = append(issues, Issue{Check: "integrity check", Detail: r, Integrity: true})
+			}
+		}
+		if len(issues) > 0 {
+			// the data of a corrupt database can't be trusted
+			return issues, nil
+		}
+	}
+	for _, c := range consistencyChecks {
+		var details []string
+		if err := db.Raw(c.query).Scan(&details).Error; err != nil {
+			return nil, fmt.Errorf("%s: %w", c.name, err)
+		}
+		for _, d := range details {
+			issues = append(issues, Issue{Check: c.name, Detail: d})
+		}
+	}
+	return issues, nil
+}
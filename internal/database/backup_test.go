@@ -0,0 +1,96 @@
+package database
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestBackup(t *testing.T) {
+	if File() == "" {
+		t.Skip("backups are only supported by sqlite")
+	}
+	dir := t.TempDir()
+	backup := filepath.Join(dir, "backup.db")
+	if err := Backup(backup); err != nil {
+		t.Fatal(err)
+	}
+	if err := Backup(backup); err == nil {
+		t.Error("expected an error for an existing file")
+	}
+	issues, err := CheckFile(backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range issues {
+		if issue.Integrity {
+			t.Errorf("unexpected integrity issue: %s", issue)
+		}
+	}
+
+	// add inconsistent data to the backup, the foreign keys are only enforced by openRepository
+	db, err := gorm.Open(sqlite.Open(backup), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&Target{TradeID: 999999}).Error
+	if d, err := db.DB(); err == nil {
+		d.Close()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues, err = CheckFile(backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasIssue(issues, "targets without trades") {
+		t.Errorf("expected the target without trade, got %v", issues)
+	}
+
+	// a corrupt file can't be restored
+	corrupt := filepath.Join(dir, "corrupt.db")
+	data, err := os.ReadFile(backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 4096; i < len(data) && i < 3*4096; i++ {
+		data[i] = 0xff
+	}
+	if err := os.WriteFile(corrupt, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	defer Configure(options)
+	Configure(Options{DSN: filepath.Join(dir, "config.db")})
+	if _, err := Restore(corrupt); err == nil {
+		t.Error("expected an error for a corrupt backup")
+	}
+	if _, err := os.Stat(File()); !errors.Is(err, os.ErrNotExist) {
+		t.Error("the database shouldn't be written by a failed restore")
+	}
+
+	if previous, err := Restore(backup); err != nil || previous != "" {
+		t.Fatalf("Restore() = %q, %v", previous, err)
+	}
+	previous, err := Restore(backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if previous != File()+restoreSuffix {
+		t.Errorf("previous = %q, want the replaced database", previous)
+	}
+}
+
+func hasIssue(issues []Issue, check string) bool {
+	for _, issue := range issues {
+		if issue.Check == check {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,320 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/pkg/ethutils"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ImportStrategy decides how an imported config is combined with the existing config.
+type ImportStrategy string
+
+const (
+	// ImportMerge adds and updates the imported entries and keeps all other entries.
+	ImportMerge ImportStrategy = "merge"
+	// ImportReplace also removes the user-defined entries which aren't part of the imported config.
+	ImportReplace ImportStrategy = "replace"
+)
+
+var (
+	ErrInvalidImportStrategy = errors.New(`invalid import strategy, use "merge" or "replace"`)
+	ErrInvalidConfigFile     = errors.New("invalid config file")
+)
+
+// ConfigFile is an exported config. It uses the same schema as the built-in data.
+type ConfigFile struct {
+	Networks []*Network `yaml:"networks"`
+}
+
+// ExportConfig writes all networks with their endpoints, dexes and tokens as yaml to w.
+// The custom endpoints and the approval policies of the tokens are exported as well.
+func ExportConfig(w io.Writer) error {
+	networks, err := FetchAllNetworks(true)
+	if err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	if err := enc.Encode(&ConfigFile{Networks: networks}); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// ImportConfig reads a config from r and combines it with the existing config.
+// Predefined entries aren't changed, except for the custom endpoint and the approval policies of the tokens.
+// Entries which are still referenced by a trade are never removed.
+func ImportConfig(r io.Reader, strategy ImportStrategy) error {
+	if strategy != ImportMerge && strategy != ImportReplace {
+		return ErrInvalidImportStrategy
+	}
+	var file ConfigFile
+	if err := yaml.NewDecoder(r).Decode(&file); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidConfigFile, err)
+	}
+	if err := file.validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidConfigFile, err)
+	}
+	return repo.Transaction(func(r Repository) error {
+		names := make(map[string]bool, len(file.Networks))
+		for _, n := range file.Networks {
+			names[n.Name] = true
+			if err := importNetwork(r, n, strategy); err != nil {
+				return fmt.Errorf("network %s: %w", n.Name, err)
+			}
+		}
+		if strategy != ImportReplace {
+			return nil
+		}
+		var networks []*Network
+		if err := r.FindUserDefinedNetworks(&networks).Error; err != nil {
+			return err
+		}
+		for _, n := range networks {
+			if names[n.Name] {
+				continue
+			}
+			used, err := usedByTrades(r, "network_id", n.ID)
+			if err != nil {
+				return err
+			}
+			if used {
+				logging.Log.WithField("network", n.Name).Warn("keeping network, it's used by a trade")
+				continue
+			}
+			if err := r.DeleteNetworkCascade(n); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// validate checks the required fields and the addresses of the config.
+func (f *ConfigFile) validate() error {
+	names := make(map[string]bool, len(f.Networks))
+	for _, n := range f.Networks {
+		if strings.TrimSpace(n.Name) == "" {
+			return errors.New("network without name")
+		}
+		if names[n.Name] {
+			return fmt.Errorf("duplicate network %s", n.Name)
+		}
+		names[n.Name] = true
+		if n.ChainID == 0 {
+			return fmt.Errorf("network %s: missing chain id", n.Name)
+		}
+		if !ethutils.IsValidAddress(n.WETH) || !ethutils.IsValidAddress(n.Multicall) {
+			return fmt.Errorf("network %s: invalid weth or multicall", n.Name)
+		}
+		var custom int
+		for _, e := range n.Endpoints {
+			if strings.TrimSpace(e.URL) == "" {
+				return fmt.Errorf("network %s: endpoint without url", n.Name)
+			}
+			if e.Custom {
+				custom++
+			}
+		}
+		if custom > 1 {
+			return fmt.Errorf("network %s: more than one custom endpoint", n.Name)
+		}
+		for _, d := range n.Dexes {
+			if strings.TrimSpace(d.Name) == "" {
+				return fmt.Errorf("network %s: dex without name", n.Name)
+			}
+			if !ethutils.IsValidAddress(d.Router) || !ethutils.IsValidAddress(d.Factory) {
+				return fmt.Errorf("network %s: dex %s: invalid router or factory", n.Name, d.Name)
+			}
+		}
+		for _, t := range n.Tokens {
+			if !ethutils.IsValidAddress(t.Contract) {
+				return fmt.Errorf("network %s: invalid token %q", n.Name, t.Contract)
+			}
+		}
+	}
+	return nil
+}
+
+// importNetwork adds the network or updates the existing network.
+// Only the user settings of a predefined network are updated.
+func importNetwork(r Repository, n *Network, strategy ImportStrategy) error {
+	n.Predefined = false
+	for _, token := range n.Tokens {
+		token.Predefined = false
+	}
+	for _, dex := range n.Dexes {
+		dex.Predefined = false
+	}
+
+	var existing Network
+	res := r.FindNetworkWithAssociationsByName(&existing, n.Name)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return r.SaveNetwork(n).Error
+	}
+
+	var custom *Endpoint
+	endpoints := make(Endpoints, 0, len(n.Endpoints))
+	for _, e := range n.Endpoints {
+		if e.Custom {
+			custom = e
+			continue
+		}
+		endpoints = append(endpoints, e)
+	}
+	if !existing.Predefined {
+		if err := r.UpdateNetwork(&existing, networkFields(n)).Error; err != nil {
+			return err
+		}
+		if err := mergeEndpoints(r, &existing, endpoints); err != nil {
+			return err
+		}
+	}
+	if err := importCustomEndpoint(r, &existing, custom, strategy); err != nil {
+		return err
+	}
+	if err := importDexes(r, &existing, n.Dexes, strategy); err != nil {
+		return err
+	}
+	return importTokens(r, &existing, n.Tokens, strategy)
+}
+
+// importCustomEndpoint sets the custom endpoint of the network.
+// Without an imported custom endpoint, the existing one is only removed by the replace strategy.
+func importCustomEndpoint(r Repository, n *Network, custom *Endpoint, strategy ImportStrategy) error {
+	var existing *Endpoint
+	for _, e := range n.Endpoints {
+		if e.Custom {
+			existing = e
+			break
+		}
+	}
+	switch {
+	case custom != nil && existing != nil:
+		return r.UpdateEndpointURL(existing, custom.URL).Error
+	case custom != nil:
+		custom.NetworkID = n.ID
+		return r.SaveEndpoint(custom).Error
+	case existing != nil && strategy == ImportReplace:
+		used, err := usedByTrades(r, "endpoint_id", existing.ID)
+		if err != nil || used {
+			return err
+		}
+		return r.DeleteEndpoint(existing).Error
+	}
+	return nil
+}
+
+// importDexes adds new dexes and updates the user-defined dexes of the network.
+func importDexes(r Repository, n *Network, dexes []*Dex, strategy ImportStrategy) error {
+	imported := make(map[string]bool, len(dexes))
+	for _, dex := range dexes {
+		imported[strings.ToLower(dex.Name)] = true
+		var existing *Dex
+		for _, d := range n.Dexes {
+			if strings.EqualFold(d.Name, dex.Name) {
+				existing = d
+				break
+			}
+		}
+		switch {
+		case existing == nil:
+			dex.NetworkID = n.ID
+			if err := r.SaveDex(dex).Error; err != nil {
+				return err
+			}
+		case !existing.Predefined:
+			if err := updateDex(r, existing, dex); err != nil {
+				return err
+			}
+		}
+	}
+	if strategy != ImportReplace {
+		return nil
+	}
+	for _, d := range n.Dexes {
+		if d.Predefined || imported[strings.ToLower(d.Name)] {
+			continue
+		}
+		used, err := usedByTrades(r, "dex_id", d.ID)
+		if err != nil {
+			return err
+		}
+		if used {
+			continue
+		}
+		if err := r.DeleteDexAndPairs(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importTokens adds new tokens and updates the user-defined tokens of the network.
+// Of a predefined token only the approval policy is updated.
+func importTokens(r Repository, n *Network, tokens []*Token, strategy ImportStrategy) error {
+	imported := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		imported[strings.ToLower(token.Contract)] = true
+		var existing *Token
+		for _, t := range n.Tokens {
+			if strings.EqualFold(t.Contract, token.Contract) {
+				existing = t
+				break
+			}
+		}
+		if existing == nil {
+			token.NetworkID = n.ID
+			if err := r.SaveToken(token).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		fields := map[string]any{"UnlimitedApproval": token.UnlimitedApproval, "Starred": token.Starred}
+		if !existing.Predefined {
+			fields = tokenFields(token)
+			fields["UnlimitedApproval"] = token.UnlimitedApproval
+			fields["Starred"] = token.Starred
+		}
+		if err := r.UpdateToken(existing, fields).Error; err != nil {
+			return err
+		}
+	}
+	if strategy != ImportReplace {
+		return nil
+	}
+	for _, t := range n.Tokens {
+		if t.Predefined || imported[strings.ToLower(t.Contract)] {
+			continue
+		}
+		used, err := tokenUsedByTrades(r, t.ID)
+		if err != nil {
+			return err
+		}
+		if used {
+			continue
+		}
+		if err := r.DeleteToken(t).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func tokenUsedByTrades(r Repository, id uint) (bool, error) {
+	for _, column := range []string{"token0_id", "token1_id"} {
+		used, err := usedByTrades(r, column, id)
+		if err != nil || used {
+			return used, err
+		}
+	}
+	return false, nil
+}
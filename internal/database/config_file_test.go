@@ -0,0 +1,84 @@
+package database
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testConfigFile = `networks:
+- name: deadshot-import-test
+  chainId: 1340
+  nativeCurrency: TEST
+  weth: "0x0000000000000000000000000000000000000001"
+  multicall: "0x0000000000000000000000000000000000000002"
+  endpoints:
+  - url: http://a
+  - url: http://custom
+    custom: true
+  dexes:
+  - name: testswap
+    router: "0x0000000000000000000000000000000000000003"
+    factory: "0x0000000000000000000000000000000000000004"
+    fee: 9970
+  tokens:
+  - contract: "0x0000000000000000000000000000000000000005"
+    symbol: TKN
+    decimals: 18
+    unlimitedApproval: true
+`
+
+func TestImportConfig(t *testing.T) {
+	if err := ImportConfig(strings.NewReader(testConfigFile), ImportMerge); err != nil {
+		t.Fatal(err)
+	}
+	n := fetchNetwork(t, "deadshot-import-test")
+	defer func() {
+		if err := repo.DeleteNetworkCascade(n); err != nil {
+			t.Error(err)
+		}
+	}()
+	if n.GetPredefined() {
+		t.Error("imported network must not be predefined")
+	}
+	if e, ok := n.GetCustomEndpoint(); !ok || e.GetURL() != "http://custom" {
+		t.Error("custom endpoint not imported")
+	}
+	if token := n.GetTokenByContract("0x0000000000000000000000000000000000000005"); token == nil || !token.GetUnlimitedApproval() {
+		t.Error("token or approval policy not imported")
+	}
+
+	// importing the export again must not change anything
+	var buf bytes.Buffer
+	if err := ExportConfig(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := ImportConfig(&buf, ImportMerge); err != nil {
+		t.Fatal(err)
+	}
+	n = fetchNetwork(t, "deadshot-import-test")
+	if len(n.GetEndpoints()) != 2 || len(n.GetDexes()) != 1 || len(n.GetTokens()) != 1 {
+		t.Errorf("unexpected network after re-import: %d endpoints, %d dexes, %d tokens", len(n.GetEndpoints()), len(n.GetDexes()), len(n.GetTokens()))
+	}
+
+	// replacing with a config without dexes removes the user-defined dex
+	withoutDexes := testConfigFile[:strings.Index(testConfigFile, "  dexes:")] + testConfigFile[strings.Index(testConfigFile, "  tokens:"):]
+	if err := ImportConfig(strings.NewReader(withoutDexes), ImportReplace); err != nil {
+		t.Fatal(err)
+	}
+	n = fetchNetwork(t, "deadshot-import-test")
+	if len(n.GetDexes()) != 0 {
+		t.Errorf("expected no dexes after replace, got %d", len(n.GetDexes()))
+	}
+}
+
+func TestImportConfigInvalid(t *testing.T) {
+	if err := ImportConfig(strings.NewReader(testConfigFile), "overwrite"); !errors.Is(err, ErrInvalidImportStrategy) {
+		t.Errorf("expected %v, got %v", ErrInvalidImportStrategy, err)
+	}
+	invalid := strings.Replace(testConfigFile, "chainId: 1340", "chainId: 0", 1)
+	if err := ImportConfig(strings.NewReader(invalid), ImportMerge); !errors.Is(err, ErrInvalidConfigFile) {
+		t.Errorf("expected %v, got %v", ErrInvalidConfigFile, err)
+	}
+}
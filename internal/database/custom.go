@@ -0,0 +1,235 @@
+package database
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/logging"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrPredefined    = errors.New("predefined entries can't be changed")
+	ErrNetworkExists = errors.New("network already exists")
+	ErrDexExists     = errors.New("dex already exists")
+	ErrDexNotFound   = errors.New("dex not found")
+	ErrTokenExists   = errors.New("token already exists")
+)
+
+// CreateNetwork saves a user-defined network with its endpoints, tokens and dexes.
+func CreateNetwork(n *Network) error {
+	var existing Network
+	res := repo.FindNetworkByName(&existing, n.Name)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		return ErrNetworkExists
+	}
+	n.Predefined = false
+	for _, token := range n.Tokens {
+		token.SetPredefined(false)
+	}
+	for _, dex := range n.Dexes {
+		dex.SetPredefined(false)
+	}
+	if err := repo.SaveNetwork(n).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"network": n.Name,
+			"err":     err,
+		}).Error("failed to save the network")
+		return err
+	}
+	return nil
+}
+
+// UpdateNetwork saves the changes of a user-defined network and replaces its endpoints.
+// The custom endpoint of the network is kept.
+func UpdateNetwork(n *Network) error {
+	if n.Predefined {
+		return ErrPredefined
+	}
+	if err := repo.ReplaceNetworkEndpoints(n); err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"network": n.Name,
+			"err":     err,
+		}).Error("failed to update the network")
+		return err
+	}
+	return nil
+}
+
+// DeleteNetwork removes a user-defined network with its endpoints, tokens, dexes and cached pairs.
+func DeleteNetwork(n *Network) error {
+	if n.Predefined {
+		return ErrPredefined
+	}
+	if err := repo.DeleteNetworkCascade(n); err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"network": n.Name,
+			"err":     err,
+		}).Error("failed to delete the network")
+		return err
+	}
+	return nil
+}
+
+// CreateDex saves a user-defined dex on the network.
+func CreateDex(n *Network, d *Dex) error {
+	if n.GetDexByName(d.Name) != nil {
+		return ErrDexExists
+	}
+	d.NetworkID = n.GetID()
+	d.Predefined = false
+	if err := repo.SaveDex(d).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"dex": d.Name,
+			"err": err,
+		}).Error("failed to save the dex")
+		return err
+	}
+	n.mu.Lock()
+	n.Dexes = append(n.Dexes, d)
+	n.mu.Unlock()
+	return nil
+}
+
+// UpdateDex saves the changes of a user-defined dex.
+// The cached pairs of the dex are removed, since they depend on the factory.
+func UpdateDex(d *Dex) error {
+	if d.GetPredefined() {
+		return ErrPredefined
+	}
+	if err := repo.UpdateDexAndPairs(d); err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"dex": d.GetName(),
+			"err": err,
+		}).Error("failed to update the dex")
+		return err
+	}
+	return nil
+}
+
+// DeleteDex removes a user-defined dex and its cached pairs from the network.
+func DeleteDex(n *Network, d *Dex) error {
+	if d.GetPredefined() {
+		return ErrPredefined
+	}
+	if err := repo.DeleteDexAndPairs(d); err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"dex": d.GetName(),
+			"err": err,
+		}).Error("failed to delete the dex")
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for i, dex := range n.Dexes {
+		if dex == d {
+			n.Dexes = append(n.Dexes[:i], n.Dexes[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// CreateToken saves a user-defined token on the network.
+// A token which is already known from a trade is updated instead.
+func CreateToken(n *Network, t *Token) error {
+	existing := n.GetTokenByContract(t.Contract)
+	if existing != nil && existing.GetPredefined() {
+		return ErrTokenExists
+	}
+	t.NetworkID = n.GetID()
+	t.Predefined = false
+	if existing != nil {
+		t.ID = existing.ID
+		t.Balance = existing.Balance
+		t.UnlimitedApproval = existing.GetUnlimitedApproval()
+		t.Starred = existing.GetStarred()
+		t.LastUsedAt = existing.GetLastUsedAt()
+	}
+	if err := repo.SaveToken(t).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"token": t.Contract,
+			"err":   err,
+		}).Error("failed to save the token")
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for i, token := range n.Tokens {
+		if token == existing {
+			n.Tokens[i] = t
+			return nil
+		}
+	}
+	n.Tokens = append(n.Tokens, t)
+	return nil
+}
+
+// UpdateToken saves the changes of a user-defined token.
+func UpdateToken(t *Token) error {
+	if t.GetPredefined() {
+		return ErrPredefined
+	}
+	if err := repo.SaveToken(t).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"token": t.GetContract(),
+			"err":   err,
+		}).Error("failed to update the token")
+		return err
+	}
+	return nil
+}
+
+// DeleteToken removes a user-defined token from the network.
+func DeleteToken(n *Network, t *Token) error {
+	if t.GetPredefined() {
+		return ErrPredefined
+	}
+	if err := repo.DeleteToken(t).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"token": t.GetContract(),
+			"err":   err,
+		}).Error("failed to delete the token")
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for i, token := range n.Tokens {
+		if token == t {
+			n.Tokens = append(n.Tokens[:i], n.Tokens[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// GetDexByName returns the dex with the name (case insensitive) or nil if there is none.
+func (n *Network) GetDexByName(name string) *Dex {
+	for _, dex := range n.GetDexes() {
+		if strings.EqualFold(dex.GetName(), name) {
+			return dex
+		}
+	}
+	return nil
+}
+
+// GetTokenByContract returns the token with the contract (case insensitive) or nil if there is none.
+func (n *Network) GetTokenByContract(contract string) *Token {
+	for _, token := range n.GetTokens() {
+		if strings.EqualFold(token.GetContract(), contract) {
+			return token
+		}
+	}
+	return nil
+}
+
+// GetPredefined returns whether the network is predefined.
+func (n *Network) GetPredefined() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.Predefined
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCustomNetwork(t *testing.T) {
+	n := &Network{
+		Name:           "deadshot-test",
+		FullName:       "Deadshot Test",
+		ChainID:        1337,
+		NativeCurrency: "TEST",
+		WETH:           "0x0000000000000000000000000000000000000001",
+		Multicall:      "0x0000000000000000000000000000000000000002",
+		Endpoints:      Endpoints{NewEndpoint("http://localhost:8545", false)},
+		Predefined:     true,
+	}
+	if err := CreateNetwork(n); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := DeleteNetwork(n); err != nil {
+			t.Error(err)
+		}
+	}()
+	if n.GetPredefined() {
+		t.Error("created network must not be predefined")
+	}
+	if err := CreateNetwork(&Network{Name: n.Name}); !errors.Is(err, ErrNetworkExists) {
+		t.Errorf("expected %v, got %v", ErrNetworkExists, err)
+	}
+
+	d := NewDex("testswap", "0x0000000000000000000000000000000000000003", "0x0000000000000000000000000000000000000004", 9970, true)
+	if err := CreateDex(n, d); err != nil {
+		t.Fatal(err)
+	}
+	if n.GetDexByName("TESTSWAP") != d {
+		t.Error("dex not added to the network")
+	}
+	if err := CreateDex(n, NewDex("testswap", "", "", 9970, false)); !errors.Is(err, ErrDexExists) {
+		t.Errorf("expected %v, got %v", ErrDexExists, err)
+	}
+
+	token := NewToken("0x0000000000000000000000000000000000000005", "TKN", 18, false, nil)
+	if err := CreateToken(n, token); err != nil {
+		t.Fatal(err)
+	}
+	networks, err := FetchAllNetworks(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := Networks(networks).GetNetworkByName(n.Name)
+	if saved == nil {
+		t.Fatal("network not saved")
+	}
+	if len(saved.GetDexes()) != 1 || saved.GetTokenByContract(token.GetContract()) == nil {
+		t.Errorf("expected the dex and the token, got %d dexes and %d tokens", len(saved.GetDexes()), len(saved.GetTokens()))
+	}
+
+	if err := DeleteToken(n, token); err != nil {
+		t.Error(err)
+	}
+	if err := DeleteDex(n, d); err != nil {
+		t.Error(err)
+	}
+	if len(n.GetDexes()) != 0 || len(n.GetTokens()) != 0 {
+		t.Error("dex or token not removed from the network")
+	}
+}
+
+func TestPredefinedCantChange(t *testing.T) {
+	if err := DeleteNetwork(&Network{Predefined: true}); !errors.Is(err, ErrPredefined) {
+		t.Errorf("expected %v, got %v", ErrPredefined, err)
+	}
+	if err := UpdateDex(NewDex("", "", "", 0, true)); !errors.Is(err, ErrPredefined) {
+		t.Errorf("expected %v, got %v", ErrPredefined, err)
+	}
+	token := NewToken("", "", 0, false, nil)
+	token.SetPredefined(true)
+	if err := UpdateToken(token); !errors.Is(err, ErrPredefined) {
+		t.Errorf("expected %v, got %v", ErrPredefined, err)
+	}
+}
@@ -1,102 +1,279 @@
 package database
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
-func findAllNetworks(dest *[]*Network) *gorm.DB {
-	return db.Preload("Tokens").Preload("Endpoints").Preload("Dexes").Find(dest)
+func (r *gormRepository) FindAllNetworks(dest *[]*Network) *gorm.DB {
+	return r.db.Preload("Tokens").Preload("Endpoints").Preload("Dexes").Preload("Profiles").Find(dest)
+}
+
+func (r *gormRepository) FindNetworkByName(dest *Network, networkName string) *gorm.DB {
+	return r.db.Where("name = (?)", networkName).Find(dest)
+}
+
+func (r *gormRepository) FindNetworkWithAssociationsByName(dest *Network, networkName string) *gorm.DB {
+	return r.db.Preload("Tokens").Preload("Endpoints").Preload("Dexes").Where("name = (?)", networkName).Find(dest)
+}
+
+func (r *gormRepository) FindUserDefinedNetworks(dest *[]*Network) *gorm.DB {
+	return r.db.Where("predefined = (?)", false).Find(dest)
+}
+
+func (r *gormRepository) SaveNetwork(n *Network) *gorm.DB {
+	return r.db.Save(n)
+}
+
+func (r *gormRepository) UpdateNetwork(n *Network, fields map[string]any) *gorm.DB {
+	return r.db.Model(n).Updates(fields)
+}
+
+func (r *gormRepository) FindTermsAndConditions(dest *Misc) *gorm.DB {
+	return r.db.Find(dest, "id = (?)", 1)
+}
+
+func (r *gormRepository) SaveTermsAndConditions(toc *Misc) *gorm.DB {
+	return r.db.Save(toc)
+}
+
+func (r *gormRepository) FindHalt(dest *Halt) *gorm.DB {
+	return r.db.Find(dest, "id = (?)", 1)
+}
+
+func (r *gormRepository) SaveHalt(halt *Halt) *gorm.DB {
+	return r.db.Save(halt)
+}
+
+func (r *gormRepository) SaveEndpoint(endpoint *Endpoint) *gorm.DB {
+	return r.db.Save(endpoint)
+}
+
+func (r *gormRepository) UpdateEndpointURL(endpoint *Endpoint, url string) *gorm.DB {
+	return r.db.Model(endpoint).Update("URL", url)
+}
+
+func (r *gormRepository) DeleteEndpoint(endpoint *Endpoint) *gorm.DB {
+	return r.db.Unscoped().Delete(endpoint)
+}
+
+func (r *gormRepository) FindCustomEndpoint(dest *Endpoint, networkName string) *gorm.DB {
+	subQuery := r.db.Select("id").Where("name = (?)", networkName).Table("networks")
+	return r.db.Model(&Endpoint{}).Where("network_id = (?) AND custom = (?)", subQuery, true).Find(dest)
 }
 
-func findNetworkByName(dest *Network, networkName string) *gorm.DB {
-	return db.Where("name = (?)", networkName).Find(dest)
+func (r *gormRepository) DeleteCustomEndpoint(networkName string) *gorm.DB {
+	subQuery := r.db.Select("id").Where("name = (?)", networkName).Table("networks")
+	return r.db.Unscoped().Where("network_id = (?) AND custom = (?)", subQuery, true).Delete(&Endpoint{})
 }
 
-func saveNetwork(n *Network) *gorm.DB {
-	return db.Save(n)
+func (r *gormRepository) FindAllEndpoints(dest *[]*Endpoint) *gorm.DB {
+	return r.db.Order("id").Find(dest)
 }
 
-func findTermsAndConditions(dest *Misc) *gorm.DB {
-	return db.Find(dest, "id = (?)", 1)
+func (r *gormRepository) FindWallet(dest *Wallet) *gorm.DB {
+	return r.db.Find(dest, "id = (?)", 1)
 }
 
-func saveTermsAndConditions(toc *Misc) *gorm.DB {
-	return db.Save(toc)
+func (r *gormRepository) SaveWallet(wallet *Wallet) *gorm.DB {
+	return r.db.Save(wallet)
 }
 
-func saveEndpoint(endpoint *Endpoint) *gorm.DB {
-	return db.Save(endpoint)
+func (r *gormRepository) FindWallets(dest *[]*Wallet, mainID uint) *gorm.DB {
+	return r.db.Where("id != (?)", mainID).Order("id").Find(dest)
 }
 
-func findCustomEndpoint(dest *Endpoint, networkName string) *gorm.DB {
-	subQuery := db.Select("id").Where("name = (?)", networkName).Table("networks")
-	return db.Model(&Endpoint{}).Where("network_id = (?) AND custom = (?)", subQuery, true).Find(dest)
+func (r *gormRepository) FindWalletsByAddress(dest *[]*Wallet, address string) *gorm.DB {
+	return r.db.Where("LOWER(wallet) = LOWER(?)", address).Find(dest)
 }
 
-func deleteCustomEndpoint(networkName string) *gorm.DB {
-	subQuery := db.Select("id").Where("name = (?)", networkName).Table("networks")
-	return db.Unscoped().Where("network_id = (?) AND custom = (?)", subQuery, true).Delete(&Endpoint{})
+func (r *gormRepository) DeleteWallet(wallet *Wallet) *gorm.DB {
+	return r.db.Unscoped().Delete(wallet)
 }
 
-func findWallet(dest *Wallet) *gorm.DB {
-	return db.Find(dest, "id = (?)", 1)
+func (r *gormRepository) FindAllTradeTypes(dest *TradeTypes) *gorm.DB {
+	return r.db.Find(dest)
 }
 
-func saveWallet(wallet *Wallet) *gorm.DB {
-	return db.Save(wallet)
+func (r *gormRepository) FindAllTargetTypes(dest *TargetTypes) *gorm.DB {
+	return r.db.Find(dest)
 }
 
-func findAllTradeTypes(dest *TradeTypes) *gorm.DB {
-	return db.Find(dest)
+func (r *gormRepository) SaveTradeType(tradeType *TradeType) *gorm.DB {
+	return r.db.Save(tradeType)
 }
 
-func findAllTargetTypes(dest *TargetTypes) *gorm.DB {
-	return db.Find(dest)
+func (r *gormRepository) FindAllAmountModes(dest *AmountModes) *gorm.DB {
+	return r.db.Find(dest)
 }
 
-func saveTradeType(tradeType *TradeType) *gorm.DB {
-	return db.Save(tradeType)
+func (r *gormRepository) SaveAmountMode(amountMode *AmountMode) *gorm.DB {
+	return r.db.Save(amountMode)
 }
 
-func findAllAmountModes(dest *AmountModes) *gorm.DB {
-	return db.Find(dest)
+func (r *gormRepository) SaveTargetType(targetType *TargetType) *gorm.DB {
+	return r.db.Save(targetType)
 }
 
-func saveAmountMode(amountMode *AmountMode) *gorm.DB {
-	return db.Save(amountMode)
+func (r *gormRepository) SaveToken(token *Token) *gorm.DB {
+	return r.db.Save(token)
 }
 
-func saveTargetType(targetType *TargetType) *gorm.DB {
-	return db.Save(targetType)
+func (r *gormRepository) CreateTokens(tokens []*Token, batchSize int) *gorm.DB {
+	return r.db.CreateInBatches(tokens, batchSize)
 }
 
-func saveToken(token *Token) *gorm.DB {
-	return db.Save(token)
+func (r *gormRepository) UpdateToken(token *Token, fields map[string]any) *gorm.DB {
+	return r.db.Model(token).Updates(fields)
 }
 
-func saveTrade(trade *Trade) *gorm.DB {
+func (r *gormRepository) DeleteToken(token *Token) *gorm.DB {
+	return r.db.Unscoped().Delete(token)
+}
+
+func (r *gormRepository) SaveDex(dex *Dex) *gorm.DB {
+	return r.db.Save(dex)
+}
+
+func (r *gormRepository) UpdateDex(dex *Dex, fields map[string]any) *gorm.DB {
+	return r.db.Model(dex).Updates(fields)
+}
+
+func (r *gormRepository) UpdateDexAndPairs(dex *Dex) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("dex_id = (?)", dex.ID).Delete(&Pair{}).Error; err != nil {
+			return err
+		}
+		return tx.Save(dex).Error
+	})
+}
+
+func (r *gormRepository) DeleteDexAndPairs(dex *Dex) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("dex_id = (?)", dex.ID).Delete(&Pair{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(dex).Error
+	})
+}
+
+func (r *gormRepository) ReplaceNetworkEndpoints(n *Network) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("network_id = (?) AND custom = (?)", n.ID, false).Delete(&Endpoint{}).Error; err != nil {
+			return err
+		}
+		for _, endpoint := range n.Endpoints {
+			if endpoint.Custom {
+				continue
+			}
+			endpoint.ID = 0
+			endpoint.NetworkID = n.ID
+		}
+		return tx.Save(n).Error
+	})
+}
+
+func (r *gormRepository) DeleteNetworkCascade(n *Network) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		dexIDs := tx.Select("id").Where("network_id = (?)", n.ID).Table("dexes")
+		if err := tx.Unscoped().Where("dex_id IN (?)", dexIDs).Delete(&Pair{}).Error; err != nil {
+			return err
+		}
+		for _, model := range []any{&Dex{}, &Token{}, &Endpoint{}, &Spending{}, &Profile{}} {
+			if err := tx.Unscoped().Where("network_id = (?)", n.ID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Delete(n).Error
+	})
+}
+
+func (r *gormRepository) SaveProfile(p *Profile) *gorm.DB {
+	return r.db.Save(p)
+}
+
+func (r *gormRepository) DeleteProfile(p *Profile) *gorm.DB {
+	return r.db.Unscoped().Delete(p)
+}
+
+func (r *gormRepository) SaveTrade(trade *Trade) *gorm.DB {
 	// fetch the complete token info to avoid duplicates
-	if db := findTokenByContractAndNetworkID(trade.Token0, trade.Token0.Contract, trade.NetworkID); db.Error != nil {
-		return db
+	if res := r.FindTokenByContractAndNetworkID(trade.Token0, trade.Token0.Contract, trade.NetworkID); res.Error != nil {
+		return res
 	}
-	if db := findTokenByContractAndNetworkID(trade.Token1, trade.Token1.Contract, trade.NetworkID); db.Error != nil {
-		return db
+	if res := r.FindTokenByContractAndNetworkID(trade.Token1, trade.Token1.Contract, trade.NetworkID); res.Error != nil {
+		return res
 	}
-	return db.Save(trade)
+	return r.db.Save(trade)
+}
+
+func (r *gormRepository) UpdateTokenBalance(tokenID uint, balance string) *gorm.DB {
+	return r.db.Model(&Token{}).Where("id = (?)", tokenID).Update("balance", balance)
+}
+
+func (r *gormRepository) UpdateTokenUnlimitedApproval(tokenID uint, unlimited bool) *gorm.DB {
+	return r.db.Model(&Token{}).Where("id = (?)", tokenID).Update("unlimited_approval", unlimited)
+}
+
+func (r *gormRepository) UpdateTokenStarred(tokenID uint, starred bool) *gorm.DB {
+	return r.db.Model(&Token{}).Where("id = (?)", tokenID).Update("starred", starred)
+}
+
+func (r *gormRepository) UpdateTokenLastUsedAt(tokenID uint, lastUsedAt time.Time) *gorm.DB {
+	return r.db.Model(&Token{}).Where("id = (?)", tokenID).Update("last_used_at", lastUsedAt)
+}
+
+func (r *gormRepository) FindTokensByNetworkID(dest *[]*Token, networkID uint) *gorm.DB {
+	return r.db.Where("network_id = (?)", networkID).Order("id").Find(dest)
+}
+
+func (r *gormRepository) FindTokenIDByContractAndNetworkID(dest *uint, contract string, networkID uint) *gorm.DB {
+	return r.db.Select("id").Where("contract = (?) AND network_id = (?)", contract, networkID).Table("tokens").Find(dest)
+}
+
+func (r *gormRepository) FindTokenBalanceByContractAndNetworkID(dest *string, contract string, networkID uint) *gorm.DB {
+	return r.db.Select("balance").Where("contract = (?) AND network_id = (?)", contract, networkID).Table("tokens").Find(dest)
+}
+
+func (r *gormRepository) FindTokenByContractAndNetworkID(dest *Token, contract string, networkID uint) *gorm.DB {
+	return r.db.Where("contract = (?) AND network_id = (?)", contract, networkID).Table("tokens").Find(dest)
+}
+
+func (r *gormRepository) FindPairsByDexID(dest *[]*Pair, dexID uint) *gorm.DB {
+	return r.db.Where("dex_id = (?)", dexID).Find(dest)
+}
+
+func (r *gormRepository) SavePair(pair *Pair) *gorm.DB {
+	return r.db.Save(pair)
+}
+
+func (r *gormRepository) DeletePairsByDexID(dexID uint) *gorm.DB {
+	return r.db.Unscoped().Where("dex_id = (?)", dexID).Delete(&Pair{})
+}
+
+func (r *gormRepository) CountTradesByReference(dest *int64, column string, id uint) *gorm.DB {
+	return r.db.Model(&Trade{}).Where(column+" = (?)", id).Count(dest)
+}
+
+func (r *gormRepository) FindSpending(dest *Spending, networkID uint, day string) *gorm.DB {
+	return r.db.Where("network_id = (?) AND day = (?)", networkID, day).Find(dest)
 }
 
-func updateTokenBalance(tokenID uint, balance string) *gorm.DB {
-	return db.Model(&Token{}).Where("id = (?)", tokenID).Update("balance", balance)
+func (r *gormRepository) SaveSpending(spending *Spending) *gorm.DB {
+	return r.db.Save(spending)
 }
 
-func findTokenIDByContractAndNetworkID(dest *uint, contract string, networkID uint) *gorm.DB {
-	return db.Select("id").Where("contract = (?) AND network_id = (?)", contract, networkID).Table("tokens").Find(dest)
+func (r *gormRepository) FindDataVersion(dest *DataVersion) *gorm.DB {
+	return r.db.Find(dest, "id = (?)", 1)
 }
 
-func findTokenBalanceByContractAndNetworkID(dest *string, contract string, networkID uint) *gorm.DB {
-	return db.Select("balance").Where("contract = (?) AND network_id = (?)", contract, networkID).Table("tokens").Find(dest)
+func (r *gormRepository) SaveDataVersion(version *DataVersion) *gorm.DB {
+	return r.db.Save(version)
 }
 
-func findTokenByContractAndNetworkID(dest *Token, contract string, networkID uint) *gorm.DB {
-	return db.Where("contract = (?) AND network_id = (?)", contract, networkID).Table("tokens").Find(dest)
+func (r *gormRepository) Transaction(fc func(r Repository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fc(&gormRepository{db: tx})
+	})
 }
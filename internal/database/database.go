@@ -7,22 +7,17 @@ import (
 
 	"github.com/jon4hz/deadshot/internal/logging"
 
-	"github.com/glebarez/sqlite"
 	"github.com/sirupsen/logrus"
 	gormv2logrus "github.com/thomas-tacquet/gormv2-logrus"
-	"gopkg.in/yaml.v2"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
 const defaultFolderPermissions = 0o755
 
-var db *gorm.DB
-
 var (
 	ConfigDBFile string
 	ConfigDir    string
-	firstTime    bool
 )
 
 func init() {
@@ -35,21 +30,9 @@ func init() {
 		ensureFolderExists(ConfigDir)
 
 		ConfigDBFile = filepath.Join(ConfigDir, "config.db")
-		firstTime = !checkIfFileExists(ConfigDBFile)
 	}
 }
 
-func checkIfFileExists(file string) bool {
-	_, err := os.Stat(file)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return false
-		}
-		panic(err)
-	}
-	return true
-}
-
 func ensureFolderExists(folder string) {
 	if _, err := os.Stat(folder); os.IsNotExist(err) {
 		if err := os.Mkdir(folder, defaultFolderPermissions); err != nil {
@@ -69,48 +52,23 @@ func InitDB() error {
 		Logger:                 gormLogger,
 		SkipDefaultTransaction: true,
 	}
-	var err error
-	db, err = gorm.Open(sqlite.Open(ConfigDBFile), gormConfig)
+	r, err := openRepository(options, gormConfig)
 	if err != nil {
 		logging.Log.WithFields(logrus.Fields{
-			"err": err,
+			"driver": options.driver(),
+			"err":    err,
 		}).Fatal("Unable to open database")
 		return err
 	}
+	repo = r
 
-	// enable foreign key constraints
-	db.Exec("PRAGMA foreign_keys = ON;")
-
-	tables := []any{
-		&Token{},
-		&Dex{},
-		&Endpoint{},
-		&Network{},
-		&Misc{},
-		&Wallet{},
-		&TradeType{},
-		&RawTarget{},
-		&Target{},
-		&AmountMode{},
-		&TargetType{},
-		&Trade{},
-	}
-
-	err = db.AutoMigrate(tables...)
-	if err != nil {
+	if err = syncBuiltinData(defaultConfig); err != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"err": err,
-		}).Fatal("Unable to migrate database")
+		}).Fatal("Unable to merge the built-in data")
 		return err
 	}
 
-	if firstTime {
-		err = createInitialData()
-		if err != nil {
-			return err
-		}
-	}
-
 	// load all trade types
 	if err = LoadAllTradeTypes(); err != nil {
 		return err
@@ -128,74 +86,8 @@ func InitDB() error {
 	return nil
 }
 
-func createInitialData() error {
-	// load the default config
-	type defaultConfigStruct struct {
-		Networks    []*Network    `yaml:"networks"`
-		TradeTypes  []*TradeType  `yaml:"tradeTypes"`
-		AmountModes []*AmountMode `yaml:"amountModes"`
-		TargetTypes []*TargetType `yaml:"targetTypes"`
-	}
-	var cfg defaultConfigStruct
-	err := yaml.Unmarshal(defaultConfig, &cfg)
-	if err != nil {
-		logging.Log.WithFields(logrus.Fields{
-			"err": err,
-		}).Fatal("Unable to parse default config")
-		return err
-	}
-	// Store the default networks and all subelements like dexes or tokens
-	for _, network := range cfg.Networks {
-		network.Predefined = true
-		for i := range network.GetTokens() {
-			network.GetTokens()[i].SetPredefined(true)
-		}
-		for i := range network.GetDexes() {
-			network.GetDexes()[i].SetPredefined(true)
-		}
-		if err := saveNetwork(network).Error; err != nil {
-			logging.Log.WithFields(logrus.Fields{
-				"err": err,
-			}).Fatal("Unable to save network")
-			return err
-		}
-	}
-	// Store the default trade types
-	for _, tradeType := range cfg.TradeTypes {
-		if err := saveTradeType(tradeType).Error; err != nil {
-			logging.Log.WithFields(logrus.Fields{
-				"err": err,
-			}).Fatal("Unable to save trade type")
-			return err
-		}
-	}
-	// Store the default amount modes
-	for _, amountMode := range cfg.AmountModes {
-		if err := saveAmountMode(amountMode).Error; err != nil {
-			logging.Log.WithFields(logrus.Fields{
-				"err": err,
-			}).Fatal("Unable to save amount mode")
-			return err
-		}
-	}
-	// Store the default trade types
-	for _, targetType := range cfg.TargetTypes {
-		if err := saveTargetType(targetType).Error; err != nil {
-			logging.Log.WithFields(logrus.Fields{
-				"err": err,
-			}).Fatal("Unable to save target type")
-			return err
-		}
-	}
-	return nil
-}
-
 // Close closes the database connection.
 // Use only when shutting down the program.
 func Close() error {
-	d, err := db.DB()
-	if err != nil {
-		return err
-	}
-	return d.Close()
+	return repo.Close()
 }
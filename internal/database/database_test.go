@@ -1,11 +1,33 @@
 package database
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
 
-func init() {
+// postgresTestDSN runs the tests against an existing postgres instead of a temporary sqlite file, e.g.
+// DEADSHOT_TEST_POSTGRES_DSN="host=localhost user=deadshot dbname=deadshot_test sslmode=disable".
+const postgresTestDSN = "DEADSHOT_TEST_POSTGRES_DSN"
+
+// TestMain runs the tests against a temporary database, the config of the user is never touched.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "deadshot-test")
+	if err != nil {
+		panic(err)
+	}
+	if dsn := os.Getenv(postgresTestDSN); dsn != "" {
+		Configure(Options{Driver: DriverPostgres, DSN: dsn})
+	} else {
+		Configure(Options{DSN: filepath.Join(dir, "config.db")})
+	}
 	if err := InitDB(); err != nil {
 		panic(err)
 	}
+	code := m.Run()
+	Close()
+	os.RemoveAll(dir)
+	os.Exit(code)
 }
 
 func TestGetAllNetwork(t *testing.T) {
@@ -19,7 +41,7 @@ func TestGetAllNetwork(t *testing.T) {
 
 func TestFindCustomEndpoint(t *testing.T) {
 	var endpoint Endpoint
-	result := findCustomEndpoint(&endpoint, "matic")
+	result := repo.FindCustomEndpoint(&endpoint, "matic")
 	if err := result.Error; err != nil {
 		t.Error(err)
 	}
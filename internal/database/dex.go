@@ -7,14 +7,25 @@ import (
 	"gorm.io/gorm"
 )
 
+// Protocols of the dexes.
+const (
+	// ProtocolUniswapV2 is the protocol of uniswap v2 forks, it's the default if a dex has no protocol.
+	ProtocolUniswapV2 = "uniswapv2"
+	// ProtocolSolidly is the protocol of solidly forks with volatile and stable pairs.
+	ProtocolSolidly = "solidly"
+)
+
 // Dex is the database model for a decentralized exchange.
 type Dex struct {
 	gorm.Model `yaml:"-"`
 	Name       string `yaml:"name"`
 	Router     string `yaml:"router"`
 	Factory    string `yaml:"factory"`
-	Fee        int64  `yaml:"fee"`
-	Predefined bool   `yaml:"-"`
+	// InitCodeHash is the hash of the pair creation code, used to derive the pair addresses.
+	InitCodeHash string `yaml:"initCodeHash,omitempty"`
+	Protocol     string `yaml:"protocol,omitempty"`
+	Fee          int64  `yaml:"fee"`
+	Predefined   bool   `yaml:"-"`
 	// Trades     []*Trade `yaml:"-"`
 	NetworkID uint `yaml:"-"`
 
@@ -38,6 +49,13 @@ func (d *Dex) Get() *Dex {
 	return d
 }
 
+// GetID returns the id of the dex.
+func (d *Dex) GetID() uint {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.ID
+}
+
 // GetName returns the name of the dex.
 func (d *Dex) GetName() string {
 	d.mu.Lock()
@@ -59,6 +77,42 @@ func (d *Dex) GetFactory() string {
 	return d.Factory
 }
 
+// GetInitCodeHash returns the init code hash of the pairs of the dex.
+func (d *Dex) GetInitCodeHash() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.InitCodeHash
+}
+
+// SetInitCodeHash sets the init code hash of the pairs of the dex.
+func (d *Dex) SetInitCodeHash(initCodeHash string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.InitCodeHash = initCodeHash
+}
+
+// GetProtocol returns the protocol of the dex.
+func (d *Dex) GetProtocol() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.Protocol == "" {
+		return ProtocolUniswapV2
+	}
+	return d.Protocol
+}
+
+// SetProtocol sets the protocol of the dex.
+func (d *Dex) SetProtocol(protocol string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.Protocol = protocol
+}
+
+// IsSolidly returns whether the dex is a solidly fork.
+func (d *Dex) IsSolidly() bool {
+	return d.GetProtocol() == ProtocolSolidly
+}
+
 // GetFee returns the fee of the dex.
 func (d *Dex) GetFee() int64 {
 	d.mu.Lock()
@@ -14,7 +14,7 @@ var ErrNoEndpointsFound = errors.New("no endpoints found")
 type Endpoint struct {
 	gorm.Model `yaml:"-"`
 	URL        string     `yaml:"url"`
-	Custom     bool       `yaml:"-"`
+	Custom     bool       `yaml:"custom,omitempty"`
 	NetworkID  uint       `yaml:"-"`
 	mu         sync.Mutex `yaml:"-" gorm:"-"`
 }
@@ -74,3 +74,39 @@ func (e Endpoints) GetUrls() []string {
 	}
 	return urls
 }
+
+// Diff returns the urls of the endpoints which were added and removed compared to the previous endpoints.
+// The url of a custom endpoint is prefixed with "custom ".
+func (e Endpoints) Diff(previous Endpoints) (added, removed []string) {
+	return missingEndpoints(e, previous), missingEndpoints(previous, e)
+}
+
+// missingEndpoints returns the endpoints of a which aren't in b.
+func missingEndpoints(a, b Endpoints) []string {
+	var missing []string
+	for _, endpoint := range a {
+		other := b.GetEndpointByURL(endpoint.GetURL())
+		if other != nil && other.GetCustom() == endpoint.GetCustom() {
+			continue
+		}
+		url := endpoint.GetURL()
+		if endpoint.GetCustom() {
+			url = "custom " + url
+		}
+		missing = append(missing, url)
+	}
+	return missing
+}
+
+// FetchEndpoints returns the endpoints of all networks by the id of the network.
+func FetchEndpoints() (map[uint]Endpoints, error) {
+	var endpoints []*Endpoint
+	if err := repo.FindAllEndpoints(&endpoints).Error; err != nil {
+		return nil, err
+	}
+	byNetwork := make(map[uint]Endpoints)
+	for _, endpoint := range endpoints {
+		byNetwork[endpoint.NetworkID] = append(byNetwork[endpoint.NetworkID], endpoint)
+	}
+	return byNetwork, nil
+}
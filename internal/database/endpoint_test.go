@@ -0,0 +1,43 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEndpointsDiff(t *testing.T) {
+	previous := Endpoints{
+		NewEndpoint("https://a.example", false),
+		NewEndpoint("https://b.example", false),
+	}
+	current := Endpoints{
+		NewEndpoint("https://b.example", true),
+		NewEndpoint("https://c.example", false),
+	}
+	added, removed := current.Diff(previous)
+	if want := []string{"custom https://b.example", "https://c.example"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("added = %v, want %v", added, want)
+	}
+	if want := []string{"https://a.example", "https://b.example"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed = %v, want %v", removed, want)
+	}
+	if added, removed := previous.Diff(previous); added != nil || removed != nil {
+		t.Errorf("diff of the same endpoints = %v, %v, want none", added, removed)
+	}
+}
+
+func TestFetchEndpoints(t *testing.T) {
+	networks, err := FetchAllNetworks(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoints, err := FetchEndpoints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range networks {
+		if added, removed := endpoints[n.GetID()].Diff(n.GetEndpoints()); added != nil || removed != nil {
+			t.Errorf("endpoints of %s differ: added %v, removed %v", n.GetName(), added, removed)
+		}
+	}
+}
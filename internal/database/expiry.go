@@ -0,0 +1,83 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidExpiry is returned if an expiry can't be parsed.
+var ErrInvalidExpiry = errors.New("invalid expiry")
+
+// expiryTimeLayout is the layout of an absolute expiry time in the local time zone.
+const expiryTimeLayout = "2006-01-02 15:04"
+
+// Expiry is the time or the block after which an unfilled order is cancelled.
+// The zero value never expires.
+type Expiry struct {
+	At    *time.Time
+	Block uint64
+}
+
+// ParseExpiry parses an expiry relative to now.
+// The expiry is either a duration (e.g. 2h30m), a local time (e.g. 2022-06-01 18:00) or a block number (e.g. #15000000).
+// An empty string never expires.
+func ParseExpiry(s string, now time.Time) (Expiry, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Expiry{}, nil
+	}
+	if strings.HasPrefix(s, "#") {
+		block, err := strconv.ParseUint(strings.TrimSpace(s[1:]), 10, 64)
+		if err != nil || block == 0 {
+			return Expiry{}, fmt.Errorf("%w: %s", ErrInvalidExpiry, s)
+		}
+		return Expiry{Block: block}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		if d <= 0 {
+			return Expiry{}, fmt.Errorf("%w: %s", ErrInvalidExpiry, s)
+		}
+		at := now.Add(d)
+		return Expiry{At: &at}, nil
+	}
+	at, err := time.ParseInLocation(expiryTimeLayout, s, time.Local)
+	if err != nil {
+		return Expiry{}, fmt.Errorf("%w: %s", ErrInvalidExpiry, s)
+	}
+	if !at.After(now) {
+		return Expiry{}, fmt.Errorf("%w: %s is in the past", ErrInvalidExpiry, s)
+	}
+	return Expiry{At: &at}, nil
+}
+
+// IsZero returns whether the expiry is not set.
+func (e Expiry) IsZero() bool {
+	return e.At == nil && e.Block == 0
+}
+
+// Reached returns whether the expiry is reached at the time or the block.
+// A block of 0 is unknown and never reaches a block expiry.
+func (e Expiry) Reached(now time.Time, block uint64) bool {
+	if e.At != nil && !now.Before(*e.At) {
+		return true
+	}
+	return e.Block > 0 && block >= e.Block
+}
+
+// String returns the expiry in the format of ParseExpiry or "never".
+func (e Expiry) String() string {
+	var s []string
+	if e.At != nil {
+		s = append(s, e.At.Local().Format(expiryTimeLayout))
+	}
+	if e.Block > 0 {
+		s = append(s, fmt.Sprintf("#%d", e.Block))
+	}
+	if len(s) == 0 {
+		return "never"
+	}
+	return strings.Join(s, " or ")
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseExpiry(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		in      string
+		at      time.Time
+		block   uint64
+		wantErr bool
+	}{
+		{in: ""},
+		{in: "2h", at: now.Add(2 * time.Hour)},
+		{in: "2022-06-01 18:00", at: time.Date(2022, 6, 1, 18, 0, 0, 0, time.Local)},
+		{in: "#15000000", block: 15000000},
+		{in: "2022-06-01 11:00", wantErr: true},
+		{in: "-5m", wantErr: true},
+		{in: "#abc", wantErr: true},
+		{in: "tomorrow", wantErr: true},
+	}
+	for _, tt := range tests {
+		e, err := ParseExpiry(tt.in, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseExpiry(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if e.Block != tt.block || (e.At == nil) != tt.at.IsZero() || (e.At != nil && !e.At.Equal(tt.at)) {
+			t.Errorf("ParseExpiry(%q) = %v, want %v #%d", tt.in, e, tt.at, tt.block)
+		}
+	}
+
+	e, _ := ParseExpiry("1h", now)
+	if e.Reached(now, 0) || !e.Reached(now.Add(time.Hour), 0) {
+		t.Error("the expiry should be reached after one hour")
+	}
+	if (Expiry{}).Reached(now.Add(time.Hour), 1) {
+		t.Error("the zero expiry should never be reached")
+	}
+}
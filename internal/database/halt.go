@@ -0,0 +1,49 @@
+package database
+
+import (
+	"gorm.io/gorm"
+)
+
+// Halt is the state of the kill switch. While trading is halted, no new trades are started.
+type Halt struct {
+	gorm.Model
+	Halted bool
+	Reason string
+	// Sell is set if the open positions are sold when trading is halted.
+	Sell bool
+}
+
+// GetHalted returns whether trading is halted.
+func (h *Halt) GetHalted() bool {
+	return h.Halted
+}
+
+// GetReason returns the reason why trading was halted.
+func (h *Halt) GetReason() string {
+	return h.Reason
+}
+
+// GetSell returns whether the open positions are sold.
+func (h *Halt) GetSell() bool {
+	return h.Sell
+}
+
+// FetchHalt returns the state of the kill switch.
+func FetchHalt() (*Halt, error) {
+	var h Halt
+	if err := repo.FindHalt(&h).Error; err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// SaveHalt persists the state of the kill switch.
+func SaveHalt(halted bool, reason string, sell bool) error {
+	h := &Halt{
+		Model:  gorm.Model{ID: 1},
+		Halted: halted,
+		Reason: reason,
+		Sell:   sell,
+	}
+	return repo.SaveHalt(h).Error
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jon4hz/deadshot/internal/logging"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ErrSchemaTooNew is returned if the database was migrated by a newer version of deadshot.
+var ErrSchemaTooNew = errors.New("database schema is newer than this version of deadshot")
+
+// SchemaMigration is an applied migration of the database schema.
+type SchemaMigration struct {
+	Version   uint `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+// migration changes the schema or the data of the database.
+// Migrations are applied in order and only once, never change or reorder an existing migration.
+// A migration only uses the frozen snapshots of the models, never the current models.
+type migration struct {
+	version uint
+	name    string
+	migrate func(tx *gorm.DB) error
+}
+
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "create the schema",
+		// databases created before the versioned migrations are brought to the same state
+		migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(
+				&v1Token{},
+				&v1Dex{},
+				&v1Endpoint{},
+				&v1Network{},
+				&v1Misc{},
+				&v1Wallet{},
+				&v1TradeType{},
+				&v1RawTarget{},
+				&v1Target{},
+				&v1AmountMode{},
+				&v1TargetType{},
+				&v1Trade{},
+				&v1Pair{},
+				&v1Spending{},
+				&v1Halt{},
+				&v1DataVersion{},
+			)
+		},
+	},
+	{
+		version: 2,
+		name:    "add the token list metadata to the tokens",
+		migrate: func(tx *gorm.DB) error {
+			return addColumns(tx, &v2Token{}, "Name", "LogoURI", "Tags")
+		},
+	},
+	{
+		version: 3,
+		name:    "add the watchlist and the last use to the tokens",
+		migrate: func(tx *gorm.DB) error {
+			return addColumns(tx, &v3Token{}, "Starred", "LastUsedAt")
+		},
+	},
+	{
+		version: 4,
+		name:    "add the trading profiles",
+		migrate: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&v4Profile{}); err != nil {
+				return err
+			}
+			return addColumns(tx, &v4Trade{}, "ProfileName")
+		},
+	},
+	{
+		version: 5,
+		name:    "store the deadlines and the expiry of the targets",
+		migrate: func(tx *gorm.DB) error {
+			if err := addColumns(tx, &v5RawTarget{}, "Deadline", "ExpiresAt", "ExpiresAtBlock"); err != nil {
+				return err
+			}
+			if err := addColumns(tx, &v5Target{}, "Deadline", "DeadlineAt", "ExpiresAt", "ExpiresAtBlock", "Expired"); err != nil {
+				return err
+			}
+			return addColumns(tx, &v5Trade{}, "ExpiresAt", "ExpiresAtBlock", "Expired")
+		},
+	},
+}
+
+// addColumns adds the missing columns of the fields to the table of the model.
+func addColumns(tx *gorm.DB, model any, fields ...string) error {
+	m := tx.Migrator()
+	for _, field := range fields {
+		if m.HasColumn(model, field) {
+			continue
+		}
+		if err := m.AddColumn(model, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrate applies all pending migrations, each one in its own transaction.
+// The migrations are the same for every backend.
+func migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return err
+	}
+	var current uint
+	if err := db.Model(&SchemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&current).Error; err != nil {
+		return err
+	}
+	latest := migrations[len(migrations)-1].version
+	if current > latest {
+		return fmt.Errorf("%w: version %d > %d", ErrSchemaTooNew, current, latest)
+	}
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		logging.Log.WithFields(logrus.Fields{
+			"version": m.version,
+			"name":    m.name,
+		}).Info("migrating database")
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.migrate(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{
+				Version:   m.version,
+				Name:      m.name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+		}
+	}
+	return nil
+}
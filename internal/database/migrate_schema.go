@@ -0,0 +1,287 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// The models below are frozen snapshots of the schema used by the migrations.
+// They must never change, a change of a model needs a new snapshot and a new migration.
+
+// v1 is the schema of the first versioned release.
+
+type v1Token struct {
+	gorm.Model
+	Contract          string
+	Symbol            string
+	Balance           string
+	NetworkID         uint
+	Decimals          uint8
+	Connector         bool
+	Predefined        bool
+	UnlimitedApproval bool
+	Native            bool
+}
+
+func (v1Token) TableName() string { return "tokens" }
+
+type v1Dex struct {
+	gorm.Model
+	Name         string
+	Router       string
+	Factory      string
+	InitCodeHash string
+	Protocol     string
+	Fee          int64
+	Predefined   bool
+	NetworkID    uint
+}
+
+func (v1Dex) TableName() string { return "dexes" }
+
+type v1Endpoint struct {
+	gorm.Model
+	URL       string
+	Custom    bool
+	NetworkID uint
+}
+
+func (v1Endpoint) TableName() string { return "endpoints" }
+
+type v1Network struct {
+	gorm.Model
+	Endpoints      []*v1Endpoint `gorm:"foreignkey:NetworkID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Tokens         []*v1Token    `gorm:"foreignkey:NetworkID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Dexes          []*v1Dex      `gorm:"foreignkey:NetworkID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Name           string        `gorm:"unique"`
+	FullName       string
+	Multicall      string
+	NativeCurrency string
+	WETH           string
+	GasLimit       uint64
+	ChainID        uint32
+	IsTestnet      bool
+	EIP1559Enabled bool
+	Predefined     bool
+}
+
+func (v1Network) TableName() string { return "networks" }
+
+type v1Misc struct {
+	gorm.Model
+	TermsAndConditions bool
+}
+
+func (v1Misc) TableName() string { return "miscs" }
+
+type v1Wallet struct {
+	gorm.Model
+	Wallet         string
+	WalletIndex    uint
+	DerivationPath string
+	Label          string
+	Imported       bool
+	SignerURL      string
+}
+
+func (v1Wallet) TableName() string { return "wallets" }
+
+type v1TradeType struct {
+	gorm.Model
+	Type string
+}
+
+func (v1TradeType) TableName() string { return "trade_types" }
+
+type v1RawTarget struct {
+	gorm.Model
+	Price       string
+	Amount      string
+	Slippage    float64
+	ExactPrice  bool
+	ExactAmount bool
+	Stoploss    bool
+	Skip        bool
+	TradeID     uint
+}
+
+func (v1RawTarget) TableName() string { return "raw_targets" }
+
+type v1Target struct {
+	gorm.Model
+	Price                string
+	Amount               string
+	AmountMinMax         string
+	TxHash               string
+	TargetTypeID         uint
+	AmountModeID         uint
+	AmountMode           *v1AmountMode `gorm:"foreignkey:AmountModeID"`
+	Slippage             *float64
+	TargetType           *v1TargetType `gorm:"foreignkey:TargetTypeID"`
+	TradeID              uint
+	GasLimit             *uint64
+	Hit                  bool
+	Confirmed            bool
+	Failed               bool
+	AmountDecimals       uint8
+	ActualAmountDecimals uint8
+	PriceDecimals        uint8
+	IsStopLoss           bool
+}
+
+func (v1Target) TableName() string { return "targets" }
+
+type v1AmountMode struct {
+	gorm.Model
+	Type string
+}
+
+func (v1AmountMode) TableName() string { return "amount_modes" }
+
+type v1TargetType struct {
+	gorm.Model
+	Type string
+}
+
+func (v1TargetType) TableName() string { return "target_types" }
+
+type v1Trade struct {
+	gorm.Model
+	RawBuyTargets  []*v1RawTarget `gorm:"foreignkey:TradeID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	RawSellTargets []*v1RawTarget `gorm:"foreignkey:TradeID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	BuyTargets     []*v1Target    `gorm:"foreignkey:TradeID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	SellTargets    []*v1Target    `gorm:"foreignkey:TradeID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	InitPrice      string
+	Token0         *v1Token `gorm:"foreignkey:Token0ID"`
+	Token0ID       uint
+	Token1         *v1Token `gorm:"foreignkey:Token1ID"`
+	Token1ID       uint
+	TradeType      *v1TradeType `gorm:"foreignkey:TradeTypeID"`
+	TradeTypeID    uint
+	Endpoint       *v1Endpoint `gorm:"foreignkey:EndpointID"`
+	EndpointID     uint
+	Network        *v1Network `gorm:"foreignkey:NetworkID"`
+	NetworkID      uint
+	Dex            *v1Dex `gorm:"foreignkey:DexID"`
+	DexID          uint
+	Wallet         *v1Wallet `gorm:"foreignkey:WalletID"`
+	WalletID       uint
+	AllDexes       bool
+	Failed         bool
+	KeepUnlocked   bool
+}
+
+func (v1Trade) TableName() string { return "trades" }
+
+type v1Pair struct {
+	gorm.Model
+	DexID     uint `gorm:"index"`
+	Token0    string
+	Token1    string
+	Stable    bool
+	Address   string
+	Exists    bool
+	CheckedAt time.Time
+}
+
+func (v1Pair) TableName() string { return "pairs" }
+
+type v1Spending struct {
+	gorm.Model
+	NetworkID uint   `gorm:"uniqueIndex:idx_spending_network_day"`
+	Day       string `gorm:"uniqueIndex:idx_spending_network_day"`
+	Amount    string
+}
+
+func (v1Spending) TableName() string { return "spendings" }
+
+type v1Halt struct {
+	gorm.Model
+	Halted bool
+	Reason string
+	Sell   bool
+}
+
+func (v1Halt) TableName() string { return "halts" }
+
+type v1DataVersion struct {
+	gorm.Model
+	Hash string
+}
+
+func (v1DataVersion) TableName() string { return "data_versions" }
+
+// v2 adds the token list metadata to the tokens.
+
+type v2Token struct {
+	Name    string
+	LogoURI string
+	Tags    string
+}
+
+func (v2Token) TableName() string { return "tokens" }
+
+// v3 adds the watchlist and the last use to the tokens.
+
+type v3Token struct {
+	Starred    bool
+	LastUsedAt *time.Time
+}
+
+func (v3Token) TableName() string { return "tokens" }
+
+// v4 adds the trading profiles.
+
+type v4Profile struct {
+	gorm.Model
+	NetworkID         uint
+	Name              string
+	Slippage          float64
+	GasStrategy       string
+	GasPrice          float64
+	GasBoost          float64
+	GasLimit          uint64
+	Deadline          time.Duration
+	MaxHops           int
+	PriceFeedInterval time.Duration
+	MaxSlippage       float64
+	MaxPriceImpact    float64
+	MaxTradeAmount    float64
+}
+
+func (v4Profile) TableName() string { return "profiles" }
+
+type v4Trade struct {
+	ProfileName string
+}
+
+func (v4Trade) TableName() string { return "trades" }
+
+// v5 stores the deadlines and the expiry of the targets.
+
+type v5RawTarget struct {
+	Deadline       *time.Duration
+	ExpiresAt      *time.Time
+	ExpiresAtBlock uint64
+}
+
+func (v5RawTarget) TableName() string { return "raw_targets" }
+
+type v5Target struct {
+	Deadline       *time.Duration
+	DeadlineAt     *time.Time
+	ExpiresAt      *time.Time
+	ExpiresAtBlock uint64
+	Expired        bool
+}
+
+func (v5Target) TableName() string { return "targets" }
+
+type v5Trade struct {
+	ExpiresAt      *time.Time
+	ExpiresAtBlock uint64
+	Expired        bool
+}
+
+func (v5Trade) TableName() string { return "trades" }
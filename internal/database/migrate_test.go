@@ -0,0 +1,46 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// TestMigrationsMatchModels checks that the migrations create every column of the current models.
+func TestMigrationsMatchModels(t *testing.T) {
+	r, err := openRepository(Options{DSN: filepath.Join(t.TempDir(), "test.db")}, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	checkSchema(t, r.db)
+}
+
+// checkSchema checks that the database has every column of the current models.
+func checkSchema(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	models := []any{
+		&Token{}, &Dex{}, &Endpoint{}, &Network{}, &Misc{}, &Wallet{}, &TradeType{}, &RawTarget{}, &Target{},
+		&AmountMode{}, &TargetType{}, &Trade{}, &Pair{}, &Spending{}, &Halt{}, &DataVersion{}, &Profile{},
+	}
+	m := db.Migrator()
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			t.Fatal(err)
+		}
+		if !m.HasTable(model) {
+			t.Errorf("missing table %s", stmt.Schema.Table)
+			continue
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			if !m.HasColumn(model, field.DBName) {
+				t.Errorf("missing column %s.%s", stmt.Schema.Table, field.DBName)
+			}
+		}
+	}
+}
@@ -30,7 +30,7 @@ func (m *Misc) CreateTermsAndConditions(accepted bool) error {
 			ID: 1,
 		}, accepted, sync.Mutex{},
 	}
-	if err := saveTermsAndConditions(misc).Error; err != nil {
+	if err := repo.SaveTermsAndConditions(misc).Error; err != nil {
 		return err
 	}
 
@@ -40,7 +40,7 @@ func (m *Misc) CreateTermsAndConditions(accepted bool) error {
 
 func FetchMisc() (*Misc, error) {
 	var misc Misc
-	result := findTermsAndConditions(&misc)
+	result := repo.FindTermsAndConditions(&misc)
 	if err := result.Error; err != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"error": err,
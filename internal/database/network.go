@@ -19,10 +19,12 @@ var (
 type Networks []*Network
 
 type Network struct {
-	gorm.Model     `yaml:"-"`
-	Endpoints      Endpoints  `yaml:"endpoints" gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
-	Tokens         []*Token   `yaml:"tokens" gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
-	Dexes          []*Dex     `yaml:"dexes" gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	gorm.Model `yaml:"-"`
+	Endpoints  Endpoints `yaml:"endpoints" gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Tokens     []*Token  `yaml:"tokens" gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Dexes      []*Dex    `yaml:"dexes" gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	// Profiles are the user-defined trading profiles, see GetProfiles for all profiles.
+	Profiles       []*Profile `yaml:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 	Name           string     `yaml:"name" gorm:"unique"`
 	FullName       string     `yaml:"fullName"`
 	Multicall      string     `yaml:"multicall"`
@@ -117,6 +119,13 @@ func (n *Network) GetEndpoints() Endpoints {
 	return n.Endpoints
 }
 
+// SetEndpoints replaces the endpoints of the network without storing them.
+func (n *Network) SetEndpoints(endpoints Endpoints) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.Endpoints = endpoints
+}
+
 func (n *Network) GetTokens() []*Token {
 	n.mu.Lock()
 	defer n.mu.Unlock()
@@ -172,7 +181,7 @@ func (n *Network) GetCustomEndpoint() (*Endpoint, bool) {
 // FetchAllNetworks fetches all networks from the database.
 func FetchAllNetworks(inclTestnets bool) ([]*Network, error) {
 	var networks []*Network
-	result := findAllNetworks(&networks)
+	result := repo.FindAllNetworks(&networks)
 	if result.Error != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"err": result.Error,
@@ -206,7 +215,7 @@ func (n *Network) CreateCustomEndpoint(endpointURL string) error {
 
 	// check if there is already a custom endpoint for this network
 	var currentEndpoint Endpoint
-	result := findCustomEndpoint(&currentEndpoint, n.Name)
+	result := repo.FindCustomEndpoint(&currentEndpoint, n.Name)
 	if err := result.Error; err != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"error": err,
@@ -218,7 +227,7 @@ func (n *Network) CreateCustomEndpoint(endpointURL string) error {
 
 	// find the id of the network by name
 	var network Network
-	result = findNetworkByName(&network, n.Name)
+	result = repo.FindNetworkByName(&network, n.Name)
 	if err := result.Error; err != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"error": err,
@@ -229,7 +238,7 @@ func (n *Network) CreateCustomEndpoint(endpointURL string) error {
 	}
 	endpoint.NetworkID = network.ID
 
-	if err := saveEndpoint(endpoint).Error; err != nil {
+	if err := repo.SaveEndpoint(endpoint).Error; err != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"error": err,
 		}).Error("Error while saving custom endpoint")
@@ -242,7 +251,7 @@ func (n *Network) CreateCustomEndpoint(endpointURL string) error {
 func (n *Network) RemoveCustomEndpoint() error {
 	n.mu.Lock()
 	defer n.mu.Unlock()
-	result := deleteCustomEndpoint(n.Name)
+	result := repo.DeleteCustomEndpoint(n.Name)
 	if result.Error != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"error": result.Error,
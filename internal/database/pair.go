@@ -0,0 +1,116 @@
+package database
+
+import (
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Pair is the database model for a cached liquidity pair of a dex.
+// Pair addresses never change, so they only have to be looked up once.
+type Pair struct {
+	gorm.Model
+	DexID uint `gorm:"index"`
+	// Token0 and Token1 are the lowercase token contracts in the order of the pair contract.
+	Token0 string
+	Token1 string
+	// Stable is set for the stable pairs of solidly dexes.
+	Stable  bool
+	Address string
+	// Exists is false if the pair wasn't deployed when it was checked.
+	Exists    bool
+	CheckedAt time.Time
+
+	mu sync.Mutex `gorm:"-"`
+}
+
+// NewPair creates a new pair which was checked just now.
+func NewPair(dexID uint, token0, token1 string, stable bool, address string, exists bool) *Pair {
+	return &Pair{
+		DexID:     dexID,
+		Token0:    token0,
+		Token1:    token1,
+		Stable:    stable,
+		Address:   address,
+		Exists:    exists,
+		CheckedAt: time.Now(),
+	}
+}
+
+// GetDexID returns the id of the dex of the pair.
+func (p *Pair) GetDexID() uint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.DexID
+}
+
+// GetToken0 returns the contract of token0.
+func (p *Pair) GetToken0() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.Token0
+}
+
+// GetToken1 returns the contract of token1.
+func (p *Pair) GetToken1() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.Token1
+}
+
+// GetStable returns whether the pair is a stable pair.
+func (p *Pair) GetStable() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.Stable
+}
+
+// GetAddress returns the address of the pair contract.
+func (p *Pair) GetAddress() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.Address
+}
+
+// GetExists returns whether the pair exists.
+func (p *Pair) GetExists() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.Exists
+}
+
+// GetCheckedAt returns the time the pair was checked last.
+func (p *Pair) GetCheckedAt() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.CheckedAt
+}
+
+// SetChecked updates the result of a new check.
+func (p *Pair) SetChecked(address string, exists bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Address = address
+	p.Exists = exists
+	p.CheckedAt = time.Now()
+}
+
+// FindPairsByDexID returns all cached pairs of the dex.
+func FindPairsByDexID(dexID uint) ([]*Pair, error) {
+	var pairs []*Pair
+	if err := repo.FindPairsByDexID(&pairs, dexID).Error; err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+// SavePairs saves the pairs in the database.
+func SavePairs(pairs []*Pair) error {
+	for _, pair := range pairs {
+		if err := repo.SavePair(pair).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
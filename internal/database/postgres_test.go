@@ -0,0 +1,93 @@
+package database
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// embeddedPostgresTest enables TestPostgres, e.g. DEADSHOT_TEST_POSTGRES=1.
+// The test needs network access to download the postgres binaries.
+const embeddedPostgresTest = "DEADSHOT_TEST_POSTGRES"
+
+// TestPostgres runs the migrations and the repository against an embedded postgres.
+// The postgres binaries are downloaded on the first run.
+func TestPostgres(t *testing.T) {
+	if os.Getenv(embeddedPostgresTest) == "" {
+		t.Skipf("set %s to run the migrations against an embedded postgres", embeddedPostgresTest)
+	}
+	port := freePort(t)
+	dir := t.TempDir()
+	pg := embeddedpostgres.NewDatabase(embeddedpostgres.DefaultConfig().
+		Port(port).
+		RuntimePath(filepath.Join(dir, "runtime")).
+		DataPath(filepath.Join(dir, "data")).
+		Logger(io.Discard))
+	if err := pg.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := pg.Stop(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	o := Options{
+		Driver: DriverPostgres,
+		DSN:    fmt.Sprintf("host=localhost port=%d user=postgres password=postgres dbname=postgres sslmode=disable", port),
+	}
+	r, err := openRepository(o, &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	checkSchema(t, r.db)
+	testFixedID(t, r)
+
+	// the built-in data is merged through the repository like on sqlite
+	defer func(previous Repository) { repo = previous }(repo)
+	repo = r
+	if err := syncBuiltinData(defaultConfig); err != nil {
+		t.Fatal(err)
+	}
+	networks, err := FetchAllNetworks(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(networks) == 0 {
+		t.Fatal("expected the built-in networks")
+	}
+	n := fetchNetwork(t, networks[0].Name)
+	if err := n.CreateCustomEndpoint("http://custom"); err != nil {
+		t.Fatal(err)
+	}
+	var custom Endpoint
+	if err := r.FindCustomEndpoint(&custom, n.Name).Error; err != nil || custom.URL != "http://custom" {
+		t.Errorf("custom endpoint = %q, %v", custom.URL, err)
+	}
+
+	// migrating an up to date database does nothing
+	again, err := openRepository(o, &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	again.Close()
+}
+
+// freePort returns a tcp port which is free at the moment.
+func freePort(t *testing.T) uint32 {
+	t.Helper()
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return uint32(l.Addr().(*net.TCPAddr).Port)
+}
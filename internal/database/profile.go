@@ -0,0 +1,346 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jon4hz/deadshot/internal/logging"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrProfileExists      = errors.New("profile already exists")
+	ErrProfileNotFound    = errors.New("profile not found")
+	ErrInvalidGasStrategy = errors.New("invalid gas strategy")
+)
+
+// GasStrategy defines how the gas price of a swap is set if the target has none.
+type GasStrategy string
+
+const (
+	// GasStrategyNetwork uses the gas price suggested by the node.
+	GasStrategyNetwork GasStrategy = "network"
+	// GasStrategyBoost increases the gas price suggested by the node by GasBoost percent.
+	GasStrategyBoost GasStrategy = "boost"
+	// GasStrategyFixed always uses GasPrice.
+	GasStrategyFixed GasStrategy = "fixed"
+)
+
+// ParseGasStrategy returns the gas strategy of the name. An empty name is the network strategy.
+func ParseGasStrategy(name string) (GasStrategy, error) {
+	switch s := GasStrategy(strings.ToLower(strings.TrimSpace(name))); s {
+	case "":
+		return GasStrategyNetwork, nil
+	case GasStrategyNetwork, GasStrategyBoost, GasStrategyFixed:
+		return s, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrInvalidGasStrategy, name)
+}
+
+// Profile contains the trading defaults of a network. A zero value keeps the default of the setting.
+// The values of a target override the profile.
+type Profile struct {
+	gorm.Model
+	NetworkID uint
+	Name      string
+	// Slippage in percent.
+	Slippage    float64
+	GasStrategy GasStrategy
+	// GasPrice in gwei, only used by the fixed gas strategy.
+	GasPrice float64
+	// GasBoost in percent, only used by the boost gas strategy.
+	GasBoost float64
+	GasLimit uint64
+	Deadline time.Duration
+	// MaxHops is the maximum number of hops of the routes.
+	MaxHops int
+	// PriceFeedInterval is the time between two price updates. The rate limit of the endpoint is never exceeded.
+	PriceFeedInterval time.Duration
+	// MaxSlippage, MaxPriceImpact and MaxTradeAmount tighten the guards of the config.
+	MaxSlippage    float64
+	MaxPriceImpact float64
+	// MaxTradeAmount is the maximum value of a single swap in the native currency of the network.
+	MaxTradeAmount float64
+	Predefined     bool       `gorm:"-"`
+	mu             sync.Mutex `gorm:"-"`
+}
+
+// builtinProfiles returns the profiles which are available on every network.
+// A user-defined profile with the same name replaces them.
+func builtinProfiles() []*Profile {
+	return []*Profile{
+		{
+			Name:              "careful",
+			Slippage:          0.5,
+			GasStrategy:       GasStrategyNetwork,
+			Deadline:          10 * time.Minute,
+			MaxHops:           3,
+			PriceFeedInterval: time.Second,
+			MaxSlippage:       1,
+			MaxPriceImpact:    2,
+		},
+		{
+			Name:              "sniper",
+			Slippage:          15,
+			GasStrategy:       GasStrategyBoost,
+			GasBoost:          30,
+			Deadline:          time.Minute,
+			MaxHops:           2,
+			PriceFeedInterval: 100 * time.Millisecond,
+			MaxSlippage:       49,
+		},
+	}
+}
+
+// GetName returns the name of the profile.
+func (p *Profile) GetName() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.Name
+}
+
+// GetPredefined returns whether the profile is a built-in profile.
+func (p *Profile) GetPredefined() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.Predefined
+}
+
+// GetSlippage returns the slippage in percent muliplied by 100, like the slippage of a target.
+func (p *Profile) GetSlippage() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.Slippage * 100
+}
+
+// GetGasStrategy returns the gas strategy.
+func (p *Profile) GetGasStrategy() GasStrategy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.GasStrategy == "" {
+		return GasStrategyNetwork
+	}
+	return p.GasStrategy
+}
+
+// GetGasPrice returns the gas price of the fixed gas strategy in gwei.
+func (p *Profile) GetGasPrice() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.GasPrice
+}
+
+// GetGasBoost returns the increase of the boost gas strategy in percent.
+func (p *Profile) GetGasBoost() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.GasBoost
+}
+
+// GetGasLimit returns the gas limit.
+func (p *Profile) GetGasLimit() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.GasLimit
+}
+
+// GetDeadline returns the deadline.
+func (p *Profile) GetDeadline() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.Deadline
+}
+
+// GetMaxHops returns the maximum number of hops or def if the profile has none.
+func (p *Profile) GetMaxHops(def int) int {
+	if p == nil {
+		return def
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.MaxHops <= 0 {
+		return def
+	}
+	return p.MaxHops
+}
+
+// GetPriceFeedInterval returns the interval of the price feed.
+func (p *Profile) GetPriceFeedInterval() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.PriceFeedInterval
+}
+
+// GetMaxSlippage returns the maximum slippage in percent.
+func (p *Profile) GetMaxSlippage() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.MaxSlippage
+}
+
+// GetMaxPriceImpact returns the maximum price impact in percent.
+func (p *Profile) GetMaxPriceImpact() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.MaxPriceImpact
+}
+
+// GetMaxTradeAmount returns the maximum value of a swap in the native currency.
+func (p *Profile) GetMaxTradeAmount() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.MaxTradeAmount
+}
+
+// Summary returns a short description of the profile.
+func (p *Profile) Summary() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var s []string
+	if p.Slippage > 0 {
+		s = append(s, fmt.Sprintf("slippage %g%%", p.Slippage))
+	}
+	switch p.GasStrategy {
+	case GasStrategyBoost:
+		s = append(s, fmt.Sprintf("gas +%g%%", p.GasBoost))
+	case GasStrategyFixed:
+		s = append(s, fmt.Sprintf("gas %g gwei", p.GasPrice))
+	}
+	if p.Deadline > 0 {
+		s = append(s, "deadline "+p.Deadline.String())
+	}
+	if p.MaxHops > 0 {
+		s = append(s, fmt.Sprintf("%d hops", p.MaxHops))
+	}
+	if len(s) == 0 {
+		return "defaults"
+	}
+	return strings.Join(s, ", ")
+}
+
+func (p *Profile) validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return errors.New("missing name")
+	}
+	strategy, err := ParseGasStrategy(string(p.GasStrategy))
+	if err != nil {
+		return err
+	}
+	p.GasStrategy = strategy
+	if p.Slippage < 0 || p.Slippage > 100 {
+		return fmt.Errorf("invalid slippage: %g", p.Slippage)
+	}
+	if strategy == GasStrategyFixed && p.GasPrice <= 0 {
+		return errors.New("the fixed gas strategy requires a gas price")
+	}
+	return nil
+}
+
+// GetProfiles returns the built-in and the user-defined profiles of the network ordered by name.
+func (n *Network) GetProfiles() []*Profile {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var profiles []*Profile
+	for _, p := range builtinProfiles() {
+		if findProfile(n.Profiles, p.Name) != nil {
+			continue
+		}
+		p.Predefined = true
+		profiles = append(profiles, p)
+	}
+	profiles = append(profiles, n.Profiles...)
+	sort.SliceStable(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles
+}
+
+// GetProfile returns the profile by name (case insensitive) or nil if the network has none.
+func (n *Network) GetProfile(name string) *Profile {
+	if name == "" {
+		return nil
+	}
+	return findProfile(n.GetProfiles(), name)
+}
+
+func findProfile(profiles []*Profile, name string) *Profile {
+	for _, p := range profiles {
+		if strings.EqualFold(p.Name, name) {
+			return p
+		}
+	}
+	return nil
+}
+
+// CreateProfile saves a user-defined profile on the network.
+// A profile with the name of a built-in profile replaces it.
+func CreateProfile(n *Network, p *Profile) error {
+	n.mu.Lock()
+	exists := findProfile(n.Profiles, p.Name) != nil
+	n.mu.Unlock()
+	if exists {
+		return ErrProfileExists
+	}
+	if err := p.validate(); err != nil {
+		return err
+	}
+	p.NetworkID = n.GetID()
+	p.Predefined = false
+	if err := repo.SaveProfile(p).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"profile": p.Name,
+			"err":     err,
+		}).Error("failed to save the profile")
+		return err
+	}
+	n.mu.Lock()
+	n.Profiles = append(n.Profiles, p)
+	n.mu.Unlock()
+	return nil
+}
+
+// UpdateProfile saves the changes of a user-defined profile.
+func UpdateProfile(p *Profile) error {
+	if p.GetPredefined() {
+		return ErrPredefined
+	}
+	if err := p.validate(); err != nil {
+		return err
+	}
+	if err := repo.SaveProfile(p).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"profile": p.Name,
+			"err":     err,
+		}).Error("failed to update the profile")
+		return err
+	}
+	return nil
+}
+
+// DeleteProfile removes a user-defined profile from the network.
+// Trades which used the profile fall back to the built-in profile with the same name or to the defaults.
+func DeleteProfile(n *Network, p *Profile) error {
+	if p.GetPredefined() {
+		return ErrPredefined
+	}
+	if err := repo.DeleteProfile(p).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"profile": p.Name,
+			"err":     err,
+		}).Error("failed to delete the profile")
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for i, profile := range n.Profiles {
+		if profile == p {
+			n.Profiles = append(n.Profiles[:i], n.Profiles[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
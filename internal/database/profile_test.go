@@ -0,0 +1,76 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestProfiles(t *testing.T) {
+	n := &Network{
+		Name:      "deadshot-profile-test",
+		ChainID:   1339,
+		Endpoints: Endpoints{NewEndpoint("http://localhost:8545", false)},
+	}
+	if err := CreateNetwork(n); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := DeleteNetwork(n); err != nil {
+			t.Error(err)
+		}
+	}()
+	if p := n.GetProfile("Sniper"); p == nil || !p.GetPredefined() {
+		t.Fatalf("expected the built-in sniper profile, got %v", p)
+	}
+	if err := CreateProfile(n, &Profile{Name: "broken", GasStrategy: GasStrategyFixed}); err == nil {
+		t.Error("expected an error for a fixed gas strategy without gas price")
+	}
+
+	// a user-defined profile replaces the built-in one
+	sniper := &Profile{Name: "sniper", Slippage: 20, Deadline: 30 * time.Second}
+	if err := CreateProfile(n, sniper); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateProfile(n, &Profile{Name: "SNIPER"}); !errors.Is(err, ErrProfileExists) {
+		t.Errorf("expected %v, got %v", ErrProfileExists, err)
+	}
+	if got := n.GetProfile("sniper"); got != sniper {
+		t.Errorf("expected the user-defined profile, got %v", got)
+	}
+	if len(n.GetProfiles()) != 2 {
+		t.Errorf("expected 2 profiles, got %d", len(n.GetProfiles()))
+	}
+	networks, err := FetchAllNetworks(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, network := range networks {
+		if network.Name == n.Name && network.GetProfile("sniper").GetSlippage() != 2000 {
+			t.Error("profile not saved")
+		}
+	}
+
+	// the values of the target override the profile
+	target := NewTargetWithProfile(sniper)
+	if target.GetSlippage() != 2000 || *target.GetDeadline() != 30*time.Second || *target.GetGasLimit() != defaultGasLimit {
+		t.Errorf("unexpected target defaults: %v %v %v", target.GetSlippage(), *target.GetDeadline(), *target.GetGasLimit())
+	}
+	slippage := float64(50)
+	target = new(Target)
+	target.SetSlippage(&slippage)
+	target.SetProfileDefaults(sniper)
+	if target.GetSlippage() != 50 {
+		t.Errorf("expected the slippage of the target, got %v", target.GetSlippage())
+	}
+
+	if err := DeleteProfile(n, sniper); err != nil {
+		t.Fatal(err)
+	}
+	if p := n.GetProfile("sniper"); p == nil || !p.GetPredefined() {
+		t.Error("expected the built-in profile after removing the user-defined one")
+	}
+	if err := DeleteProfile(n, n.GetProfile("careful")); !errors.Is(err, ErrPredefined) {
+		t.Errorf("expected %v, got %v", ErrPredefined, err)
+	}
+}
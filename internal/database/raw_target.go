@@ -4,6 +4,7 @@ import (
 	"math/big"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/jon4hz/deadshot/internal/logging"
 	"github.com/jon4hz/deadshot/pkg/ethutils"
@@ -24,9 +25,13 @@ type RawTarget struct {
 	ExactPrice  bool
 	ExactAmount bool
 	Stoploss    bool
-	Skip        bool // Skip is true, if the raw target has been converted to a database.Target already by calling Target()
-	TradeID     uint
-	mu          sync.Mutex `gorm:"-"`
+	// Deadline of the swap transaction, nil for the default.
+	Deadline       *time.Duration
+	ExpiresAt      *time.Time
+	ExpiresAtBlock uint64
+	Skip           bool // Skip is true, if the raw target has been converted to a database.Target already by calling Target()
+	TradeID        uint
+	mu             sync.Mutex `gorm:"-"`
 }
 
 // NewRawTarget creates a new raw target.
@@ -42,8 +47,35 @@ func NewRawTarget(price, amount string, exactPrice, exactAmount bool, slippage f
 	}
 }
 
+// SetDeadline sets the deadline of the swap transaction.
+func (t *RawTarget) SetDeadline(deadline *time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Deadline = deadline
+}
+
+// SetExpiry sets the expiry of the target.
+func (t *RawTarget) SetExpiry(e Expiry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ExpiresAt = e.At
+	t.ExpiresAtBlock = e.Block
+}
+
 // Target turns a raw target into a target.
 func (t *RawTarget) Target(baseToken *Token, actualToken *Token, amountMode *AmountMode, targetType *TargetType, tradeID uint) *Target {
+	target := t.target(baseToken, actualToken, amountMode, targetType, tradeID)
+	if target == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	target.SetDeadline(t.Deadline)
+	target.SetExpiry(Expiry{At: t.ExpiresAt, Block: t.ExpiresAtBlock})
+	return target
+}
+
+func (t *RawTarget) target(baseToken *Token, actualToken *Token, amountMode *AmountMode, targetType *TargetType, tradeID uint) *Target {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -0,0 +1,223 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	// DriverSQLite stores the data in a local sqlite file, it's the default.
+	DriverSQLite = "sqlite"
+	// DriverPostgres stores the data in a postgres database, e.g. to share the trade history.
+	DriverPostgres = "postgres"
+)
+
+var ErrUnknownDriver = errors.New("unknown database driver")
+
+// Options select the database backend.
+type Options struct {
+	// Driver is either sqlite or postgres. An empty driver uses sqlite.
+	Driver string `yaml:"driver" mapstructure:"driver"`
+	// DSN is the path of the sqlite file or the connection string of postgres.
+	// An empty path uses the sqlite file in the config directory.
+	DSN string `yaml:"dsn" mapstructure:"dsn"`
+}
+
+var options Options
+
+// Configure sets the backend which is opened by InitDB.
+func Configure(o Options) {
+	options = o
+}
+
+// driver returns the configured driver in lower case.
+func (o Options) driver() string {
+	if d := strings.ToLower(strings.TrimSpace(o.Driver)); d != "" {
+		return d
+	}
+	return DriverSQLite
+}
+
+// File returns the path of the sqlite file or an empty string if the data is stored in postgres.
+func File() string {
+	if options.driver() != DriverSQLite {
+		return ""
+	}
+	if options.DSN != "" {
+		return options.DSN
+	}
+	return ConfigDBFile
+}
+
+// Repository stores and loads the models.
+// Every backend is a gorm dialect, the results are the gorm results of the queries.
+type Repository interface {
+	FindAllNetworks(dest *[]*Network) *gorm.DB
+	FindNetworkByName(dest *Network, networkName string) *gorm.DB
+	FindNetworkWithAssociationsByName(dest *Network, networkName string) *gorm.DB
+	FindUserDefinedNetworks(dest *[]*Network) *gorm.DB
+	SaveNetwork(n *Network) *gorm.DB
+	UpdateNetwork(n *Network, fields map[string]any) *gorm.DB
+	ReplaceNetworkEndpoints(n *Network) error
+	DeleteNetworkCascade(n *Network) error
+
+	FindTermsAndConditions(dest *Misc) *gorm.DB
+	SaveTermsAndConditions(toc *Misc) *gorm.DB
+	FindHalt(dest *Halt) *gorm.DB
+	SaveHalt(halt *Halt) *gorm.DB
+	FindDataVersion(dest *DataVersion) *gorm.DB
+	SaveDataVersion(version *DataVersion) *gorm.DB
+
+	SaveEndpoint(endpoint *Endpoint) *gorm.DB
+	UpdateEndpointURL(endpoint *Endpoint, url string) *gorm.DB
+	DeleteEndpoint(endpoint *Endpoint) *gorm.DB
+	FindCustomEndpoint(dest *Endpoint, networkName string) *gorm.DB
+	DeleteCustomEndpoint(networkName string) *gorm.DB
+	FindAllEndpoints(dest *[]*Endpoint) *gorm.DB
+
+	FindWallet(dest *Wallet) *gorm.DB
+	SaveWallet(wallet *Wallet) *gorm.DB
+	FindWallets(dest *[]*Wallet, mainID uint) *gorm.DB
+	FindWalletsByAddress(dest *[]*Wallet, address string) *gorm.DB
+	DeleteWallet(wallet *Wallet) *gorm.DB
+
+	FindAllTradeTypes(dest *TradeTypes) *gorm.DB
+	SaveTradeType(tradeType *TradeType) *gorm.DB
+	FindAllTargetTypes(dest *TargetTypes) *gorm.DB
+	SaveTargetType(targetType *TargetType) *gorm.DB
+	FindAllAmountModes(dest *AmountModes) *gorm.DB
+	SaveAmountMode(amountMode *AmountMode) *gorm.DB
+
+	SaveToken(token *Token) *gorm.DB
+	CreateTokens(tokens []*Token, batchSize int) *gorm.DB
+	UpdateToken(token *Token, fields map[string]any) *gorm.DB
+	DeleteToken(token *Token) *gorm.DB
+	UpdateTokenBalance(tokenID uint, balance string) *gorm.DB
+	UpdateTokenUnlimitedApproval(tokenID uint, unlimited bool) *gorm.DB
+	UpdateTokenStarred(tokenID uint, starred bool) *gorm.DB
+	UpdateTokenLastUsedAt(tokenID uint, lastUsedAt time.Time) *gorm.DB
+	FindTokensByNetworkID(dest *[]*Token, networkID uint) *gorm.DB
+	FindTokenIDByContractAndNetworkID(dest *uint, contract string, networkID uint) *gorm.DB
+	FindTokenBalanceByContractAndNetworkID(dest *string, contract string, networkID uint) *gorm.DB
+	FindTokenByContractAndNetworkID(dest *Token, contract string, networkID uint) *gorm.DB
+
+	SaveDex(dex *Dex) *gorm.DB
+	UpdateDex(dex *Dex, fields map[string]any) *gorm.DB
+	UpdateDexAndPairs(dex *Dex) error
+	DeleteDexAndPairs(dex *Dex) error
+	FindPairsByDexID(dest *[]*Pair, dexID uint) *gorm.DB
+	SavePair(pair *Pair) *gorm.DB
+	DeletePairsByDexID(dexID uint) *gorm.DB
+
+	SaveProfile(p *Profile) *gorm.DB
+	DeleteProfile(p *Profile) *gorm.DB
+	SaveTrade(trade *Trade) *gorm.DB
+	// CountTradesByReference counts the trades which reference the id in the column, e.g. token0_id.
+	CountTradesByReference(dest *int64, column string, id uint) *gorm.DB
+	FindSpending(dest *Spending, networkID uint, day string) *gorm.DB
+	SaveSpending(spending *Spending) *gorm.DB
+
+	// Backup writes a consistent copy of the database to the file.
+	Backup(file string) error
+	// Check runs the integrity check of the backend and looks for inconsistent data.
+	Check() ([]Issue, error)
+	// Transaction runs fc with a repository of a transaction, the changes are rolled back if it returns an error.
+	Transaction(fc func(r Repository) error) error
+	// Close closes the connection of the backend.
+	Close() error
+}
+
+// repo is the repository opened by InitDB.
+var repo Repository
+
+var _ Repository = (*gormRepository)(nil)
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// openRepository opens the backend of the options and migrates the schema.
+func openRepository(o Options, config *gorm.Config) (*gormRepository, error) {
+	var dialector gorm.Dialector
+	switch o.driver() {
+	case DriverSQLite:
+		file := o.DSN
+		if file == "" {
+			file = ConfigDBFile
+		}
+		dialector = sqlite.Open(file)
+	case DriverPostgres:
+		if o.DSN == "" {
+			return nil, errors.New("the postgres driver requires a dsn")
+		}
+		dialector = postgres.Open(o.DSN)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDriver, o.Driver)
+	}
+	db, err := gorm.Open(dialector, config)
+	if err != nil {
+		return nil, err
+	}
+
+	switch o.driver() {
+	case DriverSQLite:
+		// enable foreign key constraints
+		db.Exec("PRAGMA foreign_keys = ON;")
+	case DriverPostgres:
+		if err := db.Callback().Create().After("gorm:create").Register("deadshot:sync_sequence", syncSequence); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &gormRepository{db: db}
+	if err := migrate(db); err != nil {
+		r.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// syncSequence moves the id sequence of the table past the highest id.
+// Some rows are created with a fixed id (e.g. the main wallet), which doesn't advance the sequence in postgres.
+func syncSequence(tx *gorm.DB) {
+	if tx.Error != nil || tx.Statement.Schema == nil || tx.Statement.Schema.PrioritizedPrimaryField == nil {
+		return
+	}
+	table, column := tx.Statement.Table, tx.Statement.Schema.PrioritizedPrimaryField.DBName
+	if !tx.Statement.Schema.PrioritizedPrimaryField.AutoIncrement {
+		return
+	}
+	tx.Session(&gorm.Session{NewDB: true}).Exec(
+		"SELECT setval(pg_get_serial_sequence(?, ?), (SELECT COALESCE(MAX("+tx.Statement.Quote(column)+"), 1) FROM "+tx.Statement.Quote(table)+"))",
+		table, column,
+	)
+}
+
+func (r *gormRepository) Backup(file string) error {
+	if r.db.Dialector.Name() != DriverSQLite {
+		return fmt.Errorf("backup %w, use the tools of the database instead", ErrUnsupportedBackend)
+	}
+	if _, err := os.Stat(file); err == nil {
+		return fmt.Errorf("%s already exists", file)
+	}
+	return r.db.Exec("VACUUM INTO ?", file).Error
+}
+
+func (r *gormRepository) Check() ([]Issue, error) {
+	return checkDB(r.db)
+}
+
+func (r *gormRepository) Close() error {
+	d, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+	return d.Close()
+}
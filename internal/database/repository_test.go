@@ -0,0 +1,56 @@
+package database
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestOpenRepository(t *testing.T) {
+	r, err := openRepository(Options{DSN: filepath.Join(t.TempDir(), "test.db")}, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	var version uint
+	if err := r.db.Model(&SchemaMigration{}).Select("MAX(version)").Scan(&version).Error; err != nil {
+		t.Fatal(err)
+	}
+	if latest := migrations[len(migrations)-1].version; version != latest {
+		t.Errorf("schema version = %d, want %d", version, latest)
+	}
+
+	if _, err := openRepository(Options{Driver: "mysql"}, &gorm.Config{}); !errors.Is(err, ErrUnknownDriver) {
+		t.Errorf("expected %v, got %v", ErrUnknownDriver, err)
+	}
+	if _, err := openRepository(Options{Driver: "Postgres"}, &gorm.Config{}); err == nil {
+		t.Error("expected an error for postgres without a dsn")
+	}
+}
+
+// TestFixedID checks that rows without an id can be created after a row with a fixed id, like the main wallet.
+func TestFixedID(t *testing.T) {
+	r, err := openRepository(Options{DSN: filepath.Join(t.TempDir(), "test.db")}, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	testFixedID(t, r)
+}
+
+func testFixedID(t *testing.T, r Repository) {
+	t.Helper()
+	fixed := &Spending{Model: gorm.Model{ID: 100}, NetworkID: 9999, Day: "fixed"}
+	next := &Spending{NetworkID: 9999, Day: "next"}
+	if err := r.SaveSpending(fixed).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SaveSpending(next).Error; err != nil {
+		t.Fatal(err)
+	}
+	if next.ID <= fixed.ID {
+		t.Errorf("id = %d, want an id after %d", next.ID, fixed.ID)
+	}
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"math/big"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// spendingDayLayout is the format of the day of a spending.
+const spendingDayLayout = "2006-01-02"
+
+// Spending is the value of all automated swaps on a network and day in the native currency.
+type Spending struct {
+	gorm.Model
+	NetworkID uint   `gorm:"uniqueIndex:idx_spending_network_day"`
+	Day       string `gorm:"uniqueIndex:idx_spending_network_day"`
+	Amount    string
+}
+
+// FetchDailySpending returns the amount spent on the network at the day of the given time (UTC).
+func FetchDailySpending(networkID uint, day time.Time) (*big.Int, error) {
+	var s Spending
+	res := repo.FindSpending(&s, networkID, day.UTC().Format(spendingDayLayout))
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	amount, ok := new(big.Int).SetString(s.Amount, 10)
+	if res.RowsAffected == 0 || !ok {
+		return big.NewInt(0), nil
+	}
+	return amount, nil
+}
+
+// AddDailySpending adds the amount to the spending of the network at the day of the given time (UTC).
+// A negative amount reverts a previous spending.
+func AddDailySpending(networkID uint, day time.Time, amount *big.Int) error {
+	var s Spending
+	res := repo.FindSpending(&s, networkID, day.UTC().Format(spendingDayLayout))
+	if res.Error != nil {
+		return res.Error
+	}
+	total, ok := new(big.Int).SetString(s.Amount, 10)
+	if !ok {
+		total = big.NewInt(0)
+	}
+	total.Add(total, amount)
+	if total.Sign() < 0 {
+		total.SetInt64(0)
+	}
+	s.NetworkID = networkID
+	s.Day = day.UTC().Format(spendingDayLayout)
+	s.Amount = total.String()
+	return repo.SaveSpending(&s).Error
+}
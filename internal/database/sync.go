@@ -0,0 +1,282 @@
+package database
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/logging"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+	"gorm.io/gorm"
+)
+
+// DataVersion is the hash of the built-in data which was last merged into the database.
+type DataVersion struct {
+	gorm.Model
+	Hash string
+}
+
+// builtinData is the built-in data of data.yml.
+type builtinData struct {
+	Networks    []*Network    `yaml:"networks"`
+	TradeTypes  []*TradeType  `yaml:"tradeTypes"`
+	AmountModes []*AmountMode `yaml:"amountModes"`
+	TargetTypes []*TargetType `yaml:"targetTypes"`
+}
+
+// syncBuiltinData merges new or changed predefined networks, dexes, endpoints and tokens into the database.
+// User-defined entries and the custom endpoints are never changed.
+// The merge only runs if the built-in data changed since the last merge.
+func syncBuiltinData(raw []byte) error {
+	sum := sha256.Sum256(raw)
+	hash := hex.EncodeToString(sum[:])
+	var version DataVersion
+	if err := repo.FindDataVersion(&version).Error; err != nil {
+		return err
+	}
+	if version.Hash == hash {
+		return nil
+	}
+
+	var data builtinData
+	if err := yaml.Unmarshal(raw, &data); err != nil {
+		return err
+	}
+	logging.Log.WithField("hash", hash).Info("merging the built-in data")
+	return repo.Transaction(func(r Repository) error {
+		var tradeTypes TradeTypes
+		if err := r.FindAllTradeTypes(&tradeTypes).Error; err != nil {
+			return err
+		}
+		if err := mergeTypes(tradeTypes, data.TradeTypes, func(t *TradeType) string { return t.Type }, r.SaveTradeType); err != nil {
+			return err
+		}
+		var amountModes AmountModes
+		if err := r.FindAllAmountModes(&amountModes).Error; err != nil {
+			return err
+		}
+		if err := mergeTypes(amountModes, data.AmountModes, func(a *AmountMode) string { return a.Type }, r.SaveAmountMode); err != nil {
+			return err
+		}
+		var targetTypes TargetTypes
+		if err := r.FindAllTargetTypes(&targetTypes).Error; err != nil {
+			return err
+		}
+		if err := mergeTypes(targetTypes, data.TargetTypes, func(t *TargetType) string { return t.Type }, r.SaveTargetType); err != nil {
+			return err
+		}
+		for _, network := range data.Networks {
+			if err := mergeNetwork(r, network); err != nil {
+				return err
+			}
+		}
+		version.ID = 1
+		version.Hash = hash
+		return r.SaveDataVersion(&version).Error
+	})
+}
+
+// mergeTypes adds the built-in types which don't exist yet.
+func mergeTypes[T TradeType | AmountMode | TargetType](existing, builtin []*T, typeOf func(*T) string, save func(*T) *gorm.DB) error {
+	known := make(map[string]bool, len(existing))
+	for _, t := range existing {
+		known[typeOf(t)] = true
+	}
+	for _, t := range builtin {
+		if known[typeOf(t)] {
+			continue
+		}
+		if err := save(t).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeNetwork adds the built-in network or updates the existing predefined network.
+// A user-defined network with the same name is left untouched.
+func mergeNetwork(r Repository, builtin *Network) error {
+	builtin.Predefined = true
+	for _, token := range builtin.Tokens {
+		token.Predefined = true
+	}
+	for _, dex := range builtin.Dexes {
+		dex.Predefined = true
+	}
+
+	var existing Network
+	res := r.FindNetworkWithAssociationsByName(&existing, builtin.Name)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return r.SaveNetwork(builtin).Error
+	}
+	if !existing.Predefined {
+		logging.Log.WithField("network", builtin.Name).Warn("skipping built-in network, a user-defined network has the same name")
+		return nil
+	}
+
+	if err := r.UpdateNetwork(&existing, networkFields(builtin)).Error; err != nil {
+		return err
+	}
+	if err := mergeEndpoints(r, &existing, builtin.Endpoints); err != nil {
+		return err
+	}
+	if err := mergeDexes(r, &existing, builtin.Dexes); err != nil {
+		return err
+	}
+	return mergeTokens(r, &existing, builtin.Tokens)
+}
+
+// mergeEndpoints replaces the predefined endpoints of the network, the custom endpoint is kept.
+// Removed endpoints which are still referenced by a trade are kept as well.
+func mergeEndpoints(r Repository, n *Network, builtin Endpoints) error {
+	urls := make(map[string]bool, len(builtin))
+	for _, e := range builtin {
+		urls[e.URL] = true
+	}
+	for _, e := range n.Endpoints {
+		if e.Custom {
+			continue
+		}
+		if urls[e.URL] {
+			delete(urls, e.URL)
+			continue
+		}
+		used, err := usedByTrades(r, "endpoint_id", e.ID)
+		if err != nil {
+			return err
+		}
+		if used {
+			continue
+		}
+		if err := r.DeleteEndpoint(e).Error; err != nil {
+			return err
+		}
+	}
+	for _, e := range builtin {
+		if !urls[e.URL] {
+			continue
+		}
+		e.NetworkID = n.ID
+		if err := r.SaveEndpoint(e).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeDexes adds new dexes and updates the predefined dexes of the network.
+func mergeDexes(r Repository, n *Network, builtin []*Dex) error {
+	for _, dex := range builtin {
+		var existing *Dex
+		for _, d := range n.Dexes {
+			if strings.EqualFold(d.Name, dex.Name) {
+				existing = d
+				break
+			}
+		}
+		if existing == nil {
+			dex.NetworkID = n.ID
+			if err := r.SaveDex(dex).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if !existing.Predefined {
+			logging.Log.WithFields(logrus.Fields{
+				"network": n.Name,
+				"dex":     dex.Name,
+			}).Warn("skipping built-in dex, a user-defined dex has the same name")
+			continue
+		}
+		if err := updateDex(r, existing, dex); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeTokens adds new tokens and updates the predefined tokens of the network.
+// Tokens which were added by the user or cached from a trade are left untouched.
+func mergeTokens(r Repository, n *Network, builtin []*Token) error {
+	for _, token := range builtin {
+		var existing *Token
+		for _, t := range n.Tokens {
+			if strings.EqualFold(t.Contract, token.Contract) {
+				existing = t
+				break
+			}
+		}
+		if existing == nil {
+			token.NetworkID = n.ID
+			if err := r.SaveToken(token).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if !existing.Predefined {
+			continue
+		}
+		if err := r.UpdateToken(existing, tokenFields(token)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// networkFields returns the fields of the network which are updated by a merge.
+func networkFields(n *Network) map[string]any {
+	return map[string]any{
+		"FullName":       n.FullName,
+		"Multicall":      n.Multicall,
+		"NativeCurrency": n.NativeCurrency,
+		"WETH":           n.WETH,
+		"GasLimit":       n.GasLimit,
+		"ChainID":        n.ChainID,
+		"IsTestnet":      n.IsTestnet,
+		"EIP1559Enabled": n.EIP1559Enabled,
+	}
+}
+
+// tokenFields returns the fields of the token which are updated by a merge.
+func tokenFields(t *Token) map[string]any {
+	return map[string]any{
+		"Symbol":    t.Symbol,
+		"Decimals":  t.Decimals,
+		"Connector": t.Connector,
+		"Native":    t.Native,
+	}
+}
+
+// updateDex updates the existing dex with the fields of the dex.
+// The cached pairs of the dex are removed if its contracts changed.
+func updateDex(r Repository, existing, dex *Dex) error {
+	if !strings.EqualFold(existing.Router, dex.Router) ||
+		!strings.EqualFold(existing.Factory, dex.Factory) ||
+		existing.InitCodeHash != dex.InitCodeHash ||
+		existing.Protocol != dex.Protocol {
+		if err := r.DeletePairsByDexID(existing.ID).Error; err != nil {
+			return err
+		}
+	}
+	return r.UpdateDex(existing, map[string]any{
+		"Router":       dex.Router,
+		"Factory":      dex.Factory,
+		"InitCodeHash": dex.InitCodeHash,
+		"Protocol":     dex.Protocol,
+		"Fee":          dex.Fee,
+	}).Error
+}
+
+// usedByTrades returns whether a trade references the id in the column, e.g. token0_id.
+func usedByTrades(r Repository, column string, id uint) (bool, error) {
+	var trades int64
+	if err := r.CountTradesByReference(&trades, column, id).Error; err != nil {
+		return false, err
+	}
+	return trades > 0, nil
+}
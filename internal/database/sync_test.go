@@ -0,0 +1,105 @@
+package database
+
+import (
+	"testing"
+)
+
+// merge merges the built-in network in a transaction like syncBuiltinData.
+func merge(n *Network) error {
+	return repo.Transaction(func(r Repository) error { return mergeNetwork(r, n) })
+}
+
+func TestMergeNetwork(t *testing.T) {
+	builtin := func(endpoints []string, fee int64, symbol string) *Network {
+		n := &Network{
+			Name:           "deadshot-sync-test",
+			ChainID:        1338,
+			NativeCurrency: "TEST",
+			Dexes:          []*Dex{NewDex("testswap", "0x0000000000000000000000000000000000000003", "0x0000000000000000000000000000000000000004", fee, false)},
+			Tokens:         []*Token{NewToken("0x0000000000000000000000000000000000000005", symbol, 18, false, nil)},
+		}
+		for _, url := range endpoints {
+			n.Endpoints = append(n.Endpoints, NewEndpoint(url, false))
+		}
+		return n
+	}
+	if err := merge(builtin([]string{"http://a", "http://b"}, 9970, "TKN")); err != nil {
+		t.Fatal(err)
+	}
+	n := fetchNetwork(t, "deadshot-sync-test")
+	defer func() {
+		if err := repo.DeleteNetworkCascade(n); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err := n.CreateCustomEndpoint("http://custom"); err != nil {
+		t.Fatal(err)
+	}
+
+	changed := builtin([]string{"http://b", "http://c"}, 9975, "TKN2")
+	changed.Dexes = append(changed.Dexes, NewDex("otherswap", "0x0000000000000000000000000000000000000006", "0x0000000000000000000000000000000000000007", 9980, false))
+	if err := merge(changed); err != nil {
+		t.Fatal(err)
+	}
+	n = fetchNetwork(t, "deadshot-sync-test")
+	urls := make(map[string]bool)
+	for _, e := range n.GetEndpoints() {
+		urls[e.GetURL()] = true
+	}
+	if len(urls) != 3 || !urls["http://b"] || !urls["http://c"] || !urls["http://custom"] {
+		t.Errorf("unexpected endpoints %v", urls)
+	}
+	if len(n.GetDexes()) != 2 {
+		t.Fatalf("expected 2 dexes, got %d", len(n.GetDexes()))
+	}
+	if fee := n.GetDexByName("testswap").GetFee(); fee != 9975 {
+		t.Errorf("expected fee 9975, got %d", fee)
+	}
+	if symbol := n.GetTokenByContract("0x0000000000000000000000000000000000000005").GetSymbol(); symbol != "TKN2" {
+		t.Errorf("expected symbol TKN2, got %s", symbol)
+	}
+}
+
+func TestMergeSkipsUserDefinedNetwork(t *testing.T) {
+	n := &Network{
+		Name:      "deadshot-sync-user",
+		ChainID:   1339,
+		Endpoints: Endpoints{NewEndpoint("http://user", false)},
+	}
+	if err := CreateNetwork(n); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := DeleteNetwork(n); err != nil {
+			t.Error(err)
+		}
+	}()
+	builtin := &Network{
+		Name:      "deadshot-sync-user",
+		ChainID:   1,
+		Endpoints: Endpoints{NewEndpoint("http://builtin", false)},
+	}
+	if err := merge(builtin); err != nil {
+		t.Fatal(err)
+	}
+	saved := fetchNetwork(t, "deadshot-sync-user")
+	if saved.GetPredefined() || saved.GetChainID() != 1339 {
+		t.Error("user-defined network was changed")
+	}
+	if urls := saved.GetEndpoints().GetUrls(); len(urls) != 1 || urls[0] != "http://user" {
+		t.Errorf("unexpected endpoints %v", urls)
+	}
+}
+
+func fetchNetwork(t *testing.T, name string) *Network {
+	t.Helper()
+	networks, err := FetchAllNetworks(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := Networks(networks).GetNetworkByName(name)
+	if n == nil {
+		t.Fatalf("network %s not found", name)
+	}
+	return n
+}
@@ -28,6 +28,10 @@ type Target struct {
 	gorm.Model
 	// The trading path of the target.
 	Path []common.Address `gorm:"-"`
+	// Stables marks the stable pairs of the path on solidly dexes.
+	Stables []bool `gorm:"-"`
+	// The dex the path belongs to. If nil, the dex of the trade is used.
+	Dex *Dex `gorm:"-"`
 	// The price of the target. Convert to *big.Int, normalized with decimals.
 	Price string
 	// The amount of the target. Convert to *big.Int, normalized with decimals.
@@ -52,8 +56,15 @@ type Target struct {
 	Slippage   *float64
 	TargetType *TargetType `gorm:"foreignkey:TargetTypeID"`
 	TradeID    uint
-	Deadline   *time.Duration `gorm:"-"` // TODO: replace with unix timestamp and store in database
-	GasLimit   *uint64
+	// Deadline is the time a swap transaction of the target is valid after it was sent.
+	Deadline *time.Duration
+	// DeadlineAt is the deadline of the last swap transaction of the target.
+	DeadlineAt *time.Time
+	// ExpiresAt and ExpiresAtBlock cancel the target if it isn't hit until then.
+	ExpiresAt      *time.Time
+	ExpiresAtBlock uint64
+	Expired        bool
+	GasLimit       *uint64
 	// In WEI
 	GasPrice             *big.Int   `gorm:"-"`
 	mu                   sync.Mutex `gorm:"-"`
@@ -187,11 +198,50 @@ func NewPercentagePriceAndAmountTarget(
 
 // NewTargetWithDefaults creates a new target with default values.
 func NewTargetWithDefaults() *Target {
+	return NewTargetWithProfile(nil)
+}
+
+// NewTargetWithProfile creates a new target with the values of the profile and the defaults for the rest.
+func NewTargetWithProfile(p *Profile) *Target {
 	t := new(Target)
-	t.SetDefaults()
+	t.SetProfileDefaults(p)
 	return t
 }
 
+// Copy returns an unsaved copy of the target, e.g. to execute a part of it.
+func (t *Target) Copy() *Target {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return &Target{
+		Path:                 t.Path,
+		Stables:              t.Stables,
+		Dex:                  t.Dex,
+		Price:                t.Price,
+		Amount:               t.Amount,
+		AmountMinMax:         t.AmountMinMax,
+		ActualAmount:         t.ActualAmount,
+		PercentageAmount:     t.PercentageAmount,
+		PercentagePrice:      t.PercentagePrice,
+		TargetTypeID:         t.TargetTypeID,
+		TargetType:           t.TargetType,
+		AmountModeID:         t.AmountModeID,
+		AmountMode:           t.AmountMode,
+		Slippage:             t.Slippage,
+		TradeID:              t.TradeID,
+		Deadline:             t.Deadline,
+		ExpiresAt:            t.ExpiresAt,
+		ExpiresAtBlock:       t.ExpiresAtBlock,
+		GasLimit:             t.GasLimit,
+		GasPrice:             t.GasPrice,
+		AmountDecimals:       t.AmountDecimals,
+		ActualAmountDecimals: t.ActualAmountDecimals,
+		PriceDecimals:        t.PriceDecimals,
+		IsStopLoss:           t.IsStopLoss,
+		TriggerFunc:          t.TriggerFunc,
+		ExecutionPrice:       t.ExecutionPrice,
+	}
+}
+
 // MarkStopLoss marks the target as a stop loss.
 func (t *Target) MarkStopLoss() {
 	t.mu.Lock()
@@ -250,6 +300,20 @@ func (t *Target) GetPath() []common.Address {
 	return t.Path
 }
 
+// GetStables returns which pairs of the path are stable pairs.
+func (t *Target) GetStables() []bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.Stables
+}
+
+// GetDex returns the dex of the target.
+func (t *Target) GetDex() *Dex {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.Dex
+}
+
 // GetAmountMode returns the amount mode.
 func (t *Target) GetAmountMode() *AmountMode {
 	t.mu.Lock()
@@ -382,6 +446,20 @@ func (t *Target) SetPath(path []common.Address) {
 	t.Path = path
 }
 
+// SetStables sets which pairs of the path are stable pairs.
+func (t *Target) SetStables(stables []bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Stables = stables
+}
+
+// SetDex sets the dex of the target.
+func (t *Target) SetDex(dex *Dex) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Dex = dex
+}
+
 // SetAmountMinMaxx sets the amount min/max.
 func (t *Target) SetAmountMinMax(amountMinMax string) {
 	t.mu.Lock()
@@ -403,6 +481,56 @@ func (t *Target) SetDeadline(deadline *time.Duration) {
 	t.Deadline = deadline
 }
 
+// GetDeadlineAt returns the deadline of the last swap transaction or nil if none was sent.
+func (t *Target) GetDeadlineAt() *time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.DeadlineAt
+}
+
+// SetDeadlineAt sets the deadline of the swap transaction.
+func (t *Target) SetDeadlineAt(deadline time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.DeadlineAt = &deadline
+}
+
+// GetExpiry returns the expiry of the target.
+func (t *Target) GetExpiry() Expiry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return Expiry{At: t.ExpiresAt, Block: t.ExpiresAtBlock}
+}
+
+// SetExpiry sets the expiry of the target.
+func (t *Target) SetExpiry(e Expiry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ExpiresAt = e.At
+	t.ExpiresAtBlock = e.Block
+}
+
+// GetExpired returns whether the target expired before it was hit.
+func (t *Target) GetExpired() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.Expired
+}
+
+// SetExpired marks the target as expired.
+func (t *Target) SetExpired() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Expired = true
+}
+
+// GetPending returns whether the target still waits for its price.
+func (t *Target) GetPending() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return !t.Hit && !t.Confirmed && !t.Expired
+}
+
 // SetGasLimit sets the gas limit.
 func (t *Target) SetGasLimit(gasLimit *uint64) {
 	t.mu.Lock()
@@ -411,17 +539,36 @@ func (t *Target) SetGasLimit(gasLimit *uint64) {
 }
 
 var (
-	defaultDeadline = 20 * time.Minute
+	DefaultDeadline = 20 * time.Minute
 	DefaultSlippage = float64(100) // 1% (support up to 2 decimals)
 	defaultGasLimit = uint64(1000000)
 )
 
+// SetProfileDefaults sets the missing values from the profile and the defaults for the rest.
+// Values which were set on the target override the profile.
+func (t *Target) SetProfileDefaults(p *Profile) {
+	if p != nil {
+		t.mu.Lock()
+		if deadline := p.GetDeadline(); t.Deadline == nil && deadline > 0 {
+			t.Deadline = &deadline
+		}
+		if slippage := p.GetSlippage(); (t.Slippage == nil || *t.Slippage == 0) && slippage > 0 {
+			t.Slippage = &slippage
+		}
+		if gasLimit := p.GetGasLimit(); t.GasLimit == nil && gasLimit > 0 {
+			t.GasLimit = &gasLimit
+		}
+		t.mu.Unlock()
+	}
+	t.SetDefaults()
+}
+
 func (t *Target) SetDefaults() {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
 	if t.Deadline == nil {
-		t.Deadline = &defaultDeadline
+		t.Deadline = &DefaultDeadline
 	}
 	if t.Slippage == nil || *t.Slippage == 0 {
 		t.Slippage = &DefaultSlippage
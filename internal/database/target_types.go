@@ -64,7 +64,7 @@ func (t TargetTypes) GetSell() *TargetType {
 
 // LoadAllTargetTypes fetches all trade types from the database and sets them as a global variable.
 func LoadAllTargetTypes() error {
-	result := findAllTargetTypes(&DefaultTargetTypes)
+	result := repo.FindAllTargetTypes(&DefaultTargetTypes)
 	if result.Error != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"err": result.Error,
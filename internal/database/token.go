@@ -3,7 +3,10 @@ package database
 import (
 	"errors"
 	"math/big"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/jon4hz/deadshot/pkg/ethutils"
 	"github.com/jon4hz/deadshot/pkg/uniswap"
@@ -13,6 +16,8 @@ import (
 	"gorm.io/gorm"
 )
 
+var ErrTokenNotFound = errors.New("token not found")
+
 // Token represents a token with some basic informations
 // must be identical to the struct internal/blockchain/multicall/token.
 type Token struct {
@@ -25,6 +30,17 @@ type Token struct {
 	Decimals   uint8      `yaml:"decimals"`
 	Connector  bool       `yaml:"connector"`
 	Predefined bool       `yaml:"-"`
+	// UnlimitedApproval approves the maximum amount instead of the amount of each trade.
+	UnlimitedApproval bool `yaml:"unlimitedApproval,omitempty"`
+	// Name, LogoURI and Tags are the metadata of an imported token list.
+	Name    string `yaml:"name,omitempty"`
+	LogoURI string `yaml:"logoURI,omitempty"`
+	// Tags are the comma separated tags of the token.
+	Tags string `yaml:"tags,omitempty"`
+	// Starred tokens are on the watchlist of the token selection.
+	Starred bool `yaml:"starred,omitempty"`
+	// LastUsedAt is the last time the token was selected, nil if it was never used.
+	LastUsedAt *time.Time `yaml:"-"`
 
 	Native bool `yaml:"native"`
 }
@@ -73,6 +89,37 @@ func (t *Token) SetSymbol(symbol string) {
 	t.Symbol = symbol
 }
 
+// GetName returns the name of the token.
+func (t *Token) GetName() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.Name
+}
+
+// SetName sets the name of the token.
+func (t *Token) SetName(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Name = name
+}
+
+// GetLogoURI returns the uri of the logo of the token.
+func (t *Token) GetLogoURI() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.LogoURI
+}
+
+// GetTags returns the tags of the token.
+func (t *Token) GetTags() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.Tags == "" {
+		return nil
+	}
+	return strings.Split(t.Tags, ",")
+}
+
 // GetDecimals returns the decimals of the token.
 func (t *Token) GetDecimals() uint8 {
 	t.mu.Lock()
@@ -130,6 +177,48 @@ func (t *Token) SetPredefined(predefined bool) {
 	t.Predefined = predefined
 }
 
+// GetUnlimitedApproval returns whether the routers get an unlimited approval for the token.
+func (t *Token) GetUnlimitedApproval() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.UnlimitedApproval
+}
+
+// SetUnlimitedApproval sets the approval policy of the token.
+func (t *Token) SetUnlimitedApproval(unlimited bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.UnlimitedApproval = unlimited
+}
+
+// GetStarred returns whether the token is on the watchlist.
+func (t *Token) GetStarred() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.Starred
+}
+
+// SetStarred adds the token to or removes it from the watchlist.
+func (t *Token) SetStarred(starred bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Starred = starred
+}
+
+// GetLastUsedAt returns the last time the token was selected or nil if it was never used.
+func (t *Token) GetLastUsedAt() *time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.LastUsedAt
+}
+
+// SetLastUsedAt sets the last time the token was selected.
+func (t *Token) SetLastUsedAt(lastUsedAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.LastUsedAt = &lastUsedAt
+}
+
 // ToUniswap converts a token to a uniswap.Token.
 func (t *Token) ToUniswap(weth string) (*uniswap.Token, error) {
 	t.mu.Lock()
@@ -160,6 +249,13 @@ func (t *Token) GetBalanceDecimal(decimals uint8) decimal.Decimal {
 	return ethutils.ToDecimal(b, decimals)
 }
 
+// SetConnector sets whether the token is a connector token.
+func (t *Token) SetConnector(connector bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Connector = connector
+}
+
 // Connector returns whether the token is a connector token or not.
 // Connector tokens are used in the path finding.
 func (t *Token) IsConnector() bool {
@@ -175,24 +271,76 @@ func UpdateBalanceByContractAndNetworkID(contract string, networkID uint, balanc
 		return nil
 	}
 	var tid uint
-	res := findTokenIDByContractAndNetworkID(&tid, contract, networkID)
+	res := repo.FindTokenIDByContractAndNetworkID(&tid, contract, networkID)
 	if res.Error != nil {
 		return res.Error
 	}
 	if res.RowsAffected == 0 {
 		return nil
 	}
-	if err := updateTokenBalance(tid, balance.String()).Error; err != nil {
+	if err := repo.UpdateTokenBalance(tid, balance.String()).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
+// UpdateUnlimitedApprovalByContractAndNetworkID updates the approval policy of the token by the contract address and network id.
+// The network id is the internal database id of the network. It's not related to the chain id.
+func UpdateUnlimitedApprovalByContractAndNetworkID(contract string, networkID uint, unlimited bool) error {
+	var tid uint
+	res := repo.FindTokenIDByContractAndNetworkID(&tid, contract, networkID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrTokenNotFound
+	}
+	return repo.UpdateTokenUnlimitedApproval(tid, unlimited).Error
+}
+
+// UpdateStarredByContractAndNetworkID adds the token to or removes it from the watchlist by the contract address and network id.
+// The network id is the internal database id of the network. It's not related to the chain id.
+func UpdateStarredByContractAndNetworkID(contract string, networkID uint, starred bool) error {
+	var tid uint
+	res := repo.FindTokenIDByContractAndNetworkID(&tid, contract, networkID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrTokenNotFound
+	}
+	return repo.UpdateTokenStarred(tid, starred).Error
+}
+
+// UpdateLastUsedByContractAndNetworkID stores the time the token was last selected by the contract address and network id.
+// The network id is the internal database id of the network. It's not related to the chain id.
+func UpdateLastUsedByContractAndNetworkID(contract string, networkID uint, lastUsedAt time.Time) error {
+	var tid uint
+	res := repo.FindTokenIDByContractAndNetworkID(&tid, contract, networkID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrTokenNotFound
+	}
+	return repo.UpdateTokenLastUsedAt(tid, lastUsedAt).Error
+}
+
+// FetchTokensByNetworkID returns all tokens of the network, including the tokens saved since the network was loaded.
+// The network id is the internal database id of the network. It's not related to the chain id.
+func FetchTokensByNetworkID(networkID uint) ([]*Token, error) {
+	var tokens []*Token
+	if err := repo.FindTokensByNetworkID(&tokens, networkID).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
 // FetchBalance returns the balance of the token by the contract address and network id.
 // The network id is the internal database id of the network. It's not related to the chain id.
 func FetchBalanceByContractAndNetworkID(contract string, networkID uint) (*big.Int, error) {
 	var balance string
-	res := findTokenBalanceByContractAndNetworkID(&balance, contract, networkID)
+	res := repo.FindTokenBalanceByContractAndNetworkID(&balance, contract, networkID)
 	if res.Error != nil {
 		return nil, res.Error
 	}
@@ -207,14 +355,42 @@ func FetchBalanceByContractAndNetworkID(contract string, networkID uint) (*big.I
 // identify the token. If a token with the same contract and network id exists, nothing happens.
 func SaveTokenUniqueByContractAndNetworkID(token *Token) error {
 	var t Token
-	res := findTokenByContractAndNetworkID(&t, token.Contract, token.NetworkID)
+	res := repo.FindTokenByContractAndNetworkID(&t, token.Contract, token.NetworkID)
 	if res.Error != nil {
 		return res.Error
 	}
 	if res.RowsAffected == 0 {
-		if err := saveToken(token).Error; err != nil {
+		if err := repo.SaveToken(token).Error; err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// RecentTokens returns up to limit tokens which were selected before, the most recently used first.
+func RecentTokens(tokens []*Token, limit int) []*Token {
+	var recent []*Token
+	for _, t := range tokens {
+		if t.GetLastUsedAt() != nil {
+			recent = append(recent, t)
+		}
+	}
+	sort.SliceStable(recent, func(i, j int) bool {
+		return recent[i].GetLastUsedAt().After(*recent[j].GetLastUsedAt())
+	})
+	if len(recent) > limit {
+		recent = recent[:limit]
+	}
+	return recent
+}
+
+// StarredTokens returns the tokens on the watchlist.
+func StarredTokens(tokens []*Token) []*Token {
+	var starred []*Token
+	for _, t := range tokens {
+		if t.GetStarred() {
+			starred = append(starred, t)
+		}
+	}
+	return starred
+}
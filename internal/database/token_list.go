@@ -0,0 +1,118 @@
+package database
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/logging"
+
+	"github.com/sahilm/fuzzy"
+	"github.com/sirupsen/logrus"
+)
+
+// tokenImportBatchSize is the number of tokens inserted per statement by ImportTokens.
+const tokenImportBatchSize = 100
+
+// ImportTokens adds the tokens of a token list to the network.
+// Known tokens keep their symbol and decimals, only the missing metadata is filled in.
+// It returns the number of added and updated tokens.
+func ImportTokens(n *Network, tokens []*Token) (added, updated int, err error) {
+	known := make(map[string]*Token, len(n.GetTokens()))
+	for _, t := range n.GetTokens() {
+		known[strings.ToLower(t.GetContract())] = t
+	}
+	var created []*Token
+	err = repo.Transaction(func(r Repository) error {
+		for _, token := range tokens {
+			contract := strings.ToLower(token.Contract)
+			existing, ok := known[contract]
+			if !ok {
+				token.NetworkID = n.GetID()
+				token.Predefined = false
+				token.Connector = false
+				known[contract] = token
+				created = append(created, token)
+				continue
+			}
+			fields := make(map[string]any)
+			if existing.GetName() == "" && token.Name != "" {
+				fields["Name"] = token.Name
+			}
+			if existing.GetLogoURI() == "" && token.LogoURI != "" {
+				fields["LogoURI"] = token.LogoURI
+			}
+			if len(existing.GetTags()) == 0 && token.Tags != "" {
+				fields["Tags"] = token.Tags
+			}
+			if len(fields) == 0 || existing.ID == 0 {
+				continue
+			}
+			if err := r.UpdateToken(existing, fields).Error; err != nil {
+				return err
+			}
+			updated++
+		}
+		if len(created) == 0 {
+			return nil
+		}
+		return r.CreateTokens(created, tokenImportBatchSize).Error
+	})
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"network": n.GetName(),
+			"err":     err,
+		}).Error("failed to import the tokens")
+		return 0, 0, err
+	}
+	n.mu.Lock()
+	n.Tokens = append(n.Tokens, created...)
+	n.mu.Unlock()
+	return len(created), updated, nil
+}
+
+// SearchTokens returns the tokens whose symbol or name fuzzy matches the query (case insensitive).
+// Exact symbol matches come first, followed by the best matches of the symbol or the name.
+func SearchTokens(tokens []*Token, query string) []*Token {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+	scores := make(map[int]int)
+	for _, field := range []func(*Token) string{(*Token).GetSymbol, (*Token).GetName} {
+		for _, m := range fuzzy.FindFrom(query, tokenSource{tokens, field}) {
+			if score, ok := scores[m.Index]; !ok || m.Score > score {
+				scores[m.Index] = m.Score
+			}
+		}
+	}
+	indexes := make([]int, 0, len(scores))
+	for i := range scores {
+		indexes = append(indexes, i)
+	}
+	sort.Slice(indexes, func(a, b int) bool {
+		i, j := indexes[a], indexes[b]
+		exactI, exactJ := strings.EqualFold(tokens[i].GetSymbol(), query), strings.EqualFold(tokens[j].GetSymbol(), query)
+		if exactI != exactJ {
+			return exactI
+		}
+		if scores[i] != scores[j] {
+			return scores[i] > scores[j]
+		}
+		return i < j
+	})
+	found := make([]*Token, len(indexes))
+	for n, i := range indexes {
+		found[n] = tokens[i]
+	}
+	return found
+}
+
+// tokenSource is a fuzzy.Source of one field of the tokens.
+type tokenSource struct {
+	tokens []*Token
+	field  func(*Token) string
+}
+
+func (s tokenSource) String(i int) string { return s.field(s.tokens[i]) }
+
+func (s tokenSource) Len() int { return len(s.tokens) }
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenWatchlist(t *testing.T) {
+	n := &Network{
+		Name:      "deadshot-watchlist-test",
+		ChainID:   1338,
+		Endpoints: Endpoints{NewEndpoint("http://localhost:8545", false)},
+	}
+	if err := CreateNetwork(n); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := DeleteNetwork(n); err != nil {
+			t.Error(err)
+		}
+	}()
+	a := NewToken("0x0000000000000000000000000000000000000005", "AAA", 18, false, nil)
+	if err := CreateToken(n, a); err != nil {
+		t.Fatal(err)
+	}
+	// saved by the token pipe, it's not part of the network tokens
+	b := NewToken("0x0000000000000000000000000000000000000006", "BBB", 18, false, nil)
+	b.SetNetworkID(n.GetID())
+	if err := SaveTokenUniqueByContractAndNetworkID(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := UpdateStarredByContractAndNetworkID(b.GetContract(), n.GetID(), true); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if err := UpdateLastUsedByContractAndNetworkID(a.GetContract(), n.GetID(), now.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateLastUsedByContractAndNetworkID(b.GetContract(), n.GetID(), now); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateStarredByContractAndNetworkID("0x0000000000000000000000000000000000000007", n.GetID(), true); err != ErrTokenNotFound {
+		t.Errorf("expected %v, got %v", ErrTokenNotFound, err)
+	}
+
+	tokens, err := FetchTokensByNetworkID(n.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 2 {
+		t.Fatalf("expected 2 tokens, got %d", len(tokens))
+	}
+	if starred := StarredTokens(tokens); len(starred) != 1 || starred[0].GetSymbol() != "BBB" {
+		t.Errorf("unexpected watchlist %v", starred)
+	}
+	recent := RecentTokens(tokens, 10)
+	if len(recent) != 2 || recent[0].GetSymbol() != "BBB" || recent[1].GetSymbol() != "AAA" {
+		t.Errorf("unexpected recent tokens %v", recent)
+	}
+	if recent = RecentTokens(tokens, 1); len(recent) != 1 {
+		t.Errorf("expected 1 recent token, got %d", len(recent))
+	}
+}
+
+func TestSearchTokens(t *testing.T) {
+	newToken := func(symbol, name string) *Token {
+		token := NewToken("", symbol, 18, false, nil)
+		token.Name = name
+		return token
+	}
+	tokens := []*Token{
+		newToken("WBTC", "Wrapped BTC"),
+		newToken("WETH", "Wrapped Ether"),
+		newToken("USDC", "USD Coin"),
+		newToken("ETH", "Ether"),
+	}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", nil},
+		{"xyz", nil},
+		{"wth", []string{"WETH"}},
+		{"eth", []string{"ETH", "WETH"}},
+		{"usd coin", []string{"USDC"}},
+		{"wrapped", []string{"WBTC", "WETH"}},
+	}
+	for _, tt := range tests {
+		found := SearchTokens(tokens, tt.query)
+		symbols := make([]string, len(found))
+		for i, token := range found {
+			symbols[i] = token.GetSymbol()
+		}
+		if len(symbols) != len(tt.want) {
+			t.Errorf("SearchTokens(%q) = %v, want %v", tt.query, symbols, tt.want)
+			continue
+		}
+		for i := range symbols {
+			if symbols[i] != tt.want[i] {
+				t.Errorf("SearchTokens(%q) = %v, want %v", tt.query, symbols, tt.want)
+				break
+			}
+		}
+	}
+}
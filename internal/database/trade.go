@@ -3,6 +3,9 @@ package database
 import (
 	"math/big"
 	"sync"
+	"time"
+
+	"github.com/jon4hz/deadshot/internal/signer"
 
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
@@ -30,18 +33,34 @@ type Trade struct {
 	NetworkID      uint
 	Dex            *Dex `gorm:"foreignkey:DexID"`
 	DexID          uint
+	// Wallet is the wallet which executes the trade.
+	Wallet   *Wallet `gorm:"foreignkey:WalletID"`
+	WalletID uint
+	// AllDexes quotes the trade across every dex of the network.
+	// Dex is only the fallback if no target specifies a dex.
+	AllDexes bool
+	// ProfileName is the name of the trading profile of the network, empty for the defaults.
+	ProfileName string
+	profile     *Profile `gorm:"-"`
 	// the amount of tokens which have been bought and not sold yet
 	amountInTrade *big.Int `gorm:"-"`
 	// the amount of tokens which have been bought
 	totalBought *big.Int `gorm:"-"`
 	Failed      bool
 	hasStoploss bool `gorm:"-"`
+	// ExpiresAt and ExpiresAtBlock cancel all pending targets of the trade and stop it.
+	ExpiresAt      *time.Time
+	ExpiresAtBlock uint64
+	Expired        bool
+	// KeepUnlocked lets the trade keep running after the wallet was locked.
+	KeepUnlocked bool
+	signer       signer.Signer `gorm:"-"`
 	// mutex
 	mu sync.Mutex `gorm:"-"`
 }
 
 // NewTrade creates a new trade.
-func NewTrade(token0, token1 *Token, buyTargets, sellTargets Targets, tradeType *TradeType, endpoint *Endpoint, network *Network, dex *Dex) *Trade {
+func NewTrade(token0, token1 *Token, buyTargets, sellTargets Targets, tradeType *TradeType, endpoint *Endpoint, network *Network, dex *Dex, wallet *Wallet) *Trade {
 	var hasSL bool
 	for _, t := range sellTargets {
 		if t.GetStopLoss() {
@@ -66,6 +85,8 @@ func NewTrade(token0, token1 *Token, buyTargets, sellTargets Targets, tradeType
 		NetworkID:   network.ID,
 		Dex:         dex,
 		DexID:       dex.ID,
+		Wallet:      wallet,
+		WalletID:    wallet.GetID(),
 		Failed:      false,
 	}
 }
@@ -77,6 +98,141 @@ func (t *Trade) GetDex() *Dex {
 	return t.Dex
 }
 
+// SetDex sets the dex for the trade.
+func (t *Trade) SetDex(dex *Dex) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Dex = dex
+	t.DexID = dex.ID
+}
+
+// GetWallet returns the wallet which executes the trade.
+func (t *Trade) GetWallet() *Wallet {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.Wallet
+}
+
+// SetWallet sets the wallet which executes the trade.
+func (t *Trade) SetWallet(wallet *Wallet) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Wallet = wallet
+	t.WalletID = wallet.GetID()
+}
+
+// GetKeepUnlocked returns whether the trade keeps running after the wallet was locked.
+func (t *Trade) GetKeepUnlocked() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.KeepUnlocked
+}
+
+// SetKeepUnlocked sets whether the trade keeps running after the wallet was locked.
+// Trades which keep running get their own signer, because locking wipes the signer of the wallet.
+func (t *Trade) SetKeepUnlocked(keep bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.KeepUnlocked = keep
+	t.signer = nil
+	if keep && t.Wallet != nil {
+		t.signer = t.Wallet.DetachSigner()
+	}
+}
+
+// GetSigner returns the signer of the trade.
+// It falls back to the signer of the wallet and returns nil if the wallet is locked.
+func (t *Trade) GetSigner() signer.Signer {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.signer != nil {
+		return t.signer
+	}
+	if t.Wallet == nil {
+		return nil
+	}
+	return t.Wallet.GetSigner()
+}
+
+// GetAllDexes returns whether the trade is quoted across all dexes.
+func (t *Trade) GetAllDexes() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.AllDexes
+}
+
+// SetAllDexes sets whether the trade is quoted across all dexes.
+func (t *Trade) SetAllDexes(allDexes bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.AllDexes = allDexes
+}
+
+// GetProfile returns the trading profile or nil if the trade uses the defaults.
+func (t *Trade) GetProfile() *Profile {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.profile == nil && t.ProfileName != "" && t.Network != nil {
+		t.profile = t.Network.GetProfile(t.ProfileName)
+	}
+	return t.profile
+}
+
+// SetProfile sets the trading profile, nil uses the defaults.
+func (t *Trade) SetProfile(p *Profile) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.profile = p
+	t.ProfileName = ""
+	if p != nil {
+		t.ProfileName = p.GetName()
+	}
+}
+
+// GetExpiry returns the expiry of the trade.
+func (t *Trade) GetExpiry() Expiry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return Expiry{At: t.ExpiresAt, Block: t.ExpiresAtBlock}
+}
+
+// SetExpiry sets the expiry of the trade.
+func (t *Trade) SetExpiry(e Expiry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ExpiresAt = e.At
+	t.ExpiresAtBlock = e.Block
+}
+
+// GetExpired returns whether the trade expired.
+func (t *Trade) GetExpired() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.Expired
+}
+
+// SetExpired marks the trade as expired.
+func (t *Trade) SetExpired() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Expired = true
+}
+
+// HasBlockExpiry returns whether the trade or one of its targets expires at a block.
+func (t *Trade) HasBlockExpiry() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.ExpiresAtBlock > 0 {
+		return true
+	}
+	for _, target := range append(append(Targets{}, t.BuyTargets...), t.SellTargets...) {
+		if target.GetExpiry().Block > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // GetNetwork returns the network for the trade.
 func (t *Trade) GetNetwork() *Network {
 	t.mu.Lock()
@@ -147,7 +303,7 @@ func (t *Trade) GetNextBuyTarget() *Target {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 	for _, target := range t.BuyTargets {
-		if !target.Hit {
+		if !target.Hit && !target.Expired {
 			return target
 		}
 	}
@@ -167,7 +323,7 @@ func (t *Trade) GetNextSellTarget() *Target {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 	for _, target := range t.SellTargets {
-		if !target.Hit {
+		if !target.Hit && !target.Expired {
 			return target
 		}
 	}
@@ -267,7 +423,7 @@ func (t *Trade) GetBuyTargetHit() int {
 func SaveTrade(t *Trade) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
-	if err := saveTrade(t).Error; err != nil {
+	if err := repo.SaveTrade(t).Error; err != nil {
 		return err
 	}
 	return nil
@@ -73,7 +73,7 @@ func (t TradeTypes) GetSnipe() *TradeType {
 
 // LoadAllTradeTypes fetches all trade types from the database and sets them as a global variable..
 func LoadAllTradeTypes() error {
-	result := findAllTradeTypes(&DefaultTradeTypes)
+	result := repo.FindAllTradeTypes(&DefaultTradeTypes)
 	if result.Error != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"err": result.Error,
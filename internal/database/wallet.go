@@ -3,10 +3,12 @@ package database
 import (
 	"crypto/ecdsa"
 	"errors"
+	"math/big"
 	"sync"
 	"time"
 
 	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/internal/signer"
 
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -15,16 +17,35 @@ import (
 type Wallet struct {
 	gorm.Model
 	tradeWalletPrivateKey *ecdsa.PrivateKey `gorm:"-"`
+	signer                signer.Signer     `gorm:"-"`
 	FirstUse              bool              `gorm:"-"`
 	Wallet                string
 	WalletIndex           uint
-	Label                 string     // label is either "trade" for wallets used for trading or "unlock" for wallets used to unlock the bot
-	nonce                 uint64     `gorm:"-"`
-	lastNonceUpdate       time.Time  `gorm:"-"`
-	mu                    sync.Mutex `gorm:"-"`
+	// DerivationPath is the template of the derivation path, e.g. m/44'/60'/0'/0/%d.
+	// The index of the wallet replaces the %d, a path without %d is fixed. An empty path means the default path.
+	DerivationPath string
+	Label          string // label is the name of the wallet shown in the wallet picker
+	// Imported is set for wallets with their own private key.
+	// All other wallets are derived from the mnemonic phrase of the main wallet.
+	Imported bool
+	// SignerURL is the endpoint of an external signer (clef) which holds the private key.
+	// Wallets with an external signer don't have a private key.
+	SignerURL       string
+	watchOnly       bool       `gorm:"-"`
+	nonce           uint64     `gorm:"-"`
+	lastNonceUpdate time.Time  `gorm:"-"`
+	mu              sync.Mutex `gorm:"-"`
 }
 
-var ErrWalletNotFound = errors.New("wallet not found")
+// MainWalletID is the id of the wallet which belongs to the main secret.
+const MainWalletID = 1
+
+var (
+	ErrWalletNotFound   = errors.New("wallet not found")
+	ErrWalletExists     = errors.New("wallet already exists")
+	ErrRemoveMainWallet = errors.New("the main wallet can't be removed")
+	ErrWalletInUse      = errors.New("the wallet is used by a trade")
+)
 
 func (w *Wallet) GetWallet() string {
 	w.mu.Lock()
@@ -32,15 +53,20 @@ func (w *Wallet) GetWallet() string {
 	return w.Wallet
 }
 
-func (w *Wallet) NewWallet(walletAddr string, walletIndex uint, label string) error {
+// NewWallet sets the main wallet. The label is only set if the wallet doesn't have one yet.
+func (w *Wallet) NewWallet(walletAddr string, walletIndex uint, derivationPath, label string) error {
+	if l := w.GetLabel(); l != "" {
+		label = l
+	}
 	wallet := &Wallet{
-		Wallet:      walletAddr,
-		WalletIndex: walletIndex,
-		Label:       label,
+		Wallet:         walletAddr,
+		WalletIndex:    walletIndex,
+		DerivationPath: derivationPath,
+		Label:          label,
 	}
-	wallet.ID = 1
+	wallet.ID = MainWalletID
 
-	if err := saveWallet(wallet).Error; err != nil {
+	if err := repo.SaveWallet(wallet).Error; err != nil {
 		logging.Log.WithFields(logrus.Fields{
 			"error": err,
 		}).Error("Error saving wallet")
@@ -49,13 +75,81 @@ func (w *Wallet) NewWallet(walletAddr string, walletIndex uint, label string) er
 
 	w.mu.Lock()
 	defer w.mu.Unlock()
+	w.ID = MainWalletID
 	w.Wallet = walletAddr
 	w.WalletIndex = walletIndex
+	w.DerivationPath = derivationPath
 	w.Label = label
 
 	return nil
 }
 
+// NewWatchOnlyWallet returns a wallet which is only monitored.
+// It isn't stored in the database and can't sign transactions.
+func NewWatchOnlyWallet(address string) *Wallet {
+	return &Wallet{
+		Wallet:    address,
+		Label:     "watch-only",
+		watchOnly: true,
+	}
+}
+
+// IsWatchOnly returns whether the wallet is only monitored.
+func (w *Wallet) IsWatchOnly() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.watchOnly
+}
+
+// GetID returns the id of the wallet.
+func (w *Wallet) GetID() uint {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.ID
+}
+
+// IsMain returns whether the wallet belongs to the main secret.
+func (w *Wallet) IsMain() bool {
+	return w.GetID() == MainWalletID
+}
+
+// GetLabel returns the label of the wallet.
+func (w *Wallet) GetLabel() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.Label
+}
+
+// SetLabel sets the label of the wallet.
+func (w *Wallet) SetLabel(label string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Label = label
+}
+
+// GetImported returns whether the wallet has its own private key.
+func (w *Wallet) GetImported() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.Imported
+}
+
+// String returns the label and the address of the wallet.
+func (w *Wallet) String() string {
+	label, address := w.GetLabel(), w.GetWallet()
+	if label == "" {
+		return address
+	}
+	return label + " (" + address + ")"
+}
+
+// GetDerivationPath returns the template of the derivation path.
+func (w *Wallet) GetDerivationPath() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.DerivationPath
+}
+
 func (w *Wallet) GetWalletIndex() uint {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -75,6 +169,73 @@ func (w *Wallet) GetPrivateKey() *ecdsa.PrivateKey {
 	return w.tradeWalletPrivateKey
 }
 
+// GetSignerURL returns the endpoint of the external signer.
+func (w *Wallet) GetSignerURL() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.SignerURL
+}
+
+// IsExternal returns whether the wallet signs with an external signer.
+func (w *Wallet) IsExternal() bool {
+	return w.GetSignerURL() != ""
+}
+
+// SetSigner sets the external signer of the wallet.
+func (w *Wallet) SetSigner(s signer.Signer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.signer = s
+}
+
+// GetSigner returns the signer of the wallet.
+// Wallets without an external signer sign with their private key.
+// It returns nil if the wallet isn't loaded.
+func (w *Wallet) GetSigner() signer.Signer {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.signer != nil {
+		return w.signer
+	}
+	if w.tradeWalletPrivateKey != nil {
+		return signer.NewKey(w.tradeWalletPrivateKey)
+	}
+	return nil
+}
+
+// DetachSigner returns a signer which keeps working after the wallet was locked.
+// The private key is copied, so it stays in memory until the signer is dropped.
+// It returns nil if the wallet isn't loaded.
+func (w *Wallet) DetachSigner() signer.Signer {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.signer != nil {
+		return w.signer
+	}
+	if w.tradeWalletPrivateKey == nil {
+		return nil
+	}
+	key := *w.tradeWalletPrivateKey
+	key.D = new(big.Int).Set(w.tradeWalletPrivateKey.D)
+	return signer.NewKey(&key)
+}
+
+// Lock wipes the private key from memory and drops the external signer.
+// The wallet has to be loaded again before it can sign.
+func (w *Wallet) Lock() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.tradeWalletPrivateKey != nil {
+		d := w.tradeWalletPrivateKey.D.Bits()
+		for i := range d {
+			d[i] = 0
+		}
+		w.tradeWalletPrivateKey.D.SetInt64(0)
+		w.tradeWalletPrivateKey = nil
+	}
+	w.signer = nil
+}
+
 // SetNonce sets the nonce for the wallet.
 func (w *Wallet) SetNonce(nonce uint64) {
 	w.mu.Lock()
@@ -107,8 +268,62 @@ func (w *Wallet) LastNonceUpdate() time.Time {
 
 func FetchWallet() (*Wallet, error) {
 	var wallet Wallet
-	if result := findWallet(&wallet); result.Error != nil {
+	if result := repo.FindWallet(&wallet); result.Error != nil {
 		return nil, result.Error
 	}
 	return &wallet, nil
 }
+
+// FetchWallets returns all wallets except the main wallet.
+func FetchWallets() ([]*Wallet, error) {
+	var wallets []*Wallet
+	if err := repo.FindWallets(&wallets, MainWalletID).Error; err != nil {
+		return nil, err
+	}
+	return wallets, nil
+}
+
+// AddWallet saves a new wallet. The address must not be used by any other wallet.
+func AddWallet(wallet *Wallet) error {
+	var existing []*Wallet
+	if err := repo.FindWalletsByAddress(&existing, wallet.GetWallet()).Error; err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return ErrWalletExists
+	}
+	wallet.mu.Lock()
+	defer wallet.mu.Unlock()
+	if err := repo.SaveWallet(wallet).Error; err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error": err,
+		}).Error("Error saving wallet")
+		return err
+	}
+	return nil
+}
+
+// SaveWallet saves the wallet in the database.
+func SaveWallet(w *Wallet) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return repo.SaveWallet(w).Error
+}
+
+// RemoveWallet removes the wallet from the database.
+// A wallet which is still referenced by a trade isn't removed to keep the trade history.
+func RemoveWallet(w *Wallet) error {
+	if w.IsMain() {
+		return ErrRemoveMainWallet
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	used, err := usedByTrades(repo, "wallet_id", w.ID)
+	if err != nil {
+		return err
+	}
+	if used {
+		return ErrWalletInUse
+	}
+	return repo.DeleteWallet(w).Error
+}
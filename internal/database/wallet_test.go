@@ -0,0 +1,81 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestWalletLock(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	w := &Wallet{Wallet: address.Hex()}
+	w.SetPrivateKey(key)
+
+	keep, locked := &Trade{Wallet: w}, &Trade{Wallet: w}
+	keep.SetKeepUnlocked(true)
+	locked.SetKeepUnlocked(false)
+
+	w.Lock()
+	if w.GetPrivateKey() != nil || w.GetSigner() != nil {
+		t.Error("expected the locked wallet to have no private key and no signer")
+	}
+	if key.D.Sign() != 0 {
+		t.Error("expected the private key to be wiped")
+	}
+	if locked.GetSigner() != nil {
+		t.Error("expected no signer for a trade which doesn't keep running")
+	}
+	s := keep.GetSigner()
+	if s == nil {
+		t.Fatal("expected a signer for a trade which keeps running")
+	}
+	if s.Address() != address {
+		t.Errorf("expected signer address %s, got %s", address, s.Address())
+	}
+}
+
+func TestRemoveWalletInUse(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the first wallet is the main wallet
+	var main Wallet
+	if err := repo.FindWallet(&main).Error; err != nil {
+		t.Fatal(err)
+	}
+	if main.ID == 0 {
+		main.ID = 1
+		if err := SaveWallet(&main); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w := &Wallet{Wallet: crypto.PubkeyToAddress(key.PublicKey).Hex(), Label: "in use"}
+	if err := AddWallet(w); err != nil {
+		t.Fatal(err)
+	}
+	networks, err := FetchAllNetworks(true)
+	if err != nil || len(networks) == 0 {
+		t.Fatalf("expected the built-in networks: %v", err)
+	}
+	n := networks[0]
+	tokens := n.GetTokens()
+	trade := NewTrade(tokens[0], tokens[1], nil, nil, DefaultTradeTypes.GetMarket(), n.GetEndpoints()[0], n, n.GetDexes()[0], w)
+	if err := repo.SaveTrade(trade).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RemoveWallet(w); err != ErrWalletInUse {
+		t.Errorf("expected %v, got %v", ErrWalletInUse, err)
+	}
+	if err := repo.(*gormRepository).db.Unscoped().Delete(trade).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveWallet(w); err != nil {
+		t.Error(err)
+	}
+}
@@ -34,7 +34,7 @@ func (Pipe) Run(ctx *context.Context) error {
 		return errors.New("No token found")
 	}
 
-	balance, err := ctx.Client.GetBalanceOf(ctx.Config.Wallet.GetWallet(), token.GetContract())
+	balance, err := ctx.Client.GetBalanceOf(ctx.TradeWallet().GetWallet(), token.GetContract())
 	if err != nil {
 		return modules.Error{
 			Message: "Error getting balance",
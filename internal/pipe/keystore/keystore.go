@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/jon4hz/deadshot/internal/context"
+	"github.com/jon4hz/deadshot/internal/database"
 	"github.com/jon4hz/deadshot/internal/wallet"
 )
 
@@ -13,7 +14,7 @@ func (PreCheck) String() string  { return "check keystore" }
 func (PreCheck) Message() string { return "" }
 
 func (PreCheck) Skip(ctx *context.Context) bool {
-	return wallet.IsInitialized()
+	return wallet.IsInitialized() || ctx.WatchOnly()
 }
 
 func (PreCheck) Run(ctx *context.Context) error {
@@ -31,7 +32,7 @@ func (PreCheck) Run(ctx *context.Context) error {
 type Pipe struct{}
 
 func (Pipe) String() string                 { return "keystore" }
-func (Pipe) Skip(ctx *context.Context) bool { return false }
+func (Pipe) Skip(ctx *context.Context) bool { return ctx.WatchOnly() }
 
 func (Pipe) Run(ctx *context.Context) error {
 	if err := wallet.InitKeystore(ctx.Cfg.Password, ctx.Cfg.Keystore); err != nil {
@@ -56,6 +57,30 @@ func (Pipe) Run(ctx *context.Context) error {
 			}
 			return err
 		}
+		wallet.LoadWallets(ctx.Config.Wallets)
+		ctx.SetUnlocked()
 	}
 	return nil
 }
+
+// Unlock loads the private keys again after the wallets were locked.
+type Unlock struct{}
+
+func (Unlock) String() string                 { return "unlock" }
+func (Unlock) Skip(ctx *context.Context) bool { return ctx.Unlocked || ctx.WatchOnly() }
+
+func (Unlock) Run(ctx *context.Context) error {
+	if err := wallet.Unlock(ctx.Cfg.Password, ctx.Config.Wallet, ctx.Config.Wallets); err != nil {
+		return err
+	}
+	ctx.SetUnlocked()
+	return nil
+}
+
+// Lock wipes the private keys of all wallets and forgets the password.
+func Lock(ctx *context.Context) {
+	wallets := append([]*database.Wallet{ctx.Config.Wallet}, ctx.Config.Wallets...)
+	wallet.Lock(wallets...)
+	ctx.Cfg.Password = ""
+	ctx.Unlocked = false
+}
@@ -10,6 +10,9 @@ import (
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules"
 )
 
+// maxHops is the maximum number of hops of the route if the profile has none.
+const maxHops = 5
+
 var (
 	_ modules.Piper         = &Pipe{}
 	_ modules.Canceler      = &Pipe{}
@@ -32,8 +35,18 @@ func (p *Pipe) CancelFunc() ctx.CancelFunc {
 
 func (p Pipe) Run(ctx *context.Context) error {
 	errChan := make(chan error)
+	hops := ctx.Profile.GetMaxHops(maxHops)
 	go func() {
-		_, err := ctx.Client.CheckListed(ctx.Token0, ctx.Token1, ctx.Dex, ctx.Network.GetWETH(), ctx.Network.Connectors())
+		if ctx.AllDexes {
+			quote, err := ctx.Client.CheckListedAllDexes(ctx.Token0, ctx.Token1, ctx.Network.GetDexes(), ctx.Network.GetWETH(), ctx.Network.Connectors(), hops)
+			if err == nil {
+				// fall back to the dex with the best price if a target doesn't specify one
+				ctx.Dex = quote.GetDex()
+			}
+			errChan <- err
+			return
+		}
+		_, err := ctx.Client.CheckListed(ctx.Token0, ctx.Token1, ctx.Dex, ctx.Network.GetWETH(), ctx.Network.Connectors(), hops)
 		errChan <- err
 	}()
 	select {
@@ -2,11 +2,12 @@ package price
 
 import (
 	ctx "context"
+	"time"
 
 	chain "github.com/jon4hz/deadshot/internal/blockchain"
 	"github.com/jon4hz/deadshot/internal/context"
+	"github.com/jon4hz/deadshot/internal/database"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules"
-	"github.com/jon4hz/deadshot/internal/ratelimit"
 )
 
 const priceFeedMaxHops = 3
@@ -30,11 +31,23 @@ func (p *Pipe) CancelFunc() ctx.CancelFunc {
 }
 
 func (p Pipe) Run(ctx *context.Context) error {
+	dexes := []*database.Dex{ctx.Dex}
+	if ctx.AllDexes {
+		dexes = ctx.Network.GetDexes()
+	}
 	ctx.Price = chain.NewPriceWithContext(p.c, p.cancel)
 	ctx.Price.StartFeed(
 		ctx.Client,
 		ctx.Token0, ctx.Token1,
-		ctx.Dex, ctx.Network.GetTokens(),
-		ratelimit.GetPriceFeedInterval(ctx.Endpoint.GetURL()), priceFeedMaxHops, ctx.Network.GetWETH())
+		dexes, ctx.Network.GetTokens(),
+		interval(ctx), ctx.Profile.GetMaxHops(priceFeedMaxHops), ctx.Network.GetWETH())
 	return nil
 }
+
+// interval returns the interval of the profile. The price feed never fetches faster than the rate limit of the endpoint allows.
+func interval(ctx *context.Context) time.Duration {
+	if ctx.Profile == nil {
+		return 0
+	}
+	return ctx.Profile.GetPriceFeedInterval()
+}
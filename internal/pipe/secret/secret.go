@@ -12,14 +12,19 @@ type Pipe struct{}
 
 func (Pipe) String() string                 { return "secret" }
 func (Pipe) Message() string                { return "" }
-func (Pipe) Skip(ctx *context.Context) bool { return false }
+func (Pipe) Skip(ctx *context.Context) bool { return ctx.WatchOnly() }
 
 func (Pipe) Run(ctx *context.Context) error {
 	if ctx.NewSecret != nil {
-		if err := wallet.Set(ctx.NewSecret.Secret, uint(ctx.NewSecret.Index), ctx.Config.Wallet); err != nil {
+		if err := wallet.Set(ctx.NewSecret.Secret, uint(ctx.NewSecret.Index), ctx.NewSecret.DerivationPath, ctx.Config.Wallet); err != nil {
 			return err
 		}
 		ctx.NewSecret = nil
 	}
-	return wallet.Load(ctx.Config.Wallet)
+	if err := wallet.Load(ctx.Config.Wallet); err != nil {
+		return err
+	}
+	wallet.LoadWallets(ctx.Config.Wallets)
+	ctx.SetUnlocked()
+	return nil
 }
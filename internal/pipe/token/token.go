@@ -1,6 +1,8 @@
 package token
 
 import (
+	"time"
+
 	"github.com/jon4hz/deadshot/internal/context"
 	"github.com/jon4hz/deadshot/internal/database"
 	"github.com/jon4hz/deadshot/internal/logging"
@@ -53,6 +55,12 @@ func (Pipe) Run(ctx *context.Context) error {
 	if err != nil {
 		logging.Log.WithField("error", err).Error("Failed to save token")
 	}
+	// remember the token for the recent tokens of the token selection
+	now := time.Now()
+	if err := database.UpdateLastUsedByContractAndNetworkID(token.GetContract(), token.NetworkID, now); err != nil {
+		logging.Log.WithField("error", err).Error("Failed to save the last use of the token")
+	}
+	token.SetLastUsedAt(now)
 	ctx.TokenContract = "" // reset the contract address
 
 	if ctx.Token0 == nil {
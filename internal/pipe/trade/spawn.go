@@ -12,7 +12,9 @@ func (Spawn) String() string { return "Spawn a new trade" }
 func (Spawn) Run(ctx *context.Context) error {
 	trade := database.NewTrade(ctx.Token0, ctx.Token1,
 		ctx.BuyTargets, ctx.SellTargets, ctx.TradeType,
-		ctx.Endpoint, ctx.Network, ctx.Dex)
+		ctx.Endpoint, ctx.Network, ctx.Dex, ctx.TradeWallet())
+	trade.SetAllDexes(ctx.AllDexes)
+	trade.SetProfile(ctx.Profile)
 	ctx.Trade = trade
 	return nil
 }
@@ -0,0 +1,398 @@
+package allowances
+
+import (
+	ctx "context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/context"
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/internal/pipe/tui/modules"
+	"github.com/jon4hz/deadshot/internal/ui/bubbles/keyvalue"
+	"github.com/jon4hz/deadshot/internal/ui/bubbles/simpleview"
+	"github.com/jon4hz/deadshot/internal/ui/style"
+
+	chain "github.com/jon4hz/deadshot/internal/blockchain"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+)
+
+type state int
+
+const (
+	stateUnknown state = iota
+	stateLoading
+	stateReady
+	stateInput
+	stateSending
+)
+
+type loadedMsg []*chain.Allowance
+
+type approvedMsg struct{}
+
+type sentMsg *types.Transaction
+
+var (
+	_ modules.Module          = (*Module)(nil)
+	_ simpleview.SimpleViewer = (*Module)(nil)
+)
+
+// Module lists the allowances of the trade wallet and lets the user revoke or change them.
+type Module struct {
+	ctx     ctx.Context
+	cancel  ctx.CancelFunc
+	D       modules.Default
+	state   state
+	err     error
+	help    help.Model
+	kv      keyvalue.Model
+	spinner spinner.Model
+	table   table.Model
+	input   textinput.Model
+
+	allowances []*chain.Allowance
+	// shown are the allowances in the table.
+	shown []*chain.Allowance
+	all   bool
+	// selected is the allowance which gets changed.
+	selected *chain.Allowance
+	tx       string
+}
+
+func New(module *modules.Default) *Module {
+	input := textinput.New()
+	input.Placeholder = `amount in token units or "unlimited"`
+	input.Prompt = style.GetFocusedPrompt()
+	input.CursorStyle = lipgloss.NewStyle().Foreground(style.GetMainColor())
+	input.CharLimit = 78
+	return &Module{
+		D: modules.Default{
+			PrePipe:     module.PrePipe,
+			Pipe:        module.Pipe,
+			PostPipe:    module.PostPipe,
+			ForkBackMsg: module.ForkBackMsg,
+		},
+		cancel:  func() {},
+		help:    help.New(),
+		kv:      keyvalue.New(),
+		spinner: style.GetSpinnerPoints(),
+		input:   input,
+		table: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "Token", Width: 10},
+				{Title: "Dex", Width: 16},
+				{Title: "Allowance", Width: 24},
+				{Title: "Policy", Width: 10},
+			}),
+			table.WithFocused(true),
+		),
+	}
+}
+
+func (m *Module) Cancel()        { m.cancel() }
+func (m *Module) State() int     { return int(m.state) }
+func (m *Module) String() string { return "allowances module" }
+
+func (m *Module) Init(c *context.Context) tea.Cmd {
+	m.ctx, m.cancel = ctx.WithCancel(c)
+	m.D.Ctx = c
+	m.err = nil
+	m.tx = ""
+	return tea.Batch(
+		m.load(),
+		spinner.Tick,
+	)
+}
+
+// load fetches the allowances of the trade wallet against all routers of the network.
+func (m *Module) load() tea.Cmd {
+	m.state = stateLoading
+	client, owner, network := m.D.Ctx.Client, m.D.Ctx.TradeWallet().GetWallet(), m.D.Ctx.Network
+	return func() tea.Msg {
+		allowances, err := client.GetAllowances(owner, network.GetTokens(), network.GetDexes())
+		if err != nil {
+			return modules.ErrMsg(modules.Error{
+				Message: "Could not load the allowances",
+				Help:    err.Error(),
+			})
+		}
+		return loadedMsg(allowances)
+	}
+}
+
+// approve sends a transaction to set the allowance of the router for the token.
+func (m *Module) approve(a *chain.Allowance, amount *big.Int) tea.Cmd {
+	if m.D.Ctx.WatchOnly() {
+		m.err = modules.ErrWatchOnly
+		return nil
+	}
+	m.state = stateSending
+	m.err = nil
+	m.tx = ""
+	client, w, network := m.D.Ctx.Client, m.D.Ctx.TradeWallet(), m.D.Ctx.Network
+	return func() tea.Msg {
+		tx, err := client.Approve(w, w.GetSigner(), big.NewInt(int64(network.GetChainID())), a.Token, a.Dex.GetRouter(), amount)
+		if err != nil {
+			return modules.ErrMsg(modules.Error{
+				Message: "Could not send the approval",
+				Help:    err.Error(),
+			})
+		}
+		return sentMsg(tx)
+	}
+}
+
+// wait waits until the approval is mined.
+func (m *Module) wait(tx *types.Transaction) tea.Cmd {
+	client := m.D.Ctx.Client
+	return func() tea.Msg {
+		ok, err := client.WaitForTransaction(tx)
+		if err == nil && !ok {
+			err = fmt.Errorf("transaction %s failed", tx.Hash())
+		}
+		if err != nil {
+			return modules.ErrMsg(modules.Error{
+				Message: "The approval failed",
+				Help:    err.Error(),
+			})
+		}
+		return approvedMsg{}
+	}
+}
+
+// togglePolicy switches the approval policy of the token between per trade and unlimited.
+func (m *Module) togglePolicy(token *database.Token) {
+	unlimited := !token.GetUnlimitedApproval()
+	if err := database.UpdateUnlimitedApprovalByContractAndNetworkID(token.GetContract(), m.D.Ctx.Network.GetID(), unlimited); err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"token": token.GetContract(),
+			"error": err,
+		}).Error("failed to save the approval policy")
+		m.err = modules.Error{
+			Message: "Could not save the approval policy",
+			Help:    err.Error(),
+		}
+		return
+	}
+	token.SetUnlimitedApproval(unlimited)
+	m.setRows()
+}
+
+func (m *Module) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch m.state {
+		case stateInput:
+			switch {
+			case key.Matches(msg, inputKeys.Enter):
+				amount, err := chain.ParseAllowance(m.input.Value(), m.selected.Token.GetDecimals())
+				if err != nil {
+					m.err = modules.Error{
+						Message: "Invalid amount",
+						Help:    `Enter an amount in token units or "unlimited".`,
+					}
+					return nil
+				}
+				return m.approve(m.selected, amount)
+			case key.Matches(msg, inputKeys.Back):
+				m.state = stateReady
+				m.err = nil
+				return modules.Resize
+			case key.Matches(msg, inputKeys.Quit):
+				return tea.Quit
+			}
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return cmd
+		}
+
+		switch {
+		case key.Matches(msg, defaultKeys.Back):
+			return m.back()
+		case key.Matches(msg, defaultKeys.Quit):
+			return tea.Quit
+		case key.Matches(msg, defaultKeys.Help):
+			m.help.ShowAll = !m.help.ShowAll
+		}
+		if m.state != stateReady {
+			return nil
+		}
+		switch {
+		case key.Matches(msg, defaultKeys.Refresh):
+			m.err = nil
+			return m.load()
+		case key.Matches(msg, defaultKeys.All):
+			m.all = !m.all
+			m.setRows()
+			return modules.Resize
+		}
+		a := m.selectedAllowance()
+		if a == nil {
+			var cmd tea.Cmd
+			m.table, cmd = m.table.Update(msg)
+			return cmd
+		}
+		switch {
+		case key.Matches(msg, defaultKeys.Set):
+			if m.D.Ctx.WatchOnly() {
+				m.err = modules.ErrWatchOnly
+				return nil
+			}
+			m.selected = a
+			m.state = stateInput
+			m.err = nil
+			m.input.SetValue("")
+			return tea.Batch(m.input.Focus(), modules.Resize)
+		case key.Matches(msg, defaultKeys.Revoke):
+			if a.Amount.Sign() == 0 {
+				return nil
+			}
+			return m.approve(a, big.NewInt(0))
+		case key.Matches(msg, defaultKeys.Policy):
+			m.togglePolicy(a.Token)
+			return nil
+		}
+		var cmd tea.Cmd
+		m.table, cmd = m.table.Update(msg)
+		return cmd
+
+	case loadedMsg:
+		m.state = stateReady
+		m.allowances = msg
+		m.setRows()
+		return modules.Resize
+
+	case sentMsg:
+		tx := (*types.Transaction)(msg)
+		m.tx = tx.Hash().Hex()
+		return m.wait(tx)
+
+	case approvedMsg:
+		return m.load()
+
+	case modules.ErrMsg:
+		m.err = msg
+		if m.state != stateInput {
+			m.state = stateReady
+		}
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *Module) back() tea.Cmd {
+	m.cancel()
+	if m.D.ForkBackMsg != 0 {
+		return func() tea.Msg { return m.D.ForkBackMsg }
+	}
+	return modules.Back
+}
+
+func (m *Module) selectedAllowance() *chain.Allowance {
+	i := m.table.Cursor()
+	if i < 0 || i >= len(m.shown) {
+		return nil
+	}
+	return m.shown[i]
+}
+
+func (m *Module) setRows() {
+	m.shown = m.shown[:0]
+	rows := make([]table.Row, 0, len(m.allowances))
+	for _, a := range m.allowances {
+		if !m.all && a.Amount.Sign() == 0 {
+			continue
+		}
+		m.shown = append(m.shown, a)
+		policy := "per trade"
+		if a.Token.GetUnlimitedApproval() {
+			policy = "unlimited"
+		}
+		rows = append(rows, table.Row{a.Token.GetSymbol(), a.Dex.GetName(), a.AmountString(), policy})
+	}
+	m.table.SetRows(rows)
+	if m.table.Cursor() >= len(rows) {
+		m.table.SetCursor(0)
+	}
+}
+
+func (m *Module) SetHeaderWidth(width int) { m.kv.SetWidth(width) }
+func (m *Module) Header() string {
+	var s strings.Builder
+	s.WriteString(style.GenLogo())
+	s.WriteString("\n\n")
+	s.WriteString(m.kv.View(
+		keyvalue.NewKV("Wallet", m.D.Ctx.TradeWallet().GetWallet()),
+		keyvalue.NewKV("Network", m.D.Ctx.Network.GetName()),
+		keyvalue.NewKV("Endpoint", m.D.Ctx.Endpoint.GetURL()),
+	))
+	return s.String()
+}
+
+func (m *Module) SetContentSize(width, height int) {
+	m.table.SetWidth(width)
+	m.table.SetHeight(height)
+	m.input.Width = width - 1
+}
+
+func (m *Module) MinContentHeight() int {
+	return 8 // TODO: don't hardcode that value
+}
+
+func (m *Module) Content() string {
+	var s strings.Builder
+	switch m.state {
+	case stateLoading:
+		s.WriteString("\n")
+		s.WriteString(m.spinner.View())
+		s.WriteString(" Loading allowances...")
+	case stateSending:
+		s.WriteString("\n")
+		s.WriteString(m.spinner.View())
+		if m.tx == "" {
+			s.WriteString(" Sending approval...")
+		} else {
+			s.WriteString(" Waiting for " + m.tx)
+		}
+	case stateInput:
+		s.WriteString(fmt.Sprintf("Enter the allowance of %s for %s\n\n", m.selected.Dex.GetName(), m.selected.Token.GetSymbol()))
+		s.WriteString(m.input.View())
+	case stateReady:
+		if len(m.shown) == 0 {
+			s.WriteString(style.SubtleStyle.Render("No allowances found, press a to show all tokens"))
+			break
+		}
+		s.WriteString(m.table.View())
+	}
+	return s.String()
+}
+
+func (m *Module) Error() error { return m.err }
+
+func (m *Module) SetFooterWidth(width int) { m.help.Width = width }
+func (m *Module) Footer() string {
+	switch m.state {
+	case stateInput:
+		return m.help.View(inputKeys)
+	}
+	return m.help.View(defaultKeys)
+}
+
+func (m *Module) PrePipe() []modules.Piper  { return m.D.PrePipe }
+func (m *Module) Pipe() []modules.Piper     { return m.D.Pipe }
+func (m *Module) PostPipe() []modules.Piper { return m.D.PostPipe }
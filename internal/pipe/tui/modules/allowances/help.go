@@ -0,0 +1,100 @@
+package allowances
+
+import "github.com/charmbracelet/bubbles/key"
+
+type keyMap struct {
+	Up      key.Binding
+	Down    key.Binding
+	Set     key.Binding
+	Revoke  key.Binding
+	Policy  key.Binding
+	All     key.Binding
+	Refresh key.Binding
+	Back    key.Binding
+	Quit    key.Binding
+	Help    key.Binding
+}
+
+var defaultKeys = keyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "down"),
+	),
+	Set: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "set allowance"),
+	),
+	Revoke: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "revoke"),
+	),
+	Policy: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "toggle unlimited approval"),
+	),
+	All: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "show zero allowances"),
+	),
+	Refresh: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "refresh"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "help"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+}
+
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.Quit}
+}
+
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Set, k.Policy, k.Refresh, k.Help},
+		{k.Down, k.Revoke, k.All, k.Back, k.Quit},
+	}
+}
+
+// inputKeys are the keys of the amount input. They don't contain any letters which could be part of the input.
+var inputKeys = inputKeyMap{
+	Enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "submit"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "cancel"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("ctrl+c"),
+		key.WithHelp("ctrl+c", "quit"),
+	),
+}
+
+type inputKeyMap struct {
+	Enter key.Binding
+	Back  key.Binding
+	Quit  key.Binding
+}
+
+func (k inputKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Enter, k.Back, k.Quit}
+}
+
+func (k inputKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
@@ -151,11 +151,11 @@ func (m *Module) Header() string {
 	switch m.state {
 	case stateBenchmarking:
 		s.WriteString(m.kv.View(
-			keyvalue.NewKV("Wallet", m.D.Ctx.Config.Wallet.GetWallet()),
+			keyvalue.NewKV("Wallet", m.D.Ctx.TradeWallet().GetWallet()),
 		))
 	case stateDone:
 		kvs := []keyvalue.KeyValue{
-			keyvalue.NewKV("Wallet", m.D.Ctx.Config.Wallet.GetWallet()),
+			keyvalue.NewKV("Wallet", m.D.Ctx.TradeWallet().GetWallet()),
 		}
 		if m.D.Ctx.Endpoint != nil {
 			kvs = append(kvs,
@@ -2,6 +2,7 @@ package exchange
 
 import (
 	ctx "context"
+	"fmt"
 	"strings"
 
 	"github.com/jon4hz/deadshot/internal/context"
@@ -22,11 +23,27 @@ const minDexListHeight = 10
 
 type dexListItem struct {
 	dex *database.Dex
+	// all is set for the item which quotes every dex of the network.
+	all   bool
+	count int
 }
 
-func (i dexListItem) Title() string       { return i.dex.GetName() }
-func (i dexListItem) Description() string { return i.dex.GetRouter() }
-func (i dexListItem) FilterValue() string { return i.dex.GetName() }
+func (i dexListItem) Title() string {
+	if i.all {
+		return allDexesTitle
+	}
+	return i.dex.GetName()
+}
+
+func (i dexListItem) Description() string {
+	if i.all {
+		return fmt.Sprintf("Best price across %d dexes", i.count)
+	}
+	return i.dex.GetRouter()
+}
+func (i dexListItem) FilterValue() string { return i.Title() }
+
+const allDexesTitle = "All dexes"
 
 type state int
 
@@ -80,9 +97,13 @@ func (m *Module) Init(c *context.Context) tea.Cmd {
 	m.D.Ctx = c
 	m.err = nil
 
-	items := make([]list.Item, len(c.Network.GetDexes()))
-	for i, dex := range c.Network.GetDexes() {
-		items[i] = dexListItem{dex: dex}
+	dexes := c.Network.GetDexes()
+	items := make([]list.Item, 0, len(dexes)+1)
+	if len(dexes) > 1 {
+		items = append(items, dexListItem{all: true, count: len(dexes)})
+	}
+	for _, dex := range dexes {
+		items = append(items, dexListItem{dex: dex})
 	}
 	m.dexList.SetItems(items)
 	m.dexList.SetShowHelp(false)
@@ -99,6 +120,7 @@ func (m *Module) Update(msg tea.Msg) tea.Cmd {
 		switch {
 		case key.Matches(msg, defaultKeyMap.Back) && !m.dexList.SettingFilter() && m.dexList.FilterState() != list.FilterApplied:
 			m.D.Ctx.Dex = nil
+			m.D.Ctx.AllDexes = false
 			return modules.Back
 
 		case key.Matches(msg, defaultKeyMap.Quit) && !m.dexList.SettingFilter():
@@ -110,7 +132,13 @@ func (m *Module) Update(msg tea.Msg) tea.Cmd {
 
 		case key.Matches(msg, defaultKeyMap.Enter):
 			dex := m.dexList.SelectedItem().(dexListItem)
-			m.D.Ctx.Dex = dex.dex
+			m.D.Ctx.AllDexes = dex.all
+			if dex.all {
+				// the listing check replaces this with the dex with the best price
+				m.D.Ctx.Dex = m.D.Ctx.Network.GetDexes()[0]
+			} else {
+				m.D.Ctx.Dex = dex.dex
+			}
 			return modules.Next
 		}
 	}
@@ -129,7 +157,7 @@ func (m *Module) Header() string {
 	s.WriteString(style.GenLogo())
 	s.WriteString("\n\n")
 	s.WriteString(m.kv.View(
-		keyvalue.NewKV("Wallet", m.D.Ctx.Config.Wallet.GetWallet()),
+		keyvalue.NewKV("Wallet", m.D.Ctx.TradeWallet().GetWallet()),
 		keyvalue.NewKV("Endpoint", m.D.Ctx.Endpoint.GetURL()),
 		keyvalue.NewKV("Tokens", m.D.Ctx.Token0.GetSymbol()+" / "+m.D.Ctx.Token1.GetSymbol()),
 		keyvalue.NewKV("Balance",
@@ -3,12 +3,13 @@ package keyderivation
 import "github.com/charmbracelet/bubbles/key"
 
 type keyMap struct {
-	Up    key.Binding
-	Down  key.Binding
-	Enter key.Binding
-	Back  key.Binding
-	Quit  key.Binding
-	Help  key.Binding
+	Up      key.Binding
+	Down    key.Binding
+	Enter   key.Binding
+	Network key.Binding
+	Back    key.Binding
+	Quit    key.Binding
+	Help    key.Binding
 }
 
 var defaultKeyMap = keyMap{
@@ -24,6 +25,10 @@ var defaultKeyMap = keyMap{
 		key.WithKeys("enter"),
 		key.WithHelp("enter", "confirm"),
 	),
+	Network: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "next network"),
+	),
 	Back: key.NewBinding(
 		key.WithKeys("esc"),
 		key.WithHelp("esc", "back"),
@@ -43,6 +48,20 @@ func (k keyMap) ShortHelp() []key.Binding {
 }
 
 func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Enter, k.Help},
+		{k.Down, k.Network, k.Back, k.Quit},
+	}
+}
+
+// pathKeyMap is the key map of the derivation path selection.
+type pathKeyMap keyMap
+
+func (k pathKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.Quit}
+}
+
+func (k pathKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Enter, k.Help},
 		{k.Down, k.Back, k.Quit},
@@ -3,22 +3,26 @@ package keyderivation
 import (
 	ctx "context"
 	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/jon4hz/deadshot/internal/context"
-	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/internal/database"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules"
 	"github.com/jon4hz/deadshot/internal/ui/bubbles/simpleview"
 	"github.com/jon4hz/deadshot/internal/ui/style"
+	"github.com/jon4hz/deadshot/internal/wallet"
+	"github.com/jon4hz/deadshot/pkg/ethutils"
+
+	chain "github.com/jon4hz/deadshot/internal/blockchain"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	hdwallet "github.com/miguelmota/go-ethereum-hdwallet"
-	"github.com/sirupsen/logrus"
 )
 
 type state int
@@ -26,8 +30,19 @@ type state int
 const (
 	stateUnknown state = iota
 	stateSelect
+	statePath
+	stateCustomPath
 )
 
+// decimal places of the native balances
+const balancePlaces = 4
+
+type balancesMsg struct {
+	network   string
+	addresses []string
+	balances  map[string]*big.Int
+}
+
 var (
 	_ modules.Module          = (*Module)(nil)
 	_ modules.ModulePiper     = (*Module)(nil)
@@ -47,11 +62,25 @@ type Module struct {
 	addressOffset uint
 	cursor        int
 	err           error
+
+	pathCursor int
+	path       string
+	pathInput  textinput.Model
+
+	networkIndex int
+	balances     map[string]*big.Int
+	clients      map[string]*chain.Client
+	clientsMu    sync.Mutex
 }
 
 const addressBatchSize = 10
 
 func NewModule(module *modules.Default) modules.Module {
+	ti := textinput.New()
+	ti.Placeholder = wallet.DefaultDerivationPath
+	ti.Prompt = style.GetFocusedPrompt()
+	ti.CursorStyle = style.GetActiveCursor()
+	ti.CharLimit = 64
 	return &Module{
 		D: modules.Default{
 			PrePipe:     module.PrePipe,
@@ -59,8 +88,10 @@ func NewModule(module *modules.Default) modules.Module {
 			PostPipe:    module.PostPipe,
 			ForkBackMsg: module.ForkBackMsg,
 		},
-		cancel: func() {},
-		help:   help.NewModel(),
+		cancel:    func() {},
+		help:      help.NewModel(),
+		pathInput: ti,
+		clients:   make(map[string]*chain.Client),
 	}
 }
 func (m *Module) Cancel()    { m.cancel() }
@@ -75,20 +106,20 @@ func (m *Module) Skip(ctx *context.Context) bool {
 func (m *Module) Init(c *context.Context) tea.Cmd {
 	m.ctx, m.cancel = ctx.WithCancel(c)
 	m.D.Ctx = c
-	m.state = 1
+	m.state = statePath
 	m.err = nil
 	m.cursor = 0
+	m.pathCursor = 0
+	for i, preset := range wallet.DerivationPresets {
+		if preset.Path == c.Config.Wallet.GetDerivationPath() {
+			m.pathCursor = i
+		}
+	}
 	m.addressOffset = 0
 	m.addresses = nil
-	var cmds []tea.Cmd
-	cmds = append(cmds, func() tea.Msg {
-		if err := m.loadWalletAddresses(0); err != nil {
-			return modules.ErrMsg(err)
-		}
-		return nil
-	})
-	cmds = append(cmds, textinput.Blink)
-	return tea.Batch(cmds...)
+	m.balances = nil
+	m.pathInput.Reset()
+	return textinput.Blink
 }
 
 func (m *Module) Update(msg tea.Msg) tea.Cmd {
@@ -99,6 +130,13 @@ func (m *Module) Update(msg tea.Msg) tea.Cmd {
 			return tea.Quit
 		}
 
+		switch m.state {
+		case statePath:
+			return m.updatePath(msg)
+		case stateCustomPath:
+			return m.updateCustomPath(msg)
+		}
+
 		switch {
 		case key.Matches(msg, defaultKeyMap.Enter):
 			m.err = nil
@@ -110,11 +148,17 @@ func (m *Module) Update(msg tea.Msg) tea.Cmd {
 		case key.Matches(msg, defaultKeyMap.Up):
 			return m.previousAddress()
 
-		case key.Matches(msg, defaultKeyMap.Back):
-			if m.D.ForkBackMsg != 0 {
-				return func() tea.Msg { return m.D.ForkBackMsg }
+		case key.Matches(msg, defaultKeyMap.Network):
+			if networks := m.D.Ctx.Config.Networks; len(networks) > 0 {
+				m.networkIndex = (m.networkIndex + 1) % len(networks)
 			}
-			return modules.Back
+			m.balances = nil
+			return m.loadBalances()
+
+		case key.Matches(msg, defaultKeyMap.Back):
+			m.state = statePath
+			m.err = nil
+			return nil
 
 		case key.Matches(msg, defaultKeyMap.Help):
 			m.help.ShowAll = !m.help.ShowAll
@@ -123,6 +167,12 @@ func (m *Module) Update(msg tea.Msg) tea.Cmd {
 			return tea.Quit
 		}
 
+	case balancesMsg:
+		// ignore outdated balances
+		if network := m.network(); network != nil && msg.network == network.GetName() && sameAddresses(msg.addresses, m.addresses) {
+			m.balances = msg.balances
+		}
+
 	case modules.ErrMsg:
 		m.err = msg
 	}
@@ -130,96 +180,232 @@ func (m *Module) Update(msg tea.Msg) tea.Cmd {
 	return nil
 }
 
-func (m *Module) nextAddress() tea.Cmd {
-	return func() tea.Msg {
-		m.cursor++
-		if m.cursor >= len(m.addresses) {
-			m.cursor--
-			m.addressOffset++
-			if err := m.loadWalletAddresses(m.addressOffset); err != nil {
-				return modules.ErrMsg(err)
+func (m *Module) updatePath(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, defaultKeyMap.Enter):
+		m.err = nil
+		if m.pathCursor == len(wallet.DerivationPresets) {
+			m.state = stateCustomPath
+			return m.pathInput.Focus()
+		}
+		return m.selectPath(wallet.DerivationPresets[m.pathCursor].Path)
+
+	case key.Matches(msg, defaultKeyMap.Down):
+		m.pathCursor = (m.pathCursor + 1) % (len(wallet.DerivationPresets) + 1)
+
+	case key.Matches(msg, defaultKeyMap.Up):
+		m.pathCursor--
+		if m.pathCursor < 0 {
+			m.pathCursor = len(wallet.DerivationPresets)
+		}
+
+	case key.Matches(msg, defaultKeyMap.Back):
+		if m.D.ForkBackMsg != 0 {
+			return func() tea.Msg { return m.D.ForkBackMsg }
+		}
+		return modules.Back
+
+	case key.Matches(msg, defaultKeyMap.Help):
+		m.help.ShowAll = !m.help.ShowAll
+
+	case key.Matches(msg, defaultKeyMap.Quit):
+		return tea.Quit
+	}
+	return nil
+}
+
+func (m *Module) updateCustomPath(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEnter:
+		path := strings.TrimSpace(m.pathInput.Value())
+		if err := wallet.ValidateDerivationPath(path); err != nil {
+			m.err = modules.Error{
+				Message: "Invalid derivation path",
+				Help:    "The path must look like m/44'/60'/0'/0/%d, the %d is replaced by the index. A path without %d is used as is.",
 			}
+			return nil
 		}
+		m.err = nil
+		m.pathInput.Blur()
+		return m.selectPath(path)
+
+	case tea.KeyEsc:
+		m.state = statePath
+		m.err = nil
+		m.pathInput.Blur()
 		return nil
 	}
+	var cmd tea.Cmd
+	m.pathInput, cmd = m.pathInput.Update(msg)
+	return cmd
 }
 
-func (m *Module) previousAddress() tea.Cmd {
-	return func() tea.Msg {
+// selectPath shows the addresses of the derivation path.
+func (m *Module) selectPath(path string) tea.Cmd {
+	m.path = path
+	m.state = stateSelect
+	m.cursor = 0
+	m.addressOffset = 0
+	if err := m.loadWalletAddresses(0); err != nil {
+		return nil
+	}
+	return tea.Batch(m.loadBalances(), modules.Resize)
+}
+
+func (m *Module) nextAddress() tea.Cmd {
+	m.cursor++
+	if m.cursor >= len(m.addresses) {
 		m.cursor--
-		if m.cursor < 0 {
-			m.cursor++
-			if m.addressOffset > 0 {
-				m.addressOffset--
-				if err := m.loadWalletAddresses(m.addressOffset); err != nil {
-					return modules.ErrMsg(err)
-				}
+		// a fixed path has only one address
+		if wallet.IsFixedDerivationPath(m.path) {
+			return nil
+		}
+		m.addressOffset++
+		if err := m.loadWalletAddresses(m.addressOffset); err != nil {
+			return nil
+		}
+		return m.loadBalances()
+	}
+	return nil
+}
+
+func (m *Module) previousAddress() tea.Cmd {
+	m.cursor--
+	if m.cursor < 0 {
+		m.cursor++
+		if m.addressOffset > 0 {
+			m.addressOffset--
+			if err := m.loadWalletAddresses(m.addressOffset); err != nil {
+				return nil
 			}
+			return m.loadBalances()
 		}
-		return nil
 	}
+	return nil
 }
 
 func (m *Module) confirm() tea.Cmd {
 	index := m.cursor + int(m.addressOffset)
 	m.D.Ctx.NewSecret.Index = index
+	m.D.Ctx.NewSecret.DerivationPath = m.path
 	return modules.Next
 }
 
 func (m *Module) loadWalletAddresses(offset uint) error {
-	wallet, err := hdwallet.NewFromMnemonic(m.D.Ctx.NewSecret.Secret)
+	addresses, err := wallet.GetAddrsByPath(m.D.Ctx.NewSecret.Secret, m.path, offset, addressBatchSize)
 	if err != nil {
-		logging.Log.WithFields(logrus.Fields{
-			"error": err,
-		}).Error("error loading wallet")
 		m.err = err
 		return err
 	}
+	m.addresses = addresses
+	return nil
+}
 
-	m.addresses = make([]string, addressBatchSize)
+// network returns the network of the balances.
+func (m *Module) network() *database.Network {
+	networks := m.D.Ctx.Config.Networks
+	if len(networks) == 0 {
+		return nil
+	}
+	return networks[m.networkIndex%len(networks)]
+}
 
-	for i := uint(0) + offset; i < addressBatchSize+offset; i++ {
-		p := fmt.Sprintf("m/44'/60'/0'/0/%d", i)
-		path, err := hdwallet.ParseDerivationPath(p)
+// loadBalances loads the native balances of the shown addresses in the background.
+func (m *Module) loadBalances() tea.Cmd {
+	network := m.network()
+	if network == nil || len(m.addresses) == 0 {
+		return nil
+	}
+	addresses := append([]string(nil), m.addresses...)
+	return func() tea.Msg {
+		client, err := m.client(network)
 		if err != nil {
-			logging.Log.WithFields(logrus.Fields{
-				"error": err,
-				"path":  p,
-			}).Error("error parsing derivation path")
-			m.err = err
-			return err
-		}
-		account, err := wallet.Derive(path, false)
+			return nil
+		}
+		balances, err := client.GetNativeBalances(addresses...)
 		if err != nil {
-			logging.Log.WithFields(logrus.Fields{
-				"error": err,
-				"path":  p,
-			}).Error("error deriving account")
-			m.err = err
-			return err
+			return nil
+		}
+		return balancesMsg{
+			network:   network.GetName(),
+			addresses: addresses,
+			balances:  balances,
 		}
+	}
+}
 
-		m.addresses[i-offset] = account.Address.Hex()
+// client returns a cached client of the network.
+func (m *Module) client(network *database.Network) (*chain.Client, error) {
+	m.clientsMu.Lock()
+	defer m.clientsMu.Unlock()
+	if client, ok := m.clients[network.GetName()]; ok {
+		return client, nil
 	}
-	return nil
+	client, err := chain.Connect(network)
+	if err != nil {
+		return nil, err
+	}
+	m.clients[network.GetName()] = client
+	return client, nil
+}
+
+func sameAddresses(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
 
 func (m *Module) SetHeaderWidth(width int) {}
 func (m *Module) Header() string           { return style.GenLogo() }
 
-func (m *Module) SetContentSize(width, height int) {}
+func (m *Module) SetContentSize(width, height int) {
+	m.pathInput.Width = width - lipgloss.Width(m.pathInput.Prompt)
+}
 
 func (m *Module) Content() string {
 	switch m.state {
+	case statePath, stateCustomPath:
+		return m.pathView()
 	case stateSelect:
 		return m.selectView()
 	}
 	return ""
 }
 
+func (m *Module) pathView() string {
+	s := strings.Builder{}
+	s.WriteString("Please choose the derivation path of your wallet:\n\n")
+	for i, preset := range wallet.DerivationPresets {
+		s.WriteString(m.pathItemView(i, fmt.Sprintf("%s %s", preset.Name, style.SubtleStyle.Render(preset.Path))))
+	}
+	s.WriteString(m.pathItemView(len(wallet.DerivationPresets), "Custom path"))
+	if m.state == stateCustomPath {
+		s.WriteString("\n" + m.pathInput.View())
+	}
+	return s.String()
+}
+
+func (m *Module) pathItemView(index int, text string) string {
+	if index == m.pathCursor {
+		return style.MainStyle.Render(style.GetPrompt()+text) + "\n"
+	}
+	return "  " + text + "\n"
+}
+
 func (m *Module) selectView() string {
 	s := strings.Builder{}
-	s.WriteString("Please choose the wallet you want to use for trading:\n\n")
+	s.WriteString("Please choose the wallet you want to use for trading:\n")
+	s.WriteString(style.SubtleStyle.Render(fmt.Sprintf("path %s", m.path)))
+	if network := m.network(); network != nil {
+		s.WriteString(style.SubtleStyle.Render(fmt.Sprintf(" • balances on %s", network.GetFullName())))
+	}
+	s.WriteString("\n\n")
 	for i := 0; i < len(m.addresses); i++ {
 		if m.cursor == i {
 			if id := int(m.addressOffset) + m.cursor; id < 10 && id >= 0 {
@@ -233,18 +419,36 @@ func (m *Module) selectView() string {
 			s.WriteString("(   ) ")
 		}
 		s.WriteString(m.addresses[i])
+		s.WriteString("  " + m.balanceView(m.addresses[i]))
 		s.WriteString("\n")
 	}
 	return s.String()
 }
 
+func (m *Module) balanceView(address string) string {
+	network := m.network()
+	if network == nil {
+		return ""
+	}
+	balance, ok := m.balances[address]
+	if !ok {
+		return style.SubtleStyle.Render("...")
+	}
+	return style.SubtleStyle.Render(fmt.Sprintf("%s %s", ethutils.ToDecimal(balance, 18).StringFixed(balancePlaces), network.GetNativeCurrency()))
+}
+
 func (m *Module) MinContentHeight() int {
 	return lipgloss.Height(m.Content())
 }
 
-func (m *Module) Error() error              { return m.err }
-func (m *Module) SetFooterWidth(width int)  { m.help.Width = width }
-func (m *Module) Footer() string            { return m.help.View(defaultKeyMap) }
+func (m *Module) Error() error             { return m.err }
+func (m *Module) SetFooterWidth(width int) { m.help.Width = width }
+func (m *Module) Footer() string {
+	if m.state == stateSelect {
+		return m.help.View(defaultKeyMap)
+	}
+	return m.help.View(pathKeyMap(defaultKeyMap))
+}
 func (m *Module) PrePipe() []modules.Piper  { return m.D.PrePipe }
 func (m *Module) Pipe() []modules.Piper     { return m.D.Pipe }
 func (m *Module) PostPipe() []modules.Piper { return m.D.PostPipe }
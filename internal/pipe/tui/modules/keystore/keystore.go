@@ -35,7 +35,9 @@ type Module struct {
 	cancel ctx.CancelFunc
 	D      modules.Default
 
-	state   state
+	state state
+	// unlock asks for the password again after the wallets were locked.
+	unlock  bool
 	input   textinput.Model
 	help    help.Model
 	passwd  string
@@ -68,11 +70,22 @@ func NewModule(module *modules.Default) modules.Module {
 	}
 }
 
+// NewUnlockModule returns a keystore module which asks for the password
+// only if the wallets were locked after the lock timeout.
+func NewUnlockModule(module *modules.Default) modules.Module {
+	m := NewModule(module).(*Module)
+	m.unlock = true
+	return m
+}
+
 func (m *Module) Cancel()        { m.cancel() }
 func (m *Module) String() string { return "keystore module" }
 func (m *Module) State() int     { return int(m.state) }
 
 func (m *Module) Skip(ctx *context.Context) bool {
+	if ctx.WatchOnly() || m.unlock && ctx.Unlocked {
+		return true
+	}
 	return ctx.Cfg.Keystore != "file"
 }
 
@@ -163,6 +176,9 @@ func (m *Module) Content() string {
 	var s strings.Builder
 	switch m.state {
 	case stateInput:
+		if m.unlock {
+			s.WriteString("The wallet was locked after " + m.D.Ctx.Cfg.LockTimeout.String() + " of inactivity\n")
+		}
 		s.WriteString("Please enter your password\n\n")
 	case stateConfirm:
 		s.WriteString("Please confirm your password\n\n")
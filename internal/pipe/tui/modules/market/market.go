@@ -16,8 +16,10 @@ import (
 	"github.com/jon4hz/deadshot/internal/logstream"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules"
 	"github.com/jon4hz/deadshot/internal/ui/bubbles/panel"
+	uiPassword "github.com/jon4hz/deadshot/internal/ui/bubbles/password"
 	"github.com/jon4hz/deadshot/internal/ui/common"
 	"github.com/jon4hz/deadshot/internal/ui/style"
+	"github.com/jon4hz/deadshot/internal/wallet"
 	"github.com/jon4hz/deadshot/pkg/ethutils"
 	"github.com/jon4hz/deadshot/pkg/uniswap"
 
@@ -30,11 +32,15 @@ import (
 )
 
 type (
-	tradeInfoMsg     struct{ t *uniswap.Trade }
+	tradeInfoMsg struct {
+		t     *uniswap.Trade
+		quote *chain.DexQuote
+		split *chain.SplitQuote
+	}
 	errTradeInfo     struct{ err error }
 	errSlippageInput struct{}
 	slippageMsg      struct{ slippage float64 }
-	swapMsg          struct{ tx *types.Transaction }
+	swapMsg          struct{ txs []*types.Transaction }
 	errSwap          struct{ err error }
 )
 
@@ -50,6 +56,7 @@ const (
 	stateUnknown state = iota
 	stateReady
 	stateLoadingData
+	stateUnlock
 )
 
 type menuChoice int
@@ -62,6 +69,7 @@ const (
 	swapChoice
 	slippageChoice
 	invertChoice
+	splitChoice
 )
 
 var menuChoices = []menuChoice{
@@ -71,6 +79,7 @@ var menuChoices = []menuChoice{
 	amount1Choice,
 	swapChoice,
 	invertChoice,
+	splitChoice,
 	slippageChoice,
 }
 
@@ -81,6 +90,8 @@ var (
 
 type tradeInfoResult struct {
 	trade *uniswap.Trade
+	quote *chain.DexQuote
+	split *chain.SplitQuote
 	err   error
 }
 
@@ -99,6 +110,7 @@ type Module struct {
 	secondaryPanel *panel.Model
 	logPanel       *panel.Model
 	spinner        spinner.Model
+	password       *uiPassword.Model
 
 	token0Input   textinput.Model
 	token1Input   textinput.Model
@@ -108,8 +120,11 @@ type Module struct {
 	menuIndex     int
 	menuChoice    menuChoice
 	valChanged    bool
+	split         bool
 
 	tradeInfo   *uniswap.Trade
+	tradeQuote  *chain.DexQuote
+	tradeSplit  *chain.SplitQuote
 	tradeInfoC  chan tradeInfoResult
 	tradeCtx    ctx.Context
 	tradeCancel ctx.CancelFunc
@@ -208,13 +223,16 @@ func (m *Module) Init(c *context.Context) tea.Cmd {
 	m.amount1Input.CursorStyle = style.GetActiveCursor()
 
 	m.slippageInput.Placeholder = "1%"
+	if c.Profile != nil && c.Profile.GetSlippage() > 0 {
+		m.slippageInput.Placeholder = fmt.Sprintf("%g%%", c.Profile.GetSlippage()/100)
+	}
 	m.slippageInput.Prompt = ""
 	m.slippageInput.CursorStyle = style.GetActiveCursor()
 
 	// create a pseudo-target to prevent a nil pointer panic
 	m.D.Ctx.Trade.SetBuyTargets(
 		[]*database.Target{
-			database.NewTargetWithDefaults(),
+			database.NewTargetWithProfile(c.Profile),
 		},
 	)
 
@@ -229,6 +247,13 @@ func (m *Module) Update(msg tea.Msg) tea.Cmd {
 			return tea.Quit
 		}
 		switch m.state {
+		case stateUnlock:
+			cmd = m.password.Update(msg)
+			if m.password.Done {
+				m.state = stateReady
+			}
+			return cmd
+
 		case stateReady, stateLoadingData:
 			switch msg.String() {
 			// Prev menu item
@@ -299,6 +324,8 @@ func (m *Module) Update(msg tea.Msg) tea.Cmd {
 					m.amount0Input, m.amount1Input = m.amount1Input, m.amount0Input
 					m.token0Input, m.token1Input = m.token1Input, m.token0Input
 					m.tradeInfo = new(uniswap.Trade)
+					m.tradeQuote = nil
+					m.tradeSplit = nil
 					if m.D.Ctx.Trade.GetBuyTargets()[0].GetActualAmount() == nil && m.D.Ctx.Trade.GetBuyTargets()[0].GetAmountMinMax() == nil {
 						return nil
 					}
@@ -306,8 +333,22 @@ func (m *Module) Update(msg tea.Msg) tea.Cmd {
 					cmd = m.listenForTradeInfo(m.tradeInfoC)
 					return tea.Batch(cmd, spinner.Tick)
 
+				case splitChoice:
+					m.split = !m.split
+					m.tradeSplit = nil
+					if m.D.Ctx.Trade.GetBuyTargets()[0].GetActualAmount() == nil && m.D.Ctx.Trade.GetBuyTargets()[0].GetAmountMinMax() == nil {
+						return nil
+					}
+
 				case swapChoice:
+					if m.D.Ctx.WatchOnly() {
+						m.err = modules.ErrWatchOnly
+						return nil
+					}
 					if m.D.Ctx.Trade.GetBuyTargets()[0].MarketSwapPossible(m.tradeInfo, m.D.Ctx.Trade.GetToken0()) {
+						if !m.D.Ctx.Unlocked {
+							return m.unlock()
+						}
 						return m.triggerSwap()
 					}
 				}
@@ -359,6 +400,8 @@ func (m *Module) Update(msg tea.Msg) tea.Cmd {
 	case tradeInfoMsg:
 		m.state = stateReady
 		m.tradeInfo = msg.t
+		m.tradeQuote = msg.quote
+		m.tradeSplit = msg.split
 		m.err = nil
 
 		info := m.tradeInfo
@@ -376,7 +419,11 @@ func (m *Module) Update(msg tea.Msg) tea.Cmd {
 		switch m.D.Ctx.Trade.GetBuyTargets()[0].GetAmountMode().GetName() {
 		case database.DefaultAmountModes.GetAmountIn().GetName():
 			m.amount1Input.Reset()
-			m.amount1Input.SetValue(info.OutputAmount().ToSignificant(6))
+			if m.splitting() {
+				m.amount1Input.SetValue(m.tradeSplit.Trade.OutputAmount().ToSignificant(6))
+			} else {
+				m.amount1Input.SetValue(info.OutputAmount().ToSignificant(6))
+			}
 
 		case database.DefaultAmountModes.GetAmountOut().GetName():
 			m.amount0Input.Reset()
@@ -390,6 +437,8 @@ func (m *Module) Update(msg tea.Msg) tea.Cmd {
 	case errTradeInfo:
 		m.state = stateReady
 		m.tradeInfo = nil
+		m.tradeQuote = nil
+		m.tradeSplit = nil
 		m.err = msg.err
 		switch err := m.err.Error(); err {
 		case ErrInvalidToken0.Error():
@@ -415,9 +464,25 @@ func (m *Module) Update(msg tea.Msg) tea.Cmd {
 	case errSwap:
 		m.err = msg.err
 
+	case uiPassword.PasswordMsg:
+		return modules.Unlock(m.D.Ctx, string(msg))
+
+	case modules.UnlockedMsg:
+		msg.SetUnlocked(m.D.Ctx)
+		m.state = stateReady
+		return m.triggerSwap()
+
+	case modules.UnlockErrMsg:
+		m.state = stateReady
+		m.err = msg.Readable()
+
 	case swapMsg:
-		if msg.tx != nil {
-			m.err = fmt.Errorf("%s", msg.tx.Hash().String())
+		hashes := make([]string, 0, len(msg.txs))
+		for _, tx := range msg.txs {
+			hashes = append(hashes, tx.Hash().String())
+		}
+		if len(hashes) > 0 {
+			m.err = fmt.Errorf("%s", strings.Join(hashes, "\n"))
 		}
 
 	default:
@@ -443,15 +508,20 @@ func (m *Module) getNewTradeInfo() {
 	m.tradeCancel()
 	m.tradeCtx, m.tradeCancel = ctx.WithCancel(m.ctx)
 
+	dexes := []*database.Dex{m.D.Ctx.Trade.GetDex()}
+	if m.D.Ctx.Trade.GetAllDexes() {
+		dexes = m.D.Ctx.Trade.GetNetwork().GetDexes()
+	}
+
 	go m.getTradeInfo(
 		m.D.Ctx.Trade.GetToken0(), m.D.Ctx.Trade.GetToken1(),
 		m.D.Ctx.Trade.GetBuyTargets()[0].GetAmountMode(),
-		m.D.Ctx.Trade.GetDex(), m.D.Ctx.Trade.GetNetwork().Connectors(),
+		dexes, m.D.Ctx.Trade.GetNetwork().Connectors(), m.split,
 		m.D.Ctx.Client, m.tradeInfoC, m.tradeCtx)
 	m.state = stateLoadingData
 }
 
-func (m *Module) getTradeInfo(token0, token1 *database.Token, amountMode *database.AmountMode, dex *database.Dex, tokens []*database.Token, client *chain.Client, infoC chan<- tradeInfoResult, ctx ctx.Context) {
+func (m *Module) getTradeInfo(token0, token1 *database.Token, amountMode *database.AmountMode, dexes []*database.Dex, tokens []*database.Token, split bool, client *chain.Client, infoC chan<- tradeInfoResult, ctx ctx.Context) {
 	type newToken struct {
 		token0 string
 		token1 string
@@ -505,7 +575,7 @@ func (m *Module) getTradeInfo(token0, token1 *database.Token, amountMode *databa
 		}
 		t0, ok := newTokens[newT.token0]
 		if ok {
-			bal, err := client.GetBalanceOf(m.D.Ctx.Config.Wallet.GetWallet(), t0.GetContract())
+			bal, err := client.GetBalanceOf(m.D.Ctx.TradeWallet().GetWallet(), t0.GetContract())
 			if err != nil {
 				go func() {
 					select {
@@ -533,7 +603,7 @@ func (m *Module) getTradeInfo(token0, token1 *database.Token, amountMode *databa
 		}
 		t1, ok := newTokens[newT.token1]
 		if ok {
-			bal, err := client.GetBalanceOf(m.D.Ctx.Config.Wallet.GetWallet(), t1.GetContract())
+			bal, err := client.GetBalanceOf(m.D.Ctx.TradeWallet().GetWallet(), t1.GetContract())
 			if err != nil {
 				logging.Log.WithField("err", err).Error("Error saving token to database")
 				go func() {
@@ -590,9 +660,24 @@ func (m *Module) getTradeInfo(token0, token1 *database.Token, amountMode *databa
 			return
 		}
 		go func() {
-			t, err := client.GetBestTradeExactIn(token0, token1, amount, dex, tokens, 5, m.D.Ctx.Trade.GetNetwork().GetWETH())
+			var (
+				quote *chain.DexQuote
+				err   error
+			)
+			if len(dexes) > 1 {
+				quote, err = client.GetBestTradeExactInAllDexes(token0, token1, amount, dexes, tokens, 5, m.D.Ctx.Trade.GetNetwork().GetWETH())
+			} else {
+				var trade *uniswap.Trade
+				trade, err = client.GetBestTradeExactIn(token0, token1, amount, dexes[0], tokens, 5, m.D.Ctx.Trade.GetNetwork().GetWETH())
+				quote = chain.SingleDexQuote(dexes[0], trade)
+			}
+			var splitQuote *chain.SplitQuote
+			if split && err == nil {
+				// fall back to the single route if the amount can't be split
+				splitQuote, _ = client.GetBestSplitTradeExactIn(token0, token1, amount, dexes, tokens, 5, m.D.Ctx.Trade.GetNetwork().GetWETH())
+			}
 			select {
-			case tradeC <- tradeInfoResult{t, err}:
+			case tradeC <- tradeInfoResult{trade: quote.GetTrade(), quote: quote, split: splitQuote, err: err}:
 			case <-ctx.Done():
 				return
 			}
@@ -615,9 +700,19 @@ func (m *Module) getTradeInfo(token0, token1 *database.Token, amountMode *databa
 			return
 		}
 		go func() {
-			t, err := client.GetBestTradeExactOut(token0, token1, amount, dex, tokens, 5, m.D.Ctx.Trade.GetNetwork().GetWETH())
+			var (
+				quote *chain.DexQuote
+				err   error
+			)
+			if len(dexes) > 1 {
+				quote, err = client.GetBestTradeExactOutAllDexes(token0, token1, amount, dexes, tokens, 5, m.D.Ctx.Trade.GetNetwork().GetWETH())
+			} else {
+				var trade *uniswap.Trade
+				trade, err = client.GetBestTradeExactOut(token0, token1, amount, dexes[0], tokens, 5, m.D.Ctx.Trade.GetNetwork().GetWETH())
+				quote = chain.SingleDexQuote(dexes[0], trade)
+			}
 			select {
-			case tradeC <- tradeInfoResult{t, err}:
+			case tradeC <- tradeInfoResult{trade: quote.GetTrade(), quote: quote, err: err}:
 			case <-ctx.Done():
 				return
 			}
@@ -666,8 +761,10 @@ func (m *Module) listenForTradeInfo(c <-chan tradeInfoResult) tea.Cmd {
 		}
 
 		m.setTradeInfoToWorkflow(info.trade)
+		// swap on the dex the route was found on
+		m.D.Ctx.Trade.GetBuyTargets()[0].SetDex(info.quote.GetDex())
 
-		return tradeInfoMsg{info.trade}
+		return tradeInfoMsg{info.trade, info.quote, info.split}
 	}
 }
 
@@ -686,6 +783,7 @@ func (m *Module) setTradeInfoToWorkflow(info *uniswap.Trade) {
 		m.D.Ctx.Trade.GetBuyTargets()[0].SetAmountMinMax(max.Raw().String())
 	}
 	m.D.Ctx.Trade.GetBuyTargets()[0].SetPath(info.Route.GetAddresses())
+	m.D.Ctx.Trade.GetBuyTargets()[0].SetStables(info.Route.GetStables())
 }
 
 // containsOnly returns wether the given string contains only the given character set.
@@ -729,16 +827,43 @@ func setSlippage(slippageInput string) tea.Cmd {
 	}
 }
 
+// unlock asks for the password before the swap if the wallets were locked.
+// Keystores without a password are unlocked right away.
+func (m *Module) unlock() tea.Cmd {
+	if !wallet.RequirePassword() {
+		return modules.Unlock(m.D.Ctx, "")
+	}
+	m.state = stateUnlock
+	m.password = uiPassword.NewModel(false)
+	return m.password.Init()
+}
+
 func (m *Module) triggerSwap() tea.Cmd {
 	return func() tea.Msg {
-		tx, err := m.D.Ctx.Client.Swap(m.D.Ctx.Config.Wallet, m.D.Ctx.Trade, m.D.Ctx.Trade.GetBuyTargets()[0])
+		if err := chain.CheckHalted(); err != nil {
+			return errSwap{err}
+		}
+		if m.splitting() {
+			txs, err := m.D.Ctx.Client.SwapSplit(m.D.Ctx.Trade.GetWallet(), m.D.Ctx.Trade, m.D.Ctx.Trade.GetBuyTargets()[0], m.tradeSplit)
+			if err != nil {
+				return errSwap{fmt.Errorf("sent %d of %d split swaps: %w", len(txs), len(m.tradeSplit.Legs), err)}
+			}
+			return swapMsg{txs}
+		}
+		tx, err := m.D.Ctx.Client.Swap(m.D.Ctx.Trade.GetWallet(), m.D.Ctx.Trade, m.D.Ctx.Trade.GetBuyTargets()[0])
 		if err != nil {
 			return errSwap{err}
 		}
-		return swapMsg{tx}
+		return swapMsg{[]*types.Transaction{tx}}
 	}
 }
 
+// splitting returns whether the current trade is executed as a split trade.
+func (m *Module) splitting() bool {
+	return m.split && m.tradeSplit != nil && len(m.tradeSplit.Legs) > 1 &&
+		m.D.Ctx.Trade.GetBuyTargets()[0].GetAmountMode().GetName() == database.DefaultAmountModes.GetAmountIn().GetName()
+}
+
 func (m *Module) SetContentSize(width, height int) {
 	if width%2 == 1 {
 		width--
@@ -757,6 +882,8 @@ func (m *Module) SetContentSize(width, height int) {
 
 func (m *Module) Content() string {
 	switch m.state {
+	case stateUnlock:
+		return "The wallet was locked after " + m.D.Ctx.Cfg.LockTimeout.String() + " of inactivity\n\n" + m.password.View()
 	case stateReady, stateLoadingData:
 		m.setInfoView()
 		m.setReadyView()
@@ -792,9 +919,9 @@ func (m *Module) infoView() string {
 	var s strings.Builder
 	s.WriteString(style.GenLogo() + "\n\n")
 	s.WriteString(common.KeyValueView(
-		"Wallet", m.D.Ctx.Config.Wallet.GetWallet(),
+		"Wallet", m.D.Ctx.TradeWallet().GetWallet(),
 		"Web3 Provider", m.D.Ctx.Trade.GetEndpoint().GetURL(),
-		"Dex", m.D.Ctx.Trade.GetDex().GetName(),
+		"Dex", chain.ExchangeName(m.tradeQuote.DexOr(m.D.Ctx.Trade.GetDex()), m.D.Ctx.Trade.GetAllDexes()),
 		"Trading Pair", fmt.Sprintf("%s / %s", m.D.Ctx.Trade.GetToken0().GetSymbol(), m.D.Ctx.Trade.GetToken1().GetSymbol()),
 		"Balance", fmt.Sprintf("%s / %s", m.D.Ctx.Trade.GetToken0().GetBalanceDecimal(m.D.Ctx.Trade.GetToken0().GetDecimals()).String(), m.D.Ctx.Trade.GetToken1().GetBalanceDecimal(m.D.Ctx.Trade.GetToken1().GetDecimals()).String()),
 	))
@@ -831,7 +958,11 @@ func (m *Module) secondaryView() string {
 	}
 	s.WriteString("\n")
 
-	priceImpact := chain.GetActualPriceImpact(info, m.D.Ctx.Trade.GetDex().GetFeeBigInt())
+	dex := m.D.Ctx.Trade.GetDex()
+	if d := m.tradeQuote.GetDex(); d != nil {
+		dex = d
+	}
+	priceImpact := chain.GetActualPriceImpact(info, dex.GetFeeBigInt())
 	if priceImpact < 0.01 {
 		s.WriteString("Price Impact: < 0.01%\n")
 	} else {
@@ -847,6 +978,35 @@ func (m *Module) secondaryView() string {
 		s.WriteString(v.Symbol() + "\n")
 	}
 
+	if m.D.Ctx.Trade.GetAllDexes() {
+		s.WriteString("Best Dex: " + dex.GetName() + "\n")
+		if improvement, ok := m.tradeQuote.Improvement(); ok {
+			s.WriteString(fmt.Sprintf("vs %s: +%s%%\n", m.tradeQuote.Next.Dex.GetName(), improvement.StringFixed(2)))
+		} else {
+			s.WriteString("No route on any other dex\n")
+		}
+	}
+
+	if m.splitting() {
+		split := m.tradeSplit.Trade
+		s.WriteString("Split:\n")
+		for _, leg := range m.tradeSplit.Legs {
+			s.WriteString(fmt.Sprintf("  %s%% %s: ", split.Percent(leg.Split).ToFixed(0), leg.Dex.GetName()))
+			for i, v := range leg.Split.Trade.Route.Path {
+				if i > 0 {
+					s.WriteString(" -> ")
+				}
+				s.WriteString(v.Symbol())
+			}
+			s.WriteString("\n")
+		}
+		single := info.OutputAmount().Raw()
+		if single.Sign() > 0 {
+			gain := new(big.Int).Sub(split.OutputAmount().Raw(), single)
+			s.WriteString(fmt.Sprintf("vs single route: +%s%%\n", uniswap.NewPercent(gain, single).ToFixed(2)))
+		}
+	}
+
 	return s.String()
 }
 
@@ -918,7 +1078,9 @@ func (m *Module) readyView() string {
 	if m.menuChoice == swapChoice {
 		p = focusedPrompt
 	}
-	if m.D.Ctx.Trade.GetBuyTargets()[0].MarketSwapPossible(m.tradeInfo, m.D.Ctx.Trade.GetToken0()) && m.state != stateLoadingData {
+	if m.D.Ctx.WatchOnly() {
+		s.WriteString(p + common.Subtle("Swap (watch-only)\n"))
+	} else if m.D.Ctx.Trade.GetBuyTargets()[0].MarketSwapPossible(m.tradeInfo, m.D.Ctx.Trade.GetToken0()) && m.state != stateLoadingData {
 		s.WriteString(p + "Swap\n")
 	} else {
 		s.WriteString(p + common.Subtle("Swap\n"))
@@ -930,6 +1092,16 @@ func (m *Module) readyView() string {
 	}
 	s.WriteString(p + "Invert\n")
 
+	p = prompt
+	if m.menuChoice == splitChoice {
+		p = focusedPrompt
+	}
+	if m.split {
+		s.WriteString(p + "Split: on\n")
+	} else {
+		s.WriteString(p + "Split: off\n")
+	}
+
 	p = prompt
 	m.slippageInput.Blur()
 	if m.menuChoice == slippageChoice {
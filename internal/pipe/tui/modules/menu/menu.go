@@ -27,15 +27,17 @@ type menuChoice int
 
 const (
 	tradeChoice menuChoice = iota
+	portfolioChoice
 	settingsChoice
 	quitChoice
 	unsetChoice
 )
 
 var menuChoices = map[menuChoice]string{
-	tradeChoice:    "Trade",
-	settingsChoice: "Settings",
-	quitChoice:     "Quit",
+	tradeChoice:     "Trade",
+	portfolioChoice: "Portfolio",
+	settingsChoice:  "Settings",
+	quitChoice:      "Quit",
 }
 
 var (
@@ -91,6 +93,8 @@ func (m *Module) Fork() modules.ForkMsg {
 	switch menuChoice(m.menuIndex) {
 	case tradeChoice:
 		return modules.ForkMsgTrade
+	case portfolioChoice:
+		return modules.ForkMsgPortfolio
 	case settingsChoice:
 		return modules.ForkMsgSettings
 	case quitChoice:
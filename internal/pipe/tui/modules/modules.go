@@ -33,7 +33,11 @@ const (
 	ForkMsgWalletSettings
 	ForkMsgWalletSettingsNew
 	ForkMsgWalletSettingsDerivation
+	ForkMsgWalletSettingsWallets
 	ForkMsgCustomEndpoint
+	ForkMsgPortfolio
+	ForkMsgAllowances
+	ForkMsgNetworks
 )
 
 type (
@@ -45,6 +49,8 @@ type (
 	ErrMsg            error
 	ResizeMsg         struct{}
 	PipeCancelFuncMsg ctx.CancelFunc
+	// LockedMsg is sent to the current module after the wallets were locked.
+	LockedMsg struct{}
 )
 
 type Error struct {
@@ -62,6 +68,12 @@ func (e Error) Render(width int) string {
 	return lipgloss.NewStyle().Width(width).Render(head + body)
 }
 
+// ErrWatchOnly is shown for every action which would send a transaction in watch-only mode.
+var ErrWatchOnly = Error{
+	Message: "Transactions are disabled in watch-only mode",
+	Help:    "Restart deadshot without --watch to trade",
+}
+
 type Piper interface {
 	fmt.Stringer
 
@@ -108,5 +120,8 @@ var Next = func() tea.Msg { return NextMsg{} }
 // Back loads the previous module in the pipeline.
 var Back = func() tea.Msg { return BackMsg{} }
 
+// Locked notifies the current module that the wallets were locked.
+var Locked = func() tea.Msg { return LockedMsg{} }
+
 // Resize can be used, to manually trigger a resize of the tui components.
 var Resize = func() tea.Msg { return ResizeMsg{} }
@@ -10,13 +10,17 @@ import (
 	chain "github.com/jon4hz/deadshot/internal/blockchain"
 	"github.com/jon4hz/deadshot/internal/context"
 	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logstream"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules"
 	"github.com/jon4hz/deadshot/internal/ui/bubbles/panel"
+	uiPassword "github.com/jon4hz/deadshot/internal/ui/bubbles/password"
 	"github.com/jon4hz/deadshot/internal/ui/bubbles/simpleview"
 	"github.com/jon4hz/deadshot/internal/ui/common"
 	"github.com/jon4hz/deadshot/internal/ui/style"
+	"github.com/jon4hz/deadshot/internal/wallet"
 	"github.com/jon4hz/deadshot/pkg/uniswap"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -33,6 +37,9 @@ type state int
 const (
 	stateUnknown state = iota
 	stateReady
+	stateUnlock
+	stateExpiry
+	stateKeepUnlocked
 )
 
 var (
@@ -49,6 +56,12 @@ type Module struct {
 
 	width int
 
+	password  *uiPassword.Model
+	unlockErr error
+
+	expiryInput textinput.Model
+	expiryErr   error
+
 	logs              string
 	logChan           chan string
 	tradeDispatchCtx  ctx.Context
@@ -100,6 +113,11 @@ func New(module *modules.Default) *Module {
 		style.GetActiveColor(), style.GetInactiveColor(),
 	)
 
+	ei := textinput.New()
+	ei.Prompt = ""
+	ei.CursorStyle = lipgloss.NewStyle().Foreground(style.GetMainColor())
+	ei.Placeholder = "never"
+
 	tdctx, cancel := ctx.WithCancel(ctx.Background())
 	return &Module{
 		D: modules.Default{
@@ -116,6 +134,7 @@ func New(module *modules.Default) *Module {
 		buyTradePanel:     buyTradePanel,
 		sellTradePanel:    sellTradePanel,
 		logPanel:          logPanel,
+		expiryInput:       ei,
 		logChan:           make(chan string),
 		tradeDispatchCtx:  tdctx,
 		tradeDispatchDone: cancel,
@@ -128,12 +147,62 @@ func (m *Module) String() string { return "order module" }
 
 func (m *Module) Init(c *context.Context) tea.Cmd {
 	m.ctx, m.cancel = ctx.WithCancel(c)
-	m.state = 1
 	m.D.Ctx = c
 
+	if !c.Unlocked {
+		return m.unlock()
+	}
+	return m.askExpiry()
+}
+
+// unlock asks for the password before the order is armed if the wallets were locked.
+// Keystores without a password are unlocked right away.
+func (m *Module) unlock() tea.Cmd {
+	m.state = stateUnlock
+	m.unlockErr = nil
+	m.password = uiPassword.NewModel(false)
+	if !wallet.RequirePassword() {
+		return modules.Unlock(m.D.Ctx, "")
+	}
+	return m.password.Init()
+}
+
+// askExpiry asks when the order is cancelled if it isn't filled yet.
+func (m *Module) askExpiry() tea.Cmd {
+	m.state = stateExpiry
+	m.expiryErr = nil
+	return tea.Batch(m.expiryInput.Focus(), modules.Resize)
+}
+
+// setExpiry sets the expiry of the trade and continues with the order.
+func (m *Module) setExpiry() tea.Cmd {
+	expiry, err := database.ParseExpiry(m.expiryInput.Value(), time.Now())
+	if err != nil {
+		m.expiryErr = err
+		return nil
+	}
+	m.D.Ctx.Trade.SetExpiry(expiry)
+	m.expiryInput.Blur()
+	return m.confirmKeepUnlocked()
+}
+
+// confirmKeepUnlocked asks whether the order keeps running after the wallet was locked.
+// Without a lock timeout the order is armed right away.
+func (m *Module) confirmKeepUnlocked() tea.Cmd {
+	if m.D.Ctx.Cfg.LockTimeout <= 0 {
+		return m.arm()
+	}
+	m.state = stateKeepUnlocked
+	return modules.Resize
+}
+
+// arm starts the trade dispatcher.
+func (m *Module) arm() tea.Cmd {
+	m.state = stateReady
+
 	// TODO: move that to a pipe
 	go m.D.Ctx.Client.TradeDispatcher(m.tradeDispatchCtx, m.tradeDispatchDone,
-		m.D.Ctx.Config.Wallet, m.D.Ctx.Trade, m.D.Ctx.Price, m.logChan,
+		m.D.Ctx.Trade.GetWallet(), m.D.Ctx.Trade, m.D.Ctx.Price, m.D.Ctx.Cfg.GetGuards().WithProfile(m.D.Ctx.Trade.GetProfile()), m.logChan,
 	)
 
 	return tea.Batch(
@@ -152,6 +221,52 @@ func (m *Module) Update(msg tea.Msg) tea.Cmd {
 			m.tradeDispatchDone()
 			return tea.Quit
 		}
+		switch m.state {
+		case stateUnlock:
+			cmd := m.password.Update(msg)
+			if m.password.Done {
+				return modules.Back
+			}
+			return cmd
+		case stateExpiry:
+			switch msg.String() {
+			case "enter":
+				return m.setExpiry()
+			case "esc":
+				return modules.Back
+			}
+			var cmd tea.Cmd
+			m.expiryInput, cmd = m.expiryInput.Update(msg)
+			return cmd
+		case stateKeepUnlocked:
+			switch msg.String() {
+			case "y":
+				m.D.Ctx.Trade.SetKeepUnlocked(true)
+				return m.arm()
+			case "n", "enter":
+				m.D.Ctx.Trade.SetKeepUnlocked(false)
+				return m.arm()
+			case "esc":
+				return modules.Back
+			}
+		}
+	case uiPassword.PasswordMsg:
+		return modules.Unlock(m.D.Ctx, string(msg))
+	case modules.UnlockedMsg:
+		msg.SetUnlocked(m.D.Ctx)
+		return m.askExpiry()
+	case modules.UnlockErrMsg:
+		m.unlockErr = msg.Readable()
+	case modules.LockedMsg:
+		switch m.state {
+		case stateExpiry, stateKeepUnlocked:
+			return m.unlock()
+		case stateReady:
+			if !m.D.Ctx.Trade.GetKeepUnlocked() {
+				m.logs += logstream.Format("the wallet was locked, stopping the order", logstream.WARN)
+				m.tradeDispatchDone()
+			}
+		}
 	case tickMsg:
 		return tickCmd()
 	case logMsg:
@@ -189,6 +304,26 @@ func (m *Module) SetContentSize(width, height int) {
 
 func (m *Module) Content() string {
 	switch m.state {
+	case stateUnlock:
+		if !wallet.RequirePassword() && m.unlockErr == nil {
+			return "Unlocking the wallet..."
+		}
+		s := "The wallet was locked after " + m.D.Ctx.Cfg.LockTimeout.String() + " of inactivity\n\n" + m.password.View()
+		if m.unlockErr != nil {
+			s += "\n" + style.ErrStyle.Render(m.unlockErr.Error())
+		}
+		return s
+	case stateExpiry:
+		s := "Cancel the order if it isn't filled by: " + m.expiryInput.View() + "\n\n" +
+			common.Subtle("Enter a duration like 2h, a time like 2022-06-01 18:00 or a block like #15000000.\nLeave it empty to keep the order until it's filled.")
+		if m.expiryErr != nil {
+			s += "\n\n" + style.ErrStyle.Render(m.expiryErr.Error())
+		}
+		return s
+	case stateKeepUnlocked:
+		return fmt.Sprintf("The wallet gets locked after %s of inactivity.\n\n", m.D.Ctx.Cfg.LockTimeout) +
+			"Keep this order running while the wallet is locked? (y/N)\n\n" +
+			common.Subtle("If you allow it, the private key stays in memory until the order stops.")
 	case stateReady:
 		m.infoPanel.SetContent(m.infoView())
 		m.helpPanel.SetContent(m.helpView())
@@ -226,11 +361,12 @@ func (m *Module) Content() string {
 
 func (m Module) infoView() string {
 	s := common.KeyValueViewWithoutVerticalLine(
-		"Wallet", m.D.Ctx.Config.Wallet.GetWallet(),
+		"Wallet", m.D.Ctx.TradeWallet().GetWallet(),
 		"Endpoint", m.D.Ctx.Trade.GetEndpoint().GetURL(),
-		"Exchange", m.D.Ctx.Trade.GetDex().GetName(),
+		"Exchange", m.exchangeName(),
 		"Tokens", m.D.Ctx.Trade.GetToken0().GetSymbol()+" / "+m.D.Ctx.Trade.GetToken1().GetSymbol(),
 		"Balance", m.D.Ctx.Trade.GetToken0().GetBalanceDecimal(m.D.Ctx.Trade.GetToken0().GetDecimals()).String()+" / "+m.D.Ctx.Trade.GetToken1().GetBalanceDecimal(m.D.Ctx.Trade.GetToken1().GetDecimals()).String(),
+		"Expires", m.D.Ctx.Trade.GetExpiry().String(),
 	) + "\n"
 	// m.infoPanel.SetHeight(strings.Count(s, "\n") + 2)
 	return s
@@ -256,7 +392,8 @@ func (m Module) buyTradeView() string {
 	min, _ := buyTrade.MinimumAmountOut(uniswap.NewPercent(big.NewInt(int64(slippage)), big.NewInt(10000)))
 	s.WriteString("Minimum received: " + min.ToSignificant(6) + " ")
 	s.WriteString(m.D.Ctx.Trade.GetToken1().GetSymbol() + "\n")
-	priceImpact := chain.GetActualPriceImpact(buyTrade, m.D.Ctx.Trade.GetDex().GetFeeBigInt())
+	buyQuote := m.D.Ctx.Price.GetBuyQuote()
+	priceImpact := chain.GetActualPriceImpact(buyTrade, buyQuote.DexOr(m.D.Ctx.Trade.GetDex()).GetFeeBigInt())
 	if priceImpact < 0.01 {
 		s.WriteString("Price Impact: < 0.01%\n")
 	} else {
@@ -270,6 +407,7 @@ func (m Module) buyTradeView() string {
 		}
 		s.WriteString(v.Symbol() + "\n")
 	}
+	s.WriteString(m.dexView(buyQuote))
 	if m.D.Ctx.Price.GetError() != nil {
 		s.WriteString("Warning: failed to reload the price...")
 	}
@@ -297,7 +435,8 @@ func (m Module) sellTradeView() string {
 	min, _ := sellTrade.MinimumAmountOut(uniswap.NewPercent(big.NewInt(int64(slippage)), big.NewInt(10000)))
 	s.WriteString("Minimum received: " + min.ToSignificant(6) + " ")
 	s.WriteString(m.D.Ctx.Trade.GetToken0().GetSymbol() + "\n")
-	priceImpact := chain.GetActualPriceImpact(sellTrade, m.D.Ctx.Trade.GetDex().GetFeeBigInt())
+	sellQuote := m.D.Ctx.Price.GetSellQuote()
+	priceImpact := chain.GetActualPriceImpact(sellTrade, sellQuote.DexOr(m.D.Ctx.Trade.GetDex()).GetFeeBigInt())
 	if priceImpact < 0.01 {
 		s.WriteString("Price Impact: < 0.01%\n")
 	} else {
@@ -311,12 +450,34 @@ func (m Module) sellTradeView() string {
 		}
 		s.WriteString(v.Symbol() + "\n")
 	}
+	s.WriteString(m.dexView(sellQuote))
 	if m.D.Ctx.Price.GetError() != nil {
 		s.WriteString("Warning: failed to reload the price...")
 	}
 	return s.String()
 }
 
+// exchangeName returns the name of the dex of the trade.
+// The best dex of all dexes is shown per quote, the buy and the sell quote may differ.
+func (m Module) exchangeName() string {
+	if m.D.Ctx.Trade.GetAllDexes() {
+		return chain.ExchangeName(nil, true)
+	}
+	return chain.ExchangeName(m.D.Ctx.Trade.GetDex(), false)
+}
+
+// dexView shows the dex of the quote and how it compares to the runner-up.
+func (m Module) dexView(q *chain.DexQuote) string {
+	if !m.D.Ctx.Trade.GetAllDexes() {
+		return ""
+	}
+	s := "Dex: " + q.DexOr(m.D.Ctx.Trade.GetDex()).GetName()
+	if improvement, ok := q.Improvement(); ok {
+		s += fmt.Sprintf(" (+%s%% vs %s)", improvement.StringFixed(2), q.Next.Dex.GetName())
+	}
+	return s + "\n"
+}
+
 func (m Module) helpView() string {
 	return ""
 }
@@ -328,6 +489,8 @@ func (m Module) buyTargetView() string {
 		var sign string
 		if v.GetFailed() {
 			sign = "❌"
+		} else if v.GetExpired() {
+			sign = "⌛"
 		} else if v.GetConfirmed() {
 			sign = "✅"
 		} else if v.GetHit() {
@@ -336,6 +499,7 @@ func (m Module) buyTargetView() string {
 			sign = "📝"
 		}
 		s.WriteString(fmt.Sprintf("%s %s - %s %s\n", sign, v.ViewPrice(), v.ViewAmount(), m.D.Ctx.Trade.GetToken0().GetSymbol()))
+		s.WriteString(targetTimesView(v))
 	}
 	if s.String() == "" {
 		s.WriteString("No buy targets")
@@ -350,6 +514,8 @@ func (m Module) sellTargetView() string {
 		var sign string
 		if v.GetFailed() {
 			sign = "❌"
+		} else if v.GetExpired() {
+			sign = "⌛"
 		} else if v.GetConfirmed() {
 			sign = "✅"
 		} else if v.GetHit() {
@@ -358,6 +524,7 @@ func (m Module) sellTargetView() string {
 			sign = "📝"
 		}
 		s.WriteString(fmt.Sprintf("%s %s - %s %s\n", sign, v.ViewPrice(), v.ViewAmount(), m.D.Ctx.Trade.GetToken0().GetSymbol()))
+		s.WriteString(targetTimesView(v))
 	}
 	if s.String() == "" {
 		s.WriteString("No sell targets")
@@ -374,3 +541,18 @@ func (m Module) Wrap() bool { return false }
 func (m *Module) PrePipe() []modules.Piper  { return m.D.PrePipe }
 func (m *Module) Pipe() []modules.Piper     { return m.D.Pipe }
 func (m *Module) PostPipe() []modules.Piper { return m.D.PostPipe }
+
+// targetTimesView shows the deadline of the sent transaction and the expiry of a pending target.
+func targetTimesView(t *database.Target) string {
+	var s []string
+	if deadline := t.GetDeadlineAt(); deadline != nil && t.GetHit() && !t.GetConfirmed() && !t.GetFailed() {
+		s = append(s, "deadline "+deadline.Local().Format("15:04:05"))
+	}
+	if expiry := t.GetExpiry(); t.GetPending() && !expiry.IsZero() {
+		s = append(s, "expires "+expiry.String())
+	}
+	if len(s) == 0 {
+		return ""
+	}
+	return common.Subtle("   "+strings.Join(s, ", ")) + "\n"
+}
@@ -0,0 +1,60 @@
+package portfolio
+
+import "github.com/charmbracelet/bubbles/key"
+
+type keyMap struct {
+	Up      key.Binding
+	Down    key.Binding
+	Refresh key.Binding
+	Quote   key.Binding
+	Wallet  key.Binding
+	Back    key.Binding
+	Quit    key.Binding
+	Help    key.Binding
+}
+
+var defaultKeys = keyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "down"),
+	),
+	Refresh: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "refresh"),
+	),
+	Quote: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "change quote token"),
+	),
+	Wallet: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "next wallet"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "help"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+}
+
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.Quit}
+}
+
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Refresh, k.Quote, k.Help},
+		{k.Down, k.Wallet, k.Back, k.Quit},
+	}
+}
@@ -0,0 +1,239 @@
+package portfolio
+
+import (
+	ctx "context"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/context"
+	"github.com/jon4hz/deadshot/internal/pipe/tui/modules"
+	"github.com/jon4hz/deadshot/internal/portfolio"
+	"github.com/jon4hz/deadshot/internal/ui/bubbles/keyvalue"
+	"github.com/jon4hz/deadshot/internal/ui/bubbles/simpleview"
+	"github.com/jon4hz/deadshot/internal/ui/style"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// decimal places of the balances in the table
+const tablePlaces = 6
+
+type state int
+
+const (
+	stateUnknown state = iota
+	stateLoading
+	stateReady
+)
+
+type loadedMsg *portfolio.Portfolio
+
+var (
+	_ modules.Module          = (*Module)(nil)
+	_ simpleview.SimpleViewer = (*Module)(nil)
+)
+
+// Module shows the balances of a wallet across all networks.
+type Module struct {
+	ctx     ctx.Context
+	cancel  ctx.CancelFunc
+	D       modules.Default
+	state   state
+	err     error
+	help    help.Model
+	kv      keyvalue.Model
+	spinner spinner.Model
+	table   table.Model
+
+	portfolio *portfolio.Portfolio
+	quote     int
+	wallet    int
+}
+
+func New(module *modules.Default) *Module {
+	return &Module{
+		D: modules.Default{
+			PrePipe:     module.PrePipe,
+			Pipe:        module.Pipe,
+			PostPipe:    module.PostPipe,
+			ForkBackMsg: module.ForkBackMsg,
+		},
+		cancel:  func() {},
+		help:    help.New(),
+		kv:      keyvalue.New(),
+		spinner: style.GetSpinnerPoints(),
+		table: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "Network", Width: 12},
+				{Title: "Token", Width: 10},
+				{Title: "Balance", Width: 22},
+				{Title: "Value", Width: 16},
+			}),
+			table.WithFocused(true),
+		),
+	}
+}
+
+func (m *Module) Cancel()        { m.cancel() }
+func (m *Module) State() int     { return int(m.state) }
+func (m *Module) String() string { return "portfolio module" }
+
+func (m *Module) Init(c *context.Context) tea.Cmd {
+	m.D.Ctx = c
+	m.err = nil
+	return tea.Batch(
+		m.load(),
+		spinner.Tick,
+	)
+}
+
+// load fetches the portfolio of the selected wallet.
+func (m *Module) load() tea.Cmd {
+	m.cancel()
+	m.ctx, m.cancel = ctx.WithCancel(m.D.Ctx)
+	m.state = stateLoading
+	c, networks, address, quote := m.ctx, m.D.Ctx.Config.Networks, m.address(), portfolio.Quotes[m.quote]
+	return func() tea.Msg {
+		return loadedMsg(portfolio.Load(c, networks, address, quote))
+	}
+}
+
+func (m *Module) address() string {
+	wallets := m.D.Ctx.Config.AllWallets()
+	if m.wallet >= len(wallets) {
+		m.wallet = 0
+	}
+	return wallets[m.wallet].GetWallet()
+}
+
+func (m *Module) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, defaultKeys.Back):
+			return m.back()
+		case key.Matches(msg, defaultKeys.Quit):
+			return tea.Quit
+		case key.Matches(msg, defaultKeys.Help):
+			m.help.ShowAll = !m.help.ShowAll
+		}
+		if m.state != stateReady {
+			return nil
+		}
+		switch {
+		case key.Matches(msg, defaultKeys.Refresh):
+			return m.load()
+		case key.Matches(msg, defaultKeys.Quote):
+			m.quote = (m.quote + 1) % len(portfolio.Quotes)
+			return m.load()
+		case key.Matches(msg, defaultKeys.Wallet):
+			m.wallet = (m.wallet + 1) % len(m.D.Ctx.Config.AllWallets())
+			return m.load()
+		}
+		var cmd tea.Cmd
+		m.table, cmd = m.table.Update(msg)
+		return cmd
+
+	case loadedMsg:
+		m.state = stateReady
+		m.portfolio = msg
+		m.table.SetRows(rows(msg))
+		m.table.GotoTop()
+		return modules.Resize
+
+	case modules.ErrMsg:
+		m.err = msg
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *Module) back() tea.Cmd {
+	m.cancel()
+	if m.D.ForkBackMsg != 0 {
+		return func() tea.Msg { return m.D.ForkBackMsg }
+	}
+	return modules.Back
+}
+
+func rows(p *portfolio.Portfolio) []table.Row {
+	rows := make([]table.Row, 0)
+	for _, n := range p.Networks {
+		if n.Error != "" {
+			rows = append(rows, table.Row{n.Network, "", "", "error: " + n.Error})
+			continue
+		}
+		for _, h := range n.Holdings {
+			value := "-"
+			if h.Valued {
+				value = h.Value.StringFixed(2)
+			}
+			rows = append(rows, table.Row{n.Network, h.Symbol, h.Balance.StringFixed(tablePlaces), value})
+		}
+		rows = append(rows, table.Row{n.Network, "Total", "", n.Total.StringFixed(2)})
+	}
+	return rows
+}
+
+func (m *Module) SetHeaderWidth(width int) { m.kv.SetWidth(width) }
+func (m *Module) Header() string {
+	var s strings.Builder
+	s.WriteString(style.GenLogo())
+	s.WriteString("\n\n")
+	total, updated := "-", "-"
+	if m.portfolio != nil && m.state == stateReady {
+		total = m.portfolio.Total.StringFixed(2) + " " + m.portfolio.Quote
+		updated = m.portfolio.Time.Format("15:04:05")
+	}
+	s.WriteString(m.kv.View(
+		keyvalue.NewKV("Wallet", m.address()),
+		keyvalue.NewKV("Quote", portfolio.Quotes[m.quote]),
+		keyvalue.NewKV("Total", total),
+		keyvalue.NewKV("Updated", updated),
+	))
+	return s.String()
+}
+
+func (m *Module) SetContentSize(width, height int) {
+	m.table.SetWidth(width)
+	m.table.SetHeight(height)
+}
+
+func (m *Module) MinContentHeight() int {
+	return 8 // TODO: don't hardcode that value
+}
+
+func (m *Module) Content() string {
+	var s strings.Builder
+	switch m.state {
+	case stateLoading:
+		s.WriteString("\n")
+		s.WriteString(m.spinner.View())
+		s.WriteString(" Loading balances...")
+	case stateReady:
+		if len(m.portfolio.Networks) == 0 {
+			s.WriteString(style.SubtleStyle.Render("No tokens with a balance found"))
+			break
+		}
+		s.WriteString(m.table.View())
+	}
+	return s.String()
+}
+
+func (m *Module) Error() error { return m.err }
+
+func (m *Module) SetFooterWidth(width int) { m.help.Width = width }
+func (m *Module) Footer() string {
+	return m.help.View(defaultKeys)
+}
+
+func (m *Module) PrePipe() []modules.Piper  { return m.D.PrePipe }
+func (m *Module) Pipe() []modules.Piper     { return m.D.Pipe }
+func (m *Module) PostPipe() []modules.Piper { return m.D.PostPipe }
@@ -0,0 +1,194 @@
+package profile
+
+import (
+	ctx "context"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/context"
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/pipe/tui/modules"
+	"github.com/jon4hz/deadshot/internal/ui/bubbles/keyvalue"
+	"github.com/jon4hz/deadshot/internal/ui/bubbles/simpleview"
+	"github.com/jon4hz/deadshot/internal/ui/style"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const minProfileListHeight = 10
+
+type profileListItem struct {
+	// profile is nil for the defaults.
+	profile *database.Profile
+}
+
+func (i profileListItem) Title() string {
+	if i.profile == nil {
+		return "default"
+	}
+	if i.profile.GetPredefined() {
+		return i.profile.GetName() + " (built-in)"
+	}
+	return i.profile.GetName()
+}
+
+func (i profileListItem) Description() string {
+	if i.profile == nil {
+		return "Default settings, every target can override them"
+	}
+	return i.profile.Summary()
+}
+func (i profileListItem) FilterValue() string { return i.Title() }
+
+type state int
+
+const (
+	stateUnknown state = iota
+	stateReady
+)
+
+var (
+	_ modules.Module          = (*Module)(nil)
+	_ modules.ModulePiper     = (*Module)(nil)
+	_ simpleview.SimpleViewer = (*Module)(nil)
+)
+
+type Module struct {
+	ctx    ctx.Context
+	cancel ctx.CancelFunc
+	D      modules.Default
+	state  state
+	err    error
+	help   help.Model
+	kv     keyvalue.Model
+
+	profileList list.Model
+}
+
+func NewModule(module *modules.Default) *Module {
+	del := list.NewDefaultDelegate()
+	del.Styles.SelectedDesc.Foreground(style.GetMainColor()).BorderForeground(style.GetSecondColor())
+	del.Styles.SelectedTitle.Foreground(style.GetMainColor()).BorderForeground(style.GetSecondColor())
+	return &Module{
+		D: modules.Default{
+			PrePipe:  module.PrePipe,
+			Pipe:     module.Pipe,
+			PostPipe: module.PostPipe,
+		},
+		cancel:      func() {},
+		help:        help.New(),
+		kv:          keyvalue.New(),
+		profileList: list.New(nil, del, 0, 0),
+	}
+}
+
+func (m *Module) Cancel()        { m.cancel() }
+func (m *Module) State() int     { return int(m.state) }
+func (m *Module) String() string { return "profile module" }
+
+func (m *Module) Init(c *context.Context) tea.Cmd {
+	m.ctx, m.cancel = ctx.WithCancel(c)
+	m.state = 1
+	m.D.Ctx = c
+	m.err = nil
+
+	profiles := c.Network.GetProfiles()
+	items := make([]list.Item, 0, len(profiles)+1)
+	items = append(items, profileListItem{})
+	for _, p := range profiles {
+		items = append(items, profileListItem{p})
+	}
+	m.profileList.SetItems(items)
+	m.profileList.SetShowHelp(false)
+	m.profileList.Title = "Please select the trading profile"
+	m.profileList.Styles.Title = style.GetListTitleStyle()
+	m.profileList.Styles.FilterCursor.Foreground(style.GetMainColor())
+
+	return modules.Resize
+}
+
+func (m *Module) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, defaultKeyMap.Back) && !m.profileList.SettingFilter() && m.profileList.FilterState() != list.FilterApplied:
+			m.D.Ctx.Profile = nil
+			return modules.Back
+
+		case key.Matches(msg, defaultKeyMap.Quit) && !m.profileList.SettingFilter():
+			return tea.Quit
+
+		case key.Matches(msg, defaultKeyMap.Help):
+			m.help.ShowAll = !m.help.ShowAll
+			return modules.Resize
+
+		case key.Matches(msg, defaultKeyMap.Enter) && !m.profileList.SettingFilter():
+			item, ok := m.profileList.SelectedItem().(profileListItem)
+			if !ok {
+				return nil
+			}
+			m.D.Ctx.Profile = item.profile
+			return modules.Next
+		}
+	}
+	switch m.state {
+	case stateReady:
+		var cmd tea.Cmd
+		m.profileList, cmd = m.profileList.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *Module) SetHeaderWidth(width int) { m.kv.SetWidth(width) }
+func (m *Module) Header() string {
+	var s strings.Builder
+	s.WriteString(style.GenLogo())
+	s.WriteString("\n\n")
+	s.WriteString(m.kv.View(
+		keyvalue.NewKV("Wallet", m.D.Ctx.TradeWallet().GetWallet()),
+		keyvalue.NewKV("Endpoint", m.D.Ctx.Endpoint.GetURL()),
+		keyvalue.NewKV("Tokens", m.D.Ctx.Token0.GetSymbol()+" / "+m.D.Ctx.Token1.GetSymbol()),
+		keyvalue.NewKV("Network", m.D.Ctx.Network.GetName()),
+	))
+	return s.String()
+}
+
+func (m *Module) SetContentSize(width, height int) {
+	m.profileList.SetSize(width, height)
+}
+
+func (m *Module) MinContentHeight() int {
+	return minProfileListHeight
+}
+
+func (m *Module) Content() string {
+	var s strings.Builder
+	switch m.state {
+	case stateReady:
+		s.WriteString(m.profileList.View())
+	}
+	return s.String()
+}
+func (m *Module) Error() error { return m.err }
+
+func (m *Module) SetFooterWidth(width int) { m.help.Width = width }
+func (m *Module) Footer() string {
+	switch m.state {
+	case stateReady:
+		if m.profileList.FilterState() == list.Filtering {
+			return m.help.View(filteringKeys)
+		}
+		if m.profileList.FilterState() == list.FilterApplied {
+			return m.help.View(filteredKeys())
+		}
+		return m.help.View(defaultKeyMap)
+	}
+	return ""
+}
+
+func (m *Module) PrePipe() []modules.Piper  { return m.D.PrePipe }
+func (m *Module) Pipe() []modules.Piper     { return m.D.Pipe }
+func (m *Module) PostPipe() []modules.Piper { return m.D.PostPipe }
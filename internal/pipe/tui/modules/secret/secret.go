@@ -30,6 +30,7 @@ type (
 	errInvalidInput        struct{}
 	errGenerateMnemonicMsg struct{}
 	showMnemonicMsg        struct{}
+	keyFileMsg             struct{}
 )
 
 type state int
@@ -39,6 +40,7 @@ const (
 	stateInput
 	stateShowNewMnemonic
 	stateConfirmNewMnemonic
+	stateKeyFilePassword
 )
 
 var menuChoices = []string{
@@ -66,6 +68,8 @@ type Module struct {
 	mnemonicInput        string
 	mnemonicConfirmCount int
 	contentWidth         int
+	keyFile              string
+	passwordInput        textinput.Model
 }
 
 const inputPlaceholder = "tag volcano eight thank tide danger coast health above..."
@@ -75,6 +79,11 @@ func NewModule(module *modules.Default) modules.Module {
 	ti.Placeholder = inputPlaceholder
 	ti.Prompt = style.GetFocusedPrompt()
 	ti.CursorStyle = style.GetActiveCursor()
+	pi := textinput.New()
+	pi.Prompt = style.GetFocusedPrompt()
+	pi.CursorStyle = style.GetActiveCursor()
+	pi.EchoMode = textinput.EchoPassword
+	pi.EchoCharacter = '•'
 	return &Module{
 		D: modules.Default{
 			PrePipe:     module.PrePipe,
@@ -82,9 +91,10 @@ func NewModule(module *modules.Default) modules.Module {
 			PostPipe:    module.PostPipe,
 			ForkBackMsg: module.ForkBackMsg,
 		},
-		cancel: func() {},
-		input:  ti,
-		help:   help.NewModel(),
+		cancel:        func() {},
+		input:         ti,
+		passwordInput: pi,
+		help:          help.NewModel(),
 	}
 }
 
@@ -93,6 +103,9 @@ func (m *Module) State() int     { return int(m.state) }
 func (m *Module) String() string { return "secret module" }
 
 func (m *Module) Skip(ctx *context.Context) bool {
+	if ctx.WatchOnly() {
+		return true
+	}
 	return ctx.KeystoreExists && !ctx.SetNewSecret && !ctx.EditSecret
 }
 
@@ -162,6 +175,21 @@ func (m *Module) Update(msg tea.Msg) tea.Cmd {
 				m.mnemonicInput = strings.TrimSpace(m.input.Value())
 				return m.confirmMnemonicWord()
 			}
+
+		case stateKeyFilePassword:
+			switch {
+			case key.Matches(msg, defaultKeyMap.Back):
+				m.state = stateInput
+				m.passwordInput.Reset()
+				m.err = nil
+				return nil
+			case key.Matches(msg, defaultKeyMap.Enter):
+				m.err = nil
+				return m.decryptKeyFile()
+			}
+			var cmd tea.Cmd
+			m.passwordInput, cmd = m.passwordInput.Update(msg)
+			return cmd
 		}
 
 	case validMnemonicMsg:
@@ -188,11 +216,16 @@ func (m *Module) Update(msg tea.Msg) tea.Cmd {
 		m.state = stateShowNewMnemonic
 		return nil
 
+	case keyFileMsg:
+		m.state = stateKeyFilePassword
+		m.passwordInput.Reset()
+		return m.passwordInput.Focus()
+
 	case errInvalidInput:
 		m.state = stateInput
 		m.err = modules.Error{
 			Message: "Invalid input",
-			Help:    "Please enter a valid mnemonic or private key",
+			Help:    "Please enter a valid mnemonic, private key or path to a keystore file",
 		}
 		m.input.Reset()
 		return nil
@@ -245,10 +278,30 @@ func (m *Module) setSecret() tea.Cmd {
 		if _, err := crypto.HexToECDSA(m.secret); err == nil {
 			return validPrivateKeyMsg{}
 		}
+		if wallet.IsKeyFile(m.secret) {
+			m.keyFile = m.secret
+			return keyFileMsg{}
+		}
 		return errInvalidInput{}
 	}
 }
 
+func (m *Module) decryptKeyFile() tea.Cmd {
+	password := m.passwordInput.Value()
+	return func() tea.Msg {
+		secret, err := wallet.DecryptKeyFile(m.keyFile, password)
+		if err != nil {
+			m.passwordInput.Reset()
+			return modules.Error{
+				Message: "Could not decrypt the key file",
+				Help:    "Please check the password and make sure the file is a valid keystore file",
+			}
+		}
+		m.secret = secret
+		return validPrivateKeyMsg{}
+	}
+}
+
 func (m *Module) generateMnemonic() tea.Cmd {
 	return func() tea.Msg {
 		var err error
@@ -284,6 +337,7 @@ func (m *Module) Header() string           { return style.GenLogo() }
 func (m *Module) SetContentSize(width, height int) {
 	w := width - lipgloss.Width(m.input.Prompt)
 	m.input.Width = w
+	m.passwordInput.Width = w
 	m.input.PlaceholderStyle = m.input.PlaceholderStyle.MaxWidth(w)
 	m.contentWidth = width
 }
@@ -296,6 +350,8 @@ func (m *Module) Content() string {
 		return m.showNewMnemonicView()
 	case stateConfirmNewMnemonic:
 		return m.confirmNewMnemonicView()
+	case stateKeyFilePassword:
+		return m.keyFilePasswordView()
 	}
 	return ""
 }
@@ -305,7 +361,7 @@ func (m *Module) inputSecretView() string {
 	if !m.D.Ctx.KeystoreExists {
 		s.WriteString("It seems that you use the bot for the first time. Let's configure your wallet.\n")
 	}
-	s.WriteString("Please enter your seed phrase, private key or generate a new wallet\n\n")
+	s.WriteString("Please enter your seed phrase, private key, the path to a keystore file or generate a new wallet\n\n")
 
 	// predefined choices
 	for i := 0; i < len(menuChoices)-1; i++ {
@@ -352,6 +408,13 @@ func (m *Module) confirmNewMnemonicView() string {
 	return s.String()
 }
 
+func (m *Module) keyFilePasswordView() string {
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("Please enter the password of the keystore file %s\n\n", m.keyFile))
+	s.WriteString(m.passwordInput.View())
+	return s.String()
+}
+
 func (m *Module) MinContentHeight() int {
 	return lipgloss.Height(m.Content())
 }
@@ -367,6 +430,8 @@ func (m *Module) Footer() string {
 		return common.HelpView("Press any key to continue")
 	case stateConfirmNewMnemonic:
 		return common.HelpView("Press enter to confirm")
+	case stateKeyFilePassword:
+		return common.HelpView("Press enter to decrypt the key file")
 	}
 	return ""
 }
@@ -0,0 +1,334 @@
+package networks
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/custom"
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/ui/style"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type formKind int
+
+const (
+	formNetwork formKind = iota
+	formDex
+	formToken
+)
+
+// field is a labeled input of the form.
+type field struct {
+	label string
+	input textinput.Model
+}
+
+// form adds or edits a network, dex or token.
+// The name of an edited entry can't be changed, so it isn't part of the form.
+type form struct {
+	kind   formKind
+	fields []*field
+	focus  int
+	// network is the network of a dex or token or the edited network.
+	network *database.Network
+	dex     *database.Dex
+	token   *database.Token
+}
+
+func newField(label, value, placeholder string) *field {
+	input := textinput.New()
+	input.Prompt = style.GetCustomPrompt()
+	input.CursorStyle = lipgloss.NewStyle().Foreground(style.GetMainColor())
+	input.Placeholder = placeholder
+	input.SetValue(value)
+	return &field{label: label, input: input}
+}
+
+func newNetworkForm(n *database.Network) *form {
+	f := &form{kind: formNetwork, network: n}
+	if n == nil {
+		f.fields = append(f.fields, newField("Name", "", "short name, e.g. base"))
+		n = &database.Network{GasLimit: custom.DefaultGasLimit}
+	}
+	var endpoints []string
+	for _, e := range n.GetEndpoints() {
+		if !e.GetCustom() {
+			endpoints = append(endpoints, e.GetURL())
+		}
+	}
+	f.fields = append(f.fields,
+		newField("Full name", n.GetFullName(), "default: name"),
+		newField("Chain ID", formatUint(uint64(n.GetChainID())), ""),
+		newField("Native currency", n.GetNativeCurrency(), "e.g. ETH"),
+		newField("WETH", n.GetWETH(), "contract of the wrapped native currency"),
+		newField("Multicall", n.GetMulticall(), "contract of the multicall"),
+		newField("Endpoints", strings.Join(endpoints, ","), "comma separated rpc urls"),
+		newField("Gas limit", formatUint(n.GetGasLimit()), ""),
+		newField("EIP-1559", formatBool(n.EIP1559Enabled), "yes or no"),
+		newField("Testnet", formatBool(n.Testnet()), "yes or no"),
+	)
+	return f
+}
+
+func newDexForm(n *database.Network, d *database.Dex) *form {
+	f := &form{kind: formDex, network: n, dex: d}
+	if d == nil {
+		f.fields = append(f.fields, newField("Name", "", ""))
+		d = database.NewDex("", "", "", custom.DefaultDexFee, false)
+	}
+	f.fields = append(f.fields,
+		newField("Router", d.GetRouter(), ""),
+		newField("Factory", d.GetFactory(), ""),
+		newField("Fee", strconv.FormatInt(d.GetFee(), 10), "basis points left after a swap, e.g. 9970"),
+		newField("Init code hash", d.GetInitCodeHash(), "optional"),
+		newField("Protocol", d.GetProtocol(), "uniswapv2 or solidly"),
+	)
+	return f
+}
+
+func newTokenForm(n *database.Network, t *database.Token) *form {
+	f := &form{kind: formToken, network: n, token: t}
+	if t == nil {
+		f.fields = append(f.fields, newField("Contract", "", ""))
+		t = database.NewToken("", "", 0, false, nil)
+		t.SetConnector(true)
+	}
+	decimals := ""
+	if t.GetDecimals() != 0 {
+		decimals = strconv.Itoa(int(t.GetDecimals()))
+	}
+	f.fields = append(f.fields,
+		newField("Symbol", t.GetSymbol(), "default: read from the contract"),
+		newField("Decimals", decimals, "default: read from the contract"),
+		newField("Connector", formatBool(t.IsConnector()), "yes or no"),
+	)
+	return f
+}
+
+func (f *form) isNew() bool {
+	switch f.kind {
+	case formNetwork:
+		return f.network == nil
+	case formDex:
+		return f.dex == nil
+	}
+	return f.token == nil
+}
+
+func (f *form) title() string {
+	switch f.kind {
+	case formNetwork:
+		if f.isNew() {
+			return "Add a network"
+		}
+		return "Edit network " + f.network.GetName()
+	case formDex:
+		if f.isNew() {
+			return fmt.Sprintf("Add a dex to %s", f.network.GetName())
+		}
+		return "Edit dex " + f.dex.GetName()
+	}
+	if f.isNew() {
+		return fmt.Sprintf("Add a token to %s", f.network.GetName())
+	}
+	return "Edit token " + f.token.GetSymbol()
+}
+
+// value returns the trimmed value of the field with the label.
+func (f *form) value(label string) string {
+	for _, field := range f.fields {
+		if field.label == label {
+			return strings.TrimSpace(field.input.Value())
+		}
+	}
+	return ""
+}
+
+func (f *form) setFocus(i int) tea.Cmd {
+	f.fields[f.focus].input.Blur()
+	f.fields[f.focus].input.Prompt = style.GetCustomPrompt()
+	f.focus = (i + len(f.fields)) % len(f.fields)
+	f.fields[f.focus].input.Prompt = style.GetFocusedCustomPrompt()
+	return f.fields[f.focus].input.Focus()
+}
+
+func (f *form) setWidth(width int) {
+	for _, field := range f.fields {
+		field.input.Width = width - f.labelWidth() - 3
+	}
+}
+
+func (f *form) labelWidth() int {
+	var width int
+	for _, field := range f.fields {
+		if w := lipgloss.Width(field.label); w > width {
+			width = w
+		}
+	}
+	return width
+}
+
+func (f *form) view() string {
+	var s strings.Builder
+	s.WriteString(f.title())
+	s.WriteString("\n\n")
+	label := lipgloss.NewStyle().Width(f.labelWidth() + 1)
+	for i, field := range f.fields {
+		l := label.Render(field.label)
+		if i == f.focus {
+			l = label.Copy().Foreground(style.GetMainColor()).Render(field.label)
+		}
+		s.WriteString(l + field.input.View() + "\n")
+	}
+	return s.String()
+}
+
+// save validates the entry of the form on-chain and saves it.
+func (f *form) save() error {
+	switch f.kind {
+	case formNetwork:
+		return f.saveNetwork()
+	case formDex:
+		return f.saveDex()
+	}
+	return f.saveToken()
+}
+
+func (f *form) saveNetwork() error {
+	chainID, err := strconv.ParseUint(f.value("Chain ID"), 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid chain id: %w", err)
+	}
+	gasLimit, err := strconv.ParseUint(f.value("Gas limit"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid gas limit: %w", err)
+	}
+	eip1559, err := parseBool(f.value("EIP-1559"))
+	if err != nil {
+		return err
+	}
+	testnet, err := parseBool(f.value("Testnet"))
+	if err != nil {
+		return err
+	}
+
+	n := f.network
+	if n == nil {
+		n = &database.Network{Name: f.value("Name")}
+	}
+	n.FullName = f.value("Full name")
+	if n.FullName == "" {
+		n.FullName = n.Name
+	}
+	n.ChainID = uint32(chainID)
+	n.NativeCurrency = f.value("Native currency")
+	n.WETH = f.value("WETH")
+	n.Multicall = f.value("Multicall")
+	n.GasLimit = gasLimit
+	n.EIP1559Enabled = eip1559
+	n.IsTestnet = testnet
+	endpoints := make(database.Endpoints, 0)
+	if e, ok := n.GetCustomEndpoint(); ok {
+		endpoints = append(endpoints, e)
+	}
+	for _, url := range strings.Split(f.value("Endpoints"), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			endpoints = append(endpoints, database.NewEndpoint(url, false))
+		}
+	}
+	n.Endpoints = endpoints
+
+	if f.network == nil {
+		return custom.AddNetwork(n)
+	}
+	return custom.EditNetwork(n)
+}
+
+func (f *form) saveDex() error {
+	fee, err := strconv.ParseInt(f.value("Fee"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid fee: %w", err)
+	}
+	d := f.dex
+	if d == nil {
+		d = database.NewDex(f.value("Name"), "", "", 0, false)
+	}
+	d.Router = f.value("Router")
+	d.Factory = f.value("Factory")
+	d.Fee = fee
+	d.SetInitCodeHash(f.value("Init code hash"))
+	d.SetProtocol(f.value("Protocol"))
+
+	if f.dex == nil {
+		return custom.AddDex(f.network, d)
+	}
+	return custom.EditDex(f.network, d)
+}
+
+func (f *form) saveToken() error {
+	var decimals uint64
+	if v := f.value("Decimals"); v != "" {
+		var err error
+		if decimals, err = strconv.ParseUint(v, 10, 8); err != nil {
+			return fmt.Errorf("invalid decimals: %w", err)
+		}
+	}
+	connector, err := parseBool(f.value("Connector"))
+	if err != nil {
+		return err
+	}
+	t := f.token
+	if t == nil {
+		t = database.NewToken(f.value("Contract"), "", 0, false, nil)
+	}
+	t.SetSymbol(f.value("Symbol"))
+	t.SetDecimals(uint8(decimals))
+	t.SetConnector(connector)
+
+	if f.token == nil {
+		return custom.AddToken(f.network, t)
+	}
+	return custom.EditToken(f.network, t)
+}
+
+var errInvalidBool = errors.New(`enter "yes" or "no"`)
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "yes", "y", "true":
+		return true, nil
+	case "no", "n", "false", "":
+		return false, nil
+	}
+	return false, errInvalidBool
+}
+
+func formatBool(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func formatUint(i uint64) string {
+	if i == 0 {
+		return ""
+	}
+	return strconv.FormatUint(i, 10)
+}
+
+func (f *form) kindName() string {
+	switch f.kind {
+	case formNetwork:
+		return "network"
+	case formDex:
+		return "dex"
+	}
+	return "token"
+}
@@ -0,0 +1,158 @@
+package networks
+
+import "github.com/charmbracelet/bubbles/key"
+
+type keyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	Open     key.Binding
+	Add      key.Binding
+	AddToken key.Binding
+	Edit     key.Binding
+	Delete   key.Binding
+	Back     key.Binding
+	Quit     key.Binding
+	Help     key.Binding
+	// entries is true for the keys of the dexes and tokens of a network.
+	entries bool
+}
+
+var networkKeys = keyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "down"),
+	),
+	Open: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "show dexes and tokens"),
+	),
+	Add: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "add network"),
+	),
+	Edit: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "edit"),
+	),
+	Delete: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "delete"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "help"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+}
+
+var entryKeys = keyMap{
+	Up:   networkKeys.Up,
+	Down: networkKeys.Down,
+	Add: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "add dex"),
+	),
+	AddToken: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "add token"),
+	),
+	Edit:   networkKeys.Edit,
+	Delete: networkKeys.Delete,
+	Back:   networkKeys.Back,
+	Help:   networkKeys.Help,
+	Quit:   networkKeys.Quit,
+
+	entries: true,
+}
+
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.Quit}
+}
+
+func (k keyMap) FullHelp() [][]key.Binding {
+	if k.entries {
+		return [][]key.Binding{
+			{k.Up, k.Add, k.Edit, k.Back, k.Help},
+			{k.Down, k.AddToken, k.Delete, k.Quit},
+		}
+	}
+	return [][]key.Binding{
+		{k.Up, k.Open, k.Edit, k.Back, k.Help},
+		{k.Down, k.Add, k.Delete, k.Quit},
+	}
+}
+
+// formKeys are the keys of the form. They don't contain any letters which could be part of the input.
+var formKeys = formKeyMap{
+	Next: key.NewBinding(
+		key.WithKeys("tab", "down"),
+		key.WithHelp("tab", "next"),
+	),
+	Prev: key.NewBinding(
+		key.WithKeys("shift+tab", "up"),
+		key.WithHelp("shift+tab", "previous"),
+	),
+	Submit: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "validate and save"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "cancel"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("ctrl+c"),
+		key.WithHelp("ctrl+c", "quit"),
+	),
+}
+
+type formKeyMap struct {
+	Next   key.Binding
+	Prev   key.Binding
+	Submit key.Binding
+	Back   key.Binding
+	Quit   key.Binding
+}
+
+func (k formKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Next, k.Prev, k.Submit, k.Back, k.Quit}
+}
+
+func (k formKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+var confirmKeys = confirmKeyMap{
+	Yes: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "delete"),
+	),
+	No: key.NewBinding(
+		key.WithKeys("n", "esc"),
+		key.WithHelp("n", "cancel"),
+	),
+}
+
+type confirmKeyMap struct {
+	Yes key.Binding
+	No  key.Binding
+}
+
+func (k confirmKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Yes, k.No}
+}
+
+func (k confirmKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
@@ -0,0 +1,531 @@
+package networks
+
+import (
+	ctx "context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/context"
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/pipe/tui/modules"
+	"github.com/jon4hz/deadshot/internal/ui/bubbles/simpleview"
+	"github.com/jon4hz/deadshot/internal/ui/style"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type state int
+
+const (
+	stateUnknown state = iota
+	stateLoading
+	stateNetworks
+	stateEntries
+	stateForm
+	stateSaving
+	stateConfirm
+)
+
+type loadedMsg []*database.Network
+
+type savedMsg struct{}
+
+var (
+	_ modules.Module          = (*Module)(nil)
+	_ simpleview.SimpleViewer = (*Module)(nil)
+)
+
+// entry is a dex or a token of a network.
+type entry struct {
+	dex   *database.Dex
+	token *database.Token
+}
+
+// Module lists the networks with their dexes and tokens and lets the user manage the user-defined ones.
+type Module struct {
+	ctx          ctx.Context
+	cancel       ctx.CancelFunc
+	D            modules.Default
+	state        state
+	err          error
+	help         help.Model
+	spinner      spinner.Model
+	networkTable table.Model
+	entryTable   table.Model
+	width        int
+
+	networks []*database.Network
+	// network is the network of which the dexes and tokens are shown.
+	network *database.Network
+	entries []entry
+	form    *form
+	// prev is the state to return to after the form or the confirmation.
+	prev state
+	// confirm is the question of the confirmation and remove deletes the selected entry after it was confirmed.
+	confirm string
+	remove  func() error
+}
+
+func New(module *modules.Default) *Module {
+	return &Module{
+		D: modules.Default{
+			PrePipe:     module.PrePipe,
+			Pipe:        module.Pipe,
+			PostPipe:    module.PostPipe,
+			ForkBackMsg: module.ForkBackMsg,
+		},
+		cancel:  func() {},
+		help:    help.New(),
+		spinner: style.GetSpinnerPoints(),
+		networkTable: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "Network", Width: 12},
+				{Title: "Chain ID", Width: 10},
+				{Title: "Currency", Width: 8},
+				{Title: "Dexes", Width: 5},
+				{Title: "Tokens", Width: 6},
+				{Title: "Type", Width: 10},
+			}),
+			table.WithFocused(true),
+		),
+		entryTable: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "Kind", Width: 5},
+				{Title: "Name", Width: 14},
+				{Title: "Contract", Width: 42},
+				{Title: "Type", Width: 10},
+			}),
+			table.WithFocused(true),
+		),
+	}
+}
+
+func (m *Module) Cancel()        { m.cancel() }
+func (m *Module) State() int     { return int(m.state) }
+func (m *Module) String() string { return "networks module" }
+
+func (m *Module) Init(c *context.Context) tea.Cmd {
+	m.ctx, m.cancel = ctx.WithCancel(c)
+	m.D.Ctx = c
+	m.err = nil
+	m.network = nil
+	m.state = stateLoading
+	return tea.Batch(
+		m.load(),
+		spinner.Tick,
+	)
+}
+
+// load fetches all networks, including testnets and the networks without dexes.
+func (m *Module) load() tea.Cmd {
+	return func() tea.Msg {
+		networks, err := database.FetchAllNetworks(true)
+		if err != nil {
+			return modules.ErrMsg(modules.Error{
+				Message: "Could not load the networks",
+				Help:    err.Error(),
+			})
+		}
+		return loadedMsg(networks)
+	}
+}
+
+// save validates the entry of the form on-chain and saves it.
+func (m *Module) save() tea.Cmd {
+	m.state = stateSaving
+	m.err = nil
+	f, cfg := m.form, m.D.Ctx.Config
+	return func() tea.Msg {
+		if err := f.save(); err != nil {
+			return modules.ErrMsg(modules.Error{
+				Message: "Could not save the " + f.kindName(),
+				Help:    err.Error(),
+			})
+		}
+		if err := cfg.ReloadNetworks(); err != nil {
+			return modules.ErrMsg(modules.Error{
+				Message: "Could not reload the networks",
+				Help:    err.Error(),
+			})
+		}
+		return savedMsg{}
+	}
+}
+
+func (m *Module) openForm(f *form) tea.Cmd {
+	m.prev = m.state
+	m.state = stateForm
+	m.err = nil
+	m.form = f
+	f.setWidth(m.width)
+	return tea.Batch(f.setFocus(0), modules.Resize)
+}
+
+func (m *Module) askDelete(question string, remove func() error) {
+	m.prev = m.state
+	m.state = stateConfirm
+	m.err = nil
+	m.confirm = question
+	m.remove = remove
+}
+
+func (m *Module) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch m.state {
+		case stateForm:
+			return m.updateForm(msg)
+		case stateConfirm:
+			return m.updateConfirm(msg)
+		}
+
+		keys := networkKeys
+		if m.state == stateEntries {
+			keys = entryKeys
+		}
+		switch {
+		case key.Matches(msg, keys.Back):
+			if m.state == stateEntries {
+				m.state = stateNetworks
+				m.network = nil
+				m.err = nil
+				return modules.Resize
+			}
+			return m.back()
+		case key.Matches(msg, keys.Quit):
+			return tea.Quit
+		case key.Matches(msg, keys.Help):
+			m.help.ShowAll = !m.help.ShowAll
+			return nil
+		}
+		switch m.state {
+		case stateNetworks:
+			return m.updateNetworks(msg)
+		case stateEntries:
+			return m.updateEntries(msg)
+		}
+
+	case loadedMsg:
+		m.networks = msg
+		m.setNetworkRows()
+		if m.network != nil {
+			// show the reloaded version of the opened network
+			m.network = database.Networks(m.networks).GetNetworkByName(m.network.GetName())
+		}
+		if m.network != nil {
+			m.state = stateEntries
+			m.setEntryRows()
+		} else {
+			m.state = stateNetworks
+		}
+		return modules.Resize
+
+	case savedMsg:
+		m.form = nil
+		return m.load()
+
+	case modules.ErrMsg:
+		m.err = msg
+		switch m.state {
+		case stateSaving:
+			m.state = stateForm
+		case stateLoading:
+			m.state = stateNetworks
+		}
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return cmd
+	}
+
+	if m.state == stateForm {
+		var cmd tea.Cmd
+		field := m.form.fields[m.form.focus]
+		field.input, cmd = field.input.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *Module) updateNetworks(msg tea.KeyMsg) tea.Cmd {
+	if key.Matches(msg, networkKeys.Add) {
+		return m.openForm(newNetworkForm(nil))
+	}
+	n := m.selectedNetwork()
+	if n == nil {
+		return nil
+	}
+	switch {
+	case key.Matches(msg, networkKeys.Open):
+		m.network = n
+		m.state = stateEntries
+		m.err = nil
+		m.entryTable.SetCursor(0)
+		m.setEntryRows()
+		return modules.Resize
+	case key.Matches(msg, networkKeys.Edit):
+		if n.GetPredefined() {
+			m.err = errPredefined
+			return nil
+		}
+		return m.openForm(newNetworkForm(n))
+	case key.Matches(msg, networkKeys.Delete):
+		if n.GetPredefined() {
+			m.err = errPredefined
+			return nil
+		}
+		m.askDelete(fmt.Sprintf("Delete the network %s with all its dexes and tokens?", n.GetName()), func() error {
+			return database.DeleteNetwork(n)
+		})
+		return nil
+	}
+	var cmd tea.Cmd
+	m.networkTable, cmd = m.networkTable.Update(msg)
+	return cmd
+}
+
+func (m *Module) updateEntries(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, entryKeys.Add):
+		return m.openForm(newDexForm(m.network, nil))
+	case key.Matches(msg, entryKeys.AddToken):
+		return m.openForm(newTokenForm(m.network, nil))
+	}
+	e := m.selectedEntry()
+	if e == nil {
+		return nil
+	}
+	switch {
+	case key.Matches(msg, entryKeys.Edit):
+		if e.predefined() {
+			m.err = errPredefined
+			return nil
+		}
+		if e.dex != nil {
+			return m.openForm(newDexForm(m.network, e.dex))
+		}
+		return m.openForm(newTokenForm(m.network, e.token))
+	case key.Matches(msg, entryKeys.Delete):
+		if e.predefined() {
+			m.err = errPredefined
+			return nil
+		}
+		n := m.network
+		if e.dex != nil {
+			m.askDelete(fmt.Sprintf("Delete the dex %s?", e.dex.GetName()), func() error {
+				return database.DeleteDex(n, e.dex)
+			})
+			return nil
+		}
+		m.askDelete(fmt.Sprintf("Delete the token %s?", e.token.GetSymbol()), func() error {
+			return database.DeleteToken(n, e.token)
+		})
+		return nil
+	}
+	var cmd tea.Cmd
+	m.entryTable, cmd = m.entryTable.Update(msg)
+	return cmd
+}
+
+func (m *Module) updateForm(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, formKeys.Submit):
+		return m.save()
+	case key.Matches(msg, formKeys.Next):
+		return m.form.setFocus(m.form.focus + 1)
+	case key.Matches(msg, formKeys.Prev):
+		return m.form.setFocus(m.form.focus - 1)
+	case key.Matches(msg, formKeys.Back):
+		// the edited entry might have been changed by a failed validation
+		m.form = nil
+		m.err = nil
+		m.state = stateLoading
+		return m.load()
+	case key.Matches(msg, formKeys.Quit):
+		return tea.Quit
+	}
+	var cmd tea.Cmd
+	field := m.form.fields[m.form.focus]
+	field.input, cmd = field.input.Update(msg)
+	return cmd
+}
+
+func (m *Module) updateConfirm(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, confirmKeys.Yes):
+		m.state = m.prev
+		if err := m.remove(); err != nil {
+			m.err = modules.Error{
+				Message: "Could not delete the entry",
+				Help:    err.Error(),
+			}
+			return nil
+		}
+		if err := m.D.Ctx.Config.ReloadNetworks(); err != nil {
+			m.err = modules.Error{
+				Message: "Could not reload the networks",
+				Help:    err.Error(),
+			}
+		}
+		m.state = stateLoading
+		return m.load()
+	case key.Matches(msg, confirmKeys.No):
+		m.state = m.prev
+	}
+	return nil
+}
+
+var errPredefined = modules.Error{
+	Message: "Predefined entries can't be changed",
+	Help:    "Only user-defined networks, dexes and tokens can be edited or deleted.",
+}
+
+func (m *Module) back() tea.Cmd {
+	m.cancel()
+	if m.D.ForkBackMsg != 0 {
+		return func() tea.Msg { return m.D.ForkBackMsg }
+	}
+	return modules.Back
+}
+
+func (m *Module) selectedNetwork() *database.Network {
+	i := m.networkTable.Cursor()
+	if i < 0 || i >= len(m.networks) {
+		return nil
+	}
+	return m.networks[i]
+}
+
+func (m *Module) selectedEntry() *entry {
+	i := m.entryTable.Cursor()
+	if i < 0 || i >= len(m.entries) {
+		return nil
+	}
+	return &m.entries[i]
+}
+
+func (e entry) predefined() bool {
+	if e.dex != nil {
+		return e.dex.GetPredefined()
+	}
+	return e.token.GetPredefined()
+}
+
+func (m *Module) setNetworkRows() {
+	rows := make([]table.Row, 0, len(m.networks))
+	for _, n := range m.networks {
+		rows = append(rows, table.Row{
+			n.GetName(),
+			strconv.FormatUint(uint64(n.GetChainID()), 10),
+			n.GetNativeCurrency(),
+			strconv.Itoa(len(n.GetDexes())),
+			strconv.Itoa(len(n.GetTokens())),
+			entryType(n.GetPredefined()),
+		})
+	}
+	m.networkTable.SetRows(rows)
+	if m.networkTable.Cursor() >= len(rows) {
+		m.networkTable.SetCursor(0)
+	}
+}
+
+func (m *Module) setEntryRows() {
+	m.entries = m.entries[:0]
+	rows := make([]table.Row, 0, len(m.network.GetDexes())+len(m.network.GetTokens()))
+	for _, d := range m.network.GetDexes() {
+		m.entries = append(m.entries, entry{dex: d})
+		rows = append(rows, table.Row{"dex", d.GetName(), d.GetRouter(), entryType(d.GetPredefined())})
+	}
+	for _, t := range m.network.GetTokens() {
+		m.entries = append(m.entries, entry{token: t})
+		rows = append(rows, table.Row{"token", t.GetSymbol(), t.GetContract(), entryType(t.GetPredefined())})
+	}
+	m.entryTable.SetRows(rows)
+	if m.entryTable.Cursor() >= len(rows) {
+		m.entryTable.SetCursor(0)
+	}
+}
+
+func entryType(predefined bool) string {
+	if predefined {
+		return "predefined"
+	}
+	return "custom"
+}
+
+func (m *Module) SetHeaderWidth(width int) {}
+func (m *Module) Header() string {
+	var s strings.Builder
+	s.WriteString(style.GenLogo())
+	s.WriteString("\n\n")
+	if m.network != nil {
+		s.WriteString(style.SubtleStyle.Render("Dexes and tokens of " + m.network.GetName()))
+	} else {
+		s.WriteString(style.SubtleStyle.Render("Networks"))
+	}
+	return s.String()
+}
+
+func (m *Module) SetContentSize(width, height int) {
+	m.width = width
+	m.networkTable.SetWidth(width)
+	m.networkTable.SetHeight(height)
+	m.entryTable.SetWidth(width)
+	m.entryTable.SetHeight(height)
+	if m.form != nil {
+		m.form.setWidth(width)
+	}
+}
+
+func (m *Module) MinContentHeight() int {
+	return 12 // TODO: don't hardcode that value
+}
+
+func (m *Module) Content() string {
+	var s strings.Builder
+	switch m.state {
+	case stateLoading:
+		s.WriteString("\n")
+		s.WriteString(m.spinner.View())
+		s.WriteString(" Loading networks...")
+	case stateSaving:
+		s.WriteString("\n")
+		s.WriteString(m.spinner.View())
+		s.WriteString(" Validating on-chain...")
+	case stateForm:
+		s.WriteString(m.form.view())
+	case stateConfirm:
+		s.WriteString("\n")
+		s.WriteString(m.confirm)
+	case stateNetworks:
+		s.WriteString(m.networkTable.View())
+	case stateEntries:
+		s.WriteString(m.entryTable.View())
+	}
+	return s.String()
+}
+
+func (m *Module) Error() error { return m.err }
+
+func (m *Module) SetFooterWidth(width int) { m.help.Width = width }
+func (m *Module) Footer() string {
+	switch m.state {
+	case stateForm:
+		return m.help.View(formKeys)
+	case stateConfirm:
+		return m.help.View(confirmKeys)
+	case stateEntries:
+		return m.help.View(entryKeys)
+	}
+	return m.help.View(networkKeys)
+}
+
+func (m *Module) PrePipe() []modules.Piper  { return m.D.PrePipe }
+func (m *Module) Pipe() []modules.Piper     { return m.D.Pipe }
+func (m *Module) PostPipe() []modules.Piper { return m.D.PostPipe }
@@ -43,6 +43,14 @@ func genListItems() []item {
 			text:    "Set a custom endpoint",
 			forkMsg: modules.ForkMsgCustomEndpoint,
 		},
+		{
+			text:    "Manage token allowances",
+			forkMsg: modules.ForkMsgAllowances,
+		},
+		{
+			text:    "Manage networks, dexes and tokens",
+			forkMsg: modules.ForkMsgNetworks,
+		},
 		{
 			text:    "Back",
 			forkMsg: modules.ForkMsgNone,
@@ -164,6 +172,10 @@ func (m *Module) Update(msg tea.Msg) tea.Cmd {
 					}
 					return modules.Back
 				}
+				if m.Fork() == modules.ForkMsgWalletSettings && m.D.Ctx.WatchOnly() {
+					m.err = modules.ErrWatchOnly
+					return nil
+				}
 				return func() tea.Msg { return m.Fork() }
 
 			case key.Matches(msg, defaultKeys.Back):
@@ -0,0 +1,102 @@
+package wallets
+
+import "github.com/charmbracelet/bubbles/key"
+
+type keyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	Enter    key.Binding
+	Remove   key.Binding
+	Export   key.Binding
+	Back     key.Binding
+	Quit     key.Binding
+	Help     key.Binding
+	Forward  key.Binding
+	Backward key.Binding
+}
+
+var defaultKeys = keyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up"),
+		key.WithHelp("↑", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down"),
+		key.WithHelp("↓", "down"),
+	),
+	Enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "confirm/rename"),
+	),
+	Remove: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "remove wallet"),
+	),
+	Export: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "export keystore file"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "help"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+	Forward: key.NewBinding(
+		key.WithKeys("tab", "down"),
+	),
+	Backward: key.NewBinding(
+		key.WithKeys("shift+tab", "up"),
+	),
+}
+
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.Quit}
+}
+
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Enter, k.Help},
+		{k.Down, k.Remove, k.Export, k.Back, k.Quit},
+	}
+}
+
+// inputKeys are the keys of the input form. They don't contain any letters which could be part of the input.
+var inputKeys = keyMap{
+	Enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "next/submit"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "cancel"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("ctrl+c"),
+		key.WithHelp("ctrl+c", "quit"),
+	),
+	Forward: key.NewBinding(
+		key.WithKeys("tab", "down"),
+		key.WithHelp("tab", "next field"),
+	),
+	Backward: key.NewBinding(
+		key.WithKeys("shift+tab", "up"),
+		key.WithHelp("shift+tab", "previous field"),
+	),
+}
+
+type inputKeyMap keyMap
+
+func (k inputKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Enter, k.Forward, k.Back, k.Quit}
+}
+
+func (k inputKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
@@ -0,0 +1,522 @@
+package wallets
+
+import (
+	ctx "context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/context"
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/pipe/tui/modules"
+	"github.com/jon4hz/deadshot/internal/ui/bubbles/keyvalue"
+	"github.com/jon4hz/deadshot/internal/ui/bubbles/simpleview"
+	"github.com/jon4hz/deadshot/internal/ui/style"
+	"github.com/jon4hz/deadshot/internal/wallet"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var itemStyle = lipgloss.NewStyle().PaddingLeft(2)
+
+type state int
+
+const (
+	stateUnknown state = iota
+	stateReady
+	stateInput
+)
+
+// action is the action of the input form.
+type action int
+
+const (
+	actionNone action = iota
+	actionDerive
+	actionImport
+	actionImportKeyFile
+	actionExternal
+	actionExport
+	actionRename
+	actionBack
+)
+
+type walletsDoneMsg struct{}
+
+type exportDoneMsg string
+
+type item struct {
+	text   string
+	wallet *database.Wallet
+	action action
+}
+
+func (i item) FilterValue() string { return "" }
+func (i item) String() string      { return i.text }
+
+type itemDelegate struct{}
+
+func (d itemDelegate) Height() int                               { return 1 }
+func (d itemDelegate) Spacing() int                              { return 0 }
+func (d itemDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd { return nil }
+func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(item)
+	if !ok {
+		return
+	}
+
+	fn := itemStyle.Width(m.Width()).Render
+	if index == m.Index() {
+		fn = func(s string) string {
+			return lipgloss.JoinHorizontal(
+				lipgloss.Left,
+				style.MainStyle.Copy().
+					Render("> "),
+				style.MainStyle.Copy().
+					Width(m.Width()-itemStyle.GetPaddingLeft()).
+					Render(s),
+			)
+		}
+	}
+
+	fmt.Fprint(w, fn(i.String()))
+}
+
+var (
+	_ modules.Module          = (*Module)(nil)
+	_ simpleview.SimpleViewer = (*Module)(nil)
+)
+
+// Module lists all wallets and lets the user add, rename and remove them.
+type Module struct {
+	ctx    ctx.Context
+	cancel ctx.CancelFunc
+	D      modules.Default
+	state  state
+	err    error
+	help   help.Model
+	kv     keyvalue.Model
+
+	list   list.Model
+	action action
+	wallet *database.Wallet
+	inputs []textinput.Model
+	focus  int
+	// exported is the path of the last exported key file.
+	exported string
+}
+
+func New(module *modules.Default) *Module {
+	return &Module{
+		D: modules.Default{
+			PrePipe:     module.PrePipe,
+			Pipe:        module.Pipe,
+			PostPipe:    module.PostPipe,
+			ForkBackMsg: module.ForkBackMsg,
+		},
+		cancel: func() {},
+		help:   help.New(),
+		kv:     keyvalue.New(),
+		list:   list.New(nil, itemDelegate{}, 0, 0),
+	}
+}
+
+func (m *Module) Cancel()        { m.cancel() }
+func (m *Module) State() int     { return int(m.state) }
+func (m *Module) String() string { return "settings wallets module" }
+
+func (m *Module) Init(c *context.Context) tea.Cmd {
+	m.ctx, m.cancel = ctx.WithCancel(c)
+	m.state = stateReady
+	m.D.Ctx = c
+	m.err = nil
+
+	m.setListItems()
+	m.list.SetShowHelp(false)
+	m.list.SetFilteringEnabled(false)
+	m.list.Title = "Select a wallet to rename it or an option"
+	m.list.Styles.Title = lipgloss.NewStyle()
+	m.list.SetShowStatusBar(false)
+	return modules.Resize
+}
+
+func (m *Module) setListItems() {
+	wallets := m.D.Ctx.Config.AllWallets()
+	items := make([]list.Item, 0, len(wallets)+3)
+	for _, w := range wallets {
+		items = append(items, item{
+			text:   fmt.Sprintf("%s - %s", w.String(), walletKind(w)),
+			wallet: w,
+			action: actionRename,
+		})
+	}
+	if _, isMnemonic, _ := wallet.SearchMnemonic(); isMnemonic {
+		items = append(items, item{text: "Add a wallet of the mnemonic phrase", action: actionDerive})
+	}
+	items = append(items,
+		item{text: "Import a private key", action: actionImport},
+		item{text: "Import a keystore file", action: actionImportKeyFile},
+		item{text: "Connect an external signer (clef)", action: actionExternal},
+		item{text: "Back", action: actionBack},
+	)
+	m.list.SetItems(items)
+}
+
+func walletKind(w *database.Wallet) string {
+	switch {
+	case w.IsMain():
+		return "main"
+	case w.GetImported():
+		return "imported"
+	case w.IsExternal():
+		return "external signer " + w.GetSignerURL()
+	}
+	if path := w.GetDerivationPath(); path != "" && path != wallet.DefaultDerivationPath {
+		return fmt.Sprintf("index %d of %s", w.GetWalletIndex(), path)
+	}
+	return fmt.Sprintf("index %d", w.GetWalletIndex())
+}
+
+func (m *Module) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch m.state {
+		case stateReady:
+			switch {
+			case key.Matches(msg, defaultKeys.Enter):
+				i, ok := m.list.SelectedItem().(item)
+				if !ok {
+					return nil
+				}
+				if i.action == actionBack {
+					return m.back()
+				}
+				m.err = nil
+				return m.showInput(i.action, i.wallet)
+
+			case key.Matches(msg, defaultKeys.Remove):
+				i, ok := m.list.SelectedItem().(item)
+				if !ok || i.wallet == nil {
+					return nil
+				}
+				m.err = nil
+				return m.removeWallet(i.wallet)
+
+			case key.Matches(msg, defaultKeys.Export):
+				i, ok := m.list.SelectedItem().(item)
+				if !ok || i.wallet == nil {
+					return nil
+				}
+				m.err = nil
+				return m.showInput(actionExport, i.wallet)
+
+			case key.Matches(msg, defaultKeys.Back):
+				return m.back()
+
+			case key.Matches(msg, defaultKeys.Quit):
+				return tea.Quit
+
+			case key.Matches(msg, defaultKeys.Help):
+				m.help.ShowAll = !m.help.ShowAll
+			}
+			var cmd tea.Cmd
+			m.list, cmd = m.list.Update(msg)
+			return cmd
+
+		case stateInput:
+			switch {
+			case key.Matches(msg, inputKeys.Enter):
+				if m.focus < len(m.inputs)-1 {
+					return m.setFocus(m.focus + 1)
+				}
+				return m.submit()
+
+			case key.Matches(msg, inputKeys.Back):
+				m.state = stateReady
+				m.err = nil
+				return modules.Resize
+
+			case key.Matches(msg, inputKeys.Quit):
+				return tea.Quit
+
+			case key.Matches(msg, inputKeys.Forward):
+				return m.setFocus(m.focus + 1)
+
+			case key.Matches(msg, inputKeys.Backward):
+				return m.setFocus(m.focus - 1)
+			}
+		}
+
+	case walletsDoneMsg:
+		m.state = stateReady
+		m.exported = ""
+		m.setListItems()
+		return modules.Resize
+
+	case exportDoneMsg:
+		m.state = stateReady
+		m.exported = string(msg)
+		return modules.Resize
+
+	case modules.ErrMsg:
+		m.err = msg
+	}
+
+	if m.state == stateInput {
+		var cmd tea.Cmd
+		m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *Module) back() tea.Cmd {
+	if m.D.ForkBackMsg != 0 {
+		return func() tea.Msg { return m.D.ForkBackMsg }
+	}
+	return modules.Back
+}
+
+// showInput shows the input form of the action.
+func (m *Module) showInput(a action, w *database.Wallet) tea.Cmd {
+	m.state = stateInput
+	m.action = a
+	m.wallet = w
+
+	label := newInput("label of the wallet", 32)
+	switch a {
+	case actionDerive:
+		path := newInput("derivation path, e.g. "+wallet.DefaultDerivationPath, 64)
+		path.SetValue(m.D.Ctx.Config.Wallet.GetDerivationPath())
+		m.inputs = []textinput.Model{newInput("index of the address, e.g. 1", 10), path, label}
+	case actionImport:
+		m.inputs = []textinput.Model{newPasswordInput("private key in hex"), label}
+	case actionImportKeyFile:
+		m.inputs = []textinput.Model{newInput("path to the keystore file", 256), newPasswordInput("password of the keystore file"), label}
+	case actionExternal:
+		m.inputs = []textinput.Model{
+			newInput("http url or ipc path of the signer, e.g. ~/.clef/clef.ipc", 256),
+			newInput("address of the wallet", 42),
+			label,
+		}
+	case actionExport:
+		m.inputs = []textinput.Model{
+			newInput("path of the keystore file or a directory", 256),
+			newPasswordInput("password"),
+			newPasswordInput("repeat the password"),
+		}
+	case actionRename:
+		label.SetValue(w.GetLabel())
+		m.inputs = []textinput.Model{label}
+	}
+	m.focus = 0
+	return tea.Batch(m.setFocus(0), textinput.Blink, modules.Resize)
+}
+
+func newInput(placeholder string, limit int) textinput.Model {
+	input := textinput.New()
+	input.Placeholder = placeholder
+	input.Prompt = style.GetPrompt()
+	input.CursorStyle = lipgloss.NewStyle().Foreground(style.GetMainColor())
+	input.CharLimit = limit
+	return input
+}
+
+func newPasswordInput(placeholder string) textinput.Model {
+	input := newInput(placeholder, 128)
+	input.EchoMode = textinput.EchoPassword
+	input.EchoCharacter = '•'
+	return input
+}
+
+// setFocus focuses the input at the index and blurs all other inputs.
+func (m *Module) setFocus(index int) tea.Cmd {
+	if index < 0 {
+		index = len(m.inputs) - 1
+	} else if index >= len(m.inputs) {
+		index = 0
+	}
+	m.focus = index
+	var cmd tea.Cmd
+	for i := range m.inputs {
+		if i == index {
+			m.inputs[i].Prompt = style.GetFocusedPrompt()
+			cmd = m.inputs[i].Focus()
+			continue
+		}
+		m.inputs[i].Prompt = style.GetPrompt()
+		m.inputs[i].Blur()
+	}
+	return cmd
+}
+
+func (m *Module) submit() tea.Cmd {
+	values := make([]string, len(m.inputs))
+	for i := range m.inputs {
+		values[i] = m.inputs[i].Value()
+		// passwords are used as they are
+		if m.inputs[i].EchoMode != textinput.EchoPassword {
+			values[i] = strings.TrimSpace(values[i])
+		}
+	}
+	a, w := m.action, m.wallet
+	return func() tea.Msg {
+		switch a {
+		case actionDerive:
+			index, err := strconv.ParseUint(values[0], 10, 32)
+			if err != nil {
+				return modules.ErrMsg(modules.Error{
+					Message: "Invalid index",
+					Help:    "The index must be a positive number.",
+				})
+			}
+			newWallet, err := wallet.AddDerivedWallet(values[1], uint(index), values[2])
+			if err != nil {
+				return walletError(err)
+			}
+			m.D.Ctx.Config.Wallets = append(m.D.Ctx.Config.Wallets, newWallet)
+
+		case actionImport:
+			newWallet, err := wallet.ImportPrivateKey(values[0], values[1])
+			if err != nil {
+				return walletError(err)
+			}
+			m.D.Ctx.Config.Wallets = append(m.D.Ctx.Config.Wallets, newWallet)
+
+		case actionImportKeyFile:
+			newWallet, err := wallet.ImportKeyFile(values[0], values[1], values[2])
+			if err != nil {
+				return walletError(err)
+			}
+			m.D.Ctx.Config.Wallets = append(m.D.Ctx.Config.Wallets, newWallet)
+
+		case actionExternal:
+			newWallet, err := wallet.AddExternalWallet(values[0], values[1], values[2])
+			if err != nil {
+				return walletError(err)
+			}
+			m.D.Ctx.Config.Wallets = append(m.D.Ctx.Config.Wallets, newWallet)
+
+		case actionExport:
+			if values[1] != values[2] {
+				return modules.ErrMsg(modules.Error{
+					Message: "Passwords don't match",
+					Help:    "Please enter the same password twice.",
+				})
+			}
+			path, err := wallet.ExportKeyFile(w, values[1], values[0])
+			if err == wallet.ErrKeyFileExists {
+				return modules.ErrMsg(modules.Error{
+					Message: "The key file already exists",
+					Help:    "Please choose another path or a directory, existing files are never overwritten.",
+				})
+			}
+			if err != nil {
+				return walletError(err)
+			}
+			return exportDoneMsg(path)
+
+		case actionRename:
+			w.SetLabel(values[0])
+			if err := database.SaveWallet(w); err != nil {
+				return walletError(err)
+			}
+		}
+		return walletsDoneMsg{}
+	}
+}
+
+func (m *Module) removeWallet(w *database.Wallet) tea.Cmd {
+	return func() tea.Msg {
+		if err := wallet.RemoveWallet(w); err != nil {
+			return walletError(err)
+		}
+		wallets := make([]*database.Wallet, 0, len(m.D.Ctx.Config.Wallets))
+		for _, x := range m.D.Ctx.Config.Wallets {
+			if x != w {
+				wallets = append(wallets, x)
+			}
+		}
+		m.D.Ctx.Config.Wallets = wallets
+		return walletsDoneMsg{}
+	}
+}
+
+func walletError(err error) tea.Msg {
+	return modules.ErrMsg(modules.Error{
+		Message: "Could not update the wallets",
+		Help:    err.Error(),
+	})
+}
+
+func (m *Module) SetHeaderWidth(width int) { m.kv.SetWidth(width) }
+func (m *Module) Header() string {
+	var s strings.Builder
+	s.WriteString(style.GenLogo())
+	s.WriteString("\n\n")
+	s.WriteString(m.kv.View(
+		keyvalue.NewKV("Wallet", m.D.Ctx.Config.Wallet.GetWallet()),
+		keyvalue.NewKV("Wallets", strconv.Itoa(len(m.D.Ctx.Config.AllWallets()))),
+	))
+	return s.String()
+}
+
+func (m *Module) SetContentSize(width, height int) {
+	m.list.SetSize(width, height)
+	for i := range m.inputs {
+		m.inputs[i].Width = width - 1
+	}
+}
+
+func (m *Module) MinContentHeight() int {
+	return 8 // TODO: don't hardcode that value
+}
+
+func (m *Module) Content() string {
+	var s strings.Builder
+	switch m.state {
+	case stateReady:
+		if m.exported != "" {
+			s.WriteString(style.SubtleStyle.Render("exported key file to "+m.exported) + "\n\n")
+		}
+		s.WriteString(m.list.View())
+	case stateInput:
+		switch m.action {
+		case actionDerive:
+			s.WriteString("Enter the index of the address, the derivation path and a label\n\n")
+		case actionImport:
+			s.WriteString("Enter the private key and a label\n\n")
+		case actionImportKeyFile:
+			s.WriteString("Enter the path and the password of the keystore file and a label\n\n")
+		case actionExport:
+			s.WriteString("Enter the path and a password to encrypt the private key\n\n")
+		case actionExternal:
+			s.WriteString("Enter the endpoint of the signer, the address it manages and a label\n\n")
+		case actionRename:
+			s.WriteString("Enter the new label of the wallet\n\n")
+		}
+		for i := range m.inputs {
+			s.WriteString(m.inputs[i].View() + "\n")
+		}
+	}
+	return s.String()
+}
+
+func (m *Module) Error() error { return m.err }
+
+func (m *Module) SetFooterWidth(width int) { m.help.Width = width }
+func (m *Module) Footer() string {
+	switch m.state {
+	case stateReady:
+		return m.help.View(defaultKeys)
+	case stateInput:
+		return m.help.View(inputKeyMap(inputKeys))
+	}
+	return ""
+}
@@ -58,6 +58,10 @@ func (m *Module) genListItems(isMenmonic bool) []item {
 		})
 	}
 	items = append(items, []item{
+		{
+			text:    "Manage trade wallets",
+			forkMsg: modules.ForkMsgWalletSettingsWallets,
+		},
 		{
 			text:    "Set a new mnemonic phrase",
 			forkMsg: modules.ForkMsgWalletSettingsNew,
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jon4hz/deadshot/internal/database"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules"
@@ -34,6 +35,8 @@ const (
 	choiceStopLoss
 	choiceSlippage
 	choiceGasPrice
+	choiceDeadline
+	choiceExpiry
 )
 
 type Model struct {
@@ -44,18 +47,24 @@ type Model struct {
 	width       int
 
 	allowPercentagePrice bool
+	// defaultSlippage is used if no slippage was entered, it's the slippage of the profile or the default slippage.
+	defaultSlippage float64
 
 	priceInput    textinput.Model
 	amountInput   textinput.Model
 	slippageInput textinput.Model
 	gasPriceInput textinput.Model
+	deadlineInput textinput.Model
+	expiryInput   textinput.Model
 	stopLoss      button.Model
 	inclStopLoss  bool
 
 	Help help.Model
 }
 
-func New(tokenSymbol string, allowPercentagePrice, inclStopLoss bool) *Model {
+// New creates the form of a new target. The values of the profile are shown as placeholders
+// and used for the empty fields, a nil profile uses the defaults.
+func New(tokenSymbol string, allowPercentagePrice, inclStopLoss bool, profile *database.Profile) *Model {
 	var menuChoices []menuChoice
 	if inclStopLoss {
 		menuChoices = []menuChoice{
@@ -64,6 +73,8 @@ func New(tokenSymbol string, allowPercentagePrice, inclStopLoss bool) *Model {
 			choiceStopLoss,
 			choiceSlippage,
 			choiceGasPrice,
+			choiceDeadline,
+			choiceExpiry,
 		}
 	} else {
 		menuChoices = []menuChoice{
@@ -72,6 +83,8 @@ func New(tokenSymbol string, allowPercentagePrice, inclStopLoss bool) *Model {
 			choiceStopLoss,
 			choiceSlippage,
 			choiceGasPrice,
+			choiceDeadline,
+			choiceExpiry,
 		}
 	}
 	pi := textinput.NewModel()
@@ -89,11 +102,37 @@ func New(tokenSymbol string, allowPercentagePrice, inclStopLoss bool) *Model {
 	si.Prompt = ""
 	si.CursorStyle = lipgloss.NewStyle().Foreground(style.GetMainColor())
 	si.Placeholder = "1%"
+	defaultSlippage := database.DefaultSlippage
+	if profile != nil && profile.GetSlippage() > 0 {
+		defaultSlippage = profile.GetSlippage()
+		si.Placeholder = fmt.Sprintf("%g%%", defaultSlippage/100)
+	}
 
 	gp := textinput.NewModel()
 	gp.Prompt = ""
 	gp.CursorStyle = lipgloss.NewStyle().Foreground(style.GetMainColor())
 	gp.Placeholder = "1 GWEI"
+	if profile != nil {
+		switch profile.GetGasStrategy() {
+		case database.GasStrategyFixed:
+			gp.Placeholder = fmt.Sprintf("%g GWEI", profile.GetGasPrice())
+		case database.GasStrategyBoost:
+			gp.Placeholder = fmt.Sprintf("network +%g%%", profile.GetGasBoost())
+		}
+	}
+
+	di := textinput.NewModel()
+	di.Prompt = ""
+	di.CursorStyle = lipgloss.NewStyle().Foreground(style.GetMainColor())
+	di.Placeholder = database.DefaultDeadline.String()
+	if profile != nil && profile.GetDeadline() > 0 {
+		di.Placeholder = profile.GetDeadline().String()
+	}
+
+	ei := textinput.NewModel()
+	ei.Prompt = ""
+	ei.CursorStyle = lipgloss.NewStyle().Foreground(style.GetMainColor())
+	ei.Placeholder = "never"
 
 	slTrigger := []string{" ", "x"}
 	slButton := button.New(slTrigger, false)
@@ -105,10 +144,13 @@ func New(tokenSymbol string, allowPercentagePrice, inclStopLoss bool) *Model {
 		amountInput:   ai,
 		slippageInput: si,
 		gasPriceInput: gp,
+		deadlineInput: di,
+		expiryInput:   ei,
 		stopLoss:      slButton,
 		inclStopLoss:  inclStopLoss,
 
 		allowPercentagePrice: allowPercentagePrice,
+		defaultSlippage:      defaultSlippage,
 
 		tokenSymbol: tokenSymbol,
 		Help:        help.New(),
@@ -160,45 +202,31 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		m.slippageInput, cmd = m.slippageInput.Update(msg)
 	case int(choiceGasPrice):
 		m.gasPriceInput, cmd = m.gasPriceInput.Update(msg)
+	case int(choiceDeadline):
+		m.deadlineInput, cmd = m.deadlineInput.Update(msg)
+	case int(choiceExpiry):
+		m.expiryInput, cmd = m.expiryInput.Update(msg)
 	}
 	return cmd
 }
 
 func (m *Model) handleFocus() tea.Cmd {
+	inputs := map[menuChoice]*textinput.Model{
+		choicePrice:    &m.priceInput,
+		choiceAmount:   &m.amountInput,
+		choiceSlippage: &m.slippageInput,
+		choiceGasPrice: &m.gasPriceInput,
+		choiceDeadline: &m.deadlineInput,
+		choiceExpiry:   &m.expiryInput,
+	}
 	var cmd tea.Cmd
-	switch m.menuIndex {
-	case int(choiceAmount):
-		if !m.amountInput.Focused() {
-			cmd = m.amountInput.Focus()
-		}
-		m.priceInput.Blur()
-		m.slippageInput.Blur()
-		m.gasPriceInput.Blur()
-	case int(choicePrice):
-		m.amountInput.Blur()
-		if !m.priceInput.Focused() {
-			cmd = m.priceInput.Focus()
-		}
-		m.slippageInput.Blur()
-		m.gasPriceInput.Blur()
-	case int(choiceStopLoss):
-		m.amountInput.Blur()
-		m.priceInput.Blur()
-		m.slippageInput.Blur()
-		m.gasPriceInput.Blur()
-	case int(choiceSlippage):
-		m.amountInput.Blur()
-		m.priceInput.Blur()
-		if !m.slippageInput.Focused() {
-			cmd = m.slippageInput.Focus()
+	for choice, input := range inputs {
+		if choice != m.menuChoices[m.menuIndex] {
+			input.Blur()
+			continue
 		}
-		m.gasPriceInput.Blur()
-	case int(choiceGasPrice):
-		m.amountInput.Blur()
-		m.priceInput.Blur()
-		m.slippageInput.Blur()
-		if !m.gasPriceInput.Focused() {
-			cmd = m.gasPriceInput.Focus()
+		if !input.Focused() {
+			cmd = input.Focus()
 		}
 	}
 	return cmd
@@ -243,6 +271,14 @@ var (
 		Message: "Invalid gas price",
 		Help:    "Please try another gas price",
 	}
+	errInvalidDeadline = modules.Error{
+		Message: "Invalid deadline",
+		Help:    "Please enter a duration like 5m",
+	}
+	errInvalidExpiry = modules.Error{
+		Message: "Invalid expiry",
+		Help:    "Please enter a duration like 2h, a time like 2022-06-01 18:00 or a block like #15000000",
+	}
 )
 
 func (m *Model) handleMenuChoice() tea.Cmd {
@@ -332,7 +368,7 @@ func (m *Model) handleMenuChoice() tea.Cmd {
 			}
 			slip = s * 100 // support up to 2 decimal places
 		} else {
-			slip = database.DefaultSlippage
+			slip = m.defaultSlippage
 		}
 
 		gasPrice := strings.TrimSpace(m.gasPriceInput.Value())
@@ -359,9 +395,26 @@ func (m *Model) handleMenuChoice() tea.Cmd {
 		} else {
 			gasP = nil
 		}
-		return TargetMsg{
-			Target: database.NewRawTarget(price, amount, exactPrice, exactAmount, slip, gasP, m.stopLoss.Triggered()),
+
+		var deadline *time.Duration
+		if v := strings.TrimSpace(m.deadlineInput.Value()); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				m.err = errInvalidDeadline
+				return nil
+			}
+			deadline = &d
+		}
+		expiry, err := database.ParseExpiry(m.expiryInput.Value(), time.Now())
+		if err != nil {
+			m.err = errInvalidExpiry
+			return nil
 		}
+
+		target := database.NewRawTarget(price, amount, exactPrice, exactAmount, slip, gasP, m.stopLoss.Triggered())
+		target.SetDeadline(deadline)
+		target.SetExpiry(expiry)
+		return TargetMsg{Target: target}
 	}
 }
 
@@ -405,6 +458,18 @@ func (m Model) View() string {
 	}
 	s.WriteString(p + lipgloss.NewStyle().Render("Gas Price: ") + m.gasPriceInput.View() + "\n")
 
+	p = style.GetCustomPrompt()
+	if m.menuChoices[m.menuIndex] == choiceDeadline {
+		p = style.GetFocusedPrompt()
+	}
+	s.WriteString(p + lipgloss.NewStyle().Render("Deadline: ") + m.deadlineInput.View() + "\n")
+
+	p = style.GetCustomPrompt()
+	if m.menuChoices[m.menuIndex] == choiceExpiry {
+		p = style.GetFocusedPrompt()
+	}
+	s.WriteString(p + lipgloss.NewStyle().Render("Expires: ") + m.expiryInput.View() + "\n")
+
 	if !reflect.DeepEqual(m.err, modules.Error{}) {
 		s.WriteString("\n" + m.err.Render(m.width) + "\n")
 	}
@@ -85,7 +85,14 @@ func (i targetListItem) Title() string {
 }
 
 func (i targetListItem) Description() string {
-	return ethutils.ShowSignificant(i.Target.GetAmount(), i.Target.GetAmountDecimals(), 5)
+	s := ethutils.ShowSignificant(i.Target.GetAmount(), i.Target.GetAmountDecimals(), 5)
+	if deadline := i.Target.GetDeadline(); deadline != nil {
+		s += " · deadline " + deadline.String()
+	}
+	if expiry := i.Target.GetExpiry(); !expiry.IsZero() {
+		s += " · expires " + expiry.String()
+	}
+	return s
 }
 
 func (i targetListItem) FilterValue() string {
@@ -295,7 +302,7 @@ func (m *Module) handleMenuChoice() tea.Cmd {
 			token = m.D.Ctx.Token0.GetSymbol()
 		}
 
-		add := addtarget.New(token, m.allowPercentagePrice, m.inclStopLoss)
+		add := addtarget.New(token, m.allowPercentagePrice, m.inclStopLoss, m.D.Ctx.Profile)
 		m.addTarget = add
 		return m.addTarget.Init()
 	}
@@ -308,14 +315,14 @@ func (m *Module) Header() string {
 	s.WriteString(style.GenLogo())
 	s.WriteString("\n\n")
 	kvs := []keyvalue.KeyValue{
-		keyvalue.NewKV("Wallet", m.D.Ctx.Config.Wallet.GetWallet()),
+		keyvalue.NewKV("Wallet", m.D.Ctx.TradeWallet().GetWallet()),
 		keyvalue.NewKV("Endpoint", m.D.Ctx.Endpoint.GetURL()),
 		keyvalue.NewKV("Tokens", m.D.Ctx.Token0.GetSymbol()+" / "+m.D.Ctx.Token1.GetSymbol()),
 		keyvalue.NewKV("Balance",
 			m.D.Ctx.Token0.GetBalanceDecimal(m.D.Ctx.Token0.GetDecimals()).String()+
 				" / "+m.D.Ctx.Token1.GetBalanceDecimal(m.D.Ctx.Token1.GetDecimals()).String(),
 		),
-		keyvalue.NewKV("Exchange", m.D.Ctx.Dex.GetName()),
+		keyvalue.NewKV("Exchange", chain.ExchangeName(m.D.Ctx.Dex, m.D.Ctx.AllDexes)),
 	}
 	var price string
 	p, err := m.D.Ctx.Price.BuyTrade()
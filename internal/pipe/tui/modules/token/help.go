@@ -41,14 +41,22 @@ var defaultKeyMap = keyMap{
 type listKeyMap struct {
 	keyMap
 	Filter key.Binding
+	Star   key.Binding
 }
 
 var listKeys = func() listKeyMap {
 	km := defaultKeyMap
-	return listKeyMap{km, key.NewBinding(
-		key.WithKeys("/"),
-		key.WithHelp("/", "filter"),
-	)}
+	return listKeyMap{
+		km,
+		key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter"),
+		),
+		key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "star"),
+		),
+	}
 }
 
 func (k listKeyMap) ShortHelp() []key.Binding {
@@ -59,7 +67,7 @@ func (k listKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Enter, k.Help},
 		{k.Down, k.Back, k.Quit},
-		{k.Filter},
+		{k.Filter, k.Star},
 	}
 }
 
@@ -125,7 +133,7 @@ func (k filteredKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Enter, k.Help},
 		{k.Down, k.Back, k.Quit},
-		{k.Filter},
+		{k.Filter, k.Star},
 	}
 }
 
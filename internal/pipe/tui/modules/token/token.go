@@ -4,10 +4,13 @@ import (
 	ctx "context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jon4hz/deadshot/internal/context"
 	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logging"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules"
+	"github.com/jon4hz/deadshot/internal/portfolio"
 	"github.com/jon4hz/deadshot/internal/ui/bubbles/keyvalue"
 	"github.com/jon4hz/deadshot/internal/ui/bubbles/simpleview"
 	"github.com/jon4hz/deadshot/internal/ui/style"
@@ -20,19 +23,32 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
 )
 
-const minTokenListHeight = 10
+const (
+	minTokenListHeight  = 10
+	significantDecimals = 6
+	// maxRecentTokens is the number of tokens shown in the recent tokens.
+	maxRecentTokens = 10
+	// priceUpdateInterval is the time between two price updates of the watchlist.
+	priceUpdateInterval = 15 * time.Second
+)
 
 type tokenOption int
 
 const (
 	tokenOptionList tokenOption = iota
+	tokenOptionWatchlist
+	tokenOptionRecent
 	tokenOptionCustom
 )
 
 var tokenOptions = []tokenOption{
 	tokenOptionList,
+	tokenOptionWatchlist,
+	tokenOptionRecent,
 	tokenOptionCustom,
 }
 
@@ -40,6 +56,10 @@ func (t tokenOption) String(m *Module) string {
 	switch t {
 	case tokenOptionList:
 		return "Select a token"
+	case tokenOptionWatchlist:
+		return fmt.Sprintf("Watchlist (%d)", len(database.StarredTokens(m.tokens)))
+	case tokenOptionRecent:
+		return "Recent tokens"
 	case tokenOptionCustom:
 		return m.tokenInput.View()
 	}
@@ -48,11 +68,41 @@ func (t tokenOption) String(m *Module) string {
 
 type tokenListItem struct {
 	token *database.Token
+	// price is the price in the main stable token, it's only set on the watchlist.
+	price string
+}
+
+func (t tokenListItem) Title() string {
+	if t.token.GetStarred() {
+		return t.token.GetSymbol() + " ★"
+	}
+	return t.token.GetSymbol()
 }
 
-func (t tokenListItem) Title() string       { return t.token.GetSymbol() }
-func (t tokenListItem) Description() string { return t.token.GetContract() }
-func (t tokenListItem) FilterValue() string { return t.token.GetSymbol() }
+func (t tokenListItem) Description() string {
+	desc := t.token.GetContract()
+	if name := t.token.GetName(); name != "" {
+		desc = name + " " + desc
+	}
+	if t.price != "" {
+		desc = t.price + " " + desc
+	}
+	return desc
+}
+
+// FilterValue matches the symbol and the name of imported tokens.
+func (t tokenListItem) FilterValue() string { return t.token.GetSymbol() + " " + t.token.GetName() }
+
+// pricesMsg contains the prices of the watchlist by the lower case contract address.
+type pricesMsg struct {
+	prices map[string]decimal.Decimal
+	gen    int
+}
+
+// priceTickMsg triggers the next price update of the watchlist.
+type priceTickMsg struct {
+	gen int
+}
 
 type state int
 
@@ -84,6 +134,16 @@ type Module struct {
 	tokenInput        textinput.Model
 
 	tokenList list.Model
+	// tokens are all saved and predefined tokens of the network.
+	tokens []*database.Token
+	// listOption is the option which filled the token list.
+	listOption tokenOption
+
+	// quote is the main stable token the watchlist is priced in.
+	quote  *database.Token
+	prices map[string]decimal.Decimal
+	// priceGen invalidates the pending price updates when the watchlist is closed.
+	priceGen int
 
 	help help.Model
 	kv   keyvalue.Model
@@ -121,17 +181,15 @@ func (m *Module) Init(c *context.Context) tea.Cmd {
 	m.state = 1
 	m.D.Ctx = c
 	m.err = nil
-	items := make([]list.Item, len(c.Network.GetTokens()))
-	for i, t := range c.Network.GetTokens() {
-		items[i] = tokenListItem{t}
-	}
-	m.tokenList.SetItems(items)
+	m.tokens = loadTokens(c.Network)
+	m.quote = portfolio.MainStable(c.Network)
+	m.prices = make(map[string]decimal.Decimal)
+	m.setTokens(m.tokens, "Tokens")
 	m.tokenList.SetShowHelp(false)
-	m.tokenList.Title = "Tokens"
 	m.tokenList.Styles.Title = style.GetListTitleStyle()
 	m.tokenList.Styles.FilterCursor.Foreground(style.GetMainColor())
 	m.tokenInput.Prompt = ""
-	m.tokenInput.Placeholder = "0xb33EaAd8d922B1083446DC23f610c2567fB5180f"
+	m.tokenInput.Placeholder = "0xb33EaAd8d922B1083446DC23f610c2567fB5180f or a symbol or name"
 	m.tokenInput.CursorStyle = lipgloss.NewStyle().Foreground(style.GetMainColor())
 	m.tokenInput.CharLimit = 64
 
@@ -177,9 +235,12 @@ func (m *Module) updateTokenOption(msg tea.Msg) tea.Cmd {
 
 		case key.Matches(msg, defaultKeyMap.Enter):
 			if tokenOption(m.tokenOptionsIndex) == tokenOptionCustom {
+				token := strings.TrimSpace(m.tokenInput.Value())
+				if token != "" && !strings.HasPrefix(token, "0x") {
+					return m.search(token)
+				}
 				m.state = stateFetchInfo
 
-				token := strings.TrimSpace(m.tokenInput.Value())
 				if !ethutils.IsValidAddress(token) {
 					return func() tea.Msg {
 						return modules.Error{
@@ -196,9 +257,7 @@ func (m *Module) updateTokenOption(msg tea.Msg) tea.Cmd {
 				)
 			}
 
-			m.state = stateTokenList
-			m.err = nil
-			return modules.Resize
+			return m.openList(tokenOption(m.tokenOptionsIndex))
 
 		default:
 			m.tokenOptionsIndex = int(tokenOptionCustom)
@@ -227,6 +286,9 @@ func (m *Module) updateTokenList(msg tea.Msg) tea.Cmd {
 		switch {
 		case key.Matches(msg, defaultKeyMap.Back) && !m.tokenList.SettingFilter() && m.tokenList.FilterState() != list.FilterApplied:
 			m.state = stateTokenOption
+			// stop the price updates and show all tokens again after a search
+			m.priceGen++
+			m.setTokens(m.tokens, "Tokens")
 			return nil
 
 		case key.Matches(msg, defaultKeyMap.Quit) && !m.tokenList.SettingFilter():
@@ -237,20 +299,38 @@ func (m *Module) updateTokenList(msg tea.Msg) tea.Cmd {
 			return modules.Resize
 
 		case key.Matches(msg, defaultKeyMap.Enter) && !m.tokenList.SettingFilter():
-			token := m.tokenList.SelectedItem().(tokenListItem)
+			item, ok := m.tokenList.SelectedItem().(tokenListItem)
+			if !ok {
+				return nil
+			}
+			return m.selectToken(item.token)
 
-			if m.isToken0 {
-				m.D.Ctx.Token0 = token.token
-			} else {
-				m.D.Ctx.Token1 = token.token
+		case key.Matches(msg, listKeys().Star) && !m.tokenList.SettingFilter():
+			item, ok := m.tokenList.SelectedItem().(tokenListItem)
+			if !ok {
+				return nil
 			}
+			return m.toggleStar(item.token)
+		}
 
-			m.state = stateFetchInfo
-			return tea.Batch(
-				m.spinner.Tick,
-				modules.Next,
-			)
+	case pricesMsg:
+		if msg.gen != m.priceGen {
+			return nil
+		}
+		for contract, price := range msg.prices {
+			m.prices[contract] = price
+		}
+		m.refreshList()
+		gen := m.priceGen
+		return tea.Tick(priceUpdateInterval, func(time.Time) tea.Msg {
+			return priceTickMsg{gen}
+		})
+
+	case priceTickMsg:
+		if msg.gen != m.priceGen {
+			return nil
 		}
+		return m.fetchPrices()
 	}
 	var cmd tea.Cmd
 	m.tokenList, cmd = m.tokenList.Update(msg)
@@ -291,6 +371,162 @@ func (m *Module) updateTokenInfo(msg tea.Msg) tea.Cmd {
 	return nil
 }
 
+// search looks up the tokens of the network by symbol or name.
+// A single match is selected right away, multiple matches are shown in the token list.
+func (m *Module) search(query string) tea.Cmd {
+	tokens := database.SearchTokens(m.tokens, query)
+	switch len(tokens) {
+	case 0:
+		m.err = modules.Error{
+			Message: "Token not found",
+			Help:    "No token matches the symbol or name, paste the contract instead or import a token list.",
+		}
+		return nil
+	case 1:
+		return m.selectToken(tokens[0])
+	}
+	m.setTokens(tokens, fmt.Sprintf("Tokens matching %q", query))
+	m.tokenList.ResetSelected()
+	m.listOption = tokenOptionCustom
+	m.state = stateTokenList
+	m.err = nil
+	return modules.Resize
+}
+
+// openList shows the tokens of the option in the token list.
+// The watchlist is priced in the main stable token of the network and updated periodically.
+func (m *Module) openList(option tokenOption) tea.Cmd {
+	m.listOption = option
+	m.err = nil
+	m.refreshList()
+	m.tokenList.ResetSelected()
+	m.state = stateTokenList
+	if option != tokenOptionWatchlist {
+		return modules.Resize
+	}
+	m.priceGen++
+	return tea.Batch(modules.Resize, m.fetchPrices())
+}
+
+// refreshList updates the items of the token list, e.g. after the watchlist or the prices changed.
+func (m *Module) refreshList() {
+	switch m.listOption {
+	case tokenOptionList:
+		m.setTokens(m.tokens, "Tokens")
+	case tokenOptionWatchlist:
+		title := "Watchlist"
+		if m.quote != nil {
+			title = fmt.Sprintf("Watchlist in %s", m.quote.GetSymbol())
+		}
+		m.setTokens(database.StarredTokens(m.tokens), title)
+	case tokenOptionRecent:
+		m.setTokens(database.RecentTokens(m.tokens, maxRecentTokens), "Recent tokens")
+	}
+	// the search results are kept, their items show the stars of the tokens directly
+}
+
+// toggleStar adds the token to or removes it from the watchlist.
+func (m *Module) toggleStar(token *database.Token) tea.Cmd {
+	starred := !token.GetStarred()
+	if err := database.UpdateStarredByContractAndNetworkID(token.GetContract(), m.D.Ctx.Network.GetID(), starred); err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"token": token.GetContract(),
+			"err":   err,
+		}).Error("failed to update the watchlist")
+		m.err = modules.Error{
+			Message: "Failed to update the watchlist",
+			Help:    "Please check the logs for more details.",
+		}
+		return nil
+	}
+	token.SetStarred(starred)
+	m.err = nil
+	m.refreshList()
+	if starred && m.listOption == tokenOptionWatchlist {
+		return m.fetchPrices()
+	}
+	return nil
+}
+
+// fetchPrices fetches the prices of the watchlist in the main stable token.
+func (m *Module) fetchPrices() tea.Cmd {
+	tokens := database.StarredTokens(m.tokens)
+	if m.quote == nil || m.D.Ctx.Client == nil || len(tokens) == 0 {
+		return nil
+	}
+	c, client, network, quote, gen := m.ctx, m.D.Ctx.Client, m.D.Ctx.Network, m.quote, m.priceGen
+	return func() tea.Msg {
+		prices := make(map[string]decimal.Decimal, len(tokens))
+		for _, token := range tokens {
+			if c.Err() != nil {
+				return nil
+			}
+			if price, ok := portfolio.Price(client, network, token, quote); ok {
+				prices[strings.ToLower(token.GetContract())] = price
+			}
+		}
+		return pricesMsg{prices, gen}
+	}
+}
+
+func (m *Module) selectToken(token *database.Token) tea.Cmd {
+	now := time.Now()
+	if err := database.UpdateLastUsedByContractAndNetworkID(token.GetContract(), m.D.Ctx.Network.GetID(), now); err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"token": token.GetContract(),
+			"err":   err,
+		}).Error("failed to save the last use of the token")
+	}
+	token.SetLastUsedAt(now)
+	m.priceGen++
+
+	if m.isToken0 {
+		m.D.Ctx.Token0 = token
+	} else {
+		m.D.Ctx.Token1 = token
+	}
+
+	m.state = stateFetchInfo
+	m.err = nil
+	return tea.Batch(
+		m.spinner.Tick,
+		modules.Next,
+	)
+}
+
+func (m *Module) setTokens(tokens []*database.Token, title string) {
+	items := make([]list.Item, len(tokens))
+	for i, t := range tokens {
+		item := tokenListItem{token: t}
+		if price, ok := m.prices[strings.ToLower(t.GetContract())]; ok && m.listOption == tokenOptionWatchlist {
+			item.price = fmt.Sprintf("%s %s", price.Round(significantDecimals).String(), m.quote.GetSymbol())
+		}
+		items[i] = item
+	}
+	m.tokenList.SetItems(items)
+	m.tokenList.Title = title
+}
+
+// loadTokens returns the tokens of the network together with the tokens saved since the network was loaded.
+func loadTokens(network *database.Network) []*database.Token {
+	tokens := append([]*database.Token(nil), network.GetTokens()...)
+	saved, err := database.FetchTokensByNetworkID(network.GetID())
+	if err != nil {
+		logging.Log.WithField("err", err).Error("failed to fetch the tokens")
+		return tokens
+	}
+	known := make(map[string]bool, len(tokens))
+	for _, t := range tokens {
+		known[strings.ToLower(t.GetContract())] = true
+	}
+	for _, t := range saved {
+		if !known[strings.ToLower(t.GetContract())] {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
 func (m *Module) tokenOptionForward() tea.Cmd {
 	m.tokenOptionsIndex++
 	if m.tokenOptionsIndex >= len(m.tokenOptions) {
@@ -322,7 +558,7 @@ func (m *Module) Header() string {
 	s.WriteString(style.GenLogo())
 	s.WriteString("\n\n")
 	kvs := []keyvalue.KeyValue{
-		keyvalue.NewKV("Wallet", m.D.Ctx.Config.Wallet.GetWallet()),
+		keyvalue.NewKV("Wallet", m.D.Ctx.TradeWallet().GetWallet()),
 		keyvalue.NewKV("Endpoint", m.D.Ctx.Endpoint.GetURL()),
 	}
 
@@ -368,7 +604,7 @@ func (m *Module) Content() string {
 
 func (m *Module) tokenOptionView() string {
 	var s strings.Builder
-	s.WriteString("Please select a token or paste a contract\n\n")
+	s.WriteString("Please select a token, paste a contract or search by symbol or name\n\n")
 	for i := 0; i < len(tokenOptions); i++ {
 		e := "  "
 		if i == m.tokenOptionsIndex && i != int(tokenOptionCustom) {
@@ -13,6 +13,8 @@ import (
 	"github.com/jon4hz/deadshot/internal/ui/bubbles/simpleview"
 	"github.com/jon4hz/deadshot/internal/ui/style"
 
+	chain "github.com/jon4hz/deadshot/internal/blockchain"
+
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
@@ -183,6 +185,10 @@ func (m *Module) Update(msg tea.Msg) tea.Cmd {
 		case stateSelectType:
 			switch {
 			case key.Matches(msg, defaultKeys.Enter):
+				if i, ok := m.list.SelectedItem().(item); ok && i.forkMsg == modules.ForkMsgOrder && m.D.Ctx.WatchOnly() {
+					m.err = modules.ErrWatchOnly
+					return nil
+				}
 				return func() tea.Msg { return m.Fork() }
 			case key.Matches(msg, defaultKeys.Back):
 				return modules.Back
@@ -223,14 +229,14 @@ func (m *Module) Header() string {
 	s.WriteString(style.GenLogo())
 	s.WriteString("\n\n")
 	s.WriteString(m.kv.View(
-		keyvalue.NewKV("Wallet", m.D.Ctx.Config.Wallet.GetWallet()),
+		keyvalue.NewKV("Wallet", m.D.Ctx.TradeWallet().GetWallet()),
 		keyvalue.NewKV("Endpoint", m.D.Ctx.Endpoint.GetURL()),
 		keyvalue.NewKV("Tokens", m.D.Ctx.Token0.GetSymbol()+" / "+m.D.Ctx.Token1.GetSymbol()),
 		keyvalue.NewKV("Balance",
 			m.D.Ctx.Token0.GetBalanceDecimal(m.D.Ctx.Token0.GetDecimals()).String()+
 				" / "+m.D.Ctx.Token1.GetBalanceDecimal(m.D.Ctx.Token1.GetDecimals()).String(),
 		),
-		keyvalue.NewKV("Exchange", m.D.Ctx.Dex.GetName()),
+		keyvalue.NewKV("Exchange", chain.ExchangeName(m.D.Ctx.Dex, m.D.Ctx.AllDexes)),
 	))
 	return s.String()
 }
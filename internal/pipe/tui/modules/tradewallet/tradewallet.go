@@ -0,0 +1,195 @@
+package tradewallet
+
+import (
+	ctx "context"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/context"
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/pipe/tui/modules"
+	"github.com/jon4hz/deadshot/internal/ui/bubbles/keyvalue"
+	"github.com/jon4hz/deadshot/internal/ui/bubbles/simpleview"
+	"github.com/jon4hz/deadshot/internal/ui/style"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const minWalletListHeight = 10
+
+type walletListItem struct {
+	wallet *database.Wallet
+}
+
+func (i walletListItem) Title() string {
+	if label := i.wallet.GetLabel(); label != "" {
+		return label
+	}
+	return i.wallet.GetWallet()
+}
+
+func (i walletListItem) Description() string { return i.wallet.GetWallet() }
+func (i walletListItem) FilterValue() string { return i.Title() }
+
+type state int
+
+const (
+	stateUnknown state = iota
+	stateReady
+)
+
+var (
+	_ modules.Module          = (*Module)(nil)
+	_ modules.ModulePiper     = (*Module)(nil)
+	_ simpleview.SimpleViewer = (*Module)(nil)
+)
+
+// Module lets the user pick the wallet which executes the trade.
+type Module struct {
+	ctx    ctx.Context
+	cancel ctx.CancelFunc
+	D      modules.Default
+	state  state
+	err    error
+	help   help.Model
+	kv     keyvalue.Model
+
+	walletList list.Model
+}
+
+func NewModule(module *modules.Default) *Module {
+	del := list.NewDefaultDelegate()
+	del.Styles.SelectedDesc.Foreground(style.GetMainColor()).BorderForeground(style.GetSecondColor())
+	del.Styles.SelectedTitle.Foreground(style.GetMainColor()).BorderForeground(style.GetSecondColor())
+	return &Module{
+		D: modules.Default{
+			PrePipe:  module.PrePipe,
+			Pipe:     module.Pipe,
+			PostPipe: module.PostPipe,
+		},
+		cancel:     func() {},
+		help:       help.New(),
+		kv:         keyvalue.New(),
+		walletList: list.New(nil, del, 0, 0),
+	}
+}
+
+func (m *Module) Cancel()        { m.cancel() }
+func (m *Module) State() int     { return int(m.state) }
+func (m *Module) String() string { return "trade wallet module" }
+
+// Skip skips the module if there is only one wallet to trade with.
+// In that case the trade falls back to the main wallet.
+func (m *Module) Skip(ctx *context.Context) bool {
+	if len(tradeWallets(ctx)) < 2 {
+		ctx.Wallet = nil
+		return true
+	}
+	return false
+}
+
+// tradeWallets returns all wallets which can sign transactions.
+func tradeWallets(ctx *context.Context) []*database.Wallet {
+	var wallets []*database.Wallet
+	for _, w := range ctx.Config.AllWallets() {
+		if w.GetSigner() != nil {
+			wallets = append(wallets, w)
+		}
+	}
+	return wallets
+}
+
+func (m *Module) Init(c *context.Context) tea.Cmd {
+	m.ctx, m.cancel = ctx.WithCancel(c)
+	m.state = 1
+	m.D.Ctx = c
+	m.err = nil
+
+	wallets := tradeWallets(c)
+	items := make([]list.Item, len(wallets))
+	for i, w := range wallets {
+		items[i] = walletListItem{wallet: w}
+	}
+	m.walletList.SetItems(items)
+	m.walletList.SetShowHelp(false)
+	m.walletList.SetFilteringEnabled(false)
+	m.walletList.Title = "Please select the wallet you want to trade with"
+	m.walletList.Styles.Title = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFF")) // TODO replace color with adaptive color
+
+	return modules.Resize
+}
+
+func (m *Module) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, defaultKeyMap.Back):
+			m.D.Ctx.Wallet = nil
+			return modules.Back
+
+		case key.Matches(msg, defaultKeyMap.Quit):
+			return tea.Quit
+
+		case key.Matches(msg, defaultKeyMap.Help):
+			m.help.ShowAll = !m.help.ShowAll
+			return modules.Resize
+
+		case key.Matches(msg, defaultKeyMap.Enter):
+			m.D.Ctx.Wallet = m.walletList.SelectedItem().(walletListItem).wallet
+			return modules.Next
+		}
+	}
+	switch m.state {
+	case stateReady:
+		var cmd tea.Cmd
+		m.walletList, cmd = m.walletList.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *Module) SetHeaderWidth(width int) { m.kv.SetWidth(width) }
+func (m *Module) Header() string {
+	var s strings.Builder
+	s.WriteString(style.GenLogo())
+	s.WriteString("\n\n")
+	s.WriteString(m.kv.View(
+		keyvalue.NewKV("Wallet", m.D.Ctx.TradeWallet().GetWallet()),
+		keyvalue.NewKV("Network", m.D.Ctx.Network.GetFullName()),
+	))
+	return s.String()
+}
+
+func (m *Module) SetContentSize(width, height int) {
+	m.walletList.SetSize(width, height)
+}
+
+func (m *Module) MinContentHeight() int {
+	return minWalletListHeight
+}
+
+func (m *Module) Content() string {
+	var s strings.Builder
+	switch m.state {
+	case stateReady:
+		s.WriteString(m.walletList.View())
+	}
+	return s.String()
+}
+func (m *Module) Error() error { return m.err }
+
+func (m *Module) SetFooterWidth(width int) { m.help.Width = width }
+func (m *Module) Footer() string {
+	switch m.state {
+	case stateReady:
+		return m.help.View(defaultKeyMap)
+	}
+	return ""
+}
+
+func (m *Module) PrePipe() []modules.Piper  { return m.D.PrePipe }
+func (m *Module) Pipe() []modules.Piper     { return m.D.Pipe }
+func (m *Module) PostPipe() []modules.Piper { return m.D.PostPipe }
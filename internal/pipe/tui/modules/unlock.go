@@ -0,0 +1,45 @@
+package modules
+
+import (
+	"errors"
+
+	"github.com/jon4hz/deadshot/internal/context"
+	"github.com/jon4hz/deadshot/internal/wallet"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type (
+	// UnlockedMsg is sent after the wallets were unlocked again.
+	UnlockedMsg struct{ password string }
+	// UnlockErrMsg is sent if the wallets couldn't be unlocked, e.g. because of a wrong password.
+	UnlockErrMsg struct{ Err error }
+)
+
+// Unlock loads the private keys of the wallets again after they were locked.
+func Unlock(ctx *context.Context, password string) tea.Cmd {
+	return func() tea.Msg {
+		if err := wallet.Unlock(password, ctx.Config.Wallet, ctx.Config.Wallets); err != nil {
+			return UnlockErrMsg{err}
+		}
+		return UnlockedMsg{password}
+	}
+}
+
+// SetUnlocked marks the wallets of the context as unlocked.
+// It must be called from the update function of the module which received the message.
+func (msg UnlockedMsg) SetUnlocked(ctx *context.Context) {
+	ctx.Cfg.Password = msg.password
+	ctx.SetUnlocked()
+}
+
+// Readable returns the error in a form which can be shown to the user.
+func (msg UnlockErrMsg) Readable() error {
+	if errors.Is(msg.Err, wallet.ErrWrongPassword) {
+		return Error{
+			Message: "wrong password",
+			Help:    "please, try again",
+		}
+	}
+	return msg.Err
+}
@@ -11,6 +11,7 @@ import (
 	tokenPipe "github.com/jon4hz/deadshot/internal/pipe/token"
 	"github.com/jon4hz/deadshot/internal/pipe/trade"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules"
+	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/allowances"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/endpoint"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/exchange"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/keyderivation"
@@ -19,14 +20,19 @@ import (
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/menu"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/network"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/order"
+	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/portfolio"
+	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/profile"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/quit"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/secret"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/settings"
 	settingsEndpoint "github.com/jon4hz/deadshot/internal/pipe/tui/modules/settings/endpoint"
+	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/settings/networks"
+	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/settings/wallets"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/settings/walletsettings"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/target"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/token"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/tradetype"
+	"github.com/jon4hz/deadshot/internal/pipe/tui/modules/tradewallet"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -82,6 +88,12 @@ func newQuit() modules.Module {
 func newTradePipeline() []modules.Module {
 	ms := []modules.Module{
 		network.NewModule(&modules.Default{}),
+		keystore.NewUnlockModule(&modules.Default{
+			PostPipe: []modules.Piper{
+				keystorePipe.Unlock{},
+			},
+		}),
+		tradewallet.NewModule(&modules.Default{}),
 		endpoint.NewModule(&modules.Default{
 			Pipe: []modules.Piper{
 				&endpointPipe.Pipe{},
@@ -100,6 +112,7 @@ func newTradePipeline() []modules.Module {
 			},
 		}, false),
 		exchange.NewModule(&modules.Default{}),
+		profile.NewModule(&modules.Default{}),
 		tradetype.NewModule(&modules.Default{
 			Pipe: []modules.Piper{
 				&listing.Pipe{},
@@ -154,6 +167,18 @@ var newOrderPipeline = func() []modules.Module {
 	return ms
 }
 
+var newPortfolioPipeline = func() []modules.Module {
+	ms := []modules.Module{
+		portfolio.New(&modules.Default{}),
+	}
+
+	m := ms[0].(*portfolio.Module) // Make sure we have the right type
+	m.D.ForkBackMsg = modules.ForkBackMsg(len(ms))
+	ms[0] = m
+
+	return ms
+}
+
 var newSettingsPipeline = func() []modules.Module {
 	ms := []modules.Module{
 		settings.New(&modules.Default{}),
@@ -179,6 +204,42 @@ var newSettingsEndpointPipeline = func() []modules.Module {
 	return ms
 }
 
+var newSettingsAllowancesPipeline = func() []modules.Module {
+	ms := []modules.Module{
+		network.NewModule(&modules.Default{}),
+		keystore.NewUnlockModule(&modules.Default{
+			PostPipe: []modules.Piper{
+				keystorePipe.Unlock{},
+			},
+		}),
+		tradewallet.NewModule(&modules.Default{}),
+		endpoint.NewModule(&modules.Default{
+			Pipe: []modules.Piper{
+				&endpointPipe.Pipe{},
+			},
+		}),
+		allowances.New(&modules.Default{}),
+	}
+
+	mn := ms[0].(*network.Module) // Make sure we have the right type
+	mn.D.ForkBackMsg = modules.ForkBackMsg(len(ms))
+	ms[0] = mn
+
+	return ms
+}
+
+var newSettingsNetworksPipeline = func() []modules.Module {
+	ms := []modules.Module{
+		networks.New(&modules.Default{}),
+	}
+
+	m := ms[0].(*networks.Module) // Make sure we have the right type
+	m.D.ForkBackMsg = modules.ForkBackMsg(len(ms))
+	ms[0] = m
+
+	return ms
+}
+
 var newSettingsWalletPipeline = func() []modules.Module {
 	ms := []modules.Module{
 		walletsettings.New(&modules.Default{}),
@@ -190,6 +251,17 @@ var newSettingsWalletPipeline = func() []modules.Module {
 	return ms
 }
 
+var newSettingsWalletWalletsPipeline = func() []modules.Module {
+	ms := []modules.Module{
+		wallets.New(&modules.Default{}),
+	}
+	m := ms[0].(*wallets.Module) // Make sure we have the right type
+	m.D.ForkBackMsg = modules.ForkBackMsg(len(ms))
+	ms[0] = m
+
+	return ms
+}
+
 var newSettingsWalletDerivationPipeline = func() []modules.Module {
 	ms := []modules.Module{
 		keyderivation.NewModule(
@@ -4,20 +4,36 @@ import (
 	ctx "context"
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/jon4hz/deadshot/internal/context"
 	"github.com/jon4hz/deadshot/internal/logging"
 	"github.com/jon4hz/deadshot/internal/middleware/logger"
 	"github.com/jon4hz/deadshot/internal/middleware/skip"
 	"github.com/jon4hz/deadshot/internal/middleware/tuihandler"
+	keystorePipe "github.com/jon4hz/deadshot/internal/pipe/keystore"
 	"github.com/jon4hz/deadshot/internal/pipe/tui/modules"
 	"github.com/jon4hz/deadshot/internal/ui/bubbles/simpleview"
 	"github.com/jon4hz/deadshot/internal/ui/style"
 
+	chain "github.com/jon4hz/deadshot/internal/blockchain"
+
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-type postPipeDoneMsg struct{}
+type (
+	postPipeDoneMsg struct{}
+	lockTickMsg     struct{}
+)
+
+// lockCheckInterval is the interval in which the tui checks whether the wallets should be locked.
+const lockCheckInterval = time.Second * 5
+
+// hotkeys of the kill switch, they work in every module.
+const (
+	haltKey     = "ctrl+k"
+	haltSellKey = "ctrl+x"
+)
 
 type Tui struct {
 	cm                 modules.Module
@@ -48,11 +64,21 @@ func newTui(c *context.Context) Tui {
 }
 
 func (t Tui) Init() tea.Cmd {
-	return tea.Batch(
+	cmds := []tea.Cmd{
 		pipeMsgListener(t.pipeMsgChan),
 		pipeCancelFuncListener(t.pipeCancelFuncChan),
 		modules.Init,
-	)
+	}
+	if t.ctx.Cfg.LockTimeout > 0 {
+		cmds = append(cmds, lockTick())
+	}
+	return tea.Batch(cmds...)
+}
+
+func lockTick() tea.Cmd {
+	return tea.Tick(lockCheckInterval, func(time.Time) tea.Msg {
+		return lockTickMsg{}
+	})
 }
 
 func pipeMsgListener(pipeMsgChan chan string) tea.Cmd {
@@ -271,6 +297,19 @@ func (t Tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case modules.PipeMsg:
 		cmds = append(cmds, pipeMsgListener(t.pipeMsgChan))
 
+	case tea.KeyMsg:
+		t.ctx.LastActivity = time.Now()
+		switch msg.String() {
+		case haltKey, haltSellKey:
+			return t, halt(msg.String() == haltSellKey)
+		}
+
+	case lockTickMsg:
+		if cmd := t.maybeLock(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		cmds = append(cmds, lockTick())
+
 	case modules.PipeCancelFuncMsg:
 		cmds = append(cmds, pipeCancelFuncListener(t.pipeCancelFuncChan))
 
@@ -307,6 +346,11 @@ func (t *Tui) handleFork(msg modules.ForkMsg) tea.Cmd {
 		t.modules = append(t.modules, newSwapPipeline()...)
 		return modules.Next
 
+	case modules.ForkMsgPortfolio:
+		logging.Log.WithField("ForkMsg", "portfolio").Debug("new pipeline")
+		t.modules = append(t.modules, newPortfolioPipeline()...)
+		return modules.Next
+
 	case modules.ForkMsgSettings:
 		logging.Log.WithField("ForkMsg", "settings").Debug("new pipeline")
 		t.modules = append(t.modules, newSettingsPipeline()...)
@@ -317,6 +361,16 @@ func (t *Tui) handleFork(msg modules.ForkMsg) tea.Cmd {
 		t.modules = append(t.modules, newSettingsEndpointPipeline()...)
 		return modules.Next
 
+	case modules.ForkMsgAllowances:
+		logging.Log.WithField("ForkMsg", "settings allowances").Debug("new pipeline")
+		t.modules = append(t.modules, newSettingsAllowancesPipeline()...)
+		return modules.Next
+
+	case modules.ForkMsgNetworks:
+		logging.Log.WithField("ForkMsg", "settings networks").Debug("new pipeline")
+		t.modules = append(t.modules, newSettingsNetworksPipeline()...)
+		return modules.Next
+
 	case modules.ForkMsgWalletSettings:
 		logging.Log.WithField("ForkMsg", "settings wallet").Debug("new pipeline")
 		t.modules = append(t.modules, newSettingsWalletPipeline()...)
@@ -332,6 +386,11 @@ func (t *Tui) handleFork(msg modules.ForkMsg) tea.Cmd {
 		t.modules = append(t.modules, newSettingsWalletDerivationPipeline()...)
 		return modules.Next
 
+	case modules.ForkMsgWalletSettingsWallets:
+		logging.Log.WithField("ForkMsg", "settings wallet wallets").Debug("new pipeline")
+		t.modules = append(t.modules, newSettingsWalletWalletsPipeline()...)
+		return modules.Next
+
 	case modules.ForkMsgQuit:
 		logging.Log.WithField("ForkMsg", "quit").Debug("new pipeline")
 		t.modules = append(t.modules, newQuit())
@@ -341,6 +400,38 @@ func (t *Tui) handleFork(msg modules.ForkMsg) tea.Cmd {
 	return nil
 }
 
+// maybeLock locks the wallets if there was no user input within the lock timeout.
+// The wallets aren't locked while a pipeline is running, the next tick tries again.
+func (t *Tui) maybeLock() tea.Cmd {
+	if !t.ctx.Unlocked || time.Since(t.ctx.LastActivity) < t.ctx.Cfg.LockTimeout {
+		return nil
+	}
+	if !t.mu.TryLock() {
+		return nil
+	}
+	defer t.mu.Unlock()
+	logging.Log.WithField("timeout", t.ctx.Cfg.LockTimeout).Info("locking the wallets after inactivity")
+	keystorePipe.Lock(t.ctx)
+	return modules.Locked
+}
+
+// halt triggers the kill switch and reports it to the current module.
+func halt(sell bool) tea.Cmd {
+	return func() tea.Msg {
+		if err := chain.Halt("halted from the tui", sell); err != nil {
+			logging.Log.WithField("err", err).Error("failed to halt trading")
+			return modules.ErrMsg(modules.Error{
+				Message: "Trading halted with errors",
+				Help:    err.Error(),
+			})
+		}
+		return modules.ErrMsg(modules.Error{
+			Message: "Trading halted",
+			Help:    "All orders were stopped. Run deadshot resume to trade again.",
+		})
+	}
+}
+
 func (t *Tui) setSimpleviewerSizes() {
 	switch module := t.cm.(type) {
 	case simpleview.SimpleViewer:
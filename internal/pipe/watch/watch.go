@@ -0,0 +1,27 @@
+package watch
+
+import (
+	"errors"
+
+	"github.com/jon4hz/deadshot/internal/context"
+	"github.com/jon4hz/deadshot/internal/database"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrInvalidAddress = errors.New("the watched address is invalid")
+
+type Pipe struct{}
+
+func (Pipe) String() string                 { return "watch-only mode" }
+func (Pipe) Skip(ctx *context.Context) bool { return ctx.Cfg.Watch == "" }
+
+// Run replaces the wallets with a watch-only wallet of the address.
+func (Pipe) Run(ctx *context.Context) error {
+	if !common.IsHexAddress(ctx.Cfg.Watch) {
+		return ErrInvalidAddress
+	}
+	ctx.Config.Wallet = database.NewWatchOnlyWallet(common.HexToAddress(ctx.Cfg.Watch).Hex())
+	ctx.Config.Wallets = nil
+	return nil
+}
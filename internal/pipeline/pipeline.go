@@ -6,6 +6,7 @@ import (
 	"github.com/jon4hz/deadshot/internal/context"
 	"github.com/jon4hz/deadshot/internal/pipe/istty"
 	"github.com/jon4hz/deadshot/internal/pipe/ui"
+	"github.com/jon4hz/deadshot/internal/pipe/watch"
 )
 
 type Piper interface {
@@ -18,6 +19,7 @@ type Piper interface {
 var NewPipeline = func() []Piper {
 	return []Piper{
 		istty.Pipe{},
+		watch.Pipe{},
 		ui.Pipe{},
 	}
 }
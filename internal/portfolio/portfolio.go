@@ -0,0 +1,199 @@
+package portfolio
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/pkg/ethutils"
+
+	chain "github.com/jon4hz/deadshot/internal/blockchain"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultQuote is the symbol of the token the holdings are valued in by default.
+const DefaultQuote = "USDC"
+
+// Quotes are the stable tokens the holdings can be valued in.
+var Quotes = []string{"USDC", "USDT", "DAI", "BUSD"}
+
+// maxHops is the maximum number of hops of the route used to value a holding.
+const maxHops = 3
+
+// Holding is the balance of a single token.
+type Holding struct {
+	Symbol   string          `json:"symbol"`
+	Contract string          `json:"contract"`
+	Balance  decimal.Decimal `json:"balance"`
+	// Value is the balance in the quote token, it's only set if Valued is true.
+	Value decimal.Decimal `json:"value"`
+	// Valued is false if there is no route to the quote token.
+	Valued bool `json:"valued"`
+}
+
+// Network contains all holdings of a network.
+type Network struct {
+	Network  string          `json:"network"`
+	Holdings []*Holding      `json:"holdings"`
+	Total    decimal.Decimal `json:"total"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// Portfolio contains the holdings of a wallet across all networks.
+type Portfolio struct {
+	Time     time.Time       `json:"time"`
+	Wallet   string          `json:"wallet"`
+	Quote    string          `json:"quote"`
+	Networks []*Network      `json:"networks"`
+	Total    decimal.Decimal `json:"total"`
+}
+
+// Load fetches the balances of the address for all tokens of the networks and values them in the quote token.
+// The portfolio is read-only, the balances are neither set on the tokens nor saved in the database
+// because the address doesn't have to be the trade wallet. The networks are loaded concurrently. Tokens with a zero balance and networks without any holdings are left out.
+func Load(ctx context.Context, networks []*database.Network, address, quote string) *Portfolio {
+	if quote == "" {
+		quote = DefaultQuote
+	}
+	p := &Portfolio{
+		Time:   time.Now(),
+		Wallet: address,
+		Quote:  strings.ToUpper(quote),
+	}
+
+	results := make([]*Network, len(networks))
+	var wg sync.WaitGroup
+	for i, network := range networks {
+		wg.Add(1)
+		go func(i int, network *database.Network) {
+			defer wg.Done()
+			results[i] = loadNetwork(ctx, network, address, quote)
+		}(i, network)
+	}
+	wg.Wait()
+
+	for _, n := range results {
+		if len(n.Holdings) == 0 && n.Error == "" {
+			continue
+		}
+		p.Networks = append(p.Networks, n)
+		p.Total = p.Total.Add(n.Total)
+	}
+	return p
+}
+
+func loadNetwork(ctx context.Context, network *database.Network, address, quote string) *Network {
+	n := &Network{Network: network.GetName()}
+	tokens := network.GetTokens()
+	if len(tokens) == 0 {
+		return n
+	}
+	client, err := chain.Connect(network)
+	if err != nil {
+		n.Error = err.Error()
+		return n
+	}
+	defer client.Close()
+
+	balances, err := client.GetTokenBalances(address, tokens...)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"network": network.GetName(),
+			"error":   err,
+		}).Error("failed to get the token balances")
+		n.Error = err.Error()
+		return n
+	}
+
+	quoteToken := findToken(tokens, quote)
+	for _, token := range tokens {
+		balance, ok := balances[token.GetContract()]
+		if !ok {
+			continue
+		}
+		if balance.Sign() == 0 {
+			continue
+		}
+		if ctx.Err() != nil {
+			n.Error = ctx.Err().Error()
+			return n
+		}
+		h := &Holding{
+			Symbol:   token.GetSymbol(),
+			Contract: token.GetContract(),
+			Balance:  ethutils.ToDecimal(balance, token.GetDecimals()),
+		}
+		if quoteToken != nil {
+			h.Value, h.Valued = value(client, network, token, quoteToken, balance)
+		}
+		n.Holdings = append(n.Holdings, h)
+		n.Total = n.Total.Add(h.Value)
+	}
+	sortHoldings(n.Holdings)
+	return n
+}
+
+// value returns the value of the balance in the quote token using the best route across all dexes of the network.
+func value(client *chain.Client, network *database.Network, token, quote *database.Token, balance *big.Int) (decimal.Decimal, bool) {
+	if strings.EqualFold(token.GetContract(), quote.GetContract()) {
+		return ethutils.ToDecimal(balance, token.GetDecimals()), true
+	}
+	q, err := client.GetBestTradeExactInAllDexes(token, quote, balance, network.GetDexes(), network.GetTokens(), maxHops, network.GetWETH())
+	if err != nil || q == nil || q.Best == nil {
+		logging.Log.WithFields(logrus.Fields{
+			"network": network.GetName(),
+			"token":   token.GetSymbol(),
+			"quote":   quote.GetSymbol(),
+			"error":   err,
+		}).Debug("no route to value the holding")
+		return decimal.Zero, false
+	}
+	return ethutils.ToDecimal(q.Best.Trade.OutputAmount().Raw(), quote.GetDecimals()), true
+}
+
+// MainStable returns the main stable token of the network, the first of the Quotes it knows, or nil if there is none.
+func MainStable(network *database.Network) *database.Token {
+	for _, symbol := range Quotes {
+		if token := findToken(network.GetTokens(), symbol); token != nil {
+			return token
+		}
+	}
+	return nil
+}
+
+// Price returns the price of one token in the quote token using the best route across all dexes of the network.
+func Price(client *chain.Client, network *database.Network, token, quote *database.Token) (decimal.Decimal, bool) {
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(token.GetDecimals())), nil)
+	return value(client, network, token, quote, unit)
+}
+
+// findToken returns the first token with the symbol or nil if there is none.
+func findToken(tokens []*database.Token, symbol string) *database.Token {
+	for _, token := range tokens {
+		if strings.EqualFold(token.GetSymbol(), symbol) {
+			return token
+		}
+	}
+	return nil
+}
+
+// sortHoldings sorts the holdings by value, holdings without a value come last ordered by symbol.
+func sortHoldings(holdings []*Holding) {
+	sort.SliceStable(holdings, func(i, j int) bool {
+		a, b := holdings[i], holdings[j]
+		if a.Valued != b.Valued {
+			return a.Valued
+		}
+		if !a.Value.Equal(b.Value) {
+			return a.Value.GreaterThan(b.Value)
+		}
+		return a.Symbol < b.Symbol
+	})
+}
@@ -0,0 +1,38 @@
+package portfolio
+
+import (
+	"testing"
+
+	"github.com/jon4hz/deadshot/internal/database"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestFindToken(t *testing.T) {
+	tokens := []*database.Token{
+		database.NewToken("0x0000000000000000000000000000000000000001", "WETH", 18, false, nil),
+		database.NewToken("0x0000000000000000000000000000000000000002", "USDC", 6, false, nil),
+	}
+	if token := findToken(tokens, "usdc"); token != tokens[1] {
+		t.Errorf("findToken(usdc) = %v, want %v", token, tokens[1])
+	}
+	if token := findToken(tokens, "DAI"); token != nil {
+		t.Errorf("findToken(DAI) = %v, want nil", token)
+	}
+}
+
+func TestSortHoldings(t *testing.T) {
+	holdings := []*Holding{
+		{Symbol: "B"},
+		{Symbol: "SMALL", Value: decimal.NewFromInt(1), Valued: true},
+		{Symbol: "A"},
+		{Symbol: "BIG", Value: decimal.NewFromInt(100), Valued: true},
+	}
+	sortHoldings(holdings)
+	want := []string{"BIG", "SMALL", "A", "B"}
+	for i, h := range holdings {
+		if h.Symbol != want[i] {
+			t.Errorf("holdings[%d] = %s, want %s", i, h.Symbol, want[i])
+		}
+	}
+}
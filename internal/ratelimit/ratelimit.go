@@ -1,14 +1,54 @@
 package ratelimit
 
-import "time"
+import (
+	"strings"
+	"sync"
+	"time"
+)
 
 // common rate limits for the rpc endpoints per millisecond.
 var rateLimits = map[string]float64{
 	"https://rpc-mainnet.maticvigil.com": 0.0117, // 700 per minute to be precise
 }
 
-func GetPriceFeedInterval(url string) time.Duration {
+// Limit is the rate limit of an rpc endpoint.
+type Limit struct {
+	URL string `yaml:"url"`
+	// PerMinute is the number of requests which are allowed per minute.
+	PerMinute float64 `yaml:"per_minute" mapstructure:"per_minute"`
+}
+
+var (
+	mu sync.RWMutex
+	// configured are the rate limits per millisecond from the config, they take precedence over the common rate limits.
+	configured = make(map[string]float64)
+)
+
+// Set replaces the configured rate limits.
+func Set(limits []Limit) {
+	m := make(map[string]float64, len(limits))
+	for _, l := range limits {
+		if l.PerMinute > 0 {
+			m[strings.TrimSpace(l.URL)] = l.PerMinute / float64(time.Minute/time.Millisecond)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	configured = m
+}
+
+func getLimit(url string) (float64, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if limit, ok := configured[url]; ok {
+		return limit, true
+	}
 	limit, ok := rateLimits[url]
+	return limit, ok
+}
+
+func GetPriceFeedInterval(url string) time.Duration {
+	limit, ok := getLimit(url)
 	if !ok {
 		return 0
 	}
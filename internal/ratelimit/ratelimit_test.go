@@ -11,3 +11,19 @@ func TestGetPriceFeedInterval(t *testing.T) {
 	interval := GetPriceFeedInterval("https://rpc-mainnet.maticvigil.com")
 	assert.Equal(t, interval, time.Duration(170940170))
 }
+
+func TestSet(t *testing.T) {
+	defer Set(nil)
+	Set([]Limit{
+		{URL: "https://rpc-mainnet.maticvigil.com", PerMinute: 60},
+		{URL: "https://rpc.example", PerMinute: 600},
+		{URL: "https://unlimited.example"},
+	})
+	// a configured limit replaces the common limit
+	assert.Equal(t, 2*time.Second, GetPriceFeedInterval("https://rpc-mainnet.maticvigil.com"))
+	assert.Equal(t, 200*time.Millisecond, GetPriceFeedInterval("https://rpc.example"))
+	assert.Equal(t, time.Duration(0), GetPriceFeedInterval("https://unlimited.example"))
+
+	Set(nil)
+	assert.Equal(t, time.Duration(170940170), GetPriceFeedInterval("https://rpc-mainnet.maticvigil.com"))
+}
@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/jon4hz/deadshot/internal/database"
+
+	chain "github.com/jon4hz/deadshot/internal/blockchain"
+)
+
+// DefaultInterval is the default time between two scans.
+const DefaultInterval = time.Second * 10
+
+// Scanner periodically scans a network for arbitrage opportunities.
+type Scanner struct {
+	client   *chain.Client
+	network  *database.Network
+	tokens   []*database.Token
+	opts     *chain.ArbitrageOptions
+	interval time.Duration
+}
+
+// Result is the result of a single scan.
+type Result struct {
+	Time          time.Time            `json:"time"`
+	Network       string               `json:"network"`
+	Opportunities []*chain.Opportunity `json:"opportunities"`
+	Error         string               `json:"error,omitempty"`
+}
+
+// New creates a new scanner for the tokens of the network.
+func New(client *chain.Client, network *database.Network, tokens []*database.Token, opts *chain.ArbitrageOptions, interval time.Duration) *Scanner {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Scanner{
+		client:   client,
+		network:  network,
+		tokens:   tokens,
+		opts:     opts,
+		interval: interval,
+	}
+}
+
+// Scan runs a single scan.
+func (s *Scanner) Scan() *Result {
+	r := &Result{
+		Time:    time.Now(),
+		Network: s.network.GetName(),
+	}
+	opportunities, err := s.client.ScanArbitrage(s.network, s.tokens, s.opts)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	r.Opportunities = opportunities
+	return r
+}
+
+// Run scans until the context is canceled and sends every result to the channel.
+// The channel is closed when the scanner stops.
+func (s *Scanner) Run(ctx context.Context, results chan<- *Result) {
+	defer close(results)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case results <- s.Scan():
+		case <-ctx.Done():
+			return
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RunJSON scans until the context is canceled and writes every result as a JSON line.
+func (s *Scanner) RunJSON(ctx context.Context, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan *Result)
+	go s.Run(ctx, results)
+	enc := json.NewEncoder(w)
+	for r := range results {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/ui/style"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// decimal places of the amounts in the table
+const tablePlaces = 6
+
+type resultMsg *Result
+
+type doneMsg struct{}
+
+// Model is a live updating table of the scan results.
+type Model struct {
+	scanner *Scanner
+	ctx     context.Context
+	cancel  context.CancelFunc
+	results chan *Result
+	table   table.Model
+	last    *Result
+	width   int
+}
+
+// NewModel creates a new tui model for the scanner.
+func NewModel(s *Scanner) *Model {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := table.New(
+		table.WithColumns([]table.Column{
+			{Title: "Kind", Width: 10},
+			{Title: "Path", Width: 28},
+			{Title: "Dexes", Width: 28},
+			{Title: "In", Width: 14},
+			{Title: "Out", Width: 14},
+			{Title: "Gas", Width: 10},
+			{Title: "Net", Width: 10},
+			{Title: "%", Width: 7},
+		}),
+		table.WithFocused(true),
+	)
+	return &Model{
+		scanner: s,
+		ctx:     ctx,
+		cancel:  cancel,
+		results: make(chan *Result),
+		table:   t,
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	go m.scanner.Run(m.ctx, m.results)
+	return m.listen
+}
+
+func (m *Model) listen() tea.Msg {
+	r, ok := <-m.results
+	if !ok {
+		return doneMsg{}
+	}
+	return resultMsg(r)
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			m.cancel()
+			return m, tea.Quit
+		}
+
+	case tea.WindowSizeMsg:
+		top, right, bottom, left := style.DocStyle.GetMargin()
+		m.width = msg.Width - right - left
+		// leave space for the header and the footer
+		m.table.SetHeight(msg.Height - top - bottom - 4)
+		m.table.SetWidth(m.width)
+
+	case resultMsg:
+		m.last = msg
+		if msg.Error == "" {
+			m.table.SetRows(rows(msg))
+		}
+		return m, m.listen
+
+	case doneMsg:
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+func rows(r *Result) []table.Row {
+	rows := make([]table.Row, len(r.Opportunities))
+	for i, o := range r.Opportunities {
+		rows[i] = table.Row{
+			o.Kind,
+			strings.Join(o.Path, " → "),
+			strings.Join(o.Dexes, ", "),
+			fmt.Sprintf("%s %s", o.AmountIn.StringFixed(tablePlaces), o.Token),
+			fmt.Sprintf("%s %s", o.AmountOut.StringFixed(tablePlaces), o.Token),
+			o.GasCost.StringFixed(tablePlaces),
+			o.NetProfit.StringFixed(tablePlaces),
+			o.ProfitPercent.StringFixed(2),
+		}
+	}
+	return rows
+}
+
+func (m *Model) View() string {
+	var header string
+	switch {
+	case m.last == nil:
+		header = style.SubtleStyle.Render("Scanning...")
+	case m.last.Error != "":
+		header = style.ErrStyle.Render(fmt.Sprintf("Scan failed at %s: %s", m.last.Time.Format("15:04:05"), m.last.Error))
+	default:
+		header = style.SubtleStyle.Render(fmt.Sprintf("%s: %d opportunities at %s",
+			m.last.Network, len(m.last.Opportunities), m.last.Time.Format("15:04:05")))
+	}
+	footer := style.SubtleStyle.Render("↑/↓: navigate • q: quit")
+	return style.DocStyle.Render(lipgloss.JoinVertical(lipgloss.Left, header, "", m.table.View(), "", footer))
+}
@@ -0,0 +1,73 @@
+package signer
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/jon4hz/deadshot/internal/logging"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrUnknownAccount = errors.New("the external signer doesn't manage the address")
+	ErrWrongSender    = errors.New("the external signer signed with a different address")
+)
+
+var _ Signer = (*Clef)(nil)
+
+// Clef signs with an external signer which speaks the clef external api.
+// The private key never leaves the signer process.
+type Clef struct {
+	signer  *external.ExternalSigner
+	account accounts.Account
+}
+
+// NewClef connects to the external signer at the endpoint.
+// The endpoint is either an http url or the path of an ipc socket.
+// The signer must manage the address.
+func NewClef(endpoint string, address common.Address) (*Clef, error) {
+	signer, err := external.NewExternalSigner(endpoint)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error":    err,
+			"endpoint": endpoint,
+		}).Error("failed to connect to external signer")
+		return nil, err
+	}
+	for _, account := range signer.Accounts() {
+		if account.Address == address {
+			return &Clef{
+				signer:  signer,
+				account: account,
+			}, nil
+		}
+	}
+	return nil, ErrUnknownAccount
+}
+
+func (c *Clef) Address() common.Address { return c.account.Address }
+
+func (c *Clef) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signed, err := c.signer.SignTx(c.account, tx, chainID)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error":   err,
+			"address": c.account.Address.String(),
+		}).Error("external signer failed to sign transaction")
+		return nil, err
+	}
+	// never trust the signer blindly
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return nil, err
+	}
+	if sender != c.account.Address {
+		return nil, ErrWrongSender
+	}
+	return signed, nil
+}
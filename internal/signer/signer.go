@@ -0,0 +1,64 @@
+// Package signer signs transactions with an in-memory key or an external signer.
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var ErrNoSigner = errors.New("the wallet doesn't have a signer")
+
+// Signer signs the transactions of a single address.
+type Signer interface {
+	// Address returns the address of the signer.
+	Address() common.Address
+	// SignTx signs the transaction for the chain.
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// NewTransactor returns the transact options for contract bindings which sign with the signer.
+func NewTransactor(s Signer, chainID *big.Int) (*bind.TransactOpts, error) {
+	if s == nil {
+		return nil, ErrNoSigner
+	}
+	from := s.Address()
+	return &bind.TransactOpts{
+		From: from,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != from {
+				return nil, bind.ErrNotAuthorized
+			}
+			return s.SignTx(tx, chainID)
+		},
+		Context: context.Background(),
+	}, nil
+}
+
+var _ Signer = (*Key)(nil)
+
+// Key signs with a private key held in memory.
+type Key struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewKey returns a signer for the private key.
+func NewKey(key *ecdsa.PrivateKey) *Key {
+	return &Key{
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+}
+
+func (k *Key) Address() common.Address { return k.address }
+
+func (k *Key) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), k.key)
+}
@@ -0,0 +1,144 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// clefStandIn implements the parts of the clef external api used by the signer.
+type clefStandIn struct {
+	key *ecdsa.PrivateKey
+}
+
+type signTransactionResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+func (c *clefStandIn) Version() string { return "6.1.0" }
+
+func (c *clefStandIn) List() []common.Address {
+	return []common.Address{crypto.PubkeyToAddress(c.key.PublicKey)}
+}
+
+func (c *clefStandIn) SignTransaction(args apitypes.SendTxArgs) (*signTransactionResult, error) {
+	tx, err := types.SignTx(args.ToTransaction(), types.LatestSignerForChainID(args.ChainID.ToInt()), c.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &signTransactionResult{Raw: raw, Tx: tx}, nil
+}
+
+func newClefAPI(t *testing.T) ([]rpc.API, *ecdsa.PrivateKey) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []rpc.API{{
+		Namespace: "account",
+		Service:   &clefStandIn{key: key},
+	}}, key
+}
+
+func testTx() *types.Transaction {
+	to := common.HexToAddress("0x10ED43C718714eb63d5aA57B78B54704E256024E")
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    7,
+		GasPrice: big.NewInt(5e9),
+		Gas:      21000,
+		To:       &to,
+		Value:    big.NewInt(1e18),
+	})
+}
+
+func checkSigner(t *testing.T, s Signer, want common.Address) {
+	t.Helper()
+	chainID := big.NewInt(56)
+	opts, err := NewTransactor(s, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := opts.Signer(want, testTx())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender != want {
+		t.Errorf("expected sender %s, got %s", want, sender)
+	}
+	if signed.Nonce() != 7 || signed.Value().Cmp(big.NewInt(1e18)) != 0 {
+		t.Error("the signer changed the transaction")
+	}
+	if _, err := opts.Signer(common.Address{}, testTx()); err == nil {
+		t.Error("expected an error for a different address")
+	}
+}
+
+func TestKey(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSigner(t, NewKey(key), crypto.PubkeyToAddress(key.PublicKey))
+
+	if _, err := NewTransactor(nil, big.NewInt(1)); err != ErrNoSigner {
+		t.Errorf("expected %v, got %v", ErrNoSigner, err)
+	}
+}
+
+func TestClefHTTP(t *testing.T) {
+	apis, key := newClefAPI(t)
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName(apis[0].Namespace, apis[0].Service); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	s, err := NewClef(ts.URL, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSigner(t, s, address)
+
+	if _, err := NewClef(ts.URL, common.Address{1}); err != ErrUnknownAccount {
+		t.Errorf("expected %v, got %v", ErrUnknownAccount, err)
+	}
+}
+
+func TestClefIPC(t *testing.T) {
+	apis, key := newClefAPI(t)
+	endpoint := filepath.Join(t.TempDir(), "clef.ipc")
+	listener, server, err := rpc.StartIPCEndpoint(endpoint, apis)
+	if err != nil {
+		t.Skipf("ipc not available: %v", err)
+	}
+	defer server.Stop()
+	defer listener.Close()
+
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	s, err := NewClef(endpoint, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSigner(t, s, address)
+}
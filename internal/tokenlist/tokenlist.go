@@ -0,0 +1,144 @@
+// Package tokenlist imports token lists in the uniswap token list format, see https://tokenlists.org.
+package tokenlist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/pkg/ethutils"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxListSize is the maximum size of a token list in bytes.
+	maxListSize  = 20 << 20
+	fetchTimeout = 30 * time.Second
+)
+
+var ErrEmptyList = errors.New("token list contains no tokens")
+
+// List is a token list.
+type List struct {
+	Name   string  `json:"name"`
+	Tokens []Token `json:"tokens"`
+}
+
+// Token is a token of a token list.
+type Token struct {
+	ChainID  uint32   `json:"chainId"`
+	Address  string   `json:"address"`
+	Name     string   `json:"name"`
+	Symbol   string   `json:"symbol"`
+	Decimals int      `json:"decimals"`
+	LogoURI  string   `json:"logoURI"`
+	Tags     []string `json:"tags"`
+}
+
+// valid returns whether the token can be imported.
+func (t Token) valid() bool {
+	return ethutils.IsValidAddress(t.Address) && t.Symbol != "" && t.Decimals >= 0 && t.Decimals <= 255
+}
+
+// toDatabaseToken converts the token to a database token.
+func (t Token) toDatabaseToken() *database.Token {
+	token := database.NewToken(t.Address, t.Symbol, uint8(t.Decimals), false, nil)
+	token.Name = t.Name
+	token.LogoURI = t.LogoURI
+	token.Tags = strings.Join(t.Tags, ",")
+	return token
+}
+
+// Parse reads a token list.
+func Parse(r io.Reader) (*List, error) {
+	var l List
+	if err := json.NewDecoder(io.LimitReader(r, maxListSize)).Decode(&l); err != nil {
+		return nil, fmt.Errorf("invalid token list: %w", err)
+	}
+	if len(l.Tokens) == 0 {
+		return nil, ErrEmptyList
+	}
+	return &l, nil
+}
+
+// Load reads a token list from a local file or a http(s) url.
+func Load(ctx context.Context, source string) (*List, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return Parse(f)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"url":   source,
+			"error": err,
+		}).Error("failed to fetch the token list")
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch the token list: %s", res.Status)
+	}
+	return Parse(res.Body)
+}
+
+// Result is the result of an import on a network.
+type Result struct {
+	Network *database.Network
+	Added   int
+	Updated int
+}
+
+// Import adds the tokens of the list to the networks with the matching chain id.
+// Tokens of other chains and invalid tokens are skipped and counted.
+func Import(l *List, networks database.Networks) (results []Result, skipped int, err error) {
+	byChain := make(map[uint32][]*database.Token)
+	for _, t := range l.Tokens {
+		if !t.valid() {
+			skipped++
+			continue
+		}
+		byChain[t.ChainID] = append(byChain[t.ChainID], t.toDatabaseToken())
+	}
+	for chainID, tokens := range byChain {
+		var n *database.Network
+		for _, network := range networks {
+			if network.GetChainID() == chainID {
+				n = network
+				break
+			}
+		}
+		if n == nil {
+			skipped += len(tokens)
+			continue
+		}
+		added, updated, err := database.ImportTokens(n, tokens)
+		if err != nil {
+			return results, skipped, fmt.Errorf("network %s: %w", n.GetName(), err)
+		}
+		results = append(results, Result{Network: n, Added: added, Updated: updated})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Network.GetName() < results[j].Network.GetName() })
+	return results, skipped, nil
+}
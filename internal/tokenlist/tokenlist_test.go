@@ -0,0 +1,82 @@
+package tokenlist
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jon4hz/deadshot/internal/database"
+)
+
+func init() {
+	if err := database.InitDB(); err != nil {
+		panic(err)
+	}
+}
+
+const testList = `{
+  "name": "Test List",
+  "tokens": [
+    {"chainId": 1341, "address": "0x0000000000000000000000000000000000000011", "name": "Test Token", "symbol": "TST", "decimals": 18, "logoURI": "ipfs://logo", "tags": ["stablecoin"]},
+    {"chainId": 1341, "address": "0x0000000000000000000000000000000000000012", "name": "Other Token", "symbol": "OTH", "decimals": 6},
+    {"chainId": 1341, "address": "invalid", "name": "Invalid", "symbol": "INV", "decimals": 18},
+    {"chainId": 99999, "address": "0x0000000000000000000000000000000000000013", "name": "Unknown Chain", "symbol": "UNK", "decimals": 18}
+  ]
+}`
+
+func TestParse(t *testing.T) {
+	l, err := Parse(strings.NewReader(testList))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Name != "Test List" || len(l.Tokens) != 4 {
+		t.Errorf("unexpected list %s with %d tokens", l.Name, len(l.Tokens))
+	}
+	if _, err := Parse(strings.NewReader(`{"name": "empty", "tokens": []}`)); err != ErrEmptyList {
+		t.Errorf("expected %v, got %v", ErrEmptyList, err)
+	}
+}
+
+func TestImport(t *testing.T) {
+	n := &database.Network{
+		Name:      "deadshot-tokenlist-test",
+		ChainID:   1341,
+		Endpoints: database.Endpoints{database.NewEndpoint("http://localhost:8545", false)},
+	}
+	if err := database.CreateNetwork(n); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := database.DeleteNetwork(n); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	l, err := Parse(strings.NewReader(testList))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, skipped, err := Import(l, database.Networks{n})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipped != 2 {
+		t.Errorf("expected 2 skipped tokens, got %d", skipped)
+	}
+	if len(results) != 1 || results[0].Added != 2 {
+		t.Fatalf("unexpected results %+v", results)
+	}
+
+	// a second import doesn't add the tokens twice
+	results, _, err = Import(l, database.Networks{n})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Added != 0 || len(n.GetTokens()) != 2 {
+		t.Errorf("expected no new tokens, got %d added and %d tokens", results[0].Added, len(n.GetTokens()))
+	}
+
+	found := database.SearchTokens(n.GetTokens(), "test")
+	if len(found) != 1 || found[0].GetSymbol() != "TST" || found[0].GetTags()[0] != "stablecoin" {
+		t.Errorf("unexpected search result %v", found)
+	}
+}
@@ -0,0 +1,66 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/99designs/keyring"
+	ethkeystore "github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+// backupKind identifies the encrypted database backups of deadshot.
+const backupKind = "deadshot-backup"
+
+// ErrNotEncrypted is returned if a backup isn't encrypted.
+var ErrNotEncrypted = errors.New("the backup isn't encrypted")
+
+// encryptedBackup is an encrypted database backup.
+// The data is encrypted like the private key of a keystore v3 file.
+type encryptedBackup struct {
+	Kind    string                 `json:"kind"`
+	Version int                    `json:"version"`
+	Crypto  ethkeystore.CryptoJSON `json:"crypto"`
+}
+
+// EncryptBackup encrypts a database backup with the password.
+func EncryptBackup(data []byte, password string) ([]byte, error) {
+	if password == "" {
+		return nil, ErrEmptyPassword
+	}
+	c, err := ethkeystore.EncryptDataV3(data, []byte(password), keyFileScryptN, keyFileScryptP)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(encryptedBackup{Kind: backupKind, Version: 1, Crypto: c})
+}
+
+// IsEncryptedBackup returns whether the data is an encrypted database backup.
+func IsEncryptedBackup(data []byte) bool {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return false
+	}
+	var b encryptedBackup
+	return json.Unmarshal(data, &b) == nil && b.Kind == backupKind
+}
+
+// DecryptBackup decrypts a database backup which was encrypted with EncryptBackup.
+func DecryptBackup(data []byte, password string) ([]byte, error) {
+	var b encryptedBackup
+	if err := json.Unmarshal(data, &b); err != nil || b.Kind != backupKind {
+		return nil, ErrNotEncrypted
+	}
+	return ethkeystore.DecryptDataV3(b.Crypto, password)
+}
+
+// CheckPassword opens the keystore with the password and returns an error if it's wrong.
+// A keystore without a secret accepts every password.
+func CheckPassword(password string) error {
+	if err := InitKeystore(password, ""); err != nil {
+		return err
+	}
+	if _, err := getSecret(); err != nil && !errors.Is(err, keyring.ErrKeyNotFound) {
+		return err
+	}
+	return nil
+}
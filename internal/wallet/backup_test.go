@@ -0,0 +1,38 @@
+package wallet
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	ethkeystore "github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+func TestEncryptBackup(t *testing.T) {
+	keyFileScryptN, keyFileScryptP = ethkeystore.LightScryptN, ethkeystore.LightScryptP
+	data := []byte("SQLite format 3\x00 deadshot")
+
+	if IsEncryptedBackup(data) {
+		t.Error("a sqlite file isn't an encrypted backup")
+	}
+	if _, err := EncryptBackup(data, ""); !errors.Is(err, ErrEmptyPassword) {
+		t.Errorf("expected %v, got %v", ErrEmptyPassword, err)
+	}
+	enc, err := EncryptBackup(data, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncryptedBackup(enc) || bytes.Contains(enc, []byte("SQLite format")) {
+		t.Error("the backup should be encrypted")
+	}
+	if _, err := DecryptBackup(enc, "wrong"); !errors.Is(err, ethkeystore.ErrDecrypt) {
+		t.Errorf("expected %v, got %v", ethkeystore.ErrDecrypt, err)
+	}
+	dec, err := DecryptBackup(enc, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(dec, data) {
+		t.Errorf("decrypted %q, want %q", dec, data)
+	}
+}
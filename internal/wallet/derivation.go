@@ -0,0 +1,111 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/logging"
+
+	hdwallet "github.com/miguelmota/go-ethereum-hdwallet"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultDerivationPath is the derivation path used by metamask, trezor and most other wallets.
+const DefaultDerivationPath = "m/44'/60'/0'/0/%d"
+
+// indexPlaceholder is replaced by the index of the wallet.
+const indexPlaceholder = "%d"
+
+var ErrInvalidDerivationPath = errors.New("the derivation path must contain at most one %d for the index")
+
+// DerivationPreset is a commonly used derivation path.
+type DerivationPreset struct {
+	Name string
+	Path string
+}
+
+// DerivationPresets are the derivation paths of popular wallets.
+var DerivationPresets = []DerivationPreset{
+	{Name: "Default (MetaMask, Trezor)", Path: DefaultDerivationPath},
+	{Name: "Ledger Live", Path: "m/44'/60'/%d'/0/0"},
+	{Name: "Ledger Legacy (MEW, MyCrypto)", Path: "m/44'/60'/0'/%d"},
+}
+
+// ValidateDerivationPath checks whether the template is a valid derivation path.
+// A template without %d is a fixed path, e.g. m/44'/60'/0'/0/7.
+func ValidateDerivationPath(template string) error {
+	placeholders := strings.Count(template, indexPlaceholder)
+	if placeholders > 1 || strings.Count(template, "%") != placeholders {
+		return ErrInvalidDerivationPath
+	}
+	path := template
+	if placeholders == 1 {
+		path = fmt.Sprintf(template, 0)
+	}
+	_, err := hdwallet.ParseDerivationPath(path)
+	return err
+}
+
+// IsFixedDerivationPath returns whether the template is a fixed path which doesn't depend on the index.
+func IsFixedDerivationPath(template string) bool {
+	return template != "" && !strings.Contains(template, indexPlaceholder)
+}
+
+// derivationPath returns the derivation path of the template for the index.
+// An empty template falls back to the default derivation path, a fixed path is used as is.
+func derivationPath(template string, index uint) (string, error) {
+	if template == "" {
+		template = DefaultDerivationPath
+	}
+	if err := ValidateDerivationPath(template); err != nil {
+		return "", err
+	}
+	if IsFixedDerivationPath(template) {
+		return template, nil
+	}
+	return fmt.Sprintf(template, index), nil
+}
+
+// GetAddrsByPath returns count addresses of the mnemonic phrase starting at the index offset.
+// A fixed path has only a single address at offset 0.
+func GetAddrsByPath(mnemonic, template string, offset, count uint) ([]string, error) {
+	if IsFixedDerivationPath(template) {
+		if offset > 0 {
+			return nil, nil
+		}
+		count = 1
+	}
+	wallet, err := hdwallet.NewFromMnemonic(mnemonic)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Error("error creating wallet from mnemonic")
+		return nil, err
+	}
+	addresses := make([]string, count)
+	for i := uint(0); i < count; i++ {
+		p, err := derivationPath(template, offset+i)
+		if err != nil {
+			return nil, err
+		}
+		path, err := hdwallet.ParseDerivationPath(p)
+		if err != nil {
+			logging.Log.WithFields(logrus.Fields{
+				"error": err.Error(),
+				"path":  p,
+			}).Error("error parsing derivation path")
+			return nil, err
+		}
+		account, err := wallet.Derive(path, false)
+		if err != nil {
+			logging.Log.WithFields(logrus.Fields{
+				"error": err.Error(),
+				"path":  p,
+			}).Error("error deriving account")
+			return nil, err
+		}
+		addresses[i] = account.Address.Hex()
+	}
+	return addresses, nil
+}
@@ -0,0 +1,69 @@
+package wallet
+
+import "testing"
+
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestValidateDerivationPath(t *testing.T) {
+	tests := []struct {
+		path  string
+		valid bool
+	}{
+		{DefaultDerivationPath, true},
+		{"m/44'/60'/%d'/0/0", true},
+		{"m/44'/60'/0'/%d", true},
+		{"m/44'/60'/0'/0/7", true},
+		{"m/44'/60'/%d'/0/%d", false},
+		{"m/44'/60'/%s'/0/0", false},
+		{"m/44'/60'/x/%d", false},
+	}
+	for _, tt := range tests {
+		if err := ValidateDerivationPath(tt.path); (err == nil) != tt.valid {
+			t.Errorf("%s: expected valid=%t, got %v", tt.path, tt.valid, err)
+		}
+	}
+}
+
+func TestGetAddrsByPath(t *testing.T) {
+	addresses, err := GetAddrsByPath(testMnemonic, "", 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		"0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+	}
+	for i := range want {
+		if addresses[i] != want[i] {
+			t.Errorf("index %d: expected %s, got %s", i, want[i], addresses[i])
+		}
+	}
+
+	// a fixed path is used as is, independent of the index
+	fixed, err := GetAddrsByPath(testMnemonic, "m/44'/60'/0'/0/1", 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixed) != 1 || fixed[0] != want[1] {
+		t.Errorf("expected only %s, got %v", want[1], fixed)
+	}
+	if fixed, err := GetAddrByPath(testMnemonic, "m/44'/60'/0'/0/1", 5); err != nil || fixed != want[1] {
+		t.Errorf("expected %s, got %s, %v", want[1], fixed, err)
+	}
+
+	// the first address of ledger live uses the same path as the default
+	ledger, err := GetAddrByPath(testMnemonic, "m/44'/60'/%d'/0/0", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ledger != want[0] {
+		t.Errorf("expected %s, got %s", want[0], ledger)
+	}
+	ledger, err = GetAddrByPath(testMnemonic, "m/44'/60'/%d'/0/0", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ledger == want[1] {
+		t.Errorf("expected a different address than %s", want[1])
+	}
+}
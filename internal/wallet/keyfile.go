@@ -0,0 +1,137 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logging"
+
+	ethkeystore "github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// scrypt parameters of exported key files.
+var (
+	keyFileScryptN = ethkeystore.StandardScryptN
+	keyFileScryptP = ethkeystore.StandardScryptP
+)
+
+var (
+	ErrNoPrivateKey  = errors.New("the wallet doesn't have a private key")
+	ErrEmptyPassword = errors.New("the password must not be empty")
+	ErrKeyFileExists = errors.New("the key file already exists")
+)
+
+// IsKeyFile returns whether the path points to an existing file.
+// It's used to tell key files apart from secrets which are entered directly.
+func IsKeyFile(path string) bool {
+	info, err := os.Stat(expandHome(path))
+	return err == nil && !info.IsDir()
+}
+
+// DecryptKeyFile decrypts a web3 secret storage (keystore v3) file
+// and returns the private key as hex string.
+func DecryptKeyFile(path, password string) (string, error) {
+	data, err := os.ReadFile(expandHome(path))
+	if err != nil {
+		return "", err
+	}
+	key, err := ethkeystore.DecryptKey(data, password)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error": err,
+			"path":  path,
+		}).Error("failed to decrypt key file")
+		return "", err
+	}
+	return hex.EncodeToString(crypto.FromECDSA(key.PrivateKey)), nil
+}
+
+// ImportKeyFile decrypts a keystore v3 file and adds its wallet.
+func ImportKeyFile(path, password, label string) (*database.Wallet, error) {
+	secret, err := DecryptKeyFile(path, password)
+	if err != nil {
+		return nil, err
+	}
+	return ImportPrivateKey(secret, label)
+}
+
+// ExportKeyFile encrypts the private key of the wallet in the keystore v3 format.
+// If the path is a directory, the file is named like the key files of geth.
+// An existing file is never overwritten.
+// It returns the path of the written file.
+func ExportKeyFile(w *database.Wallet, password, path string) (string, error) {
+	key := w.GetPrivateKey()
+	if key == nil {
+		return "", ErrNoPrivateKey
+	}
+	if password == "" {
+		return "", ErrEmptyPassword
+	}
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return "", err
+	}
+	data, err := ethkeystore.EncryptKey(&ethkeystore.Key{
+		Id:         id,
+		Address:    crypto.PubkeyToAddress(key.PublicKey),
+		PrivateKey: key,
+	}, password, keyFileScryptN, keyFileScryptP)
+	if err != nil {
+		return "", err
+	}
+
+	path = expandHome(path)
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, keyFileName(w.GetWallet()))
+	}
+	if err := writeNewFile(path, data); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return "", ErrKeyFileExists
+		}
+		logging.Log.WithFields(logrus.Fields{
+			"error": err,
+			"path":  path,
+		}).Error("failed to write key file")
+		return "", err
+	}
+	return path, nil
+}
+
+// writeNewFile writes the data to a new file and fails if the file already exists.
+func writeNewFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+// keyFileName returns the file name geth uses for key files, e.g. UTC--2022-09-01T10-00-00.000000000Z--<address>.
+func keyFileName(address string) string {
+	ts := time.Now().UTC().Format("2006-01-02T15-04-05.000000000Z")
+	return fmt.Sprintf("UTC--%s--%s", ts, strings.ToLower(strings.TrimPrefix(address, "0x")))
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
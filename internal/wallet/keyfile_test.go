@@ -0,0 +1,84 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jon4hz/deadshot/internal/database"
+
+	ethkeystore "github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestKeyFile(t *testing.T) {
+	keyFileScryptN, keyFileScryptP = ethkeystore.LightScryptN, ethkeystore.LightScryptP
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &database.Wallet{Wallet: crypto.PubkeyToAddress(key.PublicKey).Hex()}
+	if _, err := ExportKeyFile(w, "secret", t.TempDir()); err != ErrNoPrivateKey {
+		t.Fatalf("expected %v, got %v", ErrNoPrivateKey, err)
+	}
+	w.SetPrivateKey(key)
+
+	dir := t.TempDir()
+	path, err := ExportKeyFile(w, "secret", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(path) != dir {
+		t.Errorf("expected the key file in %s, got %s", dir, path)
+	}
+	if !IsKeyFile(path) {
+		t.Errorf("expected %s to be a key file", path)
+	}
+
+	if _, err := DecryptKeyFile(path, "wrong"); err != ethkeystore.ErrDecrypt {
+		t.Errorf("expected %v, got %v", ethkeystore.ErrDecrypt, err)
+	}
+	secret, err := DecryptKeyFile(path, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := hex.EncodeToString(crypto.FromECDSA(key)); secret != want {
+		t.Errorf("expected %s, got %s", want, secret)
+	}
+}
+
+func TestExportKeyFileExists(t *testing.T) {
+	keyFileScryptN, keyFileScryptP = ethkeystore.LightScryptN, ethkeystore.LightScryptP
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &database.Wallet{Wallet: crypto.PubkeyToAddress(key.PublicKey).Hex()}
+	w.SetPrivateKey(key)
+
+	path := filepath.Join(t.TempDir(), "key.json")
+	if _, err := ExportKeyFile(w, "secret", path); err != nil {
+		t.Fatal(err)
+	}
+	first, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExportKeyFile(w, "other", path); err != ErrKeyFileExists {
+		t.Fatalf("expected %v, got %v", ErrKeyFileExists, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, data) {
+		t.Error("expected the existing key file to be unchanged")
+	}
+	if _, err := DecryptKeyFile(path, "secret"); err != nil {
+		t.Errorf("expected the first key file to decrypt, got %v", err)
+	}
+}
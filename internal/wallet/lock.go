@@ -0,0 +1,36 @@
+package wallet
+
+import (
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logging"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Lock wipes the private keys of the wallets from memory.
+// The keystore is opened again without a password, so the secrets can't be read
+// until the wallets are unlocked.
+func Lock(wallets ...*database.Wallet) {
+	for _, w := range wallets {
+		if w != nil {
+			w.Lock()
+		}
+	}
+	if err := InitKeystore("", ""); err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error": err,
+		}).Error("failed to reset the keystore")
+	}
+}
+
+// Unlock opens the keystore with the password and loads the private keys of the main and the additional wallets again.
+func Unlock(password string, main *database.Wallet, wallets []*database.Wallet) error {
+	if err := InitKeystore(password, ""); err != nil {
+		return err
+	}
+	if err := Load(main); err != nil {
+		return err
+	}
+	LoadWallets(wallets)
+	return nil
+}
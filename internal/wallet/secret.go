@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/jon4hz/deadshot/internal/database"
 	"github.com/jon4hz/deadshot/internal/logging"
@@ -20,6 +21,7 @@ const (
 	defaultServiceName     = "deadshot.wallet.eth"
 	defaultKeyringName     = "deadshot"
 	keystoreFileIdentifier = "file"
+	mainWalletLabel        = "main"
 )
 
 var (
@@ -159,9 +161,7 @@ func Load(cfg *database.Wallet) error {
 	if err != nil {
 		return err
 	}
-	cfg.NewWallet(address, cfg.GetWalletIndex(), "trade")
-
-	return nil
+	return cfg.NewWallet(address, cfg.GetWalletIndex(), cfg.GetDerivationPath(), mainWalletLabel)
 }
 
 func getSecret() (string, error) {
@@ -175,7 +175,8 @@ func getSecret() (string, error) {
 // Set sets the secret in the keystore.
 // If the secret already exists, it will be overwritten.
 // Set will also set the private key in the wallet config.
-func Set(secret string, tradeWalletIndex uint, cfg *database.Wallet) error {
+// The derivation path template is only used for mnemonic phrases.
+func Set(secret string, tradeWalletIndex uint, derivationPath string, cfg *database.Wallet) error {
 	if cfg == nil {
 		return errors.New("config is nil")
 	}
@@ -187,12 +188,13 @@ func Set(secret string, tradeWalletIndex uint, cfg *database.Wallet) error {
 
 	var address string
 	if bip39.IsMnemonicValid(secret) {
-		addr, err := GetAddrByIndex(secret, tradeWalletIndex) //nolint:govet
+		addr, err := GetAddrByPath(secret, derivationPath, tradeWalletIndex) //nolint:govet
 		if err != nil {
 			return err
 		}
 		address = addr
 	} else {
+		derivationPath = ""
 		privateKey, err := crypto.HexToECDSA(secret) //nolint:govet
 		if err != nil {
 			logging.Log.WithFields(logrus.Fields{
@@ -207,7 +209,7 @@ func Set(secret string, tradeWalletIndex uint, cfg *database.Wallet) error {
 		address = addr
 	}
 
-	err = cfg.NewWallet(address, tradeWalletIndex, "trade")
+	err = cfg.NewWallet(address, tradeWalletIndex, derivationPath, mainWalletLabel)
 	if err != nil {
 		return err
 	}
@@ -236,9 +238,21 @@ func SearchMnemonic() (string, bool, error) {
 	return secret, true, nil
 }
 
+// RemoveSecret removes the main secret and the private keys of all imported wallets.
 func RemoveSecret() error {
 	if keystore == nil {
 		return ErrKeyringNotInitialized
 	}
+	keys, err := keystore.Keys()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if strings.HasPrefix(key, defaultServiceName+".") {
+			if err := keystore.Remove(key); err != nil {
+				return err
+			}
+		}
+	}
 	return keystore.Remove(defaultServiceName)
 }
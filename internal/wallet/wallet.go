@@ -3,7 +3,6 @@ package wallet
 import (
 	"crypto/ecdsa"
 	"errors"
-	"fmt"
 
 	"github.com/jon4hz/deadshot/internal/database"
 	"github.com/jon4hz/deadshot/internal/logging"
@@ -43,7 +42,10 @@ func extractPrivateKey(cfg *database.Wallet, secret string) (*ecdsa.PrivateKey,
 			}).Error("error creating hdwallet from mnemonic")
 			return nil, err
 		}
-		p := fmt.Sprintf("m/44'/60'/0'/0/%d", cfg.GetWalletIndex())
+		p, err := derivationPath(cfg.GetDerivationPath(), cfg.GetWalletIndex())
+		if err != nil {
+			return nil, err
+		}
 		path, err := hdwallet.ParseDerivationPath(p)
 		if err != nil {
 			logging.Log.WithFields(logrus.Fields{
@@ -93,6 +95,11 @@ func getAddressFromKey(key *ecdsa.PrivateKey) (string, error) {
 
 // GetAddrByIndex returns the address from the mnemonic phrase based on the index.
 func GetAddrByIndex(mnemonic string, index uint) (string, error) {
+	return GetAddrByPath(mnemonic, DefaultDerivationPath, index)
+}
+
+// GetAddrByPath returns the address from the mnemonic phrase based on the derivation path template and the index.
+func GetAddrByPath(mnemonic, template string, index uint) (string, error) {
 	wallet, err := hdwallet.NewFromMnemonic(mnemonic)
 	if err != nil {
 		logging.Log.WithFields(logrus.Fields{
@@ -100,7 +107,10 @@ func GetAddrByIndex(mnemonic string, index uint) (string, error) {
 		}).Error("error creating wallet from mnemonic")
 		return "", err
 	}
-	p := fmt.Sprintf("m/44'/60'/0'/0/%d", index)
+	p, err := derivationPath(template, index)
+	if err != nil {
+		return "", err
+	}
 	path, err := hdwallet.ParseDerivationPath(p)
 	if err != nil {
 		logging.Log.WithFields(logrus.Fields{
@@ -0,0 +1,190 @@
+package wallet
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jon4hz/deadshot/internal/database"
+	"github.com/jon4hz/deadshot/internal/logging"
+	"github.com/jon4hz/deadshot/internal/signer"
+
+	"github.com/99designs/keyring"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrNoMnemonic     = errors.New("the main secret isn't a mnemonic phrase")
+	ErrWalletMismatch = errors.New("the private key doesn't match the wallet address")
+	ErrInvalidAddress = errors.New("invalid address")
+)
+
+// walletServiceName returns the keystore key of the private key of an imported wallet.
+func walletServiceName(address string) string {
+	return defaultServiceName + "." + strings.ToLower(address)
+}
+
+// LoadWallets loads the private keys of the additional wallets.
+// Wallets which can't be loaded are skipped and don't have a private key.
+func LoadWallets(wallets []*database.Wallet) {
+	for _, w := range wallets {
+		if err := LoadWallet(w); err != nil {
+			logging.Log.WithFields(logrus.Fields{
+				"error":  err,
+				"wallet": w.GetWallet(),
+			}).Warn("failed to load wallet")
+		}
+	}
+}
+
+// LoadWallet loads the private key of an additional wallet.
+// Imported wallets have their own private key, external wallets connect to their signer
+// and all other wallets are derived from the main mnemonic phrase.
+func LoadWallet(w *database.Wallet) error {
+	if w.IsExternal() {
+		s, err := signer.NewClef(w.GetSignerURL(), common.HexToAddress(w.GetWallet()))
+		if err != nil {
+			return err
+		}
+		w.SetSigner(s)
+		return nil
+	}
+
+	var (
+		secret string
+		err    error
+	)
+	if w.GetImported() {
+		secret, err = getWalletSecret(w.GetWallet())
+	} else {
+		secret, err = getSecret()
+	}
+	if err != nil {
+		if errors.Is(err, keyring.ErrKeyNotFound) {
+			return ErrSecretNotFound
+		}
+		return err
+	}
+
+	key, err := extractPrivateKey(w, secret)
+	if err != nil {
+		return err
+	}
+	address, err := getAddressFromKey(key)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(address, w.GetWallet()) {
+		return ErrWalletMismatch
+	}
+	w.SetPrivateKey(key)
+	return nil
+}
+
+// AddDerivedWallet adds the wallet of the mnemonic phrase at the given derivation path and index.
+func AddDerivedWallet(derivationPath string, index uint, label string) (*database.Wallet, error) {
+	mnemonic, ok, err := SearchMnemonic()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNoMnemonic
+	}
+	address, err := GetAddrByPath(mnemonic, derivationPath, index)
+	if err != nil {
+		return nil, err
+	}
+	w := &database.Wallet{
+		Wallet:         address,
+		WalletIndex:    index,
+		DerivationPath: derivationPath,
+		Label:          label,
+	}
+	if err := database.AddWallet(w); err != nil {
+		return nil, err
+	}
+	return w, LoadWallet(w)
+}
+
+// ImportPrivateKey stores the private key in the keystore and adds its wallet.
+func ImportPrivateKey(secret, label string) (*database.Wallet, error) {
+	secret = strings.TrimPrefix(strings.TrimSpace(secret), "0x")
+	key, err := crypto.HexToECDSA(secret)
+	if err != nil {
+		logging.Log.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Error("error converting hex to ecdsa")
+		return nil, err
+	}
+	address, err := getAddressFromKey(key)
+	if err != nil {
+		return nil, err
+	}
+	w := &database.Wallet{
+		Wallet:   address,
+		Label:    label,
+		Imported: true,
+	}
+	if err := database.AddWallet(w); err != nil {
+		return nil, err
+	}
+	if err := keystore.Set(keyring.Item{
+		Key:  walletServiceName(address),
+		Data: []byte(secret),
+	}); err != nil {
+		if err := database.RemoveWallet(w); err != nil {
+			logging.Log.WithFields(logrus.Fields{
+				"error": err,
+			}).Error("failed to remove wallet without private key")
+		}
+		return nil, err
+	}
+	w.SetPrivateKey(key)
+	return w, nil
+}
+
+// AddExternalWallet adds a wallet whose private key is held by an external signer (clef).
+// The signer must be reachable and manage the address.
+func AddExternalWallet(signerURL, address, label string) (*database.Wallet, error) {
+	if !common.IsHexAddress(address) {
+		return nil, ErrInvalidAddress
+	}
+	signerURL = expandHome(signerURL)
+	s, err := signer.NewClef(signerURL, common.HexToAddress(address))
+	if err != nil {
+		return nil, err
+	}
+	w := &database.Wallet{
+		Wallet:    s.Address().Hex(),
+		Label:     label,
+		SignerURL: signerURL,
+	}
+	if err := database.AddWallet(w); err != nil {
+		return nil, err
+	}
+	w.SetSigner(s)
+	return w, nil
+}
+
+// RemoveWallet removes an additional wallet and the private key of imported wallets.
+// The private key is only removed if the wallet could be removed from the database.
+func RemoveWallet(w *database.Wallet) error {
+	if err := database.RemoveWallet(w); err != nil {
+		return err
+	}
+	if w.GetImported() {
+		if err := keystore.Remove(walletServiceName(w.GetWallet())); err != nil && !errors.Is(err, keyring.ErrKeyNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+func getWalletSecret(address string) (string, error) {
+	item, err := keystore.Get(walletServiceName(address))
+	if err != nil {
+		return "", err
+	}
+	return string(item.Data), nil
+}
@@ -5,6 +5,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 const (
@@ -53,6 +54,10 @@ type Pair struct {
 	LiquidityToken *Token
 	// sorted tokens
 	TokenAmounts
+	// fee overrides the dex fee passed to the amount calculations, if set.
+	fee *big.Int
+	// stable pairs use the solidly stable swap invariant instead of x * y = k.
+	stable bool
 }
 
 // NewPair creates Pair
@@ -73,6 +78,43 @@ func NewPair(address common.Address, tokenAmountA, tokenAmountB *TokenAmount) (*
 	return pair, err
 }
 
+// GetAddress computes the address of the pair of the tokens with CREATE2.
+// The init code hash is the keccak256 hash of the pair contract's creation code, it's specific to every factory.
+func GetAddress(factory common.Address, tokenA, tokenB *Token, initCodeHash common.Hash) (common.Address, error) {
+	ok, err := tokenA.SortsBefore(tokenB)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if !ok {
+		tokenA, tokenB = tokenB, tokenA
+	}
+	salt := crypto.Keccak256Hash(tokenA.CommonAddress().Bytes(), tokenB.CommonAddress().Bytes())
+	return crypto.CreateAddress2(factory, salt, initCodeHash.Bytes()), nil
+}
+
+// SetFee sets the fee of the pair, e.g. 9970 for 0.3%.
+// Pairs with a fee ignore the dex fee passed to GetOutputAmount and GetInputAmount.
+func (p *Pair) SetFee(fee *big.Int) *Pair {
+	p.fee = fee
+	return p
+}
+
+// Fee returns the fee of the pair or nil if the pair uses the dex fee.
+func (p *Pair) Fee() *big.Int {
+	return p.fee
+}
+
+// feeOr returns the fee of the pair or the given dex fee if the pair has none.
+func (p *Pair) feeOr(dexFee *big.Int) *big.Int {
+	if p.fee != nil {
+		return p.fee
+	}
+	if dexFee == nil {
+		return defaultDexFee
+	}
+	return dexFee
+}
+
 // InvolvesToken Returns true if the token is either token0 or token1.
 func (p *Pair) InvolvesToken(token *Token) bool {
 	return token.equals(p.TokenAmounts[0].Token.Address()) || token.equals(p.TokenAmounts[1].Token.Address())
@@ -157,10 +199,16 @@ func (p *Pair) GetOutputAmount(inputAmount *TokenAmount, dexFee *big.Int) (*Toke
 		return nil, nil, err
 	}
 
-	inputAmountWithFee := big.NewInt(0).Mul(inputAmount.Raw(), dexFee)
-	numerator := big.NewInt(0).Mul(inputAmountWithFee, outputReserve.Raw())
-	denominator := big.NewInt(0).Add(big.NewInt(0).Mul(inputReserve.Raw(), big.NewInt(10000)), inputAmountWithFee)
-	outputAmount, err := NewTokenAmount(token, big.NewInt(0).Div(numerator, denominator))
+	var amount *big.Int
+	if p.stable {
+		amount = stableOutputAmount(inputAmount, inputReserve, outputReserve, p.feeOr(dexFee))
+	} else {
+		inputAmountWithFee := big.NewInt(0).Mul(inputAmount.Raw(), p.feeOr(dexFee))
+		numerator := big.NewInt(0).Mul(inputAmountWithFee, outputReserve.Raw())
+		denominator := big.NewInt(0).Add(big.NewInt(0).Mul(inputReserve.Raw(), big.NewInt(10000)), inputAmountWithFee)
+		amount = big.NewInt(0).Div(numerator, denominator)
+	}
+	outputAmount, err := NewTokenAmount(token, amount)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -181,6 +229,8 @@ func (p *Pair) GetOutputAmount(inputAmount *TokenAmount, dexFee *big.Int) (*Toke
 	if err != nil {
 		return nil, nil, err
 	}
+	pair.fee = p.fee
+	pair.stable = p.stable
 	return outputAmount, pair, nil
 }
 
@@ -209,12 +259,17 @@ func (p *Pair) GetInputAmount(outputAmount *TokenAmount, dexFee *big.Int) (*Toke
 		return nil, nil, err
 	}
 
-	numerator := big.NewInt(0).Mul(inputReserve.Raw(), outputAmount.Raw())
-	numerator.Mul(numerator, big.NewInt(10000))
-	denominator := big.NewInt(0).Sub(outputReserve.Raw(), outputAmount.Raw())
-	denominator.Mul(denominator, dexFee)
-	amount := big.NewInt(0).Div(numerator, denominator)
-	amount.Add(amount, big.NewInt(1))
+	var amount *big.Int
+	if p.stable {
+		amount = stableInputAmount(outputAmount, inputReserve, outputReserve, p.feeOr(dexFee))
+	} else {
+		numerator := big.NewInt(0).Mul(inputReserve.Raw(), outputAmount.Raw())
+		numerator.Mul(numerator, big.NewInt(10000))
+		denominator := big.NewInt(0).Sub(outputReserve.Raw(), outputAmount.Raw())
+		denominator.Mul(denominator, p.feeOr(dexFee))
+		amount = big.NewInt(0).Div(numerator, denominator)
+		amount.Add(amount, big.NewInt(1))
+	}
 	inputAmount, err := NewTokenAmount(token, amount)
 	if err != nil {
 		return nil, nil, err
@@ -233,6 +288,8 @@ func (p *Pair) GetInputAmount(outputAmount *TokenAmount, dexFee *big.Int) (*Toke
 	if err != nil {
 		return nil, nil, err
 	}
+	pair.fee = p.fee
+	pair.stable = p.stable
 	return inputAmount, pair, nil
 }
 
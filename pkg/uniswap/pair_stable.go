@@ -0,0 +1,147 @@
+package uniswap
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// stableIterations is the maximum number of newton iterations to solve the stable invariant.
+const stableIterations = 255
+
+// stablePrecision is the precision all reserves are normalized to, before the stable invariant is applied.
+var stablePrecision = big.NewInt(1e18)
+
+// SetStable marks the pair as a solidly stable pair, which uses the x³y + y³x = k invariant.
+func (p *Pair) SetStable(stable bool) *Pair {
+	p.stable = stable
+	return p
+}
+
+// Stable returns whether the pair is a solidly stable pair.
+func (p *Pair) Stable() bool {
+	return p.stable
+}
+
+// GetSolidlyAddress computes the address of the volatile or stable solidly pair of the tokens with CREATE2.
+func GetSolidlyAddress(factory common.Address, tokenA, tokenB *Token, stable bool, initCodeHash common.Hash) (common.Address, error) {
+	ok, err := tokenA.SortsBefore(tokenB)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if !ok {
+		tokenA, tokenB = tokenB, tokenA
+	}
+	var s byte
+	if stable {
+		s = 1
+	}
+	salt := crypto.Keccak256Hash(tokenA.CommonAddress().Bytes(), tokenB.CommonAddress().Bytes(), []byte{s})
+	return crypto.CreateAddress2(factory, salt, initCodeHash.Bytes()), nil
+}
+
+// stableOutputAmount returns the output amount of a stable pair.
+// The amount calculations are identical to the solidly pair contract.
+func stableOutputAmount(inputAmount, inputReserve, outputReserve *TokenAmount, fee *big.Int) *big.Int {
+	inputDecimals := decimalsScale(inputAmount.Token)
+	outputDecimals := decimalsScale(outputReserve.Token)
+
+	amountIn := new(big.Int).Mul(inputAmount.Raw(), fee)
+	amountIn.Quo(amountIn, big.NewInt(10000))
+
+	xy := stableK(inputReserve.Raw(), outputReserve.Raw(), inputDecimals, outputDecimals)
+	reserveA := normalize(inputReserve.Raw(), inputDecimals)
+	reserveB := normalize(outputReserve.Raw(), outputDecimals)
+	amountIn = normalize(amountIn, inputDecimals)
+
+	y := new(big.Int).Sub(reserveB, stableGetY(new(big.Int).Add(amountIn, reserveA), xy, reserveB))
+	if y.Sign() < 0 {
+		return big.NewInt(0)
+	}
+	y.Mul(y, outputDecimals)
+	return y.Quo(y, stablePrecision)
+}
+
+// stableInputAmount returns the input amount of a stable pair.
+// Since the invariant is symmetric, the new input reserve is solved like the output reserve in stableOutputAmount.
+func stableInputAmount(outputAmount, inputReserve, outputReserve *TokenAmount, fee *big.Int) *big.Int {
+	inputDecimals := decimalsScale(inputReserve.Token)
+	outputDecimals := decimalsScale(outputAmount.Token)
+
+	xy := stableK(inputReserve.Raw(), outputReserve.Raw(), inputDecimals, outputDecimals)
+	reserveA := normalize(inputReserve.Raw(), inputDecimals)
+	reserveB := normalize(outputReserve.Raw(), outputDecimals)
+	amountOut := normalize(outputAmount.Raw(), outputDecimals)
+
+	x := new(big.Int).Sub(stableGetY(new(big.Int).Sub(reserveB, amountOut), xy, reserveA), reserveA)
+	x.Mul(x, inputDecimals)
+	x.Quo(x, stablePrecision)
+	x.Add(x, big.NewInt(1))
+
+	// add the fee, rounded up
+	x.Mul(x, big.NewInt(10000))
+	x.Add(x, new(big.Int).Sub(fee, big.NewInt(1)))
+	return x.Quo(x, fee)
+}
+
+// stableK returns the invariant x³y + y³x of the normalized reserves.
+func stableK(x, y, decimalsX, decimalsY *big.Int) *big.Int {
+	x = normalize(x, decimalsX)
+	y = normalize(y, decimalsY)
+	a := mulDiv(x, y, stablePrecision)
+	b := new(big.Int).Add(mulDiv(x, x, stablePrecision), mulDiv(y, y, stablePrecision))
+	return mulDiv(a, b, stablePrecision)
+}
+
+// stableGetY solves the invariant for y with the newton method.
+func stableGetY(x0, xy, y *big.Int) *big.Int {
+	y = new(big.Int).Set(y)
+	for i := 0; i < stableIterations; i++ {
+		prev := new(big.Int).Set(y)
+		k := stableF(x0, y)
+		d := stableD(x0, y)
+		if d.Sign() == 0 {
+			return y
+		}
+		if k.Cmp(xy) < 0 {
+			y.Add(y, mulDiv(new(big.Int).Sub(xy, k), stablePrecision, d))
+		} else {
+			y.Sub(y, mulDiv(new(big.Int).Sub(k, xy), stablePrecision, d))
+		}
+		if new(big.Int).Abs(new(big.Int).Sub(y, prev)).Cmp(big.NewInt(1)) <= 0 {
+			return y
+		}
+	}
+	return y
+}
+
+// stableF returns x0³y + y³x0.
+func stableF(x0, y *big.Int) *big.Int {
+	y3 := mulDiv(mulDiv(y, y, stablePrecision), y, stablePrecision)
+	x3 := mulDiv(mulDiv(x0, x0, stablePrecision), x0, stablePrecision)
+	return new(big.Int).Add(mulDiv(x0, y3, stablePrecision), mulDiv(x3, y, stablePrecision))
+}
+
+// stableD returns the derivative of stableF by y.
+func stableD(x0, y *big.Int) *big.Int {
+	y2 := mulDiv(y, y, stablePrecision)
+	x3 := mulDiv(mulDiv(x0, x0, stablePrecision), x0, stablePrecision)
+	d := mulDiv(new(big.Int).Mul(big.NewInt(3), x0), y2, stablePrecision)
+	return d.Add(d, x3)
+}
+
+// normalize scales the amount to the stable precision.
+func normalize(amount, decimals *big.Int) *big.Int {
+	return mulDiv(amount, stablePrecision, decimals)
+}
+
+// decimalsScale returns 10^decimals of the token.
+func decimalsScale(token *Token) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(token.Decimals())), nil)
+}
+
+func mulDiv(a, b, c *big.Int) *big.Int {
+	r := new(big.Int).Mul(a, b)
+	return r.Quo(r, c)
+}
@@ -0,0 +1,58 @@
+package uniswap
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestStablePair(t *testing.T) {
+	USDC, _ := NewToken(common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"), "USD Coin", "USDC", 6)
+	DAI, _ := NewToken(common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F"), "DAI Stablecoin", "DAI", 18)
+	e18 := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	e6 := big.NewInt(1e6)
+
+	newPair := func(stable bool) *Pair {
+		reserveUSDC, _ := NewTokenAmount(USDC, new(big.Int).Mul(big.NewInt(1e6), e6))
+		reserveDAI, _ := NewTokenAmount(DAI, new(big.Int).Mul(big.NewInt(1e6), e18))
+		p, _ := NewPair(common.HexToAddress("0x0000000000000000000000000000000000000010"), reserveUSDC, reserveDAI)
+		return p.SetFee(big.NewInt(9999)).SetStable(stable)
+	}
+	stable, volatile := newPair(true), newPair(false)
+	if !stable.Stable() || volatile.Stable() {
+		t.Fatal("expected the stable flag to be set")
+	}
+
+	// 10% of the reserves
+	amountIn, _ := NewTokenAmount(USDC, new(big.Int).Mul(big.NewInt(1e5), e6))
+	stableOut, next, err := stable.GetOutputAmount(amountIn, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !next.Stable() {
+		t.Error("expected the next pair to be stable")
+	}
+	volatileOut, _, err := volatile.GetOutputAmount(amountIn, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stableOut.Raw().Cmp(volatileOut.Raw()) <= 0 {
+		t.Errorf("expected stable output %s to beat volatile output %s", stableOut.Raw(), volatileOut.Raw())
+	}
+	// a stable pair keeps the price close to 1:1, even for large trades
+	minOut := new(big.Int).Mul(big.NewInt(99000), e18)
+	if stableOut.Raw().Cmp(minOut) < 0 || stableOut.Raw().Cmp(new(big.Int).Mul(big.NewInt(1e5), e18)) >= 0 {
+		t.Errorf("expected stable output between %s and %s, but got %s", minOut, new(big.Int).Mul(big.NewInt(1e5), e18), stableOut.Raw())
+	}
+
+	// the input amount for the output is the original input, up to rounding
+	input, _, err := stable.GetInputAmount(stableOut, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := new(big.Int).Sub(input.Raw(), amountIn.Raw())
+	if diff.Sign() < 0 || diff.Cmp(big.NewInt(2)) > 0 {
+		t.Errorf("expected input %s to be close to %s", input.Raw(), amountIn.Raw())
+	}
+}
@@ -319,3 +319,85 @@ func TestPair(t *testing.T) {
 		}
 	}
 }
+
+func TestPairFee(t *testing.T) {
+	token0, _ := NewToken(common.HexToAddress("0x0000000000000000000000000000000000000001"), "t0", "t0", 18)
+	token1, _ := NewToken(common.HexToAddress("0x0000000000000000000000000000000000000002"), "t1", "t1", 18)
+	tokenAmount_0_10000, _ := NewTokenAmount(token0, big.NewInt(10000))
+	tokenAmount_1_10000, _ := NewTokenAmount(token1, big.NewInt(10000))
+	tokenAmount_0_1000, _ := NewTokenAmount(token0, big.NewInt(1000))
+	tokenAmount_1_500, _ := NewTokenAmount(token1, big.NewInt(500))
+
+	pairA, _ := NewPair(common.HexToAddress("0xF60dDd2C94A754f0C2bC4770fE0fFF6e526fDeF8"), tokenAmount_0_10000, tokenAmount_1_10000)
+	pairB, _ := NewPair(common.HexToAddress("0xD9cf6Be4BBb62f301Aa5d9a9B1929aCe9013A073"), tokenAmount_0_10000, tokenAmount_1_10000)
+	pairB.SetFee(big.NewInt(9990))
+
+	// pairs without a fee use the dex fee
+	{
+		output, _, _ := pairA.GetOutputAmount(tokenAmount_0_1000, defaultDexFee)
+		expect := "906" // 1000 * 9970 * 10000 / (10000 * 10000 + 1000 * 9970)
+		if expect != output.Raw().String() {
+			t.Errorf("expect[%+v], but got[%+v]", expect, output.Raw().String())
+		}
+	}
+	// pairs with a fee ignore the dex fee
+	{
+		output, next, _ := pairB.GetOutputAmount(tokenAmount_0_1000, defaultDexFee)
+		expect := "908" // 1000 * 9990 * 10000 / (10000 * 10000 + 1000 * 9990)
+		if expect != output.Raw().String() {
+			t.Errorf("expect[%+v], but got[%+v]", expect, output.Raw().String())
+		}
+		if next.Fee().Cmp(big.NewInt(9990)) != 0 {
+			t.Errorf("expect[%+v], but got[%+v]", 9990, next.Fee())
+		}
+	}
+	{
+		input, _, _ := pairB.GetInputAmount(tokenAmount_1_500, defaultDexFee)
+		expect := "527" // 10000 * 500 * 10000 / ((10000 - 500) * 9990) + 1
+		if expect != input.Raw().String() {
+			t.Errorf("expect[%+v], but got[%+v]", expect, input.Raw().String())
+		}
+	}
+	// the best trade picks the pair with the lower fee
+	{
+		result, err := BestTradeExactIn([]*Pair{pairA, pairB}, tokenAmount_0_1000, token1, NewDefaultBestTradeOptions(), nil, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect := pairB.LiquidityToken.Address()
+		output := result[0].Route.Pairs[0].LiquidityToken.Address()
+		if expect != output {
+			t.Errorf("expect[%+v], but got[%+v]", expect, output)
+		}
+	}
+}
+
+func TestGetAddress(t *testing.T) {
+	factory := common.HexToAddress("0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f")
+	initCodeHash := common.HexToHash("0x96e8ac4277198ff8b6f785478aa9a39f403cb768dd02cbee326c3e7da348845f")
+	USDC, _ := NewToken(common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"), "USD Coin", "USDC", 6)
+	DAI, _ := NewToken(common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F"), "DAI Stablecoin", "DAI", 18)
+	WETH, _ := NewToken(common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"), "Wrapped Ether", "WETH", 18)
+
+	tests := []struct {
+		tokenA, tokenB *Token
+		expect         common.Address
+	}{
+		{USDC, DAI, common.HexToAddress("0xAE461cA67B15dc8dc81CE7615e0320dA1A9aB8D5")},
+		{DAI, USDC, common.HexToAddress("0xAE461cA67B15dc8dc81CE7615e0320dA1A9aB8D5")},
+		{USDC, WETH, common.HexToAddress("0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc")},
+	}
+	for _, test := range tests {
+		addr, err := GetAddress(factory, test.tokenA, test.tokenB, initCodeHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr != test.expect {
+			t.Errorf("expect[%+v], but got[%+v]", test.expect, addr)
+		}
+	}
+
+	if _, err := GetAddress(factory, USDC, USDC, initCodeHash); err != ErrSameAddrss {
+		t.Errorf("expect[%+v], but got[%+v]", ErrSameAddrss, err)
+	}
+}
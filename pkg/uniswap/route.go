@@ -71,3 +71,12 @@ func (r *Route) GetAddresses() []common.Address {
 	}
 	return addresses
 }
+
+// GetStables returns whether each pair of the route is a stable pair.
+func (r *Route) GetStables() []bool {
+	stables := make([]bool, len(r.Pairs))
+	for i := range r.Pairs {
+		stables[i] = r.Pairs[i].Stable()
+	}
+	return stables
+}
@@ -0,0 +1,188 @@
+package uniswap
+
+import (
+	"errors"
+	"math/big"
+)
+
+// DefaultSplitSteps splits the input amount in 5% steps.
+const DefaultSplitSteps = 20
+
+var (
+	ErrInvalidSteps = errors.New("invalid split steps")
+	ErrNoSplitRoute = errors.New("no route can take the split amount")
+)
+
+// Split is the part of a split trade which is routed through a single route.
+type Split struct {
+	// Index of the route in the routes passed to BestSplitTradeExactIn.
+	Index int
+	// Parts is the number of steps allocated to the route.
+	Parts int
+	Trade *Trade
+}
+
+// SplitTrade is an exact in trade with the input amount split across multiple routes.
+type SplitTrade struct {
+	Splits       []*Split
+	Steps        int
+	inputAmount  *TokenAmount
+	outputAmount *TokenAmount
+}
+
+// InputAmount returns the total input amount of the split trade.
+func (t *SplitTrade) InputAmount() *TokenAmount {
+	return t.inputAmount
+}
+
+// OutputAmount returns the total output amount of the split trade.
+func (t *SplitTrade) OutputAmount() *TokenAmount {
+	return t.outputAmount
+}
+
+// Percent returns the share of the input amount routed through the split.
+func (t *SplitTrade) Percent(s *Split) *Percent {
+	return NewPercent(big.NewInt(int64(s.Parts)), big.NewInt(int64(t.Steps)))
+}
+
+// BestSplitTradeExactIn splits the input amount across the given routes to maximise the output.
+// The amount is allocated in the given number of steps, every step goes to the route
+// with the highest marginal output for it.
+// Routes sharing a pair with a previous route are ignored, since their outputs depend on each other.
+func BestSplitTradeExactIn(routes []*Route, amountIn *TokenAmount, steps int, dexFee *big.Int) (*SplitTrade, error) {
+	if steps <= 0 {
+		return nil, ErrInvalidSteps
+	}
+	if amountIn == nil {
+		return nil, ErrTokenAmountNil
+	}
+	routes, indices := disjointRoutes(routes)
+
+	var (
+		parts   = make([]int, len(routes))
+		outputs = make([]*big.Int, len(routes))
+	)
+	for i := range outputs {
+		outputs[i] = big.NewInt(0)
+	}
+
+	for step := 0; step < steps; step++ {
+		best, bestMarginal, bestOutput := -1, big.NewInt(-1), (*big.Int)(nil)
+		for i, route := range routes {
+			if !route.Input.equals(amountIn.Token.Address()) {
+				continue
+			}
+			out, err := splitOutput(route, splitAmount(amountIn, parts[i]+1, steps), dexFee)
+			if err != nil {
+				continue
+			}
+			marginal := new(big.Int).Sub(out, outputs[i])
+			if marginal.Cmp(bestMarginal) > 0 {
+				best, bestMarginal, bestOutput = i, marginal, out
+			}
+		}
+		if best < 0 {
+			return nil, ErrNoSplitRoute
+		}
+		parts[best]++
+		outputs[best] = bestOutput
+	}
+
+	t, err := newSplitTrade(routes, parts, amountIn, steps, dexFee)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range t.Splits {
+		s.Index = indices[s.Index]
+	}
+	return t, nil
+}
+
+// newSplitTrade creates the trades for the allocated parts.
+// The route with the most parts receives the rounding remainder of the input amount.
+func newSplitTrade(routes []*Route, parts []int, amountIn *TokenAmount, steps int, dexFee *big.Int) (*SplitTrade, error) {
+	largest := 0
+	for i := range parts {
+		if parts[i] > parts[largest] {
+			largest = i
+		}
+	}
+	remainder := new(big.Int).Set(amountIn.Raw())
+	for i := range parts {
+		if parts[i] == 0 || i == largest {
+			continue
+		}
+		remainder.Sub(remainder, splitAmount(amountIn, parts[i], steps).Raw())
+	}
+
+	t := &SplitTrade{Steps: steps, inputAmount: amountIn}
+	total := big.NewInt(0)
+	var outputToken *Token
+	for i := range parts {
+		if parts[i] == 0 {
+			continue
+		}
+		amount := splitAmount(amountIn, parts[i], steps)
+		if i == largest {
+			amount, _ = NewTokenAmount(amountIn.Token, remainder)
+		}
+		trade, err := NewTrade(routes[i], amount, ExactInput, dexFee)
+		if err != nil {
+			return nil, err
+		}
+		total.Add(total, trade.OutputAmount().Raw())
+		outputToken = trade.OutputAmount().Token
+		t.Splits = append(t.Splits, &Split{Index: i, Parts: parts[i], Trade: trade})
+	}
+
+	var err error
+	t.outputAmount, err = NewTokenAmount(outputToken, total)
+	return t, err
+}
+
+// splitAmount returns parts/steps of the amount.
+func splitAmount(amount *TokenAmount, parts, steps int) *TokenAmount {
+	raw := new(big.Int).Mul(amount.Raw(), big.NewInt(int64(parts)))
+	raw.Quo(raw, big.NewInt(int64(steps)))
+	a, _ := NewTokenAmount(amount.Token, raw)
+	return a
+}
+
+// splitOutput returns the output amount of the route for the given input amount.
+func splitOutput(route *Route, amount *TokenAmount, dexFee *big.Int) (*big.Int, error) {
+	current := amount
+	for _, pair := range route.Pairs {
+		out, _, err := pair.GetOutputAmount(current, dexFee)
+		if err != nil {
+			return nil, err
+		}
+		current = out
+	}
+	return current.Raw(), nil
+}
+
+// disjointRoutes removes the routes which share a pair with a previous route.
+// It also returns the original index of every remaining route.
+func disjointRoutes(routes []*Route) ([]*Route, []int) {
+	used := make(map[string]bool)
+	res := make([]*Route, 0, len(routes))
+	indices := make([]int, 0, len(routes))
+	for i, route := range routes {
+		shared := false
+		for _, pair := range route.Pairs {
+			if used[pair.LiquidityToken.Address()] {
+				shared = true
+				break
+			}
+		}
+		if shared {
+			continue
+		}
+		for _, pair := range route.Pairs {
+			used[pair.LiquidityToken.Address()] = true
+		}
+		res = append(res, route)
+		indices = append(indices, i)
+	}
+	return res, indices
+}
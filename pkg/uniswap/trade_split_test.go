@@ -0,0 +1,91 @@
+package uniswap
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestBestSplitTradeExactIn(t *testing.T) {
+	token0, _ := NewToken(common.HexToAddress("0x0000000000000000000000000000000000000001"), "t0", "t0", 18)
+	token1, _ := NewToken(common.HexToAddress("0x0000000000000000000000000000000000000002"), "t1", "t1", 18)
+	token2, _ := NewToken(common.HexToAddress("0x0000000000000000000000000000000000000003"), "t2", "t2", 18)
+
+	newPair := func(addr string, a *Token, reserveA int64, b *Token, reserveB int64) *Pair {
+		amountA, _ := NewTokenAmount(a, big.NewInt(reserveA))
+		amountB, _ := NewTokenAmount(b, big.NewInt(reserveB))
+		p, _ := NewPair(common.HexToAddress(addr), amountA, amountB)
+		return p
+	}
+	pairA := newPair("0xF60dDd2C94A754f0C2bC4770fE0fFF6e526fDeF8", token0, 100000, token1, 100000)
+	pairB := newPair("0xD9cf6Be4BBb62f301Aa5d9a9B1929aCe9013A073", token0, 100000, token1, 100000)
+	pairC := newPair("0xB8d53323b877B9dc5746E4E94a1374Bd84CdC99A", token0, 1000000, token2, 1000000)
+	pairD := newPair("0x2f30b99E339A0a511c133eD343C649AB9FD2AF67", token2, 1000000, token1, 1000000)
+
+	routeA, _ := NewRoute([]*Pair{pairA}, token0, token1)
+	routeB, _ := NewRoute([]*Pair{pairB}, token0, token1)
+	routeAB, _ := NewRoute([]*Pair{pairA}, token0, token1)
+	routeCD, _ := NewRoute([]*Pair{pairC, pairD}, token0, token1)
+
+	amountIn, _ := NewTokenAmount(token0, big.NewInt(20000))
+
+	// two equal routes split the amount in half
+	{
+		split, err := BestSplitTradeExactIn([]*Route{routeA, routeB}, amountIn, DefaultSplitSteps, defaultDexFee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(split.Splits) != 2 {
+			t.Fatalf("expect[%+v], but got[%+v]", 2, len(split.Splits))
+		}
+		for _, s := range split.Splits {
+			if s.Parts != DefaultSplitSteps/2 {
+				t.Errorf("expect[%+v], but got[%+v]", DefaultSplitSteps/2, s.Parts)
+			}
+		}
+		single, _ := NewTrade(routeA, amountIn, ExactInput, defaultDexFee)
+		if split.OutputAmount().Raw().Cmp(single.OutputAmount().Raw()) <= 0 {
+			t.Errorf("expect split output %s to beat single route output %s", split.OutputAmount().Raw(), single.OutputAmount().Raw())
+		}
+		total := big.NewInt(0)
+		for _, s := range split.Splits {
+			total.Add(total, s.Trade.InputAmount().Raw())
+		}
+		if total.Cmp(amountIn.Raw()) != 0 {
+			t.Errorf("expect[%+v], but got[%+v]", amountIn.Raw(), total)
+		}
+	}
+
+	// routes sharing a pair with a previous route are ignored
+	{
+		split, err := BestSplitTradeExactIn([]*Route{routeA, routeAB}, amountIn, DefaultSplitSteps, defaultDexFee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(split.Splits) != 1 || split.Splits[0].Index != 0 {
+			t.Errorf("expect a single split on route 0, but got %+v", split.Splits)
+		}
+	}
+
+	// the deep route takes most of the amount and keeps its index
+	{
+		split, err := BestSplitTradeExactIn([]*Route{routeA, routeAB, routeCD}, amountIn, DefaultSplitSteps, defaultDexFee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var deep *Split
+		for _, s := range split.Splits {
+			if s.Index == 2 {
+				deep = s
+			}
+		}
+		if deep == nil || deep.Parts <= DefaultSplitSteps/2 {
+			t.Errorf("expect the deep route to take most of the amount, but got %+v", deep)
+		}
+	}
+
+	if _, err := BestSplitTradeExactIn([]*Route{routeA}, amountIn, 0, defaultDexFee); err != ErrInvalidSteps {
+		t.Errorf("expect[%+v], but got[%+v]", ErrInvalidSteps, err)
+	}
+}